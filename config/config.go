@@ -83,21 +83,27 @@ func LoadConfig() *Config {
 
 		if envFile != "" {
 			if err := godotenv.Load(envFile); err != nil {
-				log.Printf("Error loading %s file: %v", envFile, err)
+				Logger().Warn("Failed to load env file", "file", envFile, "error", err)
 				if appEnv == "development" && envFile == ".env.dev2" {
 					if fallbackErr := godotenv.Load(".env"); fallbackErr != nil {
-						log.Printf("Error loading fallback .env file: %v", fallbackErr)
+						Logger().Warn("Failed to load fallback env file", "file", ".env", "error", fallbackErr)
 					}
 				}
 			}
 		}
 
+		level, ok := ParseLogLevel(os.Getenv("LOG_LEVEL"), appEnv)
+		SetLogLevel(level)
+		if !ok {
+			Logger().Warn("Invalid LOG_LEVEL, using default", "value", os.Getenv("LOG_LEVEL"), "level", level.String())
+		}
+
 		appPort, _ := strconv.ParseUint(os.Getenv("APPPORT"), 10, 16)
 		dbPort, _ := strconv.ParseUint(os.Getenv("DBPORT"), 10, 16)
 		shutdownTimeoutStr := os.Getenv("SHUTDOWNTIMEOUT")
 		shutdownTimeout, err := strconv.Atoi(shutdownTimeoutStr)
 		if err != nil && shutdownTimeoutStr != "" {
-			log.Printf("Invalid SHUTDOWNTIMEOUT value, using default (5 seconds): %v", err)
+			Logger().Warn("Invalid SHUTDOWNTIMEOUT value, using default (5 seconds)", "error", err)
 		}
 		if shutdownTimeout <= 0 {
 			shutdownTimeout = 5 // Default to 5 seconds if not specified or invalid
@@ -137,6 +143,26 @@ func LoadConfig() *Config {
 	return config
 }
 
+// newGormLogger returns a GORM logger whose verbosity follows DB_LOG_LEVEL,
+// or LOG_LEVEL when it is not set. Colors are only used outside production.
+// Queries are logged without their bound values so slow-query and error logs
+// never carry patient data or credentials.
+func newGormLogger() logger.Interface {
+	level, ok := ParseDBLogLevel(os.Getenv("DB_LOG_LEVEL"))
+	if !ok {
+		Logger().Warn("Invalid DB_LOG_LEVEL, following LOG_LEVEL", "value", os.Getenv("DB_LOG_LEVEL"))
+	}
+	return logger.New(
+		log.New(os.Stdout, "\r\n", log.LstdFlags),
+		logger.Config{
+			SlowThreshold:        200 * time.Millisecond, // Slow SQL threshold
+			LogLevel:             level,
+			Colorful:             LoadConfig().AppEnv != "production",
+			ParameterizedQueries: true,
+		},
+	)
+}
+
 // ConnectMySQL establishes a connection to a MySQL database using the configuration values.
 func ConnectMySQL() (*gorm.DB, error) {
 
@@ -147,16 +173,7 @@ func ConnectMySQL() (*gorm.DB, error) {
 		// cross-test contamination when tests run in the same process.
 		// Example: file:testdb_123456789?mode=memory&cache=shared
 		dsn := fmt.Sprintf("file:testdb_%d?mode=memory&cache=shared", time.Now().UnixNano())
-		gormConfig := &gorm.Config{
-			Logger: logger.New(
-				log.New(os.Stdout, "\r\n", log.LstdFlags),
-				logger.Config{
-					SlowThreshold: 200 * time.Millisecond,
-					LogLevel:      logger.Info,
-					Colorful:      true,
-				},
-			),
-		}
+		gormConfig := &gorm.Config{Logger: newGormLogger()}
 		db, err := gorm.Open(sqlite.Open(dsn), gormConfig)
 		if err != nil {
 			return nil, err
@@ -175,25 +192,9 @@ func ConnectMySQL() (*gorm.DB, error) {
 	}
 	// Build the Data Source Name (DSN) using the configuration values.
 	dsn := buildMySQLDSN(cfg, cfg.DBPass)
-	gormConfig := &gorm.Config{}
-	if cfg.AppEnv == "production" {
-		gormConfig.Logger = logger.Default.LogMode(logger.Silent)
-	} else {
-		gormConfig = &gorm.Config{
-			Logger: logger.New(
-				log.New(os.Stdout, "\r\n", log.LstdFlags),
-				logger.Config{
-					SlowThreshold: 200 * time.Millisecond, // Slow SQL threshold
-					LogLevel:      logger.Info,            // Log level
-					Colorful:      true,                   // Enable colorful output
-				},
-			),
-		}
-	}
-	// Log non-sensitive connection info for debugging in non-production.
-	if cfg.AppEnv != "production" {
-		log.Printf("Connecting to MySQL host=%s port=%d db=%s user=%s", cfg.DBHost, cfg.DBPort, cfg.DBName, cfg.DBUSER)
-	}
+	gormConfig := &gorm.Config{Logger: newGormLogger()}
+	// Log non-sensitive connection info for debugging.
+	Logger().Debug("Connecting to MySQL", "host", cfg.DBHost, "port", cfg.DBPort, "db", cfg.DBName, "user", cfg.DBUSER)
 
 	// Open a database connection.
 	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
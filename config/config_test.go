@@ -1,8 +1,11 @@
 package config
 
 import (
+	"context"
 	"os"
 	"testing"
+
+	"gorm.io/gorm"
 )
 
 // Test that LoadConfig returns a non-nil config and respects APPENV=test
@@ -26,3 +29,15 @@ func TestLoadConfigAndConnectMySQL_TestEnv(t *testing.T) {
 	// cleanup environment (t.Setenv will restore automatically in Go 1.17+)
 	_ = os.Unsetenv("APPENV")
 }
+
+func TestNewGormLoggerHidesBoundValues(t *testing.T) {
+	t.Setenv("APPENV", "production")
+	l, ok := newGormLogger().(gorm.ParamsFilter)
+	if !ok {
+		t.Fatalf("expected the GORM logger to filter query params")
+	}
+	_, params := l.ParamsFilter(context.Background(), "SELECT * FROM users WHERE email = ?", "secret@example.com")
+	if len(params) != 0 {
+		t.Errorf("expected bound values to be dropped from SQL logs, got %v", params)
+	}
+}
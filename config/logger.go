@@ -0,0 +1,99 @@
+package config
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"gorm.io/gorm/logger"
+)
+
+// logLevel is shared by every logger built here so SetLogLevel affects
+// loggers created before LOG_LEVEL was read.
+var logLevel = new(slog.LevelVar)
+
+var appLogger atomic.Pointer[slog.Logger]
+
+func init() {
+	appLogger.Store(NewLogger(os.Stdout))
+}
+
+// NewLogger returns a structured key=value logger writing to w that honours
+// the configured log level.
+func NewLogger(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewTextHandler(w, &slog.HandlerOptions{Level: logLevel}))
+}
+
+// Logger returns the application logger.
+func Logger() *slog.Logger {
+	return appLogger.Load()
+}
+
+// SetLogger replaces the application logger and returns the previous one.
+// Intended for tests that capture log output.
+func SetLogger(l *slog.Logger) *slog.Logger {
+	return appLogger.Swap(l)
+}
+
+// LogLevel returns the current log level.
+func LogLevel() slog.Level {
+	return logLevel.Level()
+}
+
+// SetLogLevel changes the level of every application logger.
+func SetLogLevel(level slog.Level) {
+	logLevel.Set(level)
+}
+
+// ParseLogLevel reads a LOG_LEVEL value (debug, info, warn or error, case
+// insensitive). Empty or unknown values fall back to info in production and
+// debug elsewhere; ok is false for unknown values.
+func ParseLogLevel(value, appEnv string) (level slog.Level, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "debug":
+		return slog.LevelDebug, true
+	case "info":
+		return slog.LevelInfo, true
+	case "warn", "warning":
+		return slog.LevelWarn, true
+	case "error":
+		return slog.LevelError, true
+	}
+	if appEnv == "production" {
+		return slog.LevelInfo, value == ""
+	}
+	return slog.LevelDebug, value == ""
+}
+
+// GormLogLevel maps the application log level to GORM's: every SQL statement
+// at debug, slow queries and errors at info and warn, errors only at error.
+func GormLogLevel() logger.LogLevel {
+	switch level := LogLevel(); {
+	case level <= slog.LevelDebug:
+		return logger.Info
+	case level < slog.LevelError:
+		return logger.Warn
+	default:
+		return logger.Error
+	}
+}
+
+// ParseDBLogLevel reads a DB_LOG_LEVEL value (silent, error, warn or info,
+// case insensitive). Empty or unknown values fall back to GormLogLevel, so
+// SQL logging follows LOG_LEVEL unless set explicitly; ok is false for
+// unknown values.
+func ParseDBLogLevel(value string) (level logger.LogLevel, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "silent":
+		return logger.Silent, true
+	case "error":
+		return logger.Error, true
+	case "warn", "warning":
+		return logger.Warn, true
+	case "info":
+		return logger.Info, true
+	}
+	return GormLogLevel(), value == ""
+}
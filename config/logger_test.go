@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm/logger"
+)
+
+// captureLogs routes the application logger to a buffer at the given level
+// and restores both afterwards.
+func captureLogs(t *testing.T, level slog.Level) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	prevLevel := LogLevel()
+	prev := SetLogger(NewLogger(&buf))
+	SetLogLevel(level)
+	t.Cleanup(func() {
+		SetLogger(prev)
+		SetLogLevel(prevLevel)
+	})
+	return &buf
+}
+
+func TestLogger_SuppressesBelowThreshold(t *testing.T) {
+	buf := captureLogs(t, slog.LevelWarn)
+
+	Logger().Debug("debug message")
+	Logger().Info("info message")
+	Logger().Warn("warn message", "key", "value")
+	Logger().Error("error message")
+
+	out := buf.String()
+	for _, hidden := range []string{"debug message", "info message"} {
+		if strings.Contains(out, hidden) {
+			t.Errorf("expected %q to be suppressed, got:\n%s", hidden, out)
+		}
+	}
+	for _, shown := range []string{"level=WARN msg=\"warn message\" key=value", "level=ERROR msg=\"error message\""} {
+		if !strings.Contains(out, shown) {
+			t.Errorf("expected output to contain %q, got:\n%s", shown, out)
+		}
+	}
+}
+
+func TestSetLogLevel_AppliesToExistingLoggers(t *testing.T) {
+	buf := captureLogs(t, slog.LevelError)
+	Logger().Info("before")
+
+	SetLogLevel(slog.LevelDebug)
+	Logger().Debug("after")
+
+	if out := buf.String(); strings.Contains(out, "before") || !strings.Contains(out, "after") {
+		t.Fatalf("expected only the message after lowering the level, got:\n%s", out)
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		value, appEnv string
+		want          slog.Level
+		wantOK        bool
+	}{
+		{"debug", "production", slog.LevelDebug, true},
+		{"INFO", "local", slog.LevelInfo, true},
+		{"warn", "local", slog.LevelWarn, true},
+		{"warning", "local", slog.LevelWarn, true},
+		{" error ", "local", slog.LevelError, true},
+		{"", "production", slog.LevelInfo, true},
+		{"", "local", slog.LevelDebug, true},
+		{"verbose", "production", slog.LevelInfo, false},
+		{"verbose", "local", slog.LevelDebug, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseLogLevel(tt.value, tt.appEnv)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseLogLevel(%q, %q) = %v, %v; want %v, %v", tt.value, tt.appEnv, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestGormLogLevel(t *testing.T) {
+	prev := LogLevel()
+	t.Cleanup(func() { SetLogLevel(prev) })
+
+	tests := []struct {
+		level slog.Level
+		want  logger.LogLevel
+	}{
+		{slog.LevelDebug, logger.Info},
+		{slog.LevelInfo, logger.Warn},
+		{slog.LevelWarn, logger.Warn},
+		{slog.LevelError, logger.Error},
+	}
+	for _, tt := range tests {
+		SetLogLevel(tt.level)
+		if got := GormLogLevel(); got != tt.want {
+			t.Errorf("GormLogLevel() at %v = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestParseDBLogLevel(t *testing.T) {
+	prev := LogLevel()
+	t.Cleanup(func() { SetLogLevel(prev) })
+	SetLogLevel(slog.LevelError)
+
+	tests := []struct {
+		value  string
+		want   logger.LogLevel
+		wantOK bool
+	}{
+		{"silent", logger.Silent, true},
+		{"ERROR", logger.Error, true},
+		{"warn", logger.Warn, true},
+		{"warning", logger.Warn, true},
+		{" info ", logger.Info, true},
+		{"", logger.Error, true},
+		{"verbose", logger.Error, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseDBLogLevel(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseDBLogLevel(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// Without DB_LOG_LEVEL, SQL logging keeps following LOG_LEVEL.
+	SetLogLevel(slog.LevelDebug)
+	if got, _ := ParseDBLogLevel(""); got != logger.Info {
+		t.Errorf("ParseDBLogLevel(\"\") at debug = %v, want %v", got, logger.Info)
+	}
+}
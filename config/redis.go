@@ -3,7 +3,6 @@ package config
 import (
 	"context"
 	"fmt"
-	"log"
 	"os"
 	"strconv"
 	"sync"
@@ -67,7 +66,7 @@ func ConnectRedis() (*redis.Client, error) {
 	}
 
 	redisClient = rdb
-	log.Printf("Connected to Redis at %s", addr)
+	Logger().Info("Connected to Redis", "addr", addr)
 	return redisClient, err
 }
 
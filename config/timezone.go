@@ -0,0 +1,5 @@
+package config
+
+// Timezone is the IANA zone the clinic operates in. The process runs with it
+// as time.Local, and dates without a zone are interpreted in it.
+const Timezone = "Asia/Jakarta"
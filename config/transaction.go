@@ -0,0 +1,59 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTxMaxRetries   = 3
+	defaultTxRetryBackoff = 50 * time.Millisecond
+
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrDeadlock        = 1213
+)
+
+// txRetrySettings reads DB_TX_MAX_RETRIES (extra attempts after the first,
+// default 3) and DB_TX_RETRY_BACKOFF (delay before the first retry, doubled
+// for each further retry, default 50ms).
+func txRetrySettings() (int, time.Duration) {
+	retries := defaultTxMaxRetries
+	if v, err := strconv.Atoi(os.Getenv("DB_TX_MAX_RETRIES")); err == nil && v >= 0 {
+		retries = v
+	}
+	backoff := defaultTxRetryBackoff
+	if v, err := time.ParseDuration(os.Getenv("DB_TX_RETRY_BACKOFF")); err == nil && v >= 0 {
+		backoff = v
+	}
+	return retries, backoff
+}
+
+// IsRetryableTxError reports whether err is a MySQL deadlock or lock wait
+// timeout, after which the whole transaction can safely be run again.
+func IsRetryableTxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	return mysqlErr.Number == mysqlErrDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+}
+
+// WithRetry runs fn in a transaction and runs it again, with exponential
+// backoff, when it fails on a MySQL deadlock or lock wait timeout. fn may run
+// more than once, so it must not keep state from a failed attempt.
+func WithRetry(db *gorm.DB, fn func(tx *gorm.DB) error) error {
+	retries, backoff := txRetrySettings()
+	for attempt := 0; ; attempt++ {
+		err := db.Transaction(fn)
+		if err == nil || attempt >= retries || !IsRetryableTxError(err) {
+			return err
+		}
+		Logger().Warn("Retrying transaction after lock conflict", "attempt", attempt+1, "error", err)
+		time.Sleep(backoff << attempt)
+	}
+}
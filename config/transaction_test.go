@@ -0,0 +1,91 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+type retryRecord struct {
+	ID   uint
+	Name string
+}
+
+func newRetryTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	t.Setenv("DB_TX_RETRY_BACKOFF", "1ms")
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	if err := db.AutoMigrate(&retryRecord{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+func TestWithRetry_RetriesDeadlock(t *testing.T) {
+	db := newRetryTestDB(t)
+
+	attempts := 0
+	err := WithRetry(db, func(tx *gorm.DB) error {
+		attempts++
+		if err := tx.Create(&retryRecord{Name: fmt.Sprintf("attempt %d", attempts)}).Error; err != nil {
+			return err
+		}
+		if attempts == 1 {
+			return fmt.Errorf("create: %w", &mysql.MySQLError{Number: 1213, Message: "Deadlock found when trying to get lock"})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected the retry to succeed, got %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts, got %d", attempts)
+	}
+
+	// The failed attempt was rolled back.
+	var records []retryRecord
+	db.Find(&records)
+	if len(records) != 1 || records[0].Name != "attempt 2" {
+		t.Fatalf("expected only the second attempt to be committed, got %+v", records)
+	}
+}
+
+func TestWithRetry_GivesUpAfterMaxRetries(t *testing.T) {
+	db := newRetryTestDB(t)
+	t.Setenv("DB_TX_MAX_RETRIES", "2")
+
+	lockWait := &mysql.MySQLError{Number: 1205, Message: "Lock wait timeout exceeded"}
+	attempts := 0
+	err := WithRetry(db, func(tx *gorm.DB) error {
+		attempts++
+		return lockWait
+	})
+	if !errors.Is(err, lockWait) {
+		t.Fatalf("expected the lock wait error, got %v", err)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 1 attempt and 2 retries, got %d attempts", attempts)
+	}
+}
+
+func TestWithRetry_DoesNotRetryOtherErrors(t *testing.T) {
+	db := newRetryTestDB(t)
+
+	for _, failure := range []error{errors.New("validation failed"), &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}} {
+		attempts := 0
+		err := WithRetry(db, func(tx *gorm.DB) error {
+			attempts++
+			return failure
+		})
+		if err != failure || attempts != 1 {
+			t.Fatalf("expected %v once, got %v after %d attempts", failure, err, attempts)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+package config
+
+// Build information, overridden at build time via ldflags, e.g.
+//
+//	go build -ldflags "-X github.com/ariebrainware/basis-data-ltt/config.Version=v1.2.3 -X github.com/ariebrainware/basis-data-ltt/config.Commit=$(git rev-parse --short HEAD)"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildDate = "unknown"
+)
@@ -0,0 +1,204 @@
+package endpoint
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultActivityLimit = 20
+	maxActivityLimit     = 100
+	// maxActivityWindow bounds offset+limit, since every source is read up
+	// to that many rows before merging.
+	maxActivityWindow = 1000
+)
+
+// recentTreatmentActivity returns the newest n treatments as feed items.
+func recentTreatmentActivity(db *gorm.DB, n int) ([]model.ActivityItem, error) {
+	var rows []struct {
+		ID            uint
+		CreatedAt     time.Time
+		PatientCode   string
+		PatientName   string
+		TreatmentDate string
+	}
+	err := db.Model(&model.Treatment{}).
+		Select("treatments.id, treatments.created_at, treatments.patient_code, patients.full_name AS patient_name, treatments.treatment_date").
+		Joins("LEFT JOIN patients ON patients.patient_code = treatments.patient_code AND patients.deleted_at IS NULL").
+		Order("treatments.created_at DESC, treatments.id DESC").
+		Limit(n).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.ActivityItem, 0, len(rows))
+	for _, r := range rows {
+		items = append(items, model.ActivityItem{
+			Type:       model.ActivityTreatment,
+			ID:         r.ID,
+			OccurredAt: r.CreatedAt,
+			Summary:    fmt.Sprintf("Treatment recorded for %s (%s) on %s", r.PatientCode, r.PatientName, r.TreatmentDate),
+		})
+	}
+	return items, nil
+}
+
+// recentPatientActivity returns the newest n patients as feed items.
+func recentPatientActivity(db *gorm.DB, n int) ([]model.ActivityItem, error) {
+	var patients []model.Patient
+	if err := db.Select("id, created_at, patient_code, full_name").Order("created_at DESC, id DESC").Limit(n).Find(&patients).Error; err != nil {
+		return nil, err
+	}
+
+	items := make([]model.ActivityItem, 0, len(patients))
+	for _, p := range patients {
+		items = append(items, model.ActivityItem{
+			Type:       model.ActivityPatient,
+			ID:         p.ID,
+			OccurredAt: p.CreatedAt,
+			Summary:    fmt.Sprintf("New patient %s (%s)", p.FullName, p.PatientCode),
+		})
+	}
+	return items, nil
+}
+
+// recentApprovalActivity returns the n most recently approved therapists as
+// feed items. Therapists approved before approved_at was recorded have no
+// approval time and are left out.
+func recentApprovalActivity(db *gorm.DB, n int) ([]model.ActivityItem, error) {
+	var therapists []model.Therapist
+	err := db.Select("id, full_name, approved_at").
+		Where("is_approved = ? AND approved_at IS NOT NULL", true).
+		Order("approved_at DESC, id DESC").
+		Limit(n).
+		Find(&therapists).Error
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]model.ActivityItem, 0, len(therapists))
+	for _, t := range therapists {
+		items = append(items, model.ActivityItem{
+			Type:       model.ActivityTherapistApproval,
+			ID:         t.ID,
+			OccurredAt: *t.ApprovedAt,
+			Summary:    fmt.Sprintf("Therapist %s approved", t.FullName),
+		})
+	}
+	return items, nil
+}
+
+// countActivity returns how many items the feed holds across all sources.
+func countActivity(db *gorm.DB) (int64, error) {
+	var total int64
+	for _, query := range []*gorm.DB{
+		db.Model(&model.Treatment{}),
+		db.Model(&model.Patient{}),
+		db.Model(&model.Therapist{}).Where("is_approved = ? AND approved_at IS NOT NULL", true),
+	} {
+		var n int64
+		if err := query.Count(&n).Error; err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return total, nil
+}
+
+// fetchActivityFeed merges the newest offset+limit items of every source,
+// newest first, and returns the requested page. Each source is already
+// sorted, so reading offset+limit rows from each is enough for any page.
+func fetchActivityFeed(db *gorm.DB, limit, offset int) ([]model.ActivityItem, error) {
+	window := offset + limit
+	var feed []model.ActivityItem
+	for _, source := range []func(*gorm.DB, int) ([]model.ActivityItem, error){
+		recentTreatmentActivity,
+		recentPatientActivity,
+		recentApprovalActivity,
+	} {
+		items, err := source(db, window)
+		if err != nil {
+			return nil, err
+		}
+		feed = append(feed, items...)
+	}
+
+	sort.SliceStable(feed, func(i, j int) bool {
+		if !feed[i].OccurredAt.Equal(feed[j].OccurredAt) {
+			return feed[i].OccurredAt.After(feed[j].OccurredAt)
+		}
+		if feed[i].Type != feed[j].Type {
+			return feed[i].Type < feed[j].Type
+		}
+		return feed[i].ID > feed[j].ID
+	})
+
+	if offset >= len(feed) {
+		return []model.ActivityItem{}, nil
+	}
+	return feed[offset:min(window, len(feed))], nil
+}
+
+// ListActivity godoc
+// @Summary      Activity feed
+// @Description  Recent treatments, new patients and therapist approvals merged into one feed, newest first. Each item has a type of treatment, patient or therapist_approval. limit defaults to 20 (max 100) and offset + limit may not exceed 1000.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        limit query int false "Items per page (default 20, max 100)"
+// @Param        offset query int false "Items to skip"
+// @Success      200 {object} util.APIResponse{data=object} "Activity retrieved"
+// @Failure      400 {object} util.APIResponse "Page out of range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /activity [get]
+func ListActivity(c *gin.Context) {
+	limit := parseQueryInt(c, "limit", defaultActivityLimit)
+	if limit == 0 || limit > maxActivityLimit {
+		limit = maxActivityLimit
+	}
+	offset := parseQueryInt(c, "offset", 0)
+	if offset+limit > maxActivityWindow {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("offset + limit must not exceed %d", maxActivityWindow),
+			Err: fmt.Errorf("activity page out of range: offset %d, limit %d", offset, limit),
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	items, err := fetchActivityFeed(db, limit, offset)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve activity",
+			Err: err,
+		})
+		return
+	}
+	total, err := countActivity(db)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to count activity",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Activity retrieved",
+		Data: util.NewPageResponse(items, total, len(items), util.OffsetHasMore(offset, len(items), total), nil),
+	})
+}
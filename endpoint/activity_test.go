@@ -0,0 +1,96 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type activityPage struct {
+	Items        []model.ActivityItem `json:"items"`
+	Total        int64                `json:"total"`
+	TotalFetched int                  `json:"total_fetched"`
+	HasMore      bool                 `json:"has_more"`
+}
+
+func fetchActivityPage(t *testing.T, r *gin.Engine, query string) activityPage {
+	t.Helper()
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/activity" + query})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data activityPage `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func fetchActivity(t *testing.T, r *gin.Engine, query string) []model.ActivityItem {
+	t.Helper()
+	return fetchActivityPage(t, r, query).Items
+}
+
+func TestListActivity_MergesSourcesInTimeOrder(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/activity", ListActivity)
+
+	base := time.Date(2025, 1, 10, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	patient := model.Patient{FullName: "Feed Patient", PatientCode: "F001", Email: "feed@test.com"}
+	patient.CreatedAt = at(0)
+	assert.NoError(t, db.Create(&patient).Error)
+
+	approvedAt := at(10)
+	approved := model.Therapist{FullName: "Dr. Feed", NIK: "NIK-FEED-1", IsApproved: true, ApprovedAt: &approvedAt}
+	pending := model.Therapist{FullName: "Dr. Pending", NIK: "NIK-FEED-2"}
+	assert.NoError(t, db.Create(&approved).Error)
+	assert.NoError(t, db.Create(&pending).Error)
+
+	first := model.Treatment{PatientCode: "F001", TherapistID: approved.ID, TreatmentDate: "2025-01-10", Issues: "-", Treatment: "-", NextVisit: "-"}
+	first.CreatedAt = at(5)
+	second := first
+	second.TreatmentDate = "2025-01-11"
+	second.CreatedAt = at(20)
+	assert.NoError(t, db.Create(&first).Error)
+	assert.NoError(t, db.Create(&second).Error)
+
+	items := fetchActivity(t, r, "")
+	var got []string
+	for _, item := range items {
+		got = append(got, item.Type+":"+item.OccurredAt.UTC().Format("15:04"))
+	}
+	assert.Equal(t, []string{"treatment:09:20", "therapist_approval:09:10", "treatment:09:05", "patient:09:00"}, got)
+	assert.Equal(t, second.ID, items[0].ID)
+	assert.Equal(t, "Treatment recorded for F001 (Feed Patient) on 2025-01-11", items[0].Summary)
+	assert.Equal(t, "Therapist Dr. Feed approved", items[1].Summary)
+	assert.Equal(t, patient.ID, items[3].ID)
+
+	// Pages are cut from the merged feed, not from each source.
+	page := fetchActivityPage(t, r, "?limit=2&offset=1")
+	if assert.Len(t, page.Items, 2) {
+		assert.Equal(t, model.ActivityTherapistApproval, page.Items[0].Type)
+		assert.Equal(t, first.ID, page.Items[1].ID)
+	}
+	assert.Equal(t, activityPage{Items: page.Items, Total: 4, TotalFetched: 2, HasMore: true}, page)
+	assert.False(t, fetchActivityPage(t, r, "?limit=2&offset=2").HasMore)
+	assert.Empty(t, fetchActivity(t, r, "?offset=10"))
+}
+
+func TestListActivity_RejectsDeepPages(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/activity", requestPath: "/activity?offset=990&limit=20", handler: ListActivity})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
+
+func TestApprovalColumns(t *testing.T) {
+	now := time.Now()
+	assert.Equal(t, map[string]interface{}{"is_approved": true, "approved_at": now}, approvalColumns(true, now))
+	assert.Equal(t, map[string]interface{}{"is_approved": false, "approved_at": nil}, approvalColumns(false, now))
+}
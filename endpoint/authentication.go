@@ -2,7 +2,11 @@ package endpoint
 
 import (
 	"context"
+	"crypto/subtle"
 	"fmt"
+	"math"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -36,6 +40,8 @@ type LoginResponse struct {
 // @Param        request body LoginRequest true "Login credentials"
 // @Success      200 {object} util.APIResponse{data=LoginResponse} "Login successful"
 // @Failure      400 {object} util.APIResponse "Invalid request payload"
+// @Failure      403 {object} util.APIResponse "Email address not verified"
+// @Failure      429 {object} util.APIResponse "Retry too soon after a failed attempt; see the Retry-After header"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /login [post]
 func Login(c *gin.Context) {
@@ -62,6 +68,11 @@ func Login(c *gin.Context) {
 	ci := clientInfo{IP: c.ClientIP(), Agent: c.Request.UserAgent()}
 	ctx := loginContext{C: c, DB: db, Email: req.Email, CI: ci}
 
+	// Reject retries that arrive before the per-account backoff has elapsed
+	if !ensureLoginBackoffElapsed(ctx) {
+		return
+	}
+
 	// Load user
 	user, ok := loadUserForLogin(ctx)
 	if !ok {
@@ -78,6 +89,11 @@ func Login(c *gin.Context) {
 		return
 	}
 
+	// Refuse signups that have not verified their email yet
+	if !ensureEmailVerified(ctx, &user) {
+		return
+	}
+
 	if !finalizeLogin(ctx, &user, req.Password) {
 		return
 	}
@@ -116,6 +132,7 @@ func getDBOrRespond(c *gin.Context) (*gorm.DB, bool) {
 func loadUserForLogin(ctx loginContext) (model.User, bool) {
 	user, err := loadUserByEmail(ctx.DB, ctx.Email)
 	if err == gorm.ErrRecordNotFound {
+		util.RecordLoginBackoffFailure(ctx.Email)
 		util.LogLoginFailure(util.LoginParams{Email: ctx.Email, IP: ctx.CI.IP, UserAgent: ctx.CI.Agent, Reason: "user not found"})
 		util.CallUserError(ctx.C, util.APIErrorParams{Msg: "Invalid email or password", Err: fmt.Errorf("user not found")})
 		return model.User{}, false
@@ -128,6 +145,22 @@ func loadUserForLogin(ctx loginContext) (model.User, bool) {
 	return user, true
 }
 
+func ensureLoginBackoffElapsed(ctx loginContext) bool {
+	remaining := util.LoginBackoffRemaining(ctx.Email)
+	if remaining <= 0 {
+		return true
+	}
+	retryAfter := int64(math.Ceil(remaining.Seconds()))
+	util.LogLoginBackoff(util.RateLimitParams{Email: ctx.Email, IP: ctx.CI.IP, Endpoint: ctx.C.Request.URL.Path})
+	ctx.C.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+	util.CallTooManyRequests(ctx.C, util.APIErrorParams{
+		Msg:  fmt.Sprintf("Too many failed login attempts. Try again in %d seconds", retryAfter),
+		Err:  fmt.Errorf("login backoff in effect"),
+		Data: map[string]interface{}{"retry_after": retryAfter},
+	})
+	return false
+}
+
 func ensureAccountNotLocked(ctx loginContext, user *model.User) bool {
 	if locked, expiry := isAccountLocked(user); locked {
 		util.LogLoginFailure(util.LoginParams{Email: ctx.Email, IP: ctx.CI.IP, UserAgent: ctx.CI.Agent, Reason: "account locked"})
@@ -146,6 +179,7 @@ func verifyPasswordOrRespond(ctx loginContext, user *model.User, plain string) b
 	}
 	if !match {
 		incrementFailedAttempts(ctx.DB, user, ctx.CI)
+		util.RecordLoginBackoffFailure(ctx.Email)
 		util.LogLoginFailure(util.LoginParams{Email: ctx.Email, IP: ctx.CI.IP, UserAgent: ctx.CI.Agent, Reason: "invalid password"})
 		util.CallUserError(ctx.C, util.APIErrorParams{Msg: "Invalid email or password", Err: fmt.Errorf("invalid password")})
 		return false
@@ -192,6 +226,7 @@ func finalizeLogin(ctx loginContext, user *model.User, plain string) bool {
 	if err := resetFailedAttempts(ctx.DB, user); err != nil {
 		util.LogSecurityEvent(util.SecurityEvent{EventType: util.EventSuspiciousActivity, UserID: fmt.Sprintf("%d", user.ID), Email: user.Email, IP: ctx.CI.IP, Message: fmt.Sprintf("Failed to reset failed attempts: %v", err)})
 	}
+	util.ResetLoginBackoff(ctx.Email)
 
 	// Upgrade legacy password if needed (best-effort)
 	_ = upgradeLegacyPasswordIfNeeded(ctx.DB, user, plain, ctx.CI)
@@ -264,11 +299,37 @@ func createUserOrRespond(c *gin.Context, db *gorm.DB, user *model.User) bool {
 	return true
 }
 
-func createSignupTokenOrRespond(c *gin.Context, email string, roleID uint32) (string, bool) {
+// createSignupUserOrRespond creates user and, when SIGNUP_EMAIL_VERIFICATION
+// is enabled, its email verification in the same transaction. It returns the
+// verification token, empty when verification is not required.
+func createSignupUserOrRespond(c *gin.Context, db *gorm.DB, user *model.User) (string, bool) {
+	if !emailVerificationRequired() {
+		return "", createUserOrRespond(c, db, user)
+	}
+
+	var token string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		var err error
+		token, err = createEmailVerification(tx, *user, time.Now())
+		return err
+	})
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to create new user", Err: err})
+		return "", false
+	}
+	return token, true
+}
+
+func createSignupTokenOrRespond(c *gin.Context, user model.User) (string, bool) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"email":   email,
+		"sub":     strconv.FormatUint(uint64(user.ID), 10),
+		"iss":     jwtIssuer(),
+		"email":   user.Email,
 		"exp":     time.Now().Add(time.Hour * 1).Unix(),
-		"role_id": roleID,
+		"role_id": user.RoleID,
 	})
 
 	tokenString, err := token.SignedString(util.GetJWTSecretByte())
@@ -342,10 +403,25 @@ func fetchRole(db *gorm.DB, roleID uint32) (model.Role, error) {
 }
 
 func createJWTToken(user model.User) (string, error) {
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": user.Email, "exp": time.Now().Add(time.Hour * 1).Unix(), "role": user.RoleID})
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   strconv.FormatUint(uint64(user.ID), 10),
+		"iss":   jwtIssuer(),
+		"email": user.Email,
+		"exp":   time.Now().Add(time.Hour * 1).Unix(),
+		"role":  user.RoleID,
+	})
 	return token.SignedString(util.GetJWTSecretByte())
 }
 
+// jwtIssuer is the "iss" claim of minted tokens: APPNAME, or the module name
+// when it is unset.
+func jwtIssuer() string {
+	if name := config.LoadConfig().AppName; name != "" {
+		return name
+	}
+	return "basis-data-ltt"
+}
+
 // SessionInfo groups parameters for creating a session to avoid long argument lists.
 type SessionInfo struct {
 	UserID  uint
@@ -432,15 +508,48 @@ func Logout(c *gin.Context) {
 	})
 }
 
+// signupRoleID returns the role given to new signups: the role named or
+// numbered by DEFAULT_SIGNUP_ROLE, or Admin when it is unset.
+func signupRoleID(db *gorm.DB) (uint32, error) {
+	value := strings.TrimSpace(os.Getenv("DEFAULT_SIGNUP_ROLE"))
+	if value == "" {
+		return model.RoleAdmin, nil
+	}
+	role, err := model.FindRole(db, value)
+	if err != nil {
+		return 0, fmt.Errorf("DEFAULT_SIGNUP_ROLE %q: %w", value, err)
+	}
+	return uint32(role.ID), nil
+}
+
+// ValidateSignupRole checks at startup that DEFAULT_SIGNUP_ROLE names an
+// existing role, so a typo fails fast instead of on the first signup.
+func ValidateSignupRole(db *gorm.DB) error {
+	_, err := signupRoleID(db)
+	return err
+}
+
+// signupInviteCodeAccepted reports whether code matches SIGNUP_INVITE_CODE.
+// Signups are open, and any code is accepted, when it is unset.
+func signupInviteCodeAccepted(code string) bool {
+	required := strings.TrimSpace(os.Getenv("SIGNUP_INVITE_CODE"))
+	if required == "" {
+		return true
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(code)), []byte(required)) == 1
+}
+
 type SignupRequest struct {
 	Name     string `json:"name" binding:"required" example:"John Doe"`
 	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
 	Password string `json:"password" binding:"required,min=8" example:"password123"`
+	// InviteCode must match SIGNUP_INVITE_CODE when that is set.
+	InviteCode string `json:"invite_code,omitempty" example:"welcome-2025"`
 }
 
 // Signup godoc
 // @Summary      User signup
-// @Description  Register a new user account
+// @Description  Register a new user account. When SIGNUP_INVITE_CODE is set, invite_code must match it. When SIGNUP_EMAIL_VERIFICATION is enabled, a verification token is sent and login is refused until it is confirmed with GET /verify-email.
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
@@ -448,6 +557,7 @@ type SignupRequest struct {
 // @Param        request body SignupRequest true "Signup details"
 // @Success      200 {object} util.APIResponse{data=string} "Signup successful"
 // @Failure      400 {object} util.APIResponse "Invalid request or email already exists"
+// @Failure      401 {object} util.APIResponse "Missing or invalid invite code"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /signup [post]
 func Signup(c *gin.Context) {
@@ -457,6 +567,14 @@ func Signup(c *gin.Context) {
 		return
 	}
 
+	if !signupInviteCodeAccepted(req.InviteCode) {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{
+			Msg: "Invalid invite code",
+			Err: fmt.Errorf("signup requires a valid invite code"),
+		})
+		return
+	}
+
 	db, ok := getDBOrRespond(c)
 	if !ok {
 		return
@@ -466,6 +584,12 @@ func Signup(c *gin.Context) {
 		return
 	}
 
+	roleID, err := signupRoleID(db)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Signup role is misconfigured", Err: err})
+		return
+	}
+
 	hashedPassword, salt, ok := hashPasswordForSignup(c, req.Password)
 	if !ok {
 		return
@@ -476,13 +600,15 @@ func Signup(c *gin.Context) {
 		Email:          req.Email,
 		Password:       hashedPassword,
 		PasswordSalt:   salt,
-		RoleID:         1,
+		RoleID:         roleID,
 		FailedAttempts: 0,
 		LockedUntil:    nil,
 	}
 
-	// Insert the new user into the database.
-	if !createUserOrRespond(c, db, &newUser) {
+	// Insert the new user into the database, with a verification token when
+	// the email must be verified before login.
+	verificationToken, ok := createSignupUserOrRespond(c, db, &newUser)
+	if !ok {
 		return
 	}
 
@@ -497,13 +623,21 @@ func Signup(c *gin.Context) {
 	})
 
 	// Generate a JWT token upon successful signup.
-	tokenString, ok := createSignupTokenOrRespond(c, req.Email, newUser.RoleID)
+	tokenString, ok := createSignupTokenOrRespond(c, newUser)
 	if !ok {
 		return
 	}
 
+	msg := "Signup successful"
+	if verificationToken != "" {
+		if err := sendVerificationEmail(newUser, verificationToken); err != nil {
+			config.Logger().Error("Failed to send verification email", "user_id", newUser.ID, "error", err)
+		}
+		msg = "Signup successful; verify your email address before logging in"
+	}
+
 	util.CallSuccessOK(c, util.APISuccessParams{
-		Msg:  "Signup successful",
+		Msg:  msg,
 		Data: tokenString,
 	})
 }
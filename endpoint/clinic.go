@@ -0,0 +1,130 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// transferPatient moves the patient and all of their treatments to clinicID in
+// one transaction and reports how many treatments were moved.
+func transferPatient(db *gorm.DB, patient model.Patient, clinicID uint) (model.PatientTransferResult, error) {
+	result := model.PatientTransferResult{
+		PatientID:    patient.ID,
+		PatientCode:  patient.PatientCode,
+		FromClinicID: patient.ClinicID,
+		ToClinicID:   clinicID,
+	}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Patient{}).Where("id = ?", patient.ID).Update("clinic_id", clinicID).Error; err != nil {
+			return err
+		}
+		if patient.PatientCode == "" {
+			return nil
+		}
+		moved := tx.Model(&model.Treatment{}).Where("patient_code = ?", patient.PatientCode).Update("clinic_id", clinicID)
+		if moved.Error != nil {
+			return moved.Error
+		}
+		result.TreatmentsMoved = moved.RowsAffected
+		return nil
+	})
+	return result, err
+}
+
+// clinicExists reports whether clinicID is a branch in use. There is no
+// clinics table, so a clinic exists once a user is assigned to it or its
+// opening hours are configured; a patient moved anywhere else would be
+// visible to no clinic-bound user.
+func clinicExists(db *gorm.DB, clinicID uint) (bool, error) {
+	if clinicID == 0 {
+		return false, nil
+	}
+	var users int64
+	if err := db.Model(&model.User{}).Where("clinic_id = ?", clinicID).Count(&users).Error; err != nil {
+		return false, err
+	}
+	if users > 0 {
+		return true, nil
+	}
+	var hours int64
+	err := db.Model(&model.ClinicHours{}).Where("clinic_id = ?", clinicID).Count(&hours).Error
+	return hours > 0, err
+}
+
+// TransferPatient godoc
+// @Summary      Transfer a patient to another clinic
+// @Description  Move a patient and all of their treatments to another clinic (branch). The target clinic must have a user assigned to it or configured opening hours. Admins assigned to a clinic can only transfer their own clinic's patients unless they send X-Clinic-Scope: global.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Patient ID"
+// @Param        request body model.TransferPatientRequest true "Target clinic"
+// @Success      200 {object} util.APIResponse{data=model.PatientTransferResult} "Patient transferred"
+// @Failure      400 {object} util.APIResponse "Invalid request, unknown clinic or patient not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{id}/transfer [post]
+func TransferPatient(c *gin.Context) {
+	var req model.TransferPatientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, patient, err := getPatientByID(c, db)
+	if err != nil {
+		return
+	}
+	if patient.ClinicID == req.ClinicID {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Patient already belongs to this clinic",
+			Err: errors.New("source and target clinic are the same"),
+		})
+		return
+	}
+
+	exists, err := clinicExists(db, req.ClinicID)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to look up clinic",
+			Err: err,
+		})
+		return
+	}
+	if !exists {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Clinic not found",
+			Err: fmt.Errorf("clinic %d has no users or opening hours", req.ClinicID),
+		})
+		return
+	}
+
+	result, err := transferPatient(db, patient, req.ClinicID)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to transfer patient",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Patient transferred",
+		Data: result,
+	})
+}
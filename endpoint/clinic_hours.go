@@ -0,0 +1,127 @@
+package endpoint
+
+import (
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// listClinicHours returns a clinic's opening hours ordered Sunday to Saturday.
+func listClinicHours(db *gorm.DB, clinicID uint) ([]model.ClinicHours, error) {
+	hours := []model.ClinicHours{}
+	err := db.Where("clinic_id = ?", clinicID).Order("day_of_week ASC").Find(&hours).Error
+	return hours, err
+}
+
+// replaceClinicHours swaps a clinic's weekly hours for entries in one
+// transaction.
+func replaceClinicHours(db *gorm.DB, clinicID uint, entries []model.ClinicHoursEntry) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("clinic_id = ?", clinicID).Delete(&model.ClinicHours{}).Error; err != nil {
+			return err
+		}
+		for _, e := range entries {
+			hours := model.ClinicHours{ClinicID: clinicID, DayOfWeek: *e.DayOfWeek, Open: e.Open, Close: e.Close}
+			if err := tx.Create(&hours).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListClinicHours godoc
+// @Summary      List clinic opening hours
+// @Description  Get the opening hours of the caller's clinic, one entry per open day (0 = Sunday). Days without an entry are closed; an empty list means no hours are configured.
+// @Tags         Clinic
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=[]model.ClinicHours} "Clinic hours retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /clinic-hours [get]
+func ListClinicHours(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	clinicID, _ := middleware.GetClinicID(c)
+	hours, err := listClinicHours(db, clinicID)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve clinic hours",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Clinic hours retrieved",
+		Data: hours,
+	})
+}
+
+// SetClinicHours godoc
+// @Summary      Set clinic opening hours
+// @Description  Replace the weekly opening hours of the caller's clinic. Each day (0 = Sunday to 6 = Saturday) may appear once with HH:MM open and close times; days not listed are closed.
+// @Tags         Clinic
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        request body model.SetClinicHoursRequest true "Weekly opening hours"
+// @Success      200 {object} util.APIResponse{data=[]model.ClinicHours} "Clinic hours updated"
+// @Failure      400 {object} util.APIResponse "Invalid request"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /clinic-hours [put]
+func SetClinicHours(c *gin.Context) {
+	var req model.SetClinicHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+	if err := model.ValidateClinicHours(req.Hours); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid clinic hours",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	clinicID, _ := middleware.GetClinicID(c)
+	if err := replaceClinicHours(db, clinicID, req.Hours); err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to update clinic hours",
+			Err: err,
+		})
+		return
+	}
+
+	hours, err := listClinicHours(db, clinicID)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve clinic hours",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Clinic hours updated",
+		Data: hours,
+	})
+}
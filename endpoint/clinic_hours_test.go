@@ -0,0 +1,79 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func getClinicHours(t *testing.T, r *gin.Engine) []model.ClinicHours {
+	t.Helper()
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/clinic-hours"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data []model.ClinicHours `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestClinicHours_SetAndList(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	r := clinicRouter(t, db, 1)
+
+	assert.Empty(t, getClinicHours(t, r))
+
+	week := map[string]interface{}{"hours": []map[string]interface{}{
+		{"day_of_week": 6, "open": "09:00", "close": "13:00"},
+		{"day_of_week": 1, "open": "08:00", "close": "17:00"},
+	}}
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPut, requestPath: "/clinic-hours", body: week})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	hours := getClinicHours(t, r)
+	if assert.Len(t, hours, 2) {
+		assert.Equal(t, 1, hours[0].DayOfWeek)
+		assert.Equal(t, "08:00", hours[0].Open)
+		assert.Equal(t, "17:00", hours[0].Close)
+		assert.Equal(t, uint(1), hours[0].ClinicID)
+		assert.Equal(t, 6, hours[1].DayOfWeek)
+	}
+
+	// Setting again replaces the week: Saturday is now closed. Unpadded
+	// times are stored as HH:MM.
+	week = map[string]interface{}{"hours": []map[string]interface{}{
+		{"day_of_week": 1, "open": "8:00", "close": "18:00"},
+	}}
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPut, requestPath: "/clinic-hours", body: week})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	hours = getClinicHours(t, r)
+	if assert.Len(t, hours, 1) {
+		assert.Equal(t, "08:00", hours[0].Open)
+	}
+
+	// Other clinics keep their own hours.
+	assert.Empty(t, getClinicHours(t, clinicRouter(t, db, 2)))
+}
+
+func TestClinicHours_RejectsInvalidHours(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	r := clinicRouter(t, db, 1)
+
+	for _, entry := range []map[string]interface{}{
+		{"day_of_week": 7, "open": "08:00", "close": "17:00"},
+		{"day_of_week": 1, "open": "8am", "close": "17:00"},
+		{"day_of_week": 1, "open": "17:00", "close": "08:00"},
+		{"open": "08:00", "close": "17:00"},
+	} {
+		body := map[string]interface{}{"hours": []map[string]interface{}{entry}}
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPut, requestPath: "/clinic-hours", body: body})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+	assert.Empty(t, getClinicHours(t, r))
+}
@@ -0,0 +1,273 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// clinicRouter returns an engine acting as an admin of the given clinic with TenantScope applied.
+func clinicRouter(t *testing.T, db *gorm.DB, clinicID uint) *gin.Engine {
+	t.Helper()
+	return clinicRouterWithRole(t, db, clinicID, model.RoleAdmin)
+}
+
+func clinicRouterWithRole(t *testing.T, db *gorm.DB, clinicID uint, roleID uint32) *gin.Engine {
+	t.Helper()
+	user := model.User{
+		Name:         "Clinic User",
+		Email:        fmt.Sprintf("clinic-%d-%d-%d@test.com", clinicID, roleID, time.Now().UnixNano()),
+		Password:     "x",
+		PasswordSalt: "x",
+		RoleID:       roleID,
+		ClinicID:     clinicID,
+	}
+	assert.NoError(t, db.Create(&user).Error)
+
+	r := gin.New()
+	r.Use(middleware.DatabaseMiddleware(db), withAuthContext(user.ID, roleID), middleware.TenantScope())
+	r.GET("/patient", ListPatients)
+	r.GET("/patient/duplicates", ListDuplicatePatients)
+	r.GET("/patient/:id", GetPatientInfo)
+	r.POST("/patient/:id/transfer", TransferPatient)
+	r.GET("/treatment", ListTreatments)
+	r.GET("/clinic-hours", ListClinicHours)
+	r.PUT("/clinic-hours", SetClinicHours)
+	return r
+}
+
+func seedClinicPatients(t *testing.T, db *gorm.DB) map[string]model.Patient {
+	t.Helper()
+	patients := map[string]model.Patient{}
+	for code, clinic := range map[string]uint{"C1P": 1, "C2P": 2, "C0P": 0} {
+		p := model.Patient{FullName: "Patient " + code, PatientCode: code, ClinicID: clinic}
+		assert.NoError(t, db.Create(&p).Error)
+		patients[code] = p
+	}
+	therapist := model.Therapist{FullName: "Clinic Therapist", NIK: "NIK-CLINIC"}
+	assert.NoError(t, db.Create(&therapist).Error)
+	for _, tr := range []struct {
+		code   string
+		date   string
+		clinic uint
+	}{
+		{"C1P", "2025-01-01", 1},
+		{"C1P", "2025-01-08", 1},
+		{"C2P", "2025-01-02", 2},
+	} {
+		treatment := model.Treatment{PatientCode: tr.code, TherapistID: therapist.ID, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", ClinicID: tr.clinic}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+	return patients
+}
+
+type clinicListResponse struct {
+	Data struct {
+		Total      int64                          `json:"total"`
+		Patients   []model.Patient                `json:"patients"`
+		Treatments []model.ListTreatementResponse `json:"treatments"`
+	} `json:"data"`
+}
+
+func listAs(t *testing.T, r *gin.Engine, path string, headers ...map[string]string) clinicListResponse {
+	t.Helper()
+	spec := requestSpec{method: http.MethodGet, requestPath: path}
+	if len(headers) > 0 {
+		spec.headers = headers[0]
+	}
+	w, _, err := performRequest(r, spec)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp clinicListResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestTenantScope_ListsOnlyCallersClinic(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	patients := seedClinicPatients(t, db)
+
+	clinic1 := clinicRouter(t, db, 1)
+	resp := listAs(t, clinic1, "/patient")
+	assert.Equal(t, int64(1), resp.Data.Total)
+	if assert.Len(t, resp.Data.Patients, 1) {
+		assert.Equal(t, "C1P", resp.Data.Patients[0].PatientCode)
+	}
+	resp = listAs(t, clinic1, "/treatment")
+	assert.Equal(t, int64(2), resp.Data.Total)
+	for _, tr := range resp.Data.Treatments {
+		assert.Equal(t, "C1P", tr.PatientCode)
+	}
+
+	w, _, err := performRequest(clinic1, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/patient/%d", patients["C2P"].ID)})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code, "another clinic's patient must not be readable")
+
+	// Users without a clinic keep the single-branch view.
+	global := clinicRouter(t, db, 0)
+	assert.Equal(t, int64(3), listAs(t, global, "/patient").Data.Total)
+	assert.Equal(t, int64(3), listAs(t, global, "/treatment").Data.Total)
+}
+
+func TestTransferPatient_MovesPatientAndTreatments(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	patients := seedClinicPatients(t, db)
+	source := patients["C1P"]
+
+	r := clinicRouter(t, db, 0)
+	clinic2 := clinicRouter(t, db, 2)
+	path := fmt.Sprintf("/patient/%d/transfer", source.ID)
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: map[string]interface{}{"clinic_id": 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data model.PatientTransferResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.PatientTransferResult{PatientID: source.ID, PatientCode: "C1P", FromClinicID: 1, ToClinicID: 2, TreatmentsMoved: 2}, resp.Data)
+
+	var moved model.Patient
+	assert.NoError(t, db.First(&moved, source.ID).Error)
+	assert.Equal(t, uint(2), moved.ClinicID)
+	var remaining int64
+	db.Model(&model.Treatment{}).Where("clinic_id = ?", 1).Count(&remaining)
+	assert.Zero(t, remaining)
+
+	assert.Equal(t, int64(2), listAs(t, clinic2, "/patient").Data.Total)
+	assert.Equal(t, int64(3), listAs(t, clinic2, "/treatment").Data.Total)
+
+	// Transferring to the current clinic is rejected.
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: map[string]interface{}{"clinic_id": 2}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// A clinic-bound admin cannot transfer another clinic's patient.
+	clinic1 := clinicRouter(t, db, 1)
+	w, _, err = performRequest(clinic1, requestSpec{method: http.MethodPost, requestPath: path, body: map[string]interface{}{"clinic_id": 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.NoError(t, db.First(&moved, source.ID).Error)
+	assert.Equal(t, uint(2), moved.ClinicID)
+}
+
+func TestTransferPatient_RejectsUnknownClinic(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	patients := seedClinicPatients(t, db)
+	source := patients["C1P"]
+	r := clinicRouter(t, db, 0)
+	path := fmt.Sprintf("/patient/%d/transfer", source.ID)
+
+	for name, clinicID := range map[string]uint{"unknown": 99, "zero": 0} {
+		t.Run(name, func(t *testing.T) {
+			w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: map[string]interface{}{"clinic_id": clinicID}})
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+		})
+	}
+	var unchanged model.Patient
+	assert.NoError(t, db.First(&unchanged, source.ID).Error)
+	assert.Equal(t, uint(1), unchanged.ClinicID)
+
+	// Configuring opening hours is enough to make a new branch a target.
+	assert.NoError(t, db.Create(&model.ClinicHours{ClinicID: 99, DayOfWeek: 1, Open: "08:00", Close: "17:00"}).Error)
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: map[string]interface{}{"clinic_id": 99}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestTenantScope_HidesOtherClinicsPatientsEverywhere(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	// Same name and phone in two clinics would be reported as duplicates globally.
+	for _, clinic := range []uint{1, 2} {
+		p := model.Patient{FullName: "Shared Name", PatientCode: fmt.Sprintf("S%d", clinic), PhoneNumber: "0811111111", ClinicID: clinic}
+		assert.NoError(t, db.Create(&p).Error)
+	}
+
+	clinic1 := clinicRouter(t, db, 1)
+	w, _, err := performRequest(clinic1, requestSpec{method: http.MethodGet, requestPath: "/patient/duplicates"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var dup struct {
+		Data struct {
+			Total int `json:"total"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &dup))
+	assert.Zero(t, dup.Data.Total, "clinic 1 must not see clinic 2's patient as a duplicate")
+
+	global := clinicRouter(t, db, 0)
+	w, _, err = performRequest(global, requestSpec{method: http.MethodGet, requestPath: "/patient/duplicates"})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &dup))
+	assert.Equal(t, 1, dup.Data.Total)
+}
+
+func TestTenantScope_GlobalViewHeader(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	seedClinicPatients(t, db)
+	globalHeader := map[string]string{middleware.ClinicScopeHeader: "global"}
+
+	admin := clinicRouter(t, db, 1)
+	assert.Equal(t, int64(1), listAs(t, admin, "/patient").Data.Total)
+	assert.Equal(t, int64(3), listAs(t, admin, "/patient", globalHeader).Data.Total)
+	assert.Equal(t, int64(3), listAs(t, admin, "/treatment", globalHeader).Data.Total)
+
+	// Only admins may opt out of clinic scoping.
+	therapist := clinicRouterWithRole(t, db, 1, model.RoleTherapist)
+	assert.Equal(t, int64(2), listAs(t, therapist, "/treatment", globalHeader).Data.Total)
+}
+
+func TestTenantScope_TransactionsAndTherapistExport(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	seedClinicPatients(t, db)
+	var treatments []model.Treatment
+	assert.NoError(t, db.Order("id").Find(&treatments).Error)
+	var clinic2Transaction model.Transaction
+	for _, tr := range treatments {
+		transaction := model.Transaction{TreatmentID: tr.ID, TherapistID: tr.TherapistID, Amount: int64(tr.ClinicID) * 1000, PaymentStatus: "paid"}
+		assert.NoError(t, db.Create(&transaction).Error)
+		if tr.ClinicID == 2 {
+			clinic2Transaction = transaction
+		}
+	}
+
+	r := clinicRouter(t, db, 1)
+	r.GET("/transaction", ListTransactions)
+	r.GET("/transaction/:id", GetTransactionInfo)
+	r.GET("/therapist/export", ExportTherapists)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/transaction"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data model.ListTransactionsResponseData `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Data.Transactions, 2) {
+		for _, tr := range resp.Data.Transactions {
+			assert.Equal(t, "Patient C1P", tr.PatientName)
+		}
+	}
+	assert.Equal(t, int64(2000), resp.Data.Summary.TotalAmount)
+	assert.Equal(t, int64(2), resp.Data.Summary.PaymentStatusCounts.Paid)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/transaction/%d", clinic2Transaction.ID)})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// The export lists every therapist but counts only the clinic's treatments.
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/therapist/export", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "Clinic Therapist,NIK-CLINIC,,false,2\n")
+}
@@ -1,6 +1,7 @@
 package endpoint
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -98,9 +99,32 @@ func getIDParam(c *gin.Context) (string, bool) {
 		})
 		return "", false
 	}
+	if parsed, err := strconv.ParseUint(id, 10, 0); err != nil || parsed == 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid disease ID",
+			Err: fmt.Errorf("disease ID must be a positive integer"),
+		})
+		return "", false
+	}
 	return id, true
 }
 
+// helper: respond to a failed disease lookup with 404 when the record does
+// not exist and 500 for any other database error
+func respondDiseaseLookupError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.CallErrorNotFound(c, util.APIErrorParams{
+			Msg: "Disease not found",
+			Err: err,
+		})
+		return
+	}
+	util.CallServerError(c, util.APIErrorParams{
+		Msg: "Failed to retrieve disease",
+		Err: err,
+	})
+}
+
 // helper: fetch disease by id
 func fetchDiseaseByID(db *gorm.DB, id string) (model.Disease, error) {
 	var d model.Disease
@@ -153,86 +177,69 @@ func validateRequiredFields(c *gin.Context, name, codename string) bool {
 	return true
 }
 
-// checkDuplicateDisease checks if a disease with the given name or codename already exists
-func checkDuplicateDisease(c *gin.Context, db *gorm.DB, name, codename string) bool {
-	exists, err := diseaseExists(db, "LOWER(name) = ?", strings.ToLower(name))
-	if err != nil {
-		util.CallServerError(c, util.APIErrorParams{
-			Msg: "Failed to check existing diseases",
-			Err: err,
-		})
-		return false
+// findDuplicateDisease reports which column, "name" or "codename", another
+// disease than excludeID already uses, compared case-insensitively. Empty
+// values are not checked; it returns "" when there is no duplicate.
+func findDuplicateDisease(db *gorm.DB, name, codename string, excludeID uint) (string, error) {
+	if name != "" {
+		exists, err := diseaseExists(db, "LOWER(name) = ? AND id != ?", strings.ToLower(name), excludeID)
+		if err != nil || exists {
+			return "name", err
+		}
 	}
-	if exists {
-		util.CallUserError(c, util.APIErrorParams{
-			Msg: "Disease with similar name already exists",
-			Err: fmt.Errorf("disease already exists"),
-		})
-		return false
+	if codename != "" {
+		exists, err := diseaseExists(db, "LOWER(codename) = ? AND id != ?", strings.ToLower(codename), excludeID)
+		if err != nil || exists {
+			return "codename", err
+		}
 	}
+	return "", nil
+}
 
-	exists, err = diseaseExists(db, "LOWER(codename) = ?", strings.ToLower(codename))
+// respondDuplicateDisease runs findDuplicateDisease and responds when the
+// check fails or finds a duplicate. It returns false in that case.
+func respondDuplicateDisease(c *gin.Context, db *gorm.DB, name, codename string, excludeID uint) bool {
+	column, err := findDuplicateDisease(db, name, codename, excludeID)
 	if err != nil {
 		util.CallServerError(c, util.APIErrorParams{
-			Msg: "Failed to check existing codenames",
+			Msg: "Failed to check existing diseases",
 			Err: err,
 		})
 		return false
 	}
-	if exists {
-		util.CallUserError(c, util.APIErrorParams{
-			Msg: "Disease with this codename already exists",
-			Err: fmt.Errorf("codename already exists"),
-		})
+	switch column {
+	case "name":
+		util.CallUserError(c, util.APIErrorParams{Msg: "Disease with similar name already exists", Err: fmt.Errorf("disease already exists")})
+		return false
+	case "codename":
+		util.CallUserError(c, util.APIErrorParams{Msg: "Disease with this codename already exists", Err: fmt.Errorf("codename already exists")})
 		return false
 	}
-
 	return true
 }
 
+// checkDuplicateDisease checks if a disease with the given name or codename already exists
+func checkDuplicateDisease(c *gin.Context, db *gorm.DB, name, codename string) bool {
+	return respondDuplicateDisease(c, db, name, codename, 0)
+}
+
 // checkDuplicateDiseaseExcluding checks if a disease with the given name or codename already exists, excluding a specific disease ID
 func checkDuplicateDiseaseExcluding(c *gin.Context, db *gorm.DB, req createDiseaseRequest, excludeID uint) bool {
-	// Helper to check a single column for duplicates while excluding an ID.
-	check := func(column, value, userMsg string) bool {
-		if value == "" {
-			return true
-		}
-
-		// Whitelist allowed columns to prevent SQL injection via column interpolation.
-		var condition string
-		switch column {
-		case "name":
-			condition = "LOWER(name) = ? AND id != ?"
-		case "codename":
-			condition = "LOWER(codename) = ? AND id != ?"
-		default:
-			util.CallServerError(c, util.APIErrorParams{
-				Msg: "Invalid column for duplicate check",
-				Err: fmt.Errorf("unsupported column: %s", column),
-			})
-			return false
-		}
-
-		var existing model.Disease
-		err := db.Where(condition, strings.ToLower(value), excludeID).First(&existing).Error
-		if err != nil && err != gorm.ErrRecordNotFound {
-			util.CallServerError(c, util.APIErrorParams{Msg: "Failed to check existing records", Err: err})
-			return false
-		}
-		if err == nil {
-			util.CallUserError(c, util.APIErrorParams{Msg: userMsg, Err: fmt.Errorf("duplicate %s", column)})
-			return false
-		}
-		return true
-	}
+	return respondDuplicateDisease(c, db, req.Name, req.Codename, excludeID)
+}
 
-	if !check("name", req.Name, "Disease with similar name already exists") {
+// isDuplicateKeyError reports whether err was raised by a unique index
+// violation. It covers both the MySQL and SQLite driver messages so a
+// concurrent insert that slips past the pre-check is still reported as 400.
+func isDuplicateKeyError(err error) bool {
+	if err == nil {
 		return false
 	}
-	if !check("codename", req.Codename, "Disease with this codename already exists") {
-		return false
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return true
 	}
-	return true
+	msg := err.Error()
+	return strings.Contains(msg, "Duplicate entry") || strings.Contains(msg, "UNIQUE constraint failed")
 }
 
 // createDiseaseRecord creates a new disease record in the database
@@ -286,6 +293,13 @@ func CreateDisease(c *gin.Context) {
 	}
 
 	disease, err := createDiseaseRecord(db, name, codename, description)
+	if isDuplicateKeyError(err) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Disease with similar name or codename already exists",
+			Err: fmt.Errorf("disease already exists"),
+		})
+		return
+	}
 	if err != nil {
 		util.CallServerError(c, util.APIErrorParams{
 			Msg: "Failed to create disease",
@@ -302,17 +316,19 @@ func CreateDisease(c *gin.Context) {
 
 // UpdateDisease godoc
 // @Summary      Update disease information
-// @Description  Update an existing disease's information
+// @Description  Update an existing disease's information. Renaming a disease that patients list in their health history is rejected with the usage in data unless confirm=true is passed.
 // @Tags         Disease
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Security     SessionToken
 // @Param        id path string true "Disease ID"
+// @Param        confirm query bool false "Rename even if patients refer to the disease"
 // @Param        request body createDiseaseRequest true "Updated disease information"
 // @Success      200 {object} util.APIResponse{data=model.Disease} "Disease updated"
-// @Failure      400 {object} util.APIResponse "Invalid request or disease not found"
+// @Failure      400 {object} util.APIResponse "Invalid disease ID or request body"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Disease not found"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /disease/{id} [patch]
 func UpdateDisease(c *gin.Context) {
@@ -337,10 +353,7 @@ func UpdateDisease(c *gin.Context) {
 
 	existingDisease, err := fetchDiseaseByID(db, id)
 	if err != nil {
-		util.CallUserError(c, util.APIErrorParams{
-			Msg: "Disease not found",
-			Err: err,
-		})
+		respondDiseaseLookupError(c, err)
 		return
 	}
 
@@ -351,7 +364,19 @@ func UpdateDisease(c *gin.Context) {
 		return
 	}
 
-	if err := applyDiseaseUpdate(db, &existingDisease, diseaseRequest); err != nil {
+	if !checkRenameUsage(c, db, existingDisease, diseaseRequest) {
+		return
+	}
+
+	err = applyDiseaseUpdate(db, &existingDisease, diseaseRequest)
+	if isDuplicateKeyError(err) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Disease with similar name or codename already exists",
+			Err: fmt.Errorf("disease already exists"),
+		})
+		return
+	}
+	if err != nil {
 		util.CallServerError(c, util.APIErrorParams{
 			Msg: "Failed to update disease",
 			Err: err,
@@ -375,8 +400,9 @@ func UpdateDisease(c *gin.Context) {
 // @Security     SessionToken
 // @Param        id path string true "Disease ID"
 // @Success      200 {object} util.APIResponse "Disease deleted"
-// @Failure      400 {object} util.APIResponse "Disease not found"
+// @Failure      400 {object} util.APIResponse "Invalid disease ID"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Disease not found"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /disease/{id} [delete]
 func DeleteDisease(c *gin.Context) {
@@ -392,10 +418,7 @@ func DeleteDisease(c *gin.Context) {
 
 	existingDisease, err := fetchDiseaseByID(db, id)
 	if err != nil {
-		util.CallUserError(c, util.APIErrorParams{
-			Msg: "Disease not found",
-			Err: err,
-		})
+		respondDiseaseLookupError(c, err)
 		return
 	}
 
@@ -423,8 +446,9 @@ func DeleteDisease(c *gin.Context) {
 // @Security     SessionToken
 // @Param        id path string true "Disease ID"
 // @Success      200 {object} util.APIResponse{data=model.Disease} "Disease retrieved"
-// @Failure      400 {object} util.APIResponse "Disease not found"
+// @Failure      400 {object} util.APIResponse "Invalid disease ID"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Disease not found"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /disease/{id} [get]
 func GetDiseaseInfo(c *gin.Context) {
@@ -440,10 +464,7 @@ func GetDiseaseInfo(c *gin.Context) {
 
 	existingDisease, err := fetchDiseaseByID(db, id)
 	if err != nil {
-		util.CallUserError(c, util.APIErrorParams{
-			Msg: "Disease not found",
-			Err: err,
-		})
+		respondDiseaseLookupError(c, err)
 		return
 	}
 
@@ -0,0 +1,213 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// diseaseImportConflictError reports a code list entry whose name or
+// codename is already used by another disease.
+type diseaseImportConflictError struct {
+	code string
+}
+
+func (e *diseaseImportConflictError) Error() string {
+	return fmt.Sprintf("disease %s conflicts with an existing disease name or codename", e.code)
+}
+
+// diseaseImportInUseError reports a code list entry that would rename a
+// disease patients still refer to.
+type diseaseImportInUseError struct {
+	code  string
+	usage model.DiseaseUsage
+}
+
+func (e *diseaseImportInUseError) Error() string {
+	return fmt.Sprintf("disease %s is used by patients, pass confirm=true to rename it", e.code)
+}
+
+// normalizeDiseaseImport trims the entries and rejects codes listed twice.
+func normalizeDiseaseImport(items []model.DiseaseImportItem) ([]model.DiseaseImportItem, error) {
+	seen := map[string]bool{}
+	normalized := make([]model.DiseaseImportItem, 0, len(items))
+	for _, item := range items {
+		item.Code = strings.ToUpper(strings.TrimSpace(item.Code))
+		item.Name = strings.TrimSpace(item.Name)
+		item.Description = strings.TrimSpace(item.Description)
+		if item.Code == "" || item.Name == "" {
+			return nil, fmt.Errorf("code and name are required")
+		}
+		if seen[item.Code] {
+			return nil, fmt.Errorf("code %s is listed more than once", item.Code)
+		}
+		seen[item.Code] = true
+		normalized = append(normalized, item)
+	}
+	return normalized, nil
+}
+
+// findDiseaseForImport returns the disease an entry updates: the one with the
+// same code, or else a hand-entered disease without a code with the same name.
+func findDiseaseForImport(tx *gorm.DB, item model.DiseaseImportItem) (model.Disease, bool, error) {
+	var disease model.Disease
+	err := tx.Where("code = ?", item.Code).First(&disease).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = tx.Where("code = '' AND name = ?", item.Name).First(&disease).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return disease, false, nil
+	}
+	return disease, err == nil, err
+}
+
+// checkDiseaseImport applies the single-record checks to an entry: its name
+// and codename must not be used by another disease, compared
+// case-insensitively, and renaming a disease patients refer to needs confirm.
+func checkDiseaseImport(tx *gorm.DB, item model.DiseaseImportItem, disease model.Disease, found, confirm bool) error {
+	req := createDiseaseRequest{Name: item.Name}
+	if !found {
+		req.Codename = strings.ToLower(item.Code)
+	}
+	column, err := findDuplicateDisease(tx, req.Name, req.Codename, disease.ID)
+	if err != nil {
+		return err
+	}
+	if column != "" {
+		return &diseaseImportConflictError{code: item.Code}
+	}
+	if !found || confirm {
+		return nil
+	}
+
+	usage, inUse, err := renameInUse(tx, disease, req)
+	if err != nil {
+		return err
+	}
+	if inUse {
+		return &diseaseImportInUseError{code: item.Code, usage: usage}
+	}
+	return nil
+}
+
+// importDiseases upserts the entries by code in one transaction. New diseases
+// get the lower-cased code as codename; entries whose name and description
+// already match are left untouched. Renaming a disease patients refer to
+// fails unless confirm is set.
+func importDiseases(db *gorm.DB, items []model.DiseaseImportItem, confirm bool) (model.DiseaseImportResult, error) {
+	var result model.DiseaseImportResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result = model.DiseaseImportResult{}
+		for _, item := range items {
+			disease, found, err := findDiseaseForImport(tx, item)
+			if err != nil {
+				return err
+			}
+
+			if found && disease.Code == item.Code && disease.Name == item.Name && disease.Description == item.Description {
+				result.Unchanged++
+				continue
+			}
+			if err := checkDiseaseImport(tx, item, disease, found, confirm); err != nil {
+				return err
+			}
+
+			if !found {
+				disease = model.Disease{Code: item.Code, Name: item.Name, Codename: strings.ToLower(item.Code), Description: item.Description}
+				err = tx.Create(&disease).Error
+				result.Inserted++
+			} else {
+				err = tx.Model(&disease).Updates(map[string]interface{}{
+					"code":        item.Code,
+					"name":        item.Name,
+					"description": item.Description,
+				}).Error
+				result.Updated++
+			}
+			if isDuplicateKeyError(err) {
+				return &diseaseImportConflictError{code: item.Code}
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// ImportDiseases godoc
+// @Summary      Import diseases from a code list
+// @Description  Upsert up to 1000 standard code list entries (e.g. ICD codes) by code. A disease without a code and with the same name is adopted by the entry. New diseases get the lower-cased code as codename. Entries whose name and description already match are counted as unchanged. Names and codenames are checked against other diseases case-insensitively, and renaming a disease listed in patient health histories needs confirm=true. The import is all or nothing.
+// @Tags         Disease
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        request body model.DiseaseImportRequest true "Code list"
+// @Param        confirm query bool false "Allow renaming diseases patients refer to"
+// @Success      200 {object} util.APIResponse{data=model.DiseaseImportResult} "Diseases imported"
+// @Failure      400 {object} util.APIResponse "Invalid request, conflicting disease or unconfirmed rename of a disease in use"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /disease/import [post]
+func ImportDiseases(c *gin.Context) {
+	var req model.DiseaseImportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+
+	items, err := normalizeDiseaseImport(req.Diseases)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: err.Error(),
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := ensureDB(c)
+	if !ok {
+		return
+	}
+
+	result, err := importDiseases(db, items, c.Query("confirm") == "true")
+	var conflict *diseaseImportConflictError
+	if errors.As(err, &conflict) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: conflict.Error(),
+			Err: err,
+		})
+		return
+	}
+	var inUse *diseaseImportInUseError
+	if errors.As(err, &inUse) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg:  inUse.Error(),
+			Err:  err,
+			Data: inUse.usage,
+		})
+		return
+	}
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to import diseases",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Diseases imported",
+		Data: result,
+	})
+}
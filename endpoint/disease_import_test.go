@@ -0,0 +1,109 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportDiseases_InsertUpdateAndNoOp(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.POST("/disease/import", ImportDiseases)
+
+	importList := func(body string) model.DiseaseImportResult {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/disease/import", body: body})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data model.DiseaseImportResult `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	// A hand-entered disease with the same name is adopted rather than duplicated.
+	assert.NoError(t, db.Create(&model.Disease{Name: "Asthma", Codename: "asthma"}).Error)
+
+	got := importList(`{"diseases": [
+		{"code": "e11", "name": "Type 2 diabetes mellitus", "description": "Metabolic"},
+		{"code": "J45", "name": "Asthma"}
+	]}`)
+	assert.Equal(t, model.DiseaseImportResult{Inserted: 1, Updated: 1}, got)
+
+	var diabetes model.Disease
+	assert.NoError(t, db.Where("code = ?", "E11").First(&diabetes).Error)
+	assert.Equal(t, "e11", diabetes.Codename)
+	assert.Equal(t, "Metabolic", diabetes.Description)
+	var asthma model.Disease
+	assert.NoError(t, db.Where("code = ?", "J45").First(&asthma).Error)
+	assert.Equal(t, "asthma", asthma.Codename)
+
+	got = importList(`{"diseases": [
+		{"code": "E11", "name": "Type 2 diabetes mellitus", "description": "Metabolic"},
+		{"code": "J45", "name": "Asthma"}
+	]}`)
+	assert.Equal(t, model.DiseaseImportResult{Unchanged: 2}, got)
+
+	got = importList(`{"diseases": [
+		{"code": "E11", "name": "Type 2 diabetes", "description": "Metabolic"},
+		{"code": "J45", "name": "Asthma"}
+	]}`)
+	assert.Equal(t, model.DiseaseImportResult{Updated: 1, Unchanged: 1}, got)
+	assert.NoError(t, db.First(&diabetes, diabetes.ID).Error)
+	assert.Equal(t, "Type 2 diabetes", diabetes.Name)
+
+	var count int64
+	assert.NoError(t, db.Model(&model.Disease{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
+
+func TestImportDiseases_Rejected(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.POST("/disease/import", ImportDiseases)
+	assert.NoError(t, db.Create(&model.Disease{Name: "Gout", Codename: "gout", Code: "M10"}).Error)
+
+	for name, body := range map[string]string{
+		"empty":          `{"diseases": []}`,
+		"missing name":   `{"diseases": [{"code": "E11"}]}`,
+		"repeated code":  `{"diseases": [{"code": "E11", "name": "A"}, {"code": "e11", "name": "B"}]}`,
+		"name conflicts": `{"diseases": [{"code": "E11", "name": "Diabetes"}, {"code": "M11", "name": "Gout"}]}`,
+		"name case":      `{"diseases": [{"code": "E11", "name": "Diabetes"}, {"code": "M11", "name": "GOUT"}]}`,
+		"codename case":  `{"diseases": [{"code": "E11", "name": "Diabetes"}, {"code": "Gout", "name": "Arthritis"}]}`,
+		"within batch":   `{"diseases": [{"code": "J10", "name": "Flu"}, {"code": "J11", "name": "flu"}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/disease/import", body: body})
+			assertStatusWithError(t, w, http.StatusBadRequest, err)
+		})
+	}
+
+	// The conflicting batch was rolled back as a whole.
+	var count int64
+	assert.NoError(t, db.Model(&model.Disease{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestImportDiseases_RenameInUse(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.POST("/disease/import", ImportDiseases)
+	assert.NoError(t, db.Create(&model.Disease{Name: "Flu", Codename: "flu", Code: "J10"}).Error)
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Jane", PatientCode: "J001", HealthHistory: "Flu"}).Error)
+
+	body := `{"diseases": [{"code": "J10", "name": "Influenza"}]}`
+	w, resp, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/disease/import", body: body})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+	data, _ := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["patient_count"])
+
+	var flu model.Disease
+	assert.NoError(t, db.Where("code = ?", "J10").First(&flu).Error)
+	assert.Equal(t, "Flu", flu.Name)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/disease/import?confirm=true", body: body})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.NoError(t, db.First(&flu, flu.ID).Error)
+	assert.Equal(t, "Influenza", flu.Name)
+}
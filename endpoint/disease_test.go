@@ -1,12 +1,16 @@
 package endpoint
 
 import (
+	"errors"
+	"net/http"
 	"os"
+	"strconv"
 	"testing"
 
 	"github.com/ariebrainware/basis-data-ltt/config"
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
 
@@ -123,3 +127,97 @@ func TestDiseaseCodenameNormalization(t *testing.T) {
 		t.Logf("Info: Codename stored as '%s' (normalization handled at API layer)", found.Codename)
 	}
 }
+
+func TestCreateDisease_RejectsDuplicateName(t *testing.T) {
+	cases := []struct {
+		name      string
+		duplicate string
+	}{
+		{name: "same case", duplicate: "Diabetes"},
+		{name: "different case", duplicate: "DIABETES"},
+		{name: "surrounding whitespace", duplicate: "  diabetes  "},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, db := setupEndpointTest(t)
+			r.POST("/disease", CreateDisease)
+
+			w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/disease", body: map[string]string{"name": "Diabetes", "codename": "diabetes"}})
+			assert.NoError(t, err)
+			assertStatus(t, w, http.StatusOK)
+
+			w, response, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/disease", body: map[string]string{"name": tc.duplicate, "codename": "diabetes-2"}})
+			assert.NoError(t, err)
+			assertStatus(t, w, http.StatusBadRequest)
+			assert.Equal(t, "Disease with similar name already exists", response["msg"])
+
+			var count int64
+			db.Model(&model.Disease{}).Count(&count)
+			assert.Equal(t, int64(1), count)
+		})
+	}
+}
+
+func TestDiseaseNameUniqueIndex(t *testing.T) {
+	_, db := setupEndpointTest(t)
+
+	assert.True(t, db.Migrator().HasIndex(&model.Disease{}, model.DiseaseActiveNameIndex))
+
+	first := model.Disease{Name: "Asthma", Codename: "asthma"}
+	if err := db.Create(&first).Error; err != nil {
+		t.Fatalf("create first disease: %v", err)
+	}
+	err := db.Create(&model.Disease{Name: "Asthma", Codename: "asthma-2"}).Error
+	assert.Error(t, err)
+	assert.True(t, isDuplicateKeyError(err))
+
+	// Soft-deleted diseases release their name.
+	assert.NoError(t, db.Delete(&first).Error)
+	assert.NoError(t, db.Create(&model.Disease{Name: "Asthma", Codename: "asthma-3"}).Error)
+}
+
+func TestIsDuplicateKeyError(t *testing.T) {
+	assert.False(t, isDuplicateKeyError(nil))
+	assert.False(t, isDuplicateKeyError(errors.New("connection refused")))
+	assert.True(t, isDuplicateKeyError(gorm.ErrDuplicatedKey))
+	assert.True(t, isDuplicateKeyError(errors.New("Error 1062 (23000): Duplicate entry 'x' for key 'idx_diseases_name'")))
+	assert.True(t, isDuplicateKeyError(errors.New("UNIQUE constraint failed: diseases.name")))
+}
+
+func TestDiseaseHandlers_NotFoundVersusInvalidID(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/disease/:id", GetDiseaseInfo)
+	r.PATCH("/disease/:id", UpdateDisease)
+	r.DELETE("/disease/:id", DeleteDisease)
+
+	existing := model.Disease{Name: "Hypertension", Codename: "hypertension"}
+	assert.NoError(t, db.Create(&existing).Error)
+
+	updateBody := map[string]string{"name": "Renamed", "codename": "renamed"}
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		status int
+		msg    string
+	}{
+		{name: "get missing", method: http.MethodGet, path: "/disease/9999", status: http.StatusNotFound, msg: "Disease not found"},
+		{name: "update missing", method: http.MethodPatch, path: "/disease/9999", body: updateBody, status: http.StatusNotFound, msg: "Disease not found"},
+		{name: "delete missing", method: http.MethodDelete, path: "/disease/9999", status: http.StatusNotFound, msg: "Disease not found"},
+		{name: "get non-numeric", method: http.MethodGet, path: "/disease/abc", status: http.StatusBadRequest, msg: "Invalid disease ID"},
+		{name: "update zero", method: http.MethodPatch, path: "/disease/0", body: updateBody, status: http.StatusBadRequest, msg: "Invalid disease ID"},
+		{name: "delete negative", method: http.MethodDelete, path: "/disease/-1", status: http.StatusBadRequest, msg: "Invalid disease ID"},
+		{name: "update bad body", method: http.MethodPatch, path: "/disease/9999", body: `{"name":`, status: http.StatusBadRequest, msg: "Invalid request body"},
+		{name: "get existing", method: http.MethodGet, path: "/disease/" + strconv.Itoa(int(existing.ID)), status: http.StatusOK, msg: "Disease retrieved"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, response, err := performRequest(r, requestSpec{method: tc.method, requestPath: tc.path, body: tc.body})
+			assert.NoError(t, err)
+			assertStatus(t, w, tc.status)
+			assert.Equal(t, tc.msg, response["msg"])
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package endpoint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// computeDiseaseUsage counts the patients whose health history lists the
+// disease by name or codename, matched the same way as the treatments by
+// disease report.
+func computeDiseaseUsage(db *gorm.DB, disease model.Disease) (model.DiseaseUsage, error) {
+	usage := model.DiseaseUsage{DiseaseID: disease.ID, Name: disease.Name, Codename: disease.Codename}
+
+	// LIKE narrows the candidates; entries are then matched exactly.
+	var histories []string
+	err := db.Model(&model.Patient{}).
+		Where("LOWER(health_history) LIKE ? OR LOWER(health_history) LIKE ?",
+			"%"+strings.ToLower(strings.TrimSpace(disease.Name))+"%",
+			"%"+strings.ToLower(strings.TrimSpace(disease.Codename))+"%").
+		Pluck("health_history", &histories).Error
+	if err != nil {
+		return usage, err
+	}
+
+	_, index := diseaseLookup([]model.Disease{disease})
+	for _, history := range histories {
+		if len(patientDiseaseIndexes(history, index)) > 0 {
+			usage.PatientCount++
+		}
+	}
+	return usage, nil
+}
+
+// renamesDisease reports whether an update changes the name or codename
+// patients' health histories refer to.
+func renamesDisease(existing model.Disease, req createDiseaseRequest) bool {
+	return (req.Name != "" && req.Name != existing.Name) ||
+		(req.Codename != "" && req.Codename != existing.Codename)
+}
+
+// renameInUse reports the usage of a disease an update would rename while
+// patients still refer to it. It returns false when the update does not
+// rename the disease or no patient lists it.
+func renameInUse(db *gorm.DB, existing model.Disease, req createDiseaseRequest) (model.DiseaseUsage, bool, error) {
+	if !renamesDisease(existing, req) {
+		return model.DiseaseUsage{}, false, nil
+	}
+	usage, err := computeDiseaseUsage(db, existing)
+	if err != nil {
+		return usage, false, err
+	}
+	return usage, usage.PatientCount > 0, nil
+}
+
+// checkRenameUsage rejects renaming a disease that patients still refer to
+// unless the request is confirmed with confirm=true, and reports the usage so
+// the caller can decide. It responds and returns false when the update must
+// not proceed.
+func checkRenameUsage(c *gin.Context, db *gorm.DB, existing model.Disease, req createDiseaseRequest) bool {
+	if c.Query("confirm") == "true" {
+		return true
+	}
+
+	usage, inUse, err := renameInUse(db, existing, req)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to check disease usage",
+			Err: err,
+		})
+		return false
+	}
+	if !inUse {
+		return true
+	}
+
+	util.CallUserError(c, util.APIErrorParams{
+		Msg:  "Disease is used by patients, pass confirm=true to rename it",
+		Err:  fmt.Errorf("disease is listed in %d patient health histories", usage.PatientCount),
+		Data: usage,
+	})
+	return false
+}
+
+// GetDiseaseUsage godoc
+// @Summary      Get disease usage
+// @Description  Count the patients whose health history lists the disease by name or codename. Check this before renaming a disease: patients keep referring to the old name.
+// @Tags         Disease
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Disease ID"
+// @Success      200 {object} util.APIResponse{data=model.DiseaseUsage} "Disease usage retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid disease ID"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Disease not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /disease/{id}/usage [get]
+func GetDiseaseUsage(c *gin.Context) {
+	id, ok := getIDParam(c)
+	if !ok {
+		return
+	}
+
+	db, ok := ensureDB(c)
+	if !ok {
+		return
+	}
+
+	disease, err := fetchDiseaseByID(db, id)
+	if err != nil {
+		respondDiseaseLookupError(c, err)
+		return
+	}
+
+	usage, err := computeDiseaseUsage(db, disease)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve disease usage",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Disease usage retrieved",
+		Data: usage,
+	})
+}
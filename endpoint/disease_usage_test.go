@@ -0,0 +1,77 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDiseaseUsage_CountsLinkedPatients(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/disease/:id/usage", GetDiseaseUsage)
+
+	disease := model.Disease{Name: "Diabetes", Codename: "diabetes-2"}
+	assert.NoError(t, db.Create(&disease).Error)
+
+	for i, history := range []string{
+		"Diabetes, Hypertension",
+		"hypertension,DIABETES-2",
+		"Prediabetes",
+		"",
+	} {
+		patient := model.Patient{FullName: fmt.Sprintf("Usage %d", i), PatientCode: fmt.Sprintf("U%d", i), HealthHistory: history}
+		assert.NoError(t, db.Create(&patient).Error)
+	}
+	deleted := model.Patient{FullName: "Usage Deleted", PatientCode: "UD", HealthHistory: "Diabetes"}
+	assert.NoError(t, db.Create(&deleted).Error)
+	assert.NoError(t, db.Delete(&deleted).Error)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/disease/%d/usage", disease.ID)})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.DiseaseUsage `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, disease.ID, resp.Data.DiseaseID)
+	assert.Equal(t, 2, resp.Data.PatientCount)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/disease/9999/usage"})
+	assertStatusWithError(t, w, http.StatusNotFound, err)
+}
+
+func TestUpdateDisease_RenameRequiresConfirmWhenUsed(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.PATCH("/disease/:id", UpdateDisease)
+
+	used := model.Disease{Name: "Asthma", Codename: "asthma"}
+	unused := model.Disease{Name: "Gout", Codename: "gout"}
+	assert.NoError(t, db.Create(&used).Error)
+	assert.NoError(t, db.Create(&unused).Error)
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Asthma Patient", PatientCode: "A1", HealthHistory: "Asthma"}).Error)
+
+	rename := map[string]string{"name": "Bronchial Asthma"}
+	w, resp, err := performRequest(r, requestSpec{method: http.MethodPatch, requestPath: fmt.Sprintf("/disease/%d", used.ID), body: rename})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+	data, _ := resp["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["patient_count"])
+
+	var unchanged model.Disease
+	assert.NoError(t, db.First(&unchanged, used.ID).Error)
+	assert.Equal(t, "Asthma", unchanged.Name)
+
+	// Description-only updates and unused diseases are not guarded.
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPatch, requestPath: fmt.Sprintf("/disease/%d", used.ID), body: map[string]string{"description": "Airway disease"}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPatch, requestPath: fmt.Sprintf("/disease/%d", unused.ID), body: map[string]string{"name": "Gouty Arthritis"}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPatch, requestPath: fmt.Sprintf("/disease/%d?confirm=true", used.ID), body: rename})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.NoError(t, db.First(&unchanged, used.ID).Error)
+	assert.Equal(t, "Bronchial Asthma", unchanged.Name)
+}
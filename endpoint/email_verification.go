@@ -0,0 +1,273 @@
+package endpoint
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const defaultEmailVerificationTTL = 24 * time.Hour
+
+// emailVerificationRequired reports whether new signups must verify their
+// email before they can log in (SIGNUP_EMAIL_VERIFICATION=true).
+func emailVerificationRequired() bool {
+	return os.Getenv("SIGNUP_EMAIL_VERIFICATION") == "true"
+}
+
+// emailVerificationTTL is how long a verification token stays valid:
+// EMAIL_VERIFICATION_TTL when it is a positive duration, 24 hours otherwise.
+func emailVerificationTTL() time.Duration {
+	if d, err := time.ParseDuration(os.Getenv("EMAIL_VERIFICATION_TTL")); err == nil && d > 0 {
+		return d
+	}
+	return defaultEmailVerificationTTL
+}
+
+// ValidateEmailVerification checks at startup that a mailer is configured
+// when SIGNUP_EMAIL_VERIFICATION is enabled; without one no token could reach
+// its user and every new signup would be locked out.
+func ValidateEmailVerification() error {
+	if !emailVerificationRequired() {
+		return nil
+	}
+	if _, ok := util.MailerConfigFromEnv(); !ok {
+		return fmt.Errorf("SIGNUP_EMAIL_VERIFICATION=true requires a mailer: set SMTP_HOST and SMTP_FROM")
+	}
+	return nil
+}
+
+// verificationLink is the link sent to a user: EMAIL_VERIFICATION_URL, such as
+// a frontend page, or the API's /verify-email path, with the token appended.
+func verificationLink(token string) string {
+	base := strings.TrimSpace(os.Getenv("EMAIL_VERIFICATION_URL"))
+	if base == "" {
+		base = "/verify-email"
+	}
+	sep := "?"
+	if strings.Contains(base, "?") {
+		sep = "&"
+	}
+	return base + sep + "token=" + token
+}
+
+// sendVerificationEmail mails a verification token to a user through the SMTP
+// mailer. The token is a credential and never reaches the log. Tests replace
+// it to capture the token.
+var sendVerificationEmail = func(user model.User, token string) error {
+	body := fmt.Sprintf("Hello %s,\r\n\r\nConfirm your email address by opening this link:\r\n%s\r\n\r\nThe link expires in %s.\r\n",
+		user.Name, verificationLink(token), emailVerificationTTL())
+	return util.SendMail(user.Email, "Verify your email address", body)
+}
+
+// hashVerificationToken returns the hex SHA-256 of token, the form in which
+// tokens are stored.
+func hashVerificationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// createEmailVerification stores a new verification token for user, valid
+// until now plus the TTL, and returns the token.
+func createEmailVerification(db *gorm.DB, user model.User, now time.Time) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate verification token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+	verification := model.EmailVerification{
+		UserID:    user.ID,
+		TokenHash: hashVerificationToken(token),
+		ExpiresAt: now.Add(emailVerificationTTL()),
+	}
+	if err := db.Create(&verification).Error; err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// emailVerificationPending reports whether the user signed up with email
+// verification and has not verified yet. Users without a verification record,
+// such as those created before the feature was enabled, are not pending.
+func emailVerificationPending(db *gorm.DB, userID uint) (bool, error) {
+	var count int64
+	err := db.Model(&model.EmailVerification{}).Where("user_id = ? AND verified_at IS NULL", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// ensureEmailVerified blocks login for users whose email is still unverified
+// while SIGNUP_EMAIL_VERIFICATION is enabled.
+func ensureEmailVerified(ctx loginContext, user *model.User) bool {
+	if !emailVerificationRequired() {
+		return true
+	}
+	pending, err := emailVerificationPending(ctx.DB, user.ID)
+	if err != nil {
+		util.CallServerError(ctx.C, util.APIErrorParams{Msg: "Database error", Err: err})
+		return false
+	}
+	if pending {
+		util.LogLoginFailure(util.LoginParams{Email: ctx.Email, IP: ctx.CI.IP, UserAgent: ctx.CI.Agent, Reason: "email not verified"})
+		util.CallForbidden(ctx.C, util.APIErrorParams{Msg: "Email address has not been verified", Err: fmt.Errorf("email not verified")})
+		return false
+	}
+	return true
+}
+
+// reissueEmailVerification replaces a user's pending verification tokens,
+// expired or not, with a fresh one. It returns an empty token when the user
+// has nothing to verify.
+func reissueEmailVerification(db *gorm.DB, user model.User, now time.Time) (string, error) {
+	var token string
+	err := db.Transaction(func(tx *gorm.DB) error {
+		pending, err := emailVerificationPending(tx, user.ID)
+		if err != nil || !pending {
+			return err
+		}
+		if err := tx.Where("user_id = ? AND verified_at IS NULL", user.ID).Delete(&model.EmailVerification{}).Error; err != nil {
+			return err
+		}
+		token, err = createEmailVerification(tx, user, now)
+		return err
+	})
+	return token, err
+}
+
+var (
+	errVerificationTokenInvalid = errors.New("verification token not found or already used")
+	errVerificationTokenExpired = errors.New("verification token expired")
+)
+
+// verifyEmailToken marks the verification matching token as verified at now.
+func verifyEmailToken(db *gorm.DB, token string, now time.Time) (model.EmailVerification, error) {
+	var verification model.EmailVerification
+	err := db.Where("token_hash = ? AND verified_at IS NULL", hashVerificationToken(token)).First(&verification).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return verification, errVerificationTokenInvalid
+	}
+	if err != nil {
+		return verification, err
+	}
+	if now.After(verification.ExpiresAt) {
+		return verification, errVerificationTokenExpired
+	}
+	if err := db.Model(&verification).Update("verified_at", now).Error; err != nil {
+		return verification, err
+	}
+	return verification, nil
+}
+
+// VerifyEmail godoc
+// @Summary      Verify a signup email address
+// @Description  Confirm the email address of a new account with the token sent after signup. Until then, login is refused while SIGNUP_EMAIL_VERIFICATION is enabled. Tokens expire after EMAIL_VERIFICATION_TTL (default 24h) and can be used once.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        token query string true "Verification token"
+// @Success      200 {object} util.APIResponse "Email verified"
+// @Failure      400 {object} util.APIResponse "Missing, invalid, used or expired token"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /verify-email [get]
+func VerifyEmail(c *gin.Context) {
+	token := strings.TrimSpace(c.Query("token"))
+	if token == "" {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Missing verification token",
+			Err: fmt.Errorf("token query parameter is required"),
+		})
+		return
+	}
+
+	db, ok := getDBOrRespond(c)
+	if !ok {
+		return
+	}
+
+	verification, err := verifyEmailToken(db, token, time.Now())
+	switch {
+	case errors.Is(err, errVerificationTokenInvalid):
+		util.CallUserError(c, util.APIErrorParams{Msg: "Invalid or already used verification token", Err: err})
+		return
+	case errors.Is(err, errVerificationTokenExpired):
+		util.CallUserError(c, util.APIErrorParams{Msg: "Verification token has expired", Err: err})
+		return
+	case err != nil:
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to verify email", Err: err})
+		return
+	}
+
+	util.LogSecurityEvent(util.SecurityEvent{
+		EventType: util.EventEmailVerified,
+		UserID:    fmt.Sprintf("%d", verification.UserID),
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Message:   "Email address verified",
+	})
+	util.CallSuccessOK(c, util.APISuccessParams{Msg: "Email verified"})
+}
+
+// ResendVerificationRequest is the body of a verification email resend.
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required" example:"user@example.com"`
+}
+
+// ResendVerificationEmail godoc
+// @Summary      Resend a signup verification email
+// @Description  Issue a new verification token for an account that has not verified its email yet, replacing any earlier token including expired ones. The response is the same whether or not the email belongs to a pending account.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Param        request body ResendVerificationRequest true "Email address to verify"
+// @Success      200 {object} util.APIResponse "Verification email sent if the account is pending"
+// @Failure      400 {object} util.APIResponse "Invalid request payload"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /verify-email/resend [post]
+func ResendVerificationEmail(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request payload",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrRespond(c)
+	if !ok {
+		return
+	}
+
+	const msg = "If the account is awaiting verification, a new verification email has been sent"
+	var user model.User
+	err := db.Where("email = ?", strings.TrimSpace(req.Email)).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.CallSuccessOK(c, util.APISuccessParams{Msg: msg})
+		return
+	}
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Database error", Err: err})
+		return
+	}
+
+	token, err := reissueEmailVerification(db, user, time.Now())
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to issue verification token", Err: err})
+		return
+	}
+	if token != "" {
+		if err := sendVerificationEmail(user, token); err != nil {
+			config.Logger().Error("Failed to send verification email", "user_id", user.ID, "error", err)
+		}
+	}
+	util.CallSuccessOK(c, util.APISuccessParams{Msg: msg})
+}
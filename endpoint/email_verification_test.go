@@ -0,0 +1,160 @@
+package endpoint
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// setupEmailVerificationTest registers signup, login and verify-email and
+// captures the tokens that would be emailed.
+func setupEmailVerificationTest(t *testing.T) (*gin.Engine, *gorm.DB, *[]string) {
+	t.Helper()
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, model.SeedRoles(db))
+	r.POST("/signup", Signup)
+	r.POST("/login", Login)
+	r.GET("/verify-email", VerifyEmail)
+	r.POST("/verify-email/resend", ResendVerificationEmail)
+
+	var sent []string
+	orig := sendVerificationEmail
+	sendVerificationEmail = func(_ model.User, token string) error {
+		sent = append(sent, token)
+		return nil
+	}
+	t.Cleanup(func() { sendVerificationEmail = orig })
+	return r, db, &sent
+}
+
+func postJSON(t *testing.T, r *gin.Engine, path string, body map[string]string) int {
+	t.Helper()
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: body})
+	assert.NoError(t, err)
+	return w.Code
+}
+
+func verifyEmailStatus(t *testing.T, r *gin.Engine, token string) int {
+	t.Helper()
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/verify-email?token=" + token})
+	assert.NoError(t, err)
+	return w.Code
+}
+
+func TestEmailVerification_RequiredBlocksLoginUntilVerified(t *testing.T) {
+	t.Setenv("SIGNUP_EMAIL_VERIFICATION", "true")
+	r, db, sent := setupEmailVerificationTest(t)
+
+	creds := map[string]string{"email": "verify-me@example.com", "password": "password123"}
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/signup", map[string]string{"name": "Verify Me", "email": creds["email"], "password": creds["password"]}))
+	if !assert.Len(t, *sent, 1) {
+		return
+	}
+	token := (*sent)[0]
+	var stored model.EmailVerification
+	assert.NoError(t, db.First(&stored).Error)
+	assert.NotEqual(t, token, stored.TokenHash, "only the token hash is stored")
+
+	assert.Equal(t, http.StatusForbidden, postJSON(t, r, "/login", creds))
+
+	assert.Equal(t, http.StatusBadRequest, verifyEmailStatus(t, r, "not-the-token"))
+	assert.Equal(t, http.StatusBadRequest, verifyEmailStatus(t, r, ""))
+	assert.Equal(t, http.StatusOK, verifyEmailStatus(t, r, token))
+	assert.Equal(t, http.StatusBadRequest, verifyEmailStatus(t, r, token), "tokens are single-use")
+
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/login", creds))
+}
+
+func TestEmailVerification_ExpiredToken(t *testing.T) {
+	t.Setenv("SIGNUP_EMAIL_VERIFICATION", "true")
+	r, db, _ := setupEmailVerificationTest(t)
+
+	user := model.User{Name: "Late", Email: "late@example.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	assert.NoError(t, db.Create(&user).Error)
+	token, err := createEmailVerification(db, user, time.Now().Add(-emailVerificationTTL()-time.Minute))
+	assert.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, verifyEmailStatus(t, r, token))
+	pending, err := emailVerificationPending(db, user.ID)
+	assert.NoError(t, err)
+	assert.True(t, pending)
+}
+
+func TestEmailVerification_ResendReplacesExpiredToken(t *testing.T) {
+	t.Setenv("SIGNUP_EMAIL_VERIFICATION", "true")
+	r, db, sent := setupEmailVerificationTest(t)
+
+	creds := map[string]string{"email": "resend@example.com", "password": "password123"}
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/signup", map[string]string{"name": "Resend", "email": creds["email"], "password": creds["password"]}))
+	if !assert.Len(t, *sent, 1) {
+		return
+	}
+	expired := (*sent)[0]
+	assert.NoError(t, db.Model(&model.EmailVerification{}).Where("verified_at IS NULL").Update("expires_at", time.Now().Add(-time.Minute)).Error)
+	assert.Equal(t, http.StatusBadRequest, verifyEmailStatus(t, r, expired))
+
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/verify-email/resend", map[string]string{"email": creds["email"]}))
+	if !assert.Len(t, *sent, 2) {
+		return
+	}
+	assert.Equal(t, http.StatusBadRequest, verifyEmailStatus(t, r, expired), "the old token is replaced")
+	assert.Equal(t, http.StatusOK, verifyEmailStatus(t, r, (*sent)[1]))
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/login", creds))
+
+	// Verified and unknown accounts get the same answer and no email.
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/verify-email/resend", map[string]string{"email": creds["email"]}))
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/verify-email/resend", map[string]string{"email": "nobody@example.com"}))
+	assert.Len(t, *sent, 2)
+	assert.Equal(t, http.StatusBadRequest, postJSON(t, r, "/verify-email/resend", map[string]string{}))
+}
+
+func TestEmailVerification_Disabled(t *testing.T) {
+	t.Setenv("SIGNUP_EMAIL_VERIFICATION", "")
+	r, db, sent := setupEmailVerificationTest(t)
+
+	creds := map[string]string{"email": "no-verify@example.com", "password": "password123"}
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/signup", map[string]string{"name": "No Verify", "email": creds["email"], "password": creds["password"]}))
+	assert.Empty(t, *sent)
+	var count int64
+	assert.NoError(t, db.Model(&model.EmailVerification{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/login", creds))
+}
+
+func TestEmailVerification_EnabledDoesNotBlockExistingUsers(t *testing.T) {
+	r, _, _ := setupEmailVerificationTest(t)
+
+	creds := map[string]string{"email": "existing@example.com", "password": "password123"}
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/signup", map[string]string{"name": "Existing", "email": creds["email"], "password": creds["password"]}))
+
+	t.Setenv("SIGNUP_EMAIL_VERIFICATION", "true")
+	assert.Equal(t, http.StatusOK, postJSON(t, r, "/login", creds))
+}
+
+func TestValidateEmailVerification(t *testing.T) {
+	t.Setenv("SIGNUP_EMAIL_VERIFICATION", "true")
+	t.Setenv("SMTP_HOST", "")
+	t.Setenv("SMTP_FROM", "")
+	assert.Error(t, ValidateEmailVerification(), "verification without a mailer locks out every signup")
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_FROM", "noreply@example.com")
+	assert.NoError(t, ValidateEmailVerification())
+
+	t.Setenv("SIGNUP_EMAIL_VERIFICATION", "")
+	t.Setenv("SMTP_HOST", "")
+	assert.NoError(t, ValidateEmailVerification())
+}
+
+func TestVerificationLink(t *testing.T) {
+	t.Setenv("EMAIL_VERIFICATION_URL", "")
+	assert.Equal(t, "/verify-email?token=abc", verificationLink("abc"))
+	t.Setenv("EMAIL_VERIFICATION_URL", "https://app.example.com/verify?lang=id")
+	assert.Equal(t, "https://app.example.com/verify?lang=id&token=abc", verificationLink("abc"))
+}
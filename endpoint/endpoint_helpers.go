@@ -28,6 +28,11 @@ var EndpointTestModels = []interface{}{
 	&model.Transaction{},
 	&model.PatientCode{},
 	&model.Employee{},
+	&model.Tag{},
+	&model.TreatmentTag{},
+	&model.ClinicHours{},
+	&model.Schedule{},
+	&model.EmailVerification{},
 }
 
 // setupEndpointTestDB initializes a test database with all standard models migrated.
@@ -51,6 +56,12 @@ func setupEndpointTestDB(t *testing.T) *gorm.DB {
 	if err := db.AutoMigrate(EndpointTestModels...); err != nil {
 		t.Fatalf("auto migrate failed: %v", err)
 	}
+	if err := model.EnsureTherapistEmailIndex(db); err != nil {
+		t.Fatalf("therapist email index failed: %v", err)
+	}
+	if err := model.EnsureDiseaseNameIndex(db); err != nil {
+		t.Fatalf("disease name index failed: %v", err)
+	}
 
 	// Clean up all tables
 	for _, m := range EndpointTestModels {
@@ -79,6 +90,16 @@ func setupEndpointTest(t *testing.T) (*gin.Engine, *gorm.DB) {
 	return r, db
 }
 
+// withAuthContext returns a middleware that stores the given user and role in
+// the Gin context, mimicking what ValidateLoginToken does for real requests.
+func withAuthContext(userID uint, roleID uint32) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(middleware.UserIDKey, userID)
+		c.Set(middleware.RoleIDKey, roleID)
+		c.Next()
+	}
+}
+
 // newTestRouter returns a new Gin engine configured for tests.
 // Use this for tests that don't need a DB injected.
 func newTestRouter() *gin.Engine {
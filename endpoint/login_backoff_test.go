@@ -0,0 +1,65 @@
+package endpoint
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogin_BackoffRejectsRapidRetries(t *testing.T) {
+	t.Setenv("LOGIN_BACKOFF_ENABLED", "true")
+	t.Setenv("LOGIN_BACKOFF_BASE", "100ms")
+	t.Setenv("LOGIN_BACKOFF_MAX", "10s")
+
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, model.SeedRoles(db))
+	r.POST("/login", Login)
+
+	email := "backoff-login@example.com"
+	t.Cleanup(func() { util.ResetLoginBackoff(email) })
+	salt, err := util.GenerateSalt()
+	assert.NoError(t, err)
+	hash, err := util.HashPasswordArgon2("correct-pass", salt)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Create(&model.User{Name: "Backoff", Email: email, Password: hash, PasswordSalt: salt, RoleID: model.RoleAdmin}).Error)
+
+	login := func(password string) *http.Response {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/login", body: map[string]string{"email": email, "password": password}})
+		assert.NoError(t, err)
+		return w.Result()
+	}
+
+	// The first failure is reported normally and starts a 200ms backoff.
+	assert.Equal(t, http.StatusBadRequest, login("wrong-pass").StatusCode)
+
+	// An immediate retry, even with the right password, is throttled. It is
+	// counted as a backoff, not as a rate-limit rejection.
+	util.ResetLoginMetricsForTest()
+	t.Cleanup(util.ResetLoginMetricsForTest)
+	resp := login("correct-pass")
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	metrics := util.GetLoginMetrics()
+	assert.Equal(t, int64(1), metrics.LoginBackoff)
+	assert.Zero(t, metrics.RateLimitExceeded)
+	retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, retryAfter)
+
+	// Once the delay has elapsed the attempt is processed again; a second
+	// failure doubles the wait.
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, http.StatusBadRequest, login("wrong-pass").StatusCode)
+	assert.Equal(t, http.StatusTooManyRequests, login("correct-pass").StatusCode)
+	remaining := util.LoginBackoffRemaining(email)
+	assert.Greater(t, remaining, 200*time.Millisecond)
+	assert.LessOrEqual(t, remaining, 400*time.Millisecond)
+
+	time.Sleep(remaining + 50*time.Millisecond)
+	assert.Equal(t, http.StatusOK, login("correct-pass").StatusCode)
+	assert.Zero(t, util.LoginBackoffRemaining(email), "successful login should clear the backoff")
+}
@@ -0,0 +1,30 @@
+package endpoint
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics godoc
+// @Summary      Prometheus metrics
+// @Description  Expose login and GeoIP cache counters and per-route request latency in the Prometheus text format. Latency is reported as the http_request_duration_seconds histogram, labelled by method and route (the route pattern, e.g. /patient/:id), and as p50/p95 estimates in the http_request_duration_seconds_estimate gauge, labelled by method, route and quantile.
+// @Tags         Metrics
+// @Produce      plain
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {string} string "Prometheus metrics"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      403 {object} util.APIResponse "Admin only"
+// @Failure      500 {object} util.APIResponse "Failed to render metrics"
+// @Router       /metrics [get]
+func Metrics(c *gin.Context) {
+	var buf bytes.Buffer
+	if err := util.WritePrometheusMetrics(&buf); err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to render metrics", Err: err})
+		return
+	}
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", buf.Bytes())
+}
@@ -0,0 +1,67 @@
+package endpoint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMetrics_ReportsLoginFailures(t *testing.T) {
+	util.ResetLoginMetricsForTest()
+	t.Cleanup(util.ResetLoginMetricsForTest)
+
+	r, _ := setupEndpointTest(t)
+	r.POST("/login", Login)
+	r.GET("/metrics", Metrics)
+
+	body := `{"email":"nobody@example.com","password":"wrongpass"}`
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/login", body: body})
+	if err != nil {
+		t.Fatalf("login request failed: %v", err)
+	}
+	if w.Code == http.StatusOK {
+		t.Fatalf("expected login to fail, got 200")
+	}
+
+	w, _, _ = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/metrics"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("expected text/plain content type, got %q", ct)
+	}
+	if !strings.Contains(w.Body.String(), "login_failure_total 1\n") {
+		t.Errorf("expected one login failure in metrics, got:\n%s", w.Body.String())
+	}
+}
+
+func TestMetrics_ReportsRouteLatency(t *testing.T) {
+	util.ResetRouteLatenciesForTest()
+	t.Cleanup(util.ResetRouteLatenciesForTest)
+
+	r := gin.New()
+	r.Use(middleware.RequestLogger())
+	r.GET("/therapist/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/metrics", Metrics)
+
+	for _, path := range []string{"/therapist/1", "/therapist/2", "/not-a-route"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	w, _, _ := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/metrics"})
+	out := w.Body.String()
+	if !strings.Contains(out, `http_request_duration_seconds_count{method="GET",route="/therapist/:id"} 2`+"\n") {
+		t.Errorf("expected two recorded /therapist/:id requests, got:\n%s", out)
+	}
+	if !strings.Contains(out, `http_request_duration_seconds_estimate{method="GET",route="/therapist/:id",quantile="0.95"}`) {
+		t.Errorf("expected a p95 estimate for /therapist/:id, got:\n%s", out)
+	}
+	if strings.Contains(out, "not-a-route") {
+		t.Errorf("unmatched paths must not be recorded, got:\n%s", out)
+	}
+}
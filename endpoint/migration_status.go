@@ -0,0 +1,127 @@
+package endpoint
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+// GetMigrationStatus godoc
+// @Summary      Get database migration status
+// @Description  Report which tables of the models migrated at startup exist and which are missing, to confirm a deploy migrated the database
+// @Tags         Admin
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=model.MigrationStatus} "Migration status retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /admin/migration-status [get]
+func GetMigrationStatus(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	status, err := model.CheckMigrationStatus(db)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to check migration status",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Migration status retrieved",
+		Data: status,
+	})
+}
+
+// onDemandMigrationEnabled reports whether POST /admin/migrate may run
+// (ADMIN_MIGRATE_ENABLED=true).
+func onDemandMigrationEnabled() bool {
+	return os.Getenv("ADMIN_MIGRATE_ENABLED") == "true"
+}
+
+// MigrateModel godoc
+// @Summary      Migrate one model on demand
+// @Description  Run AutoMigrate for one of the models migrated at startup, named by its table as listed by GET /admin/migration-status, without restarting. Only available when ADMIN_MIGRATE_ENABLED=true.
+// @Tags         Admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        request body model.MigrateModelRequest true "Table of the model to migrate"
+// @Success      200 {object} util.APIResponse{data=model.MigrationStatus} "Model migrated; data is the migration status afterwards"
+// @Failure      400 {object} util.APIResponse "Invalid request or unknown model"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "On-demand migration is disabled"
+// @Failure      500 {object} util.APIResponse "Migration failed"
+// @Router       /admin/migrate [post]
+func MigrateModel(c *gin.Context) {
+	if !onDemandMigrationEnabled() {
+		util.CallErrorNotFound(c, util.APIErrorParams{
+			Msg: "On-demand migration is disabled",
+			Err: fmt.Errorf("ADMIN_MIGRATE_ENABLED is not true"),
+		})
+		return
+	}
+
+	var req model.MigrateModelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	target, found, err := model.MigratedModelByTable(db, req.Model)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to resolve model",
+			Err: err,
+		})
+		return
+	}
+	if !found {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Unknown model; use a table listed by GET /admin/migration-status",
+			Err: fmt.Errorf("model %q is not migrated by this application", req.Model),
+		})
+		return
+	}
+
+	if err := db.AutoMigrate(target); err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Migration failed",
+			Err: err,
+		})
+		return
+	}
+	config.Logger().Info("Migrated model on demand", "model", req.Model)
+
+	status, err := model.CheckMigrationStatus(db)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to check migration status",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Model migrated",
+		Data: status,
+	})
+}
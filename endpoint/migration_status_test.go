@@ -0,0 +1,78 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func getMigrationStatus(t *testing.T, r *gin.Engine) model.MigrationStatus {
+	t.Helper()
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/admin/migration-status"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.MigrationStatus `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestGetMigrationStatus(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/admin/migration-status", GetMigrationStatus)
+
+	assert.NoError(t, db.AutoMigrate(model.MigratedModels()...))
+	t.Cleanup(func() { _ = db.Migrator().DropTable(&model.SecurityLog{}) })
+
+	status := getMigrationStatus(t, r)
+	assert.True(t, status.Complete)
+	assert.Empty(t, status.Missing)
+	assert.Len(t, status.Present, len(model.MigratedModels()))
+
+	// Partially migrated: two tables were never created.
+	assert.NoError(t, db.Migrator().DropTable(&model.Schedule{}, &model.SecurityLog{}))
+
+	status = getMigrationStatus(t, r)
+	assert.False(t, status.Complete)
+	assert.ElementsMatch(t, []string{"schedules", "security_logs"}, status.Missing)
+	assert.Contains(t, status.Present, "patients")
+	assert.NotContains(t, status.Present, "schedules")
+}
+
+func TestMigrateModel(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.POST("/admin/migrate", MigrateModel)
+	t.Setenv("ADMIN_MIGRATE_ENABLED", "true")
+	assert.NoError(t, db.Migrator().DropTable(&model.Schedule{}))
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/admin/migrate", body: map[string]string{"model": "schedules"}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.True(t, db.Migrator().HasTable(&model.Schedule{}))
+
+	var resp struct {
+		Data model.MigrationStatus `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Contains(t, resp.Data.Present, "schedules")
+
+	for _, body := range []interface{}{map[string]string{"model": "nope"}, map[string]string{}} {
+		w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/admin/migrate", body: body})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+}
+
+func TestMigrateModel_Disabled(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.POST("/admin/migrate", MigrateModel)
+	t.Setenv("ADMIN_MIGRATE_ENABLED", "")
+	assert.NoError(t, db.Migrator().DropTable(&model.Schedule{}))
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/admin/migrate", body: map[string]string{"model": "schedules"}})
+	assertStatusWithError(t, w, http.StatusNotFound, err)
+	assert.False(t, db.Migrator().HasTable(&model.Schedule{}))
+}
@@ -1,11 +1,14 @@
 package endpoint
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"os"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/ariebrainware/basis-data-ltt/config"
 	"github.com/ariebrainware/basis-data-ltt/middleware"
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
@@ -16,26 +19,35 @@ import (
 type listQuery struct {
 	Limit       int
 	Offset      int
+	TherapistID int
 	Keyword     string
 	GroupByDate string
 	SortBy      string
 	SortDir     string
+	WithCounts  bool
 }
 
+// queryString returns the trimmed value of a query parameter, so a missing
+// parameter, an empty one and one holding only whitespace all read as "".
+func queryString(c *gin.Context, key string) string {
+	return strings.TrimSpace(c.Query(key))
+}
+
+// parseQueryParams reads the common list parameters. Empty and missing
+// parameters are treated identically and never filter: numeric parameters
+// (limit, offset, therapist_id) fall back to 0, meaning no limit, offset or
+// therapist filter, and so do negative or non-numeric values. String
+// parameters are trimmed, so a blank keyword or group_by_date is no filter.
 func parseQueryParams(c *gin.Context) listQuery {
-	limit, _ := strconv.Atoi(c.Query("limit"))
-	offset, _ := strconv.Atoi(c.Query("offset"))
-	keyword := c.Query("keyword")
-	groupByDate := c.Query("group_by_date")
-	sortBy := c.Query("sort")                       // supported values: full_name, patient_code
-	sortDir := strings.ToLower(c.Query("sort_dir")) // supported values: asc, desc
 	return listQuery{
-		Limit:       limit,
-		Offset:      offset,
-		Keyword:     keyword,
-		GroupByDate: groupByDate,
-		SortBy:      sortBy,
-		SortDir:     sortDir,
+		Limit:       parseQueryInt(c, "limit", 0),
+		Offset:      parseQueryInt(c, "offset", 0),
+		TherapistID: parseQueryInt(c, "therapist_id", 0),
+		Keyword:     queryString(c, "keyword"),
+		GroupByDate: queryString(c, "group_by_date"),
+		SortBy:      queryString(c, "sort"),                      // supported values: full_name, patient_code
+		SortDir:     strings.ToLower(queryString(c, "sort_dir")), // supported values: asc, desc
+		WithCounts:  queryString(c, "with_counts") == "true",
 	}
 }
 
@@ -52,13 +64,15 @@ func applyCreatedAtFilter(query *gorm.DB, groupByDate string) *gorm.DB {
 	default:
 		// If an unknown non-empty value is provided, log it for debugging.
 		if groupByDate != "" {
-			fmt.Printf("applyCreatedAtFilter: unknown group_by_date value: %s\n", groupByDate)
+			config.Logger().Debug("Unknown group_by_date value", "value", groupByDate)
 		}
 	}
 	return query
 }
 
 // getDBOrAbort retrieves the database connection or aborts with an error response.
+// The returned connection is bound to the request context so queries stop when
+// the client disconnects or the request deadline passes.
 // Returns the database connection and true if successful, or nil and false if failed.
 func getDBOrAbort(c *gin.Context) (*gorm.DB, bool) {
 	db := middleware.GetDB(c)
@@ -69,13 +83,44 @@ func getDBOrAbort(c *gin.Context) (*gorm.DB, bool) {
 		})
 		return nil, false
 	}
+	if c.Request != nil {
+		db = db.WithContext(c.Request.Context())
+	}
 	return db, true
 }
 
-func fetchPatients(db *gorm.DB, q listQuery) ([]model.Patient, int64, error) {
-	var patients []model.Patient
+// withTreatmentCounts adds each patient's number of treatments as
+// treatment_count, counted in one grouped join rather than per patient.
+func withTreatmentCounts(query *gorm.DB) *gorm.DB {
+	return query.
+		Joins(`LEFT JOIN (
+			SELECT patient_code, COUNT(*) AS treatment_count
+			FROM treatments
+			WHERE deleted_at IS NULL
+			GROUP BY patient_code
+		) AS treatment_counts ON treatment_counts.patient_code = patients.patient_code`).
+		Select("patients.*, COALESCE(treatment_counts.treatment_count, 0) AS treatment_count")
+}
+
+// applyPatientKeywordFilter matches keyword against a patient's name, code,
+// address or phone number.
+func applyPatientKeywordFilter(query *gorm.DB, keyword string) *gorm.DB {
+	if keyword == "" {
+		return query
+	}
+	kw := "%" + keyword + "%"
+	return query.Where("patients.full_name LIKE ? OR patients.patient_code LIKE ? OR patients.address LIKE ? OR patients.phone_number LIKE ?", kw, kw, kw, kw)
+}
+
+func fetchPatients(db *gorm.DB, q listQuery) ([]model.ListPatientResponse, int64, error) {
+	var patients []model.ListPatientResponse
 	var totalPatient int64
-	query := db
+	// Select explicitly: ListPatientResponse has treatment_count, which only
+	// exists when counts are joined in.
+	query := db.Model(&model.Patient{}).Select("patients.*")
+	if q.WithCounts {
+		query = withTreatmentCounts(query)
+	}
 
 	// Determine order direction safely (only allow asc/desc)
 	orderDir := "ASC"
@@ -99,23 +144,23 @@ func fetchPatients(db *gorm.DB, q listQuery) ([]model.Patient, int64, error) {
 	if q.Offset > 0 {
 		query = query.Offset(q.Offset)
 	}
-	if q.Keyword != "" {
-		kw := "%" + q.Keyword + "%"
-		query = query.Where("full_name LIKE ? OR patient_code LIKE ? OR address LIKE ? OR phone_number LIKE ?", kw, kw, kw, kw)
-	}
+	query = applyPatientKeywordFilter(query, q.Keyword)
 	query = applyCreatedAtFilter(query, q.GroupByDate)
 
 	if err := query.Find(&patients).Error; err != nil {
 		return nil, 0, err
 	}
 
-	db.Model(&model.Patient{}).Count(&totalPatient)
+	countQuery := applyCreatedAtFilter(applyPatientKeywordFilter(db.Model(&model.Patient{}), q.Keyword), q.GroupByDate)
+	if err := countQuery.Count(&totalPatient).Error; err != nil {
+		return nil, 0, err
+	}
 	return patients, totalPatient, nil
 }
 
 // ListPatients godoc
 // @Summary      List all patients
-// @Description  Get a paginated list of patients with optional filtering
+// @Description  Get a paginated list of patients with optional filtering. total counts every patient matching the filters.
 // @Tags         Patient
 // @Accept       json
 // @Produce      json
@@ -127,6 +172,7 @@ func fetchPatients(db *gorm.DB, q listQuery) ([]model.Patient, int64, error) {
 // @Param        group_by_date query string false "Filter by date range (last_2_days, last_3_months, last_6_months)"
 // @Param        sort query string false "Optional sort field: full_name|patient_code"
 // @Param        sort_dir query string false "Optional sort direction: asc|desc"
+// @Param        with_counts query bool false "Include each patient's treatment_count"
 // @Success      200 {object} util.APIResponse{data=object} "Patients retrieved"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
 // @Failure      500 {object} util.APIResponse "Server error"
@@ -134,12 +180,8 @@ func fetchPatients(db *gorm.DB, q listQuery) ([]model.Patient, int64, error) {
 func ListPatients(c *gin.Context) {
 	query := parseQueryParams(c)
 
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{
-			Msg: "Database connection not available",
-			Err: fmt.Errorf("db is nil"),
-		})
+	db, ok := getDBOrAbort(c)
+	if !ok {
 		return
 	}
 
@@ -154,7 +196,7 @@ func ListPatients(c *gin.Context) {
 
 	util.CallSuccessOK(c, util.APISuccessParams{
 		Msg:  "Patients retrieved",
-		Data: map[string]interface{}{"total": totalPatient, "total_fetched": len(patients), "patients": patients},
+		Data: util.NewPageResponse(patients, totalPatient, len(patients), util.OffsetHasMore(query.Offset, len(patients), totalPatient), nil).Named("patients"),
 	})
 }
 
@@ -170,6 +212,8 @@ type createPatientRequest struct {
 	PatientCode    string   `json:"patient_code" example:"J001"`
 	Password       string   `json:"password,omitempty" example:"password123"`
 	Email          string   `json:"email,omitempty" example:"john@example.com"`
+	Latitude       *float64 `json:"latitude,omitempty" example:"-6.2088"`
+	Longitude      *float64 `json:"longitude,omitempty" example:"106.8456"`
 }
 
 func normalizePhoneNumbers(numbers []string) []string {
@@ -221,16 +265,10 @@ func buildPatientCode(tx *gorm.DB, fullName, requestedCode string) (string, erro
 	}
 
 	initials := getInitials(fullName)
-	var patientCodeTable model.PatientCode
-	if err := tx.Order("id DESC").Where("alphabet = ?", initials).First(&patientCodeTable).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return "", fmt.Errorf("patient code not found")
-		}
+	newNumber, patientCode, err := peekNextPatientCode(tx, initials)
+	if err != nil {
 		return "", err
 	}
-
-	newNumber := patientCodeTable.Number + 1
-	patientCode := fmt.Sprintf("%s%d", initials, newNumber)
 	if err := tx.Where("alphabet = ?", initials).Updates(&model.PatientCode{
 		Number:   newNumber,
 		Alphabet: initials,
@@ -242,9 +280,29 @@ func buildPatientCode(tx *gorm.DB, fullName, requestedCode string) (string, erro
 	return patientCode, nil
 }
 
+// errPatientCodeSequenceNotFound is returned when no PatientCode row exists for an initial.
+var errPatientCodeSequenceNotFound = errors.New("patient code not found")
+
+// peekNextPatientCode returns the number and code the next patient with the
+// given initial would receive, without advancing the sequence.
+func peekNextPatientCode(db *gorm.DB, initials string) (int, string, error) {
+	var patientCodeTable model.PatientCode
+	if err := db.Order("id DESC").Where("alphabet = ?", initials).First(&patientCodeTable).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return 0, "", errPatientCodeSequenceNotFound
+		}
+		return 0, "", err
+	}
+
+	newNumber := patientCodeTable.Number + 1
+	return newNumber, fmt.Sprintf("%s%d", initials, newNumber), nil
+}
+
+// ensurePatientCodeAvailable fails when a patient already has patientCode,
+// ignoring case so codes stored before normalization still count.
 func ensurePatientCodeAvailable(tx *gorm.DB, patientCode string) error {
 	var existing model.Patient
-	if err := tx.Where("patient_code = ?", patientCode).First(&existing).Error; err == nil {
+	if err := tx.Where("UPPER(TRIM(patient_code)) = ?", util.NormalizePatientCode(patientCode)).First(&existing).Error; err == nil {
 		return fmt.Errorf("patient_code already registered")
 	} else if err != gorm.ErrRecordNotFound {
 		return err
@@ -299,6 +357,8 @@ func buildPatientModel(req createPatientRequest, patientCode string, phoneNumber
 		SurgeryHistory: req.SurgeryHistory,
 		Email:          req.Email,
 		Password:       util.HashPassword(req.Password),
+		Latitude:       req.Latitude,
+		Longitude:      req.Longitude,
 	}
 }
 
@@ -334,12 +394,8 @@ func CreatePatient(c *gin.Context) {
 		return
 	}
 
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{
-			Msg: "Database connection not available",
-			Err: fmt.Errorf("db is nil"),
-		})
+	db, ok := getDBOrAbort(c)
+	if !ok {
 		return
 	}
 
@@ -360,8 +416,9 @@ func CreatePatient(c *gin.Context) {
 		return
 	}
 
-	// Perform creation inside a transaction (extracted)
-	if err := db.Transaction(func(tx *gorm.DB) error {
+	// Perform creation inside a transaction (extracted). Code allocation
+	// updates a shared patient_codes row, so concurrent signups can deadlock.
+	if err := config.WithRetry(db, func(tx *gorm.DB) error {
 		return createPatientInTx(tx, patientRequest, normalizedPhones)
 	}); err != nil {
 		util.CallServerError(c, util.APIErrorParams{
@@ -381,6 +438,7 @@ func CreatePatient(c *gin.Context) {
 // Returns normalized phone numbers or an error when payload is invalid.
 func prepareCreatePatient(req *createPatientRequest) ([]string, error) {
 	req.FullName = util.NormalizeName(req.FullName)
+	req.PatientCode = util.NormalizePatientCode(req.PatientCode)
 	normalizedPhones := normalizePhoneNumbers(req.PhoneNumber)
 	if req.FullName == "" || len(normalizedPhones) == 0 {
 		return nil, fmt.Errorf("invalid payload")
@@ -421,7 +479,7 @@ func createPatientInTx(tx *gorm.DB, req createPatientRequest, normalizedPhones [
 
 // UpdatePatient godoc
 // @Summary      Update patient information
-// @Description  Update an existing patient's information
+// @Description  Update an existing patient's information. When the patient's email belongs to a user account, an email change is applied to that account too, or rejected when PATIENT_USER_EMAIL_SYNC=reject.
 // @Tags         Patient
 // @Accept       json
 // @Produce      json
@@ -430,7 +488,7 @@ func createPatientInTx(tx *gorm.DB, req createPatientRequest, normalizedPhones [
 // @Param        id path string true "Patient ID"
 // @Param        request body model.UpdatePatientRequest true "Updated patient information"
 // @Success      200 {object} util.APIResponse{data=model.Patient} "Patient updated"
-// @Failure      400 {object} util.APIResponse "Invalid request or patient not found"
+// @Failure      400 {object} util.APIResponse "Invalid request, invalid phone numbers, patient not found, or email conflicting with a user account"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /patient/{id} [patch]
@@ -453,11 +511,16 @@ func UpdatePatient(c *gin.Context) {
 		return
 	}
 
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{
-			Msg: "Database connection not available",
-			Err: fmt.Errorf("db is nil"),
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	if invalid := invalidPhoneNumbers(req.PhoneNumber); len(invalid) > 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg:  "Invalid phone number",
+			Err:  fmt.Errorf("%d phone number(s) failed validation", len(invalid)),
+			Data: map[string]interface{}{"invalid_phone_numbers": invalid},
 		})
 		return
 	}
@@ -467,15 +530,24 @@ func UpdatePatient(c *gin.Context) {
 		return
 	}
 
+	oldEmail := existingPatient.Email
 	mergeUpdatePatient(&existingPatient, req)
 
-	if err := db.Save(&existingPatient).Error; err != nil {
+	linkedUser, ok := linkedUserForEmailChange(c, db, oldEmail, existingPatient.Email)
+	if !ok {
+		return
+	}
+
+	if err := savePatientAndLinkedUser(db, &existingPatient, linkedUser); err != nil {
 		util.CallServerError(c, util.APIErrorParams{
 			Msg: "Failed to update patient",
 			Err: err,
 		})
 		return
 	}
+	if linkedUser != nil {
+		util.UserEmailCacheSet(linkedUser.ID, linkedUser.Email)
+	}
 
 	util.CallSuccessOK(c, util.APISuccessParams{
 		Msg:  "Patient updated",
@@ -483,6 +555,45 @@ func UpdatePatient(c *gin.Context) {
 	})
 }
 
+// defaultPhonePattern accepts an optional leading "+" followed by 6-20 digits,
+// spaces or dashes, starting with a digit.
+const defaultPhonePattern = `^\+?[0-9][0-9 -]{5,19}$`
+
+// PhoneNumberError describes a phone number rejected by validation.
+type PhoneNumberError struct {
+	PhoneNumber string `json:"phone_number" example:"08-abc"`
+	Error       string `json:"error" example:"does not match the expected phone number format"`
+}
+
+// phoneNumberPattern returns the pattern configured via PATIENT_PHONE_PATTERN,
+// falling back to defaultPhonePattern when unset or not a valid expression.
+func phoneNumberPattern() *regexp.Regexp {
+	if custom := os.Getenv("PATIENT_PHONE_PATTERN"); custom != "" {
+		if re, err := regexp.Compile(custom); err == nil {
+			return re
+		}
+		config.Logger().Warn("Invalid PATIENT_PHONE_PATTERN, using default", "pattern", custom)
+	}
+	return regexp.MustCompile(defaultPhonePattern)
+}
+
+// invalidPhoneNumbers validates each normalized phone number and returns one
+// error entry per number that does not match the configured pattern.
+func invalidPhoneNumbers(phones []string) []PhoneNumberError {
+	normalized := normalizePhoneNumbers(phones)
+	if len(normalized) == 0 {
+		return nil
+	}
+	pattern := phoneNumberPattern()
+	var invalid []PhoneNumberError
+	for _, p := range normalized {
+		if !pattern.MatchString(p) {
+			invalid = append(invalid, PhoneNumberError{PhoneNumber: p, Error: "does not match the expected phone number format"})
+		}
+	}
+	return invalid
+}
+
 // mergeUpdatePatient merges non-zero/empty fields from req into existing.
 func mergeUpdatePatient(existing *model.Patient, req model.UpdatePatientRequest) {
 	updatePatientPhones(existing, req.PhoneNumber)
@@ -512,8 +623,8 @@ func updatePatientIdentity(existing *model.Patient, req model.UpdatePatientReque
 	if req.FullName != "" {
 		existing.FullName = util.NormalizeName(req.FullName)
 	}
-	if req.PatientCode != "" {
-		existing.PatientCode = req.PatientCode
+	if code := util.NormalizePatientCode(req.PatientCode); code != "" {
+		existing.PatientCode = code
 	}
 	if req.Email != "" {
 		existing.Email = req.Email
@@ -540,6 +651,12 @@ func updatePatientDetails(existing *model.Patient, req model.UpdatePatientReques
 	if req.SurgeryHistory != "" {
 		existing.SurgeryHistory = req.SurgeryHistory
 	}
+	if req.Latitude != nil {
+		existing.Latitude = req.Latitude
+	}
+	if req.Longitude != nil {
+		existing.Longitude = req.Longitude
+	}
 }
 
 // updatePatientPassword handles password hashing and update
@@ -596,12 +713,8 @@ func getPatientByID(c *gin.Context, db *gorm.DB) (string, model.Patient, error)
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /patient/{id} [delete]
 func DeletePatient(c *gin.Context) {
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{
-			Msg: "Database connection not available",
-			Err: fmt.Errorf("db is nil"),
-		})
+	db, ok := getDBOrAbort(c)
+	if !ok {
 		return
 	}
 
@@ -625,35 +738,152 @@ func DeletePatient(c *gin.Context) {
 
 // GetPatientInfo godoc
 // @Summary      Get patient information
-// @Description  Get detailed information about a specific patient
+// @Description  Get detailed information about a specific patient, including the name of their preferred therapist when one is set
 // @Tags         Patient
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Security     SessionToken
 // @Param        id path string true "Patient ID"
-// @Success      200 {object} util.APIResponse{data=model.Patient} "Patient retrieved"
+// @Success      200 {object} util.APIResponse{data=model.PatientInfo} "Patient retrieved"
 // @Failure      400 {object} util.APIResponse "Patient not found"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /patient/{id} [get]
 func GetPatientInfo(c *gin.Context) {
-	db := middleware.GetDB(c)
-	if db == nil {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, patient, err := getPatientByID(c, db)
+	if err != nil {
+		return
+	}
+
+	info, err := patientInfo(db, patient)
+	if err != nil {
 		util.CallServerError(c, util.APIErrorParams{
-			Msg: "Database connection not available",
-			Err: fmt.Errorf("db is nil"),
+			Msg: "Failed to retrieve preferred therapist",
+			Err: err,
 		})
 		return
 	}
 
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Patient retrieved",
+		Data: info,
+	})
+}
+
+// canViewPatient reports whether the authenticated caller may read the given
+// patient's records: admins always can, other users only when their account
+// email matches the patient's email.
+func canViewPatient(c *gin.Context, db *gorm.DB, patient model.Patient) (bool, error) {
+	if roleID, ok := middleware.GetRoleID(c); ok && roleID == model.RoleAdmin {
+		return true, nil
+	}
+	userID, ok := middleware.GetUserID(c)
+	if !ok || patient.Email == "" {
+		return false, nil
+	}
+	var user model.User
+	if err := db.Select("id, email").First(&user, userID).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return strings.EqualFold(strings.TrimSpace(user.Email), strings.TrimSpace(patient.Email)), nil
+}
+
+// buildTreatmentSummary loads a patient's treatments ordered chronologically
+// and computes the totals shown on the printable summary.
+func buildTreatmentSummary(db *gorm.DB, patient model.Patient) (model.PatientTreatmentSummary, error) {
+	items := []model.TreatmentSummaryItem{}
+	err := db.Table("treatments").
+		Select("treatments.treatment_date, treatments.issues, treatments.treatment, treatments.remarks, treatments.next_visit, therapists.full_name as therapist_name").
+		Joins("LEFT JOIN therapists ON therapists.id = treatments.therapist_id").
+		Where("treatments.patient_code = ? AND treatments.deleted_at IS NULL", patient.PatientCode).
+		Order("treatments.treatment_date ASC, treatments.id ASC").
+		Scan(&items).Error
+	if err != nil {
+		return model.PatientTreatmentSummary{}, err
+	}
+
+	therapists := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if item.TherapistName != "" {
+			therapists[item.TherapistName] = struct{}{}
+		}
+	}
+
+	summary := model.PatientTreatmentSummary{
+		PatientCode:     patient.PatientCode,
+		PatientName:     patient.FullName,
+		TotalTreatments: len(items),
+		TotalTherapists: len(therapists),
+		Treatments:      items,
+	}
+	if len(items) > 0 {
+		summary.FirstTreatmentDate = items[0].TreatmentDate
+		summary.LastTreatmentDate = items[len(items)-1].TreatmentDate
+	}
+	return summary, nil
+}
+
+// GetPatientTreatmentSummary godoc
+// @Summary      Get a patient's treatment summary
+// @Description  Return the patient's treatments ordered by date with therapist names and totals, suitable for a printable report. Admin or the patient's linked user only.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Patient ID"
+// @Success      200 {object} util.APIResponse{data=model.PatientTreatmentSummary} "Treatment summary retrieved"
+// @Failure      400 {object} util.APIResponse "Patient not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{id}/treatment-summary [get]
+func GetPatientTreatmentSummary(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
 	_, patient, err := getPatientByID(c, db)
 	if err != nil {
 		return
 	}
 
+	allowed, err := canViewPatient(c, db, patient)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to verify patient access",
+			Err: err,
+		})
+		return
+	}
+	if !allowed {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{
+			Msg: "Insufficient permissions to access this resource",
+			Err: fmt.Errorf("user is neither admin nor linked to patient"),
+		})
+		return
+	}
+
+	summary, err := buildTreatmentSummary(db, patient)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to build treatment summary",
+			Err: err,
+		})
+		return
+	}
+
 	util.CallSuccessOK(c, util.APISuccessParams{
-		Msg:  "Patient retrieved",
-		Data: patient,
+		Msg:  "Treatment summary retrieved",
+		Data: summary,
 	})
 }
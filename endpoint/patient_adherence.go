@@ -0,0 +1,161 @@
+package endpoint
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultAdherenceToleranceDays = 3
+	maxAdherenceToleranceDays     = 30
+)
+
+// adherenceTolerance reads tolerance_days, the number of days a visit may
+// fall before or after a next visit and still keep it. It defaults to 3.
+func adherenceTolerance(c *gin.Context) (int, error) {
+	s := queryString(c, "tolerance_days")
+	if s == "" {
+		return defaultAdherenceToleranceDays, nil
+	}
+	days, err := strconv.Atoi(s)
+	if err != nil || days < 0 || days > maxAdherenceToleranceDays {
+		return 0, fmt.Errorf("tolerance_days must be between 0 and %d", maxAdherenceToleranceDays)
+	}
+	return days, nil
+}
+
+// scheduledFollowUps returns the distinct (treatment_date, next_visit) pairs
+// of the patient's attended treatments, oldest first. Treatments whose
+// next_visit is not a date, such as "-", scheduled nothing and are skipped.
+func scheduledFollowUps(db *gorm.DB, patientCode string) ([]model.FollowUp, error) {
+	var rows []model.FollowUp
+	err := db.Model(&model.Treatment{}).
+		Distinct("treatment_date", "next_visit").
+		Scopes(attendedStatusScope).
+		Where("patient_code = ?", patientCode).
+		Order("treatment_date ASC, next_visit ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	followUps := make([]model.FollowUp, 0, len(rows))
+	for _, row := range rows {
+		if _, err := time.Parse(cadenceDateLayout, row.NextVisit); err != nil {
+			continue
+		}
+		if _, err := time.Parse(cadenceDateLayout, row.TreatmentDate); err != nil {
+			continue
+		}
+		followUps = append(followUps, row)
+	}
+	return followUps, nil
+}
+
+// computeAdherence marks each follow-up kept when a visit after its treatment
+// falls within toleranceDays of the next visit, pending when that window has
+// not closed by today, and missed otherwise. visits must be sorted.
+func computeAdherence(patientCode string, followUps []model.FollowUp, visits []time.Time, toleranceDays int, today time.Time) model.PatientAdherence {
+	report := model.PatientAdherence{
+		PatientCode:   patientCode,
+		ToleranceDays: toleranceDays,
+		FollowUps:     make([]model.FollowUp, 0, len(followUps)),
+	}
+	tolerance := time.Duration(toleranceDays) * 24 * time.Hour
+	for _, f := range followUps {
+		treated, _ := time.Parse(cadenceDateLayout, f.TreatmentDate)
+		due, _ := time.Parse(cadenceDateLayout, f.NextVisit)
+
+		from := due.Add(-tolerance)
+		if !from.After(treated) {
+			from = treated.AddDate(0, 0, 1)
+		}
+		i := sort.Search(len(visits), func(i int) bool { return !visits[i].Before(from) })
+		switch {
+		case i < len(visits) && !visits[i].After(due.Add(tolerance)):
+			f.Outcome = model.FollowUpKept
+			f.KeptOn = visits[i].Format(cadenceDateLayout)
+			report.Kept++
+		case !today.After(due.Add(tolerance)):
+			f.Outcome = model.FollowUpPending
+			report.Pending++
+		default:
+			f.Outcome = model.FollowUpMissed
+			report.Missed++
+		}
+		report.FollowUps = append(report.FollowUps, f)
+	}
+	if due := report.Kept + report.Missed; due > 0 {
+		report.AdherencePercent = math.Round(float64(report.Kept)/float64(due)*10000) / 100
+	}
+	return report
+}
+
+// GetPatientAdherence godoc
+// @Summary      Get a patient's follow-up adherence
+// @Description  Cross-reference the next_visit recorded on each attended treatment with the patient's later visits. A follow-up is kept when a visit falls within tolerance_days (default 3) of it, pending while that window is still open, and missed otherwise. adherence_percent is kept / (kept + missed) * 100, 0 when nothing is due. Only completed treatments are visits, so a follow-up that is merely booked as a scheduled treatment is not kept.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        code path string true "Patient code"
+// @Param        tolerance_days query int false "Days a visit may be early or late (0-30)" default(3)
+// @Success      200 {object} util.APIResponse{data=model.PatientAdherence} "Adherence retrieved"
+// @Failure      400 {object} util.APIResponse "Missing patient code or invalid tolerance"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Patient not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{code}/adherence [get]
+func GetPatientAdherence(c *gin.Context) {
+	tolerance, err := adherenceTolerance(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid tolerance",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	patient, ok := getPatientByCodeParam(c, db)
+	if !ok {
+		return
+	}
+
+	followUps, err := scheduledFollowUps(db, patient.PatientCode)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve treatment history",
+			Err: err,
+		})
+		return
+	}
+	visits, err := attendedVisitDates(db, patient.PatientCode)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve treatment history",
+			Err: err,
+		})
+		return
+	}
+
+	now := clinicWallClock(time.Now())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Adherence retrieved",
+		Data: computeAdherence(patient.PatientCode, followUps, visits, tolerance, today),
+	})
+}
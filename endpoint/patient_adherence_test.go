@@ -0,0 +1,113 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPatientAdherence(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id/adherence", GetPatientAdherence)
+
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Adherent", PatientCode: "ADH001"}).Error)
+	future := clinicDate(t, 10)
+	treatments := []model.Treatment{
+		// Kept two days late, within the default tolerance.
+		{TreatmentDate: "2025-01-01", NextVisit: "2025-01-08"},
+		// Kept on the day.
+		{TreatmentDate: "2025-01-10", NextVisit: "2025-01-20"},
+		// Missed: the next visit came 12 days late.
+		{TreatmentDate: "2025-01-20", NextVisit: "2025-01-27"},
+		// Missed: the only visit near this date was a no-show.
+		{TreatmentDate: "2025-02-08", NextVisit: "2025-02-15"},
+		{TreatmentDate: "2025-02-15", NextVisit: "-", Status: model.TreatmentStatusNoShow},
+		// Kept.
+		{TreatmentDate: "2025-03-01", NextVisit: "2025-03-08"},
+		// No next visit recorded, nothing scheduled.
+		{TreatmentDate: "2025-03-08", NextVisit: "-"},
+		// Not due yet.
+		{TreatmentDate: clinicDate(t, 0), NextVisit: future},
+	}
+	for i := range treatments {
+		treatments[i].PatientCode, treatments[i].TherapistID, treatments[i].Issues, treatments[i].Treatment = "ADH001", 1, "-", "-"
+		if treatments[i].Status == "" {
+			treatments[i].Status = model.TreatmentStatusCompleted
+		}
+		assert.NoError(t, db.Create(&treatments[i]).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/ADH001/adherence"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	var resp struct {
+		Data model.PatientAdherence `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	got := resp.Data
+	assert.Equal(t, "ADH001", got.PatientCode)
+	assert.Equal(t, defaultAdherenceToleranceDays, got.ToleranceDays)
+	assert.Equal(t, 3, got.Kept)
+	assert.Equal(t, 2, got.Missed)
+	assert.Equal(t, 1, got.Pending)
+	assert.Equal(t, 60.0, got.AdherencePercent)
+	if assert.Len(t, got.FollowUps, 6) {
+		assert.Equal(t, model.FollowUp{TreatmentDate: "2025-01-01", NextVisit: "2025-01-08", Outcome: model.FollowUpKept, KeptOn: "2025-01-10"}, got.FollowUps[0])
+		assert.Equal(t, model.FollowUpMissed, got.FollowUps[2].Outcome)
+		assert.Equal(t, model.FollowUpPending, got.FollowUps[5].Outcome)
+	}
+
+	// A wider tolerance turns the late visit into a kept follow-up.
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/ADH001/adherence?tolerance_days=12"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 4, resp.Data.Kept)
+	assert.Equal(t, 1, resp.Data.Missed)
+	assert.Equal(t, 80.0, resp.Data.AdherencePercent)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/ADH001/adherence?tolerance_days=-1"})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/NOPE/adherence"})
+	assertStatusWithError(t, w, http.StatusNotFound, err)
+}
+
+func TestGetPatientAdherence_ScheduledVisitDoesNotKeepFollowUp(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id/adherence", GetPatientAdherence)
+
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Booked", PatientCode: "ADH003"}).Error)
+	treatments := []model.Treatment{
+		{TreatmentDate: "2025-01-01", NextVisit: "2025-01-08", Status: model.TreatmentStatusCompleted},
+		// The only later visit was booked but never attended.
+		{TreatmentDate: "2025-01-08", NextVisit: "2025-01-15", Status: model.TreatmentStatusScheduled},
+	}
+	for i := range treatments {
+		treatments[i].PatientCode, treatments[i].TherapistID, treatments[i].Issues, treatments[i].Treatment = "ADH003", 1, "-", "-"
+		assert.NoError(t, db.Create(&treatments[i]).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/ADH003/adherence"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	var resp struct {
+		Data model.PatientAdherence `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 0, resp.Data.Kept)
+	assert.Equal(t, 1, resp.Data.Missed)
+	if assert.Len(t, resp.Data.FollowUps, 1, "scheduled treatments schedule no follow-up of their own") {
+		assert.Equal(t, model.FollowUpMissed, resp.Data.FollowUps[0].Outcome)
+	}
+}
+
+func TestComputeAdherence_NothingDue(t *testing.T) {
+	today := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	followUps := []model.FollowUp{{TreatmentDate: "2025-01-09", NextVisit: "2025-01-16"}}
+
+	got := computeAdherence("ADH002", followUps, nil, 3, today)
+	assert.Equal(t, 0, got.Kept+got.Missed)
+	assert.Equal(t, 1, got.Pending)
+	assert.Zero(t, got.AdherencePercent)
+}
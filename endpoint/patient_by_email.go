@@ -0,0 +1,73 @@
+package endpoint
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findPatientByEmail returns the patient whose email matches email ignoring
+// case and surrounding whitespace, the oldest one if several share it.
+func findPatientByEmail(db *gorm.DB, email string) (model.Patient, error) {
+	var patient model.Patient
+	err := db.Where("LOWER(TRIM(email)) = ?", strings.ToLower(strings.TrimSpace(email))).
+		Order("id ASC").
+		First(&patient).Error
+	return patient, err
+}
+
+// GetPatientByEmail godoc
+// @Summary      Look up a patient by email
+// @Description  Get the patient whose email matches, ignoring case and surrounding whitespace. When several patients share the email the oldest is returned.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        email query string true "Patient email"
+// @Success      200 {object} util.APIResponse{data=model.Patient} "Patient retrieved"
+// @Failure      400 {object} util.APIResponse "Missing email"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Patient not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/by-email [get]
+func GetPatientByEmail(c *gin.Context) {
+	email := queryString(c, "email")
+	if email == "" {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Missing email",
+			Err: errors.New("email is required"),
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	patient, err := findPatientByEmail(db, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallErrorNotFound(c, util.APIErrorParams{
+				Msg: "Patient not found",
+				Err: err,
+			})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve patient",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Patient retrieved",
+		Data: patient,
+	})
+}
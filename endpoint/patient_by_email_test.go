@@ -0,0 +1,36 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPatientByEmail(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/by-email", GetPatientByEmail)
+
+	jane := model.Patient{FullName: "Jane Doe", PatientCode: "EML1", Email: " Jane.Doe@Example.com"}
+	assert.NoError(t, db.Create(&jane).Error)
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Other", PatientCode: "EML2", Email: "other@example.com"}).Error)
+
+	get := func(email string, wantStatus int) model.Patient {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/by-email?email=" + url.QueryEscape(email)})
+		assertStatusWithError(t, w, wantStatus, err)
+		var resp struct {
+			Data model.Patient `json:"data"`
+		}
+		_ = json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.Data
+	}
+
+	assert.Equal(t, jane.ID, get("jane.doe@example.com", http.StatusOK).ID)
+	assert.Equal(t, jane.ID, get("JANE.DOE@EXAMPLE.COM ", http.StatusOK).ID)
+	get("JOHN.DOE@EXAMPLE.COM", http.StatusNotFound)
+	get("", http.StatusBadRequest)
+}
@@ -0,0 +1,76 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+// PreviewNextPatientCode godoc
+// @Summary      Preview the next patient code
+// @Description  Return the patient code that would be allocated to a new patient with the given name, without consuming the sequence. The initial is the first character of the name, as CreatePatient allocates it; names that do not start with a letter A-Z are rejected because no sequence exists for them. The actual code may differ if another patient with the same initial is created first.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        name query string true "Patient full name"
+// @Success      200 {object} util.APIResponse{data=model.PatientCodePreview} "Next patient code"
+// @Failure      400 {object} util.APIResponse "Missing name or name not starting with A-Z"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "No patient code sequence for the initial"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient-code/next [get]
+func PreviewNextPatientCode(c *gin.Context) {
+	name := util.NormalizeName(c.Query("name"))
+	if name == "" {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Missing name",
+			Err: fmt.Errorf("name query parameter is required"),
+		})
+		return
+	}
+
+	initial := getInitials(name)
+	if len(initial) != 1 || initial[0] < 'A' || initial[0] > 'Z' {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Name must start with a letter A-Z",
+			Err: fmt.Errorf("cannot derive a patient code initial from %q", name),
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	number, code, err := peekNextPatientCode(db, initial)
+	if err != nil {
+		if errors.Is(err, errPatientCodeSequenceNotFound) {
+			util.CallErrorNotFound(c, util.APIErrorParams{
+				Msg: "Patient code sequence not found",
+				Err: err,
+			})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to preview patient code",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg: "Next patient code",
+		Data: model.PatientCodePreview{
+			Name:    name,
+			Initial: initial,
+			Number:  number,
+			Code:    code,
+		},
+	})
+}
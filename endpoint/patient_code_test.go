@@ -0,0 +1,56 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreviewNextPatientCode(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient-code/next", PreviewNextPatientCode)
+	assert.NoError(t, db.Create(&model.PatientCode{Alphabet: "J", Number: 41, Code: "J41"}).Error)
+	assert.NoError(t, db.Create(&model.PatientCode{Alphabet: "A", Number: 0, Code: "A0"}).Error)
+
+	cases := []struct {
+		name    string
+		status  int
+		initial string
+		code    string
+	}{
+		{name: "John Doe", status: http.StatusOK, initial: "J", code: "J42"},
+		{name: "  john   doe ", status: http.StatusOK, initial: "J", code: "J42"},
+		{name: "anna", status: http.StatusOK, initial: "A", code: "A1"},
+		{name: "'Ana", status: http.StatusBadRequest},
+		{name: "1 John", status: http.StatusBadRequest},
+		{name: "Élodie", status: http.StatusBadRequest},
+		{name: "Zed", status: http.StatusNotFound},
+		{name: "#42", status: http.StatusBadRequest},
+		{name: "   ", status: http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient-code/next?name=" + url.QueryEscape(tc.name)})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, w.Code, w.Body.String())
+			if tc.status != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Data model.PatientCodePreview `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tc.initial, resp.Data.Initial)
+			assert.Equal(t, tc.code, resp.Data.Code)
+		})
+	}
+
+	// Previewing must not consume the sequence.
+	var seq model.PatientCode
+	assert.NoError(t, db.Where("alphabet = ?", "J").First(&seq).Error)
+	assert.Equal(t, 41, seq.Number)
+}
@@ -240,3 +240,43 @@ func TestCreatePatient_DuplicateDetectionWithWhitespace(t *testing.T) {
 	}
 	assertDuplicateResponse(t, rr3, "Patient already exists")
 }
+
+func TestCreatePatient_NormalizesPatientCode(t *testing.T) {
+	cfg, db := setupTestEnv(t, testSetupParams{
+		secret: "test-secret",
+	})
+	cleanupTestData(t, db)
+
+	if err := model.SeedRoles(db); err != nil {
+		t.Fatalf("seed roles: %v", err)
+	}
+
+	r := setupTestRouter(cfg, db)
+
+	patientBody := map[string]interface{}{
+		"full_name":    "Case Patient",
+		"patient_code": " c77 ",
+		"email":        "case@example.com",
+		"phone_number": []string{"081400000"},
+	}
+	rr, err := sendPatientRequest(r, patientBody)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	assertResponseStatus(t, rr, http.StatusOK, "expected 200 OK, got %d (expected %d): %s")
+	if p := assertPatientExists(t, db, "case@example.com"); p.PatientCode != "C77" {
+		t.Fatalf("expected patient_code C77, got %q", p.PatientCode)
+	}
+
+	// A code differing only in case is already taken.
+	patientBody["full_name"] = "Other Patient"
+	patientBody["email"] = "other@example.com"
+	patientBody["patient_code"] = "C77"
+	rr, err = sendPatientRequest(r, patientBody)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if rr.Code == http.StatusOK || !strings.Contains(rr.Body.String(), "patient_code already registered") {
+		t.Fatalf("expected patient_code to be rejected as taken, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
@@ -0,0 +1,115 @@
+package endpoint
+
+import (
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// temporaryPasswordLength is the length of passwords generated when a
+// patient's credentials are re-sent.
+const temporaryPasswordLength = 12
+
+// resetPatientCredentials gives the patient's linked user a new generated
+// password and revokes the user's sessions in one transaction. The patient
+// row keeps a hash of the same password, as it does on creation.
+func resetPatientCredentials(db *gorm.DB, patient model.Patient, user model.User) (model.PatientCredentials, error) {
+	password, err := util.GenerateTemporaryPassword(temporaryPasswordLength)
+	if err != nil {
+		return model.PatientCredentials{}, err
+	}
+	if err := hashUserPassword(&user, password); err != nil {
+		return model.PatientCredentials{}, err
+	}
+	user.FailedAttempts = 0
+	user.LockedUntil = nil
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&patient).Update("password", util.HashPassword(password)).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", user.ID).Delete(&model.Session{}).Error
+	})
+	if err != nil {
+		return model.PatientCredentials{}, err
+	}
+
+	return model.PatientCredentials{
+		PatientID:         patient.ID,
+		UserID:            user.ID,
+		Email:             user.Email,
+		TemporaryPassword: password,
+	}, nil
+}
+
+// ResendPatientCredentials godoc
+// @Summary      Re-send a patient's credentials
+// @Description  Reset the password of the user account linked to a patient (matched by email) to a new generated value, revoke the user's sessions and return the temporary password so it can be passed on to the patient. Fails when the patient has no linked user.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Patient ID"
+// @Success      200 {object} util.APIResponse{data=model.PatientCredentials} "Patient credentials reset"
+// @Failure      400 {object} util.APIResponse "Patient not found or has no linked user"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{id}/resend-credentials [post]
+func ResendPatientCredentials(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, patient, err := getPatientByID(c, db)
+	if err != nil {
+		return
+	}
+
+	user, err := findUserByEmail(db, patient.Email)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to look up linked user",
+			Err: err,
+		})
+		return
+	}
+	if user == nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Patient has no linked user account",
+			Err: fmt.Errorf("no user with email %q", patient.Email),
+		})
+		return
+	}
+
+	credentials, err := resetPatientCredentials(db, patient, *user)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to reset patient credentials",
+			Err: err,
+		})
+		return
+	}
+	_ = util.InvalidateUserSessions(user.ID)
+
+	util.LogSecurityEvent(util.SecurityEvent{
+		EventType: util.EventPasswordChanged,
+		UserID:    fmt.Sprintf("%d", user.ID),
+		Email:     user.Email,
+		IP:        c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Message:   "Patient credentials reset by admin",
+	})
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Patient credentials reset",
+		Data: credentials,
+	})
+}
@@ -0,0 +1,76 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResendPatientCredentials_ResetsPassword(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	user := model.User{Name: "Linked Patient", Email: "linked.patient@test.com", Password: util.HashPassword("old-password"), RoleID: model.RoleUser, FailedAttempts: 3}
+	assert.NoError(t, db.Create(&user).Error)
+	patient := model.Patient{FullName: "Linked Patient", PatientCode: "L1", Email: "Linked.Patient@test.com", Password: util.HashPassword("old-password")}
+	assert.NoError(t, db.Create(&patient).Error)
+	assert.NoError(t, db.Create(&model.Session{UserID: user.ID, SessionToken: "resend-creds-token", ExpiresAt: time.Now().Add(time.Hour)}).Error)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPost, registerPath: "/patient/:id/resend-credentials", requestPath: fmt.Sprintf("/patient/%d/resend-credentials", patient.ID), handler: ResendPatientCredentials})
+	assert.NoError(t, err)
+	if !assert.Equal(t, http.StatusOK, w.Code, w.Body.String()) {
+		return
+	}
+
+	var resp struct {
+		Data model.PatientCredentials `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	creds := resp.Data
+	assert.Equal(t, patient.ID, creds.PatientID)
+	assert.Equal(t, user.ID, creds.UserID)
+	assert.Equal(t, user.Email, creds.Email)
+	assert.Len(t, creds.TemporaryPassword, temporaryPasswordLength)
+
+	var updated model.User
+	assert.NoError(t, db.First(&updated, user.ID).Error)
+	ok, err := util.VerifyPassword(creds.TemporaryPassword, updated.Password, updated.PasswordSalt)
+	assert.NoError(t, err)
+	assert.True(t, ok, "the temporary password should log the user in")
+	ok, _ = util.VerifyPassword("old-password", updated.Password, updated.PasswordSalt)
+	assert.False(t, ok, "the old password should no longer work")
+	assert.Zero(t, updated.FailedAttempts)
+
+	var storedPatient model.Patient
+	assert.NoError(t, db.First(&storedPatient, patient.ID).Error)
+	assert.Equal(t, util.HashPassword(creds.TemporaryPassword), storedPatient.Password)
+
+	var sessions int64
+	assert.NoError(t, db.Model(&model.Session{}).Where("user_id = ?", user.ID).Count(&sessions).Error)
+	assert.Zero(t, sessions)
+}
+
+func TestResendPatientCredentials_RejectsPatientWithoutUser(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	withoutUser := model.Patient{FullName: "No Account", PatientCode: "N1", Email: "no.account@test.com"}
+	withoutEmail := model.Patient{FullName: "No Email", PatientCode: "N2"}
+	assert.NoError(t, db.Create(&withoutUser).Error)
+	assert.NoError(t, db.Create(&withoutEmail).Error)
+
+	r.POST("/patient/:id/resend-credentials", ResendPatientCredentials)
+	for _, id := range []uint{withoutUser.ID, withoutEmail.ID, 99999} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: fmt.Sprintf("/patient/%d/resend-credentials", id)})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	}
+
+	var users int64
+	assert.NoError(t, db.Model(&model.User{}).Count(&users).Error)
+	assert.Zero(t, users)
+}
@@ -0,0 +1,167 @@
+package endpoint
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	duplicateMatchName  = "full_name"
+	duplicateMatchPhone = "phone_number"
+)
+
+// patientUnionFind groups patient indexes that were matched on any field.
+type patientUnionFind struct {
+	parent []int
+}
+
+func newPatientUnionFind(n int) *patientUnionFind {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &patientUnionFind{parent: parent}
+}
+
+func (u *patientUnionFind) find(i int) int {
+	for u.parent[i] != i {
+		u.parent[i] = u.parent[u.parent[i]]
+		i = u.parent[i]
+	}
+	return i
+}
+
+func (u *patientUnionFind) union(a, b int) {
+	ra, rb := u.find(a), u.find(b)
+	if ra == rb {
+		return
+	}
+	if ra < rb {
+		u.parent[rb] = ra
+		return
+	}
+	u.parent[ra] = rb
+}
+
+type duplicateEdge struct {
+	a, b   int
+	reason string
+}
+
+// duplicateNameKey normalizes a full name the same way patient creation does,
+// ignoring case so "john  doe" and "John Doe" match.
+func duplicateNameKey(fullName string) string {
+	return strings.ToLower(util.NormalizeName(fullName))
+}
+
+// collectDuplicateEdges links each patient to the first patient seen with the
+// same normalized name or any shared phone number.
+func collectDuplicateEdges(patients []model.Patient) []duplicateEdge {
+	var edges []duplicateEdge
+	byName := make(map[string]int)
+	byPhone := make(map[string]int)
+	for i, p := range patients {
+		if key := duplicateNameKey(p.FullName); key != "" {
+			if j, ok := byName[key]; ok {
+				edges = append(edges, duplicateEdge{a: j, b: i, reason: duplicateMatchName})
+			} else {
+				byName[key] = i
+			}
+		}
+		for _, phone := range normalizePhoneNumbers(strings.Split(p.PhoneNumber, ",")) {
+			if j, ok := byPhone[phone]; ok {
+				edges = append(edges, duplicateEdge{a: j, b: i, reason: duplicateMatchPhone})
+				continue
+			}
+			byPhone[phone] = i
+		}
+	}
+	return edges
+}
+
+// groupDuplicatePatients returns groups of two or more patients sharing a
+// normalized full name or at least one phone number. Matches are transitive,
+// so A~B by name and B~C by phone puts A, B and C in one group.
+func groupDuplicatePatients(patients []model.Patient) []model.PatientDuplicateGroup {
+	uf := newPatientUnionFind(len(patients))
+	edges := collectDuplicateEdges(patients)
+	for _, e := range edges {
+		uf.union(e.a, e.b)
+	}
+
+	reasons := make(map[int]map[string]struct{})
+	for _, e := range edges {
+		root := uf.find(e.a)
+		if reasons[root] == nil {
+			reasons[root] = make(map[string]struct{})
+		}
+		reasons[root][e.reason] = struct{}{}
+	}
+
+	members := make(map[int][]model.Patient)
+	var roots []int
+	for i, p := range patients {
+		root := uf.find(i)
+		if _, ok := reasons[root]; !ok {
+			continue
+		}
+		if _, seen := members[root]; !seen {
+			roots = append(roots, root)
+		}
+		members[root] = append(members[root], p)
+	}
+
+	groups := make([]model.PatientDuplicateGroup, 0, len(roots))
+	for _, root := range roots {
+		matched := make([]string, 0, len(reasons[root]))
+		for r := range reasons[root] {
+			matched = append(matched, r)
+		}
+		sort.Strings(matched)
+		groups = append(groups, model.PatientDuplicateGroup{MatchedOn: matched, Patients: members[root]})
+	}
+	return groups
+}
+
+func fetchDuplicatePatientGroups(db *gorm.DB) ([]model.PatientDuplicateGroup, error) {
+	var patients []model.Patient
+	if err := db.Order("id ASC").Find(&patients).Error; err != nil {
+		return nil, err
+	}
+	return groupDuplicatePatients(patients), nil
+}
+
+// ListDuplicatePatients godoc
+// @Summary      List duplicate patient candidates
+// @Description  Get groups of patients sharing a normalized full name or a phone number so staff can review and merge them
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=object} "Duplicate patient candidates retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/duplicates [get]
+func ListDuplicatePatients(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	groups, err := fetchDuplicatePatientGroups(db)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to find duplicate patients", Err: err})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Duplicate patient candidates retrieved",
+		Data: map[string]interface{}{"total": len(groups), "groups": groups},
+	})
+}
@@ -0,0 +1,82 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func patientCodes(group model.PatientDuplicateGroup) []string {
+	codes := make([]string, 0, len(group.Patients))
+	for _, p := range group.Patients {
+		codes = append(codes, p.PatientCode)
+	}
+	return codes
+}
+
+func TestGroupDuplicatePatients(t *testing.T) {
+	patients := []model.Patient{
+		{FullName: "John Doe", PhoneNumber: "0811", PatientCode: "A"},
+		{FullName: "  john   DOE ", PhoneNumber: "0822", PatientCode: "B"},
+		{FullName: "Jane Roe", PhoneNumber: "0833, 0844", PatientCode: "C"},
+		{FullName: "J. Roe", PhoneNumber: "0844", PatientCode: "D"},
+		{FullName: "Someone Else", PhoneNumber: "0855", PatientCode: "E"},
+		{FullName: "Johnny", PhoneNumber: "0822,0866", PatientCode: "F"},
+	}
+
+	groups := groupDuplicatePatients(patients)
+	if !assert.Len(t, groups, 2) {
+		return
+	}
+
+	// A~B by name and B~F by phone are merged into one group.
+	assert.Equal(t, []string{"A", "B", "F"}, patientCodes(groups[0]))
+	assert.Equal(t, []string{"full_name", "phone_number"}, groups[0].MatchedOn)
+
+	assert.Equal(t, []string{"C", "D"}, patientCodes(groups[1]))
+	assert.Equal(t, []string{"phone_number"}, groups[1].MatchedOn)
+}
+
+func TestGroupDuplicatePatients_NoDuplicates(t *testing.T) {
+	patients := []model.Patient{
+		{FullName: "Alpha", PhoneNumber: "1"},
+		{FullName: "Beta", PhoneNumber: "2"},
+		{FullName: "Gamma", PhoneNumber: ""},
+		{FullName: "Delta", PhoneNumber: ""},
+	}
+	assert.Empty(t, groupDuplicatePatients(patients))
+}
+
+func TestListDuplicatePatients(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	seed := []model.Patient{
+		{FullName: "Budi Santoso", PhoneNumber: "081234", PatientCode: "B001"},
+		{FullName: "budi  santoso", PhoneNumber: "089999", PatientCode: "B002"},
+		{FullName: "Siti Aminah", PhoneNumber: "087777", PatientCode: "S001"},
+	}
+	for i := range seed {
+		assert.NoError(t, db.Create(&seed[i]).Error)
+	}
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/patient/duplicates", requestPath: "/patient/duplicates", handler: ListDuplicatePatients})
+	assert.NoError(t, err)
+	if !assert.Equal(t, http.StatusOK, w.Code, w.Body.String()) {
+		return
+	}
+
+	var resp struct {
+		Data struct {
+			Total  int                           `json:"total"`
+			Groups []model.PatientDuplicateGroup `json:"groups"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Total)
+	if assert.Len(t, resp.Data.Groups, 1) {
+		assert.Equal(t, []string{"B001", "B002"}, patientCodes(resp.Data.Groups[0]))
+		assert.Equal(t, []string{"full_name"}, resp.Data.Groups[0].MatchedOn)
+	}
+}
@@ -0,0 +1,86 @@
+package endpoint
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// inactivePatientsQuery selects patients whose latest visit is before since,
+// including patients with no visit at all. Only completed treatments are
+// visits.
+func inactivePatientsQuery(db *gorm.DB, since string) *gorm.DB {
+	return db.Model(&model.Patient{}).
+		Select("patients.id, patients.patient_code, patients.full_name, patients.email, patients.phone_number, patients.address, COALESCE(MAX(treatments.treatment_date), '') AS last_treatment_date").
+		Joins("LEFT JOIN treatments ON treatments.patient_code = patients.patient_code AND treatments.deleted_at IS NULL AND "+attendedTreatmentCondition,
+			model.TreatmentStatusCompleted).
+		Group("patients.id, patients.patient_code, patients.full_name, patients.email, patients.phone_number, patients.address").
+		Having("MAX(treatments.treatment_date) IS NULL OR MAX(treatments.treatment_date) < ?", since)
+}
+
+// fetchInactivePatients returns one page of inactive patients, those who
+// have never been treated first, and the total number of matches.
+func fetchInactivePatients(db *gorm.DB, since string, limit, offset int) ([]model.InactivePatient, int64, error) {
+	var total int64
+	if err := db.Table("(?) AS inactive", inactivePatientsQuery(db, since)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	patients := []model.InactivePatient{}
+	query := applyPagination(inactivePatientsQuery(db, since).Order("last_treatment_date ASC, patients.id ASC"), limit, offset)
+	if err := query.Scan(&patients).Error; err != nil {
+		return nil, 0, err
+	}
+	return patients, total, nil
+}
+
+// ListInactivePatients godoc
+// @Summary      List inactive patients
+// @Description  Get patients whose most recent treatment is before the given date, or who have never been treated, with their contact details. Only completed treatments count as visits.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        since query string true "Cut-off date (YYYY-MM-DD)"
+// @Param        limit query int false "Limit number of results"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200 {object} util.APIResponse{data=object} "Inactive patients retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid since date"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/inactive [get]
+func ListInactivePatients(c *gin.Context) {
+	since := c.Query("since")
+	if _, err := time.Parse(cadenceDateLayout, since); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid since date, expected YYYY-MM-DD",
+			Err: fmt.Errorf("invalid since %q: %w", since, err),
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	offset := parseQueryInt(c, "offset", 0)
+	patients, total, err := fetchInactivePatients(db, since, parseQueryInt(c, "limit", 0), offset)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve inactive patients",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Inactive patients retrieved",
+		Data: util.NewPageResponse(patients, total, len(patients), util.OffsetHasMore(offset, len(patients), total), nil).Named("patients"),
+	})
+}
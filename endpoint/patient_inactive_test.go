@@ -0,0 +1,94 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type inactivePatientsResponse struct {
+	Data struct {
+		Total        int64                   `json:"total"`
+		TotalFetched int                     `json:"total_fetched"`
+		Patients     []model.InactivePatient `json:"patients"`
+	} `json:"data"`
+}
+
+func seedInactivePatients(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	for _, p := range []model.Patient{
+		{FullName: "Active", PatientCode: "ACT1", Email: "active@test.com"},
+		{FullName: "Lapsed", PatientCode: "LAP1", Email: "lapsed@test.com", PhoneNumber: "0811"},
+		{FullName: "Never Treated", PatientCode: "NEW1", PhoneNumber: "0822"},
+		{FullName: "Missed Only", PatientCode: "MIS1"},
+	} {
+		assert.NoError(t, db.Create(&p).Error)
+	}
+
+	for _, tr := range []struct{ code, date, status string }{
+		{"ACT1", "2024-10-01", model.TreatmentStatusCompleted},
+		{"ACT1", "2025-02-01", model.TreatmentStatusCompleted},
+		{"LAP1", "2024-09-01", model.TreatmentStatusCompleted},
+		{"LAP1", "2024-12-01", model.TreatmentStatusCompleted},
+		// Booked but not attended yet, so LAP1 stays lapsed.
+		{"LAP1", "2025-03-01", model.TreatmentStatusScheduled},
+		{"MIS1", "2024-08-01", model.TreatmentStatusCompleted},
+		{"MIS1", "2025-02-10", model.TreatmentStatusNoShow},
+	} {
+		treatment := model.Treatment{PatientCode: tr.code, TherapistID: 1, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", Status: tr.status}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+}
+
+func TestListInactivePatients_ReturnsLapsedOnly(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	seedInactivePatients(t, db)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/patient/inactive", requestPath: "/patient/inactive?since=2025-01-01", handler: ListInactivePatients})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp inactivePatientsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(3), resp.Data.Total)
+
+	got := map[string]string{}
+	for _, p := range resp.Data.Patients {
+		got[p.PatientCode] = p.LastTreatmentDate
+	}
+	assert.Equal(t, map[string]string{"NEW1": "", "MIS1": "2024-08-01", "LAP1": "2024-12-01"}, got)
+	if assert.Len(t, resp.Data.Patients, 3) {
+		assert.Equal(t, "NEW1", resp.Data.Patients[0].PatientCode)
+		assert.Equal(t, "0822", resp.Data.Patients[0].PhoneNumber)
+		assert.Equal(t, "lapsed@test.com", resp.Data.Patients[2].Email)
+	}
+}
+
+func TestListInactivePatients_Paginates(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	seedInactivePatients(t, db)
+	r.GET("/patient/inactive", ListInactivePatients)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/inactive?since=2025-01-01&limit=2&offset=1"})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp inactivePatientsResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(3), resp.Data.Total)
+	assert.Equal(t, 2, resp.Data.TotalFetched)
+	if assert.Len(t, resp.Data.Patients, 2) {
+		assert.Equal(t, "MIS1", resp.Data.Patients[0].PatientCode)
+		assert.Equal(t, "LAP1", resp.Data.Patients[1].PatientCode)
+	}
+
+	for _, query := range []string{"", "?since=2025-13-01", "?since=yesterday"} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/inactive" + query})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
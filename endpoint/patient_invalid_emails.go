@@ -0,0 +1,77 @@
+package endpoint
+
+import (
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// fetchInvalidEmailPatients returns one page of patients whose email fails
+// util.IsValidEmail, ordered by ID, and the total number of matches. Patients
+// without an email are not listed. The pattern is checked in Go rather than
+// SQL so that it is the same on every database.
+func fetchInvalidEmailPatients(db *gorm.DB, limit, offset int) ([]model.InvalidEmailPatient, int64, error) {
+	var candidates []model.InvalidEmailPatient
+	err := db.Model(&model.Patient{}).
+		Select("id, patient_code, full_name, email, phone_number").
+		Where("email IS NOT NULL AND email <> ''").
+		Order("id ASC").
+		Scan(&candidates).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	invalid := []model.InvalidEmailPatient{}
+	for _, p := range candidates {
+		if !util.IsValidEmail(p.Email) {
+			invalid = append(invalid, p)
+		}
+	}
+
+	total := int64(len(invalid))
+	if int64(offset) >= total {
+		return []model.InvalidEmailPatient{}, total, nil
+	}
+	invalid = invalid[offset:]
+	if limit > 0 && limit < len(invalid) {
+		invalid = invalid[:limit]
+	}
+	return invalid, total, nil
+}
+
+// ListInvalidEmailPatients godoc
+// @Summary      List patients with malformed emails
+// @Description  Get patients whose stored email is not a well-formed address, so it can be corrected before notifications are sent. Patients without an email are not listed.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        limit query int false "Limit number of results"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200 {object} util.APIResponse{data=object} "Patients with invalid emails retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/invalid-emails [get]
+func ListInvalidEmailPatients(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	offset := parseQueryInt(c, "offset", 0)
+	patients, total, err := fetchInvalidEmailPatients(db, parseQueryInt(c, "limit", 0), offset)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve patients with invalid emails",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Patients with invalid emails retrieved",
+		Data: util.NewPageResponse(patients, total, len(patients), util.OffsetHasMore(offset, len(patients), total), nil).Named("patients"),
+	})
+}
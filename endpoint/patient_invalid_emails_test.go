@@ -0,0 +1,59 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type invalidEmailPatientsResponse struct {
+	Data struct {
+		Total        int                         `json:"total"`
+		TotalFetched int                         `json:"total_fetched"`
+		Patients     []model.InvalidEmailPatient `json:"patients"`
+	} `json:"data"`
+}
+
+func TestListInvalidEmailPatients(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/invalid-emails", ListInvalidEmailPatients)
+
+	for _, p := range []model.Patient{
+		{FullName: "Valid", PatientCode: "EM01", Email: "valid@example.com"},
+		{FullName: "No Domain", PatientCode: "EM02", Email: "nodomain@", PhoneNumber: "0811"},
+		{FullName: "No Email", PatientCode: "EM03"},
+		{FullName: "Typo", PatientCode: "EM04", Email: "typo@gmail,com"},
+		{FullName: "Also Valid", PatientCode: "EM05", Email: "also.valid+tag@mail.example.co.id"},
+		{FullName: "No At", PatientCode: "EM06", Email: "no-at.example.com"},
+	} {
+		assert.NoError(t, db.Create(&p).Error)
+	}
+
+	list := func(query string) invalidEmailPatientsResponse {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/invalid-emails" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp invalidEmailPatientsResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := list("")
+	assert.Equal(t, 3, resp.Data.Total)
+	var codes []string
+	for _, p := range resp.Data.Patients {
+		codes = append(codes, p.PatientCode)
+	}
+	assert.Equal(t, []string{"EM02", "EM04", "EM06"}, codes)
+	assert.Equal(t, "nodomain@", resp.Data.Patients[0].Email)
+	assert.Equal(t, "0811", resp.Data.Patients[0].PhoneNumber)
+
+	resp = list("?limit=1&offset=1")
+	assert.Equal(t, 3, resp.Data.Total)
+	if assert.Len(t, resp.Data.Patients, 1) {
+		assert.Equal(t, "EM04", resp.Data.Patients[0].PatientCode)
+	}
+}
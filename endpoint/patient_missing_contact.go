@@ -0,0 +1,67 @@
+package endpoint
+
+import (
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// missingContactPatientsQuery selects patients whose phone number and email
+// are both empty or blank.
+func missingContactPatientsQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.Patient{}).
+		Where("COALESCE(TRIM(phone_number), '') = '' AND COALESCE(TRIM(email), '') = ''")
+}
+
+// fetchMissingContactPatients returns one page of patients without contact
+// details, oldest first, and the total number of matches.
+func fetchMissingContactPatients(db *gorm.DB, limit, offset int) ([]model.MissingContactPatient, int64, error) {
+	var total int64
+	if err := missingContactPatientsQuery(db).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	patients := []model.MissingContactPatient{}
+	query := applyPagination(missingContactPatientsQuery(db).Select("id, patient_code, full_name, address").Order("id ASC"), limit, offset)
+	if err := query.Scan(&patients).Error; err != nil {
+		return nil, 0, err
+	}
+	return patients, total, nil
+}
+
+// ListMissingContactPatients godoc
+// @Summary      List patients missing contact information
+// @Description  Get patients with neither a phone number nor an email, oldest first, for data-quality cleanup. Blank values count as missing.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        limit query int false "Limit number of results"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200 {object} util.APIResponse{data=object} "Patients missing contact information retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/missing-contact [get]
+func ListMissingContactPatients(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	offset := parseQueryInt(c, "offset", 0)
+	patients, total, err := fetchMissingContactPatients(db, parseQueryInt(c, "limit", 0), offset)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve patients missing contact information",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Patients missing contact information retrieved",
+		Data: util.NewPageResponse(patients, total, len(patients), util.OffsetHasMore(offset, len(patients), total), nil).Named("patients"),
+	})
+}
@@ -0,0 +1,58 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type missingContactPatientsResponse struct {
+	Data struct {
+		Total    int                           `json:"total"`
+		HasMore  bool                          `json:"has_more"`
+		Patients []model.MissingContactPatient `json:"patients"`
+	} `json:"data"`
+}
+
+func TestListMissingContactPatients(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/missing-contact", ListMissingContactPatients)
+
+	for _, p := range []model.Patient{
+		{FullName: "Complete", PatientCode: "MC01", Email: "complete@example.com", PhoneNumber: "0811"},
+		{FullName: "No Contact", PatientCode: "MC02", Address: "Jl. Merdeka 1"},
+		{FullName: "Phone Only", PatientCode: "MC03", PhoneNumber: "0812"},
+		{FullName: "Blank Contact", PatientCode: "MC04", Email: "  ", PhoneNumber: " "},
+		{FullName: "Email Only", PatientCode: "MC05", Email: "email@example.com"},
+	} {
+		assert.NoError(t, db.Create(&p).Error)
+	}
+
+	list := func(query string) missingContactPatientsResponse {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/missing-contact" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp missingContactPatientsResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp
+	}
+
+	resp := list("")
+	assert.Equal(t, 2, resp.Data.Total)
+	var codes []string
+	for _, p := range resp.Data.Patients {
+		codes = append(codes, p.PatientCode)
+	}
+	assert.Equal(t, []string{"MC02", "MC04"}, codes)
+	assert.Equal(t, "Jl. Merdeka 1", resp.Data.Patients[0].Address)
+
+	resp = list("?limit=1")
+	assert.Equal(t, 2, resp.Data.Total)
+	assert.True(t, resp.Data.HasMore)
+	if assert.Len(t, resp.Data.Patients, 1) {
+		assert.Equal(t, "MC02", resp.Data.Patients[0].PatientCode)
+	}
+}
@@ -0,0 +1,92 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// clinicWallClock returns now as clinic wall-clock time stored as UTC, the
+// way schedule times are kept.
+func clinicWallClock(now time.Time) time.Time {
+	if loc, err := time.LoadLocation(config.Timezone); err == nil {
+		now = now.In(loc)
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), time.UTC)
+}
+
+// findNextAppointment returns the patient's earliest booked schedule slot
+// starting after now, or gorm.ErrRecordNotFound when none is booked.
+// Cancelled slots are skipped.
+func findNextAppointment(db *gorm.DB, patientCode string, now time.Time) (model.PatientAppointment, error) {
+	var appointments []model.PatientAppointment
+	err := db.Model(&model.Schedule{}).
+		Scopes(model.ActiveSchedules).
+		Select("schedules.id AS schedule_id, schedules.patient_code, schedules.clinic_id, schedules.start_time, schedules.end_time, schedules.therapist_id, COALESCE(therapists.full_name, '') AS therapist_name, COALESCE(therapists.phone_number, '') AS therapist_phone").
+		Joins("LEFT JOIN therapists ON therapists.id = schedules.therapist_id AND therapists.deleted_at IS NULL").
+		Where("schedules.patient_code = ? AND schedules.start_time > ?", patientCode, clinicWallClock(now)).
+		Order("schedules.start_time ASC, schedules.id ASC").
+		Limit(1).
+		Scan(&appointments).Error
+	if err != nil {
+		return model.PatientAppointment{}, err
+	}
+	if len(appointments) == 0 {
+		return model.PatientAppointment{}, gorm.ErrRecordNotFound
+	}
+	return appointments[0], nil
+}
+
+// GetPatientNextAppointment godoc
+// @Summary      Get a patient's next appointment
+// @Description  Return the earliest schedule slot booked for the patient that has not started yet, with the therapist's name and phone number. Schedule times are clinic wall-clock times.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        code path string true "Patient code"
+// @Success      200 {object} util.APIResponse{data=model.PatientAppointment} "Next appointment retrieved"
+// @Failure      400 {object} util.APIResponse "Missing patient code"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Patient not found or no upcoming appointment"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{code}/next-appointment [get]
+func GetPatientNextAppointment(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	patient, ok := getPatientByCodeParam(c, db)
+	if !ok {
+		return
+	}
+
+	appointment, err := findNextAppointment(db, patient.PatientCode, time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		util.CallErrorNotFound(c, util.APIErrorParams{
+			Msg: "No upcoming appointment",
+			Err: fmt.Errorf("no upcoming schedule for patient %s", patient.PatientCode),
+		})
+		return
+	}
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve next appointment",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Next appointment retrieved",
+		Data: appointment,
+	})
+}
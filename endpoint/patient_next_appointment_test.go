@@ -0,0 +1,62 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPatientNextAppointment(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id/next-appointment", GetPatientNextAppointment)
+
+	_ = createPatientIfNotExists(db, t, "NA001", "na001@test.com")
+	_ = createPatientIfNotExists(db, t, "NA002", "na002@test.com")
+	ann := model.Therapist{FullName: "Ann", Email: "ann-na@test.com", PhoneNumber: "0811"}
+	assert.NoError(t, db.Create(&ann).Error)
+
+	now := clinicWallClock(time.Now()).Truncate(time.Hour)
+	slot := func(patientCode string, start time.Time) model.Schedule {
+		s := model.Schedule{TherapistID: ann.ID, PatientCode: patientCode, StartTime: start, EndTime: start.Add(time.Hour)}
+		assert.NoError(t, db.Create(&s).Error)
+		return s
+	}
+	slot("NA001", now.AddDate(0, 0, -2))
+	slot("NA001", now.AddDate(0, 0, 7))
+	nearest := slot("NA001", now.AddDate(0, 0, 2))
+	slot("NA002", now.AddDate(0, 0, 1))
+	slot("", now.AddDate(0, 0, 1)) // open slot
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/NA001/next-appointment"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.PatientAppointment `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, nearest.ID, resp.Data.ScheduleID)
+	assert.Equal(t, "NA001", resp.Data.PatientCode)
+	assert.True(t, nearest.StartTime.Equal(resp.Data.StartTime), "expected %s, got %s", nearest.StartTime, resp.Data.StartTime)
+	assert.Equal(t, ann.ID, resp.Data.TherapistID)
+	assert.Equal(t, "Ann", resp.Data.TherapistName)
+	assert.Equal(t, "0811", resp.Data.TherapistPhone)
+}
+
+func TestGetPatientNextAppointment_NoneScheduled(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id/next-appointment", GetPatientNextAppointment)
+
+	_ = createPatientIfNotExists(db, t, "NA003", "na003@test.com")
+	past := clinicWallClock(time.Now()).AddDate(0, 0, -1)
+	assert.NoError(t, db.Create(&model.Schedule{TherapistID: 1, PatientCode: "NA003", StartTime: past, EndTime: past.Add(time.Hour)}).Error)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/NA003/next-appointment"})
+	assertStatusWithError(t, w, http.StatusNotFound, err)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/MISSING/next-appointment"})
+	assertStatusWithError(t, w, http.StatusNotFound, err)
+}
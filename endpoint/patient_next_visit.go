@@ -0,0 +1,170 @@
+package endpoint
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultNextVisitIntervalDays = 7
+	nextVisitBasisHistory        = "history"
+	nextVisitBasisDefault        = "default"
+)
+
+// nextVisitDefaultInterval is the interval in days used when a patient has
+// fewer than two visits: NEXT_VISIT_DEFAULT_DAYS, or 7 when unset or invalid.
+func nextVisitDefaultInterval() int {
+	if days, err := strconv.Atoi(os.Getenv("NEXT_VISIT_DEFAULT_DAYS")); err == nil && days > 0 {
+		return days
+	}
+	return defaultNextVisitIntervalDays
+}
+
+// medianIntervalDays returns the median gap in days between consecutive
+// visits, which must be sorted and distinct. An even number of gaps averages
+// the middle two, rounded to the nearest day.
+func medianIntervalDays(visits []time.Time) int {
+	gaps := make([]int, 0, len(visits)-1)
+	for i := 1; i < len(visits); i++ {
+		gaps = append(gaps, int(visits[i].Sub(visits[i-1]).Hours()/24))
+	}
+	sort.Ints(gaps)
+	mid := len(gaps) / 2
+	if len(gaps)%2 == 1 {
+		return gaps[mid]
+	}
+	return (gaps[mid-1] + gaps[mid] + 1) / 2
+}
+
+// attendedVisitDates returns the patient's distinct attended treatment dates
+// in ascending order. Only completed treatments are visits, and unparseable
+// dates are skipped.
+func attendedVisitDates(db *gorm.DB, patientCode string) ([]time.Time, error) {
+	var dates []string
+	err := db.Model(&model.Treatment{}).
+		Distinct("treatment_date").
+		Scopes(attendedStatusScope).
+		Where("patient_code = ?", patientCode).
+		Order("treatment_date ASC").
+		Pluck("treatment_date", &dates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	visits := make([]time.Time, 0, len(dates))
+	for _, d := range dates {
+		if t, err := time.Parse(cadenceDateLayout, d); err == nil {
+			visits = append(visits, t)
+		}
+	}
+	return visits, nil
+}
+
+// suggestNextVisit proposes the next visit as the last visit plus the median
+// interval between past visits. With fewer than two visits the default
+// interval is used, counted from the last visit or from today.
+func suggestNextVisit(patientCode string, visits []time.Time, today time.Time) model.SuggestedNextVisit {
+	suggestion := model.SuggestedNextVisit{
+		PatientCode:  patientCode,
+		VisitCount:   len(visits),
+		IntervalDays: nextVisitDefaultInterval(),
+		Basis:        nextVisitBasisDefault,
+	}
+
+	from := today
+	if len(visits) > 0 {
+		from = visits[len(visits)-1]
+		suggestion.LastVisit = from.Format(cadenceDateLayout)
+	}
+	if len(visits) >= 2 {
+		if interval := medianIntervalDays(visits); interval > 0 {
+			suggestion.IntervalDays = interval
+			suggestion.Basis = nextVisitBasisHistory
+		}
+	}
+	suggestion.SuggestedDate = from.AddDate(0, 0, suggestion.IntervalDays).Format(cadenceDateLayout)
+	return suggestion
+}
+
+// getPatientByCodeParam loads the patient whose code is in the path, writing
+// an error response and returning false when it is missing or unknown.
+func getPatientByCodeParam(c *gin.Context, db *gorm.DB) (model.Patient, bool) {
+	// Gin requires one wildcard name per path segment, so the patient code
+	// arrives in the :id parameter shared with the other /patient routes.
+	code := c.Param("id")
+	if code == "" {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Missing patient code",
+			Err: errors.New("patient code is required"),
+		})
+		return model.Patient{}, false
+	}
+
+	var patient model.Patient
+	if err := db.Where("patient_code = ?", code).First(&patient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallErrorNotFound(c, util.APIErrorParams{
+				Msg: "Patient not found",
+				Err: err,
+			})
+			return model.Patient{}, false
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve patient",
+			Err: err,
+		})
+		return model.Patient{}, false
+	}
+	return patient, true
+}
+
+// GetSuggestedNextVisit godoc
+// @Summary      Suggest a patient's next visit
+// @Description  Propose the next visit date as the last visit plus the median interval between the patient's attended treatments. With fewer than two visits, the NEXT_VISIT_DEFAULT_DAYS interval (default 7) is used from the last visit, or from today when there is none.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        code path string true "Patient code"
+// @Success      200 {object} util.APIResponse{data=model.SuggestedNextVisit} "Next visit suggested"
+// @Failure      400 {object} util.APIResponse "Missing patient code"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Patient not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{code}/suggested-next-visit [get]
+func GetSuggestedNextVisit(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	patient, ok := getPatientByCodeParam(c, db)
+	if !ok {
+		return
+	}
+
+	visits, err := attendedVisitDates(db, patient.PatientCode)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve treatment history",
+			Err: err,
+		})
+		return
+	}
+
+	today := time.Now()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Next visit suggested",
+		Data: suggestNextVisit(patient.PatientCode, visits, today),
+	})
+}
@@ -0,0 +1,90 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func seedVisits(t *testing.T, db *gorm.DB, patientCode string, dates map[string]string) {
+	t.Helper()
+	_ = createPatientIfNotExists(db, t, patientCode, patientCode+"@test.com")
+	for date, status := range dates {
+		treatment := model.Treatment{PatientCode: patientCode, TherapistID: 1, TreatmentDate: date, Issues: "-", Treatment: "-", NextVisit: "-", Status: status}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+}
+
+func suggestNext(t *testing.T, r *gin.Engine, code string, wantStatus int) model.SuggestedNextVisit {
+	t.Helper()
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/" + code + "/suggested-next-visit"})
+	assertStatusWithError(t, w, wantStatus, err)
+
+	var resp struct {
+		Data model.SuggestedNextVisit `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestGetSuggestedNextVisit(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id/suggested-next-visit", GetSuggestedNextVisit)
+
+	seedVisits(t, db, "REG001", map[string]string{
+		"2025-01-01": "",
+		"2025-01-15": "",
+		"2025-01-29": model.TreatmentStatusCompleted,
+		"2025-02-05": model.TreatmentStatusNoShow, // missed visits do not count
+		"2025-02-12": "",
+	})
+	seedVisits(t, db, "IRR001", map[string]string{
+		"2025-01-01": "",
+		"2025-01-08": "", // 7 days
+		"2025-02-07": "", // 30 days
+		"2025-02-17": "", // 10 days
+	})
+	seedVisits(t, db, "ONE001", map[string]string{"2025-03-01": ""})
+	_ = createPatientIfNotExists(db, t, "NEW001", "NEW001@test.com")
+
+	t.Run("regular cadence", func(t *testing.T) {
+		got := suggestNext(t, r, "REG001", http.StatusOK)
+		assert.Equal(t, model.SuggestedNextVisit{PatientCode: "REG001", LastVisit: "2025-02-12", VisitCount: 4, IntervalDays: 14, Basis: "history", SuggestedDate: "2025-02-26"}, got)
+	})
+
+	t.Run("irregular cadence uses the median", func(t *testing.T) {
+		got := suggestNext(t, r, "IRR001", http.StatusOK)
+		assert.Equal(t, 10, got.IntervalDays)
+		assert.Equal(t, "2025-02-27", got.SuggestedDate)
+	})
+
+	t.Run("single visit falls back to the default interval", func(t *testing.T) {
+		t.Setenv("NEXT_VISIT_DEFAULT_DAYS", "21")
+		got := suggestNext(t, r, "ONE001", http.StatusOK)
+		assert.Equal(t, model.SuggestedNextVisit{PatientCode: "ONE001", LastVisit: "2025-03-01", VisitCount: 1, IntervalDays: 21, Basis: "default", SuggestedDate: "2025-03-22"}, got)
+	})
+
+	t.Run("no history counts from today", func(t *testing.T) {
+		got := suggestNext(t, r, "NEW001", http.StatusOK)
+		assert.Equal(t, "default", got.Basis)
+		assert.Empty(t, got.LastVisit)
+		assert.Equal(t, time.Now().AddDate(0, 0, 7).Format("2006-01-02"), got.SuggestedDate)
+	})
+
+	t.Run("unknown patient", func(t *testing.T) {
+		suggestNext(t, r, "NOPE", http.StatusNotFound)
+	})
+}
+
+func TestMedianIntervalDays(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC) }
+	assert.Equal(t, 5, medianIntervalDays([]time.Time{day(1), day(6)}))
+	// Gaps 2, 4, 10, 12 -> (4 + 10) / 2.
+	assert.Equal(t, 7, medianIntervalDays([]time.Time{day(1), day(3), day(7), day(17), day(29)}))
+}
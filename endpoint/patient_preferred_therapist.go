@@ -0,0 +1,147 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findApprovedTherapist returns the therapist with the given ID, responding
+// with 400 and returning false when it does not exist or is not approved.
+func findApprovedTherapist(c *gin.Context, db *gorm.DB, therapistID uint) (model.Therapist, bool) {
+	var therapist model.Therapist
+	if err := db.First(&therapist, therapistID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallUserError(c, util.APIErrorParams{
+				Msg: "Therapist not found",
+				Err: err,
+			})
+			return model.Therapist{}, false
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve therapist",
+			Err: err,
+		})
+		return model.Therapist{}, false
+	}
+	if !therapist.IsApproved {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Therapist is not approved",
+			Err: fmt.Errorf("therapist %d is not approved", therapist.ID),
+		})
+		return model.Therapist{}, false
+	}
+	return therapist, true
+}
+
+// patientInfo adds the preferred therapist's name to patient. The name is
+// empty when none is set or the therapist has since been deleted.
+func patientInfo(db *gorm.DB, patient model.Patient) (model.PatientInfo, error) {
+	info := model.PatientInfo{Patient: patient}
+	if patient.PreferredTherapistID == nil {
+		return info, nil
+	}
+	var therapist model.Therapist
+	if err := db.Select("full_name").Where("id = ?", *patient.PreferredTherapistID).Limit(1).Find(&therapist).Error; err != nil {
+		return info, err
+	}
+	info.PreferredTherapistName = therapist.FullName
+	return info, nil
+}
+
+// SetPatientPreferredTherapist godoc
+// @Summary      Set a patient's preferred therapist
+// @Description  Set the therapist a patient should see again for continuity of care. The therapist must exist and be approved.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Patient ID"
+// @Param        request body model.SetPreferredTherapistRequest true "Preferred therapist"
+// @Success      200 {object} util.APIResponse{data=model.PatientInfo} "Preferred therapist updated"
+// @Failure      400 {object} util.APIResponse "Invalid request, patient or therapist not found, or therapist not approved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{id}/preferred-therapist [put]
+func SetPatientPreferredTherapist(c *gin.Context) {
+	var req model.SetPreferredTherapistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, patient, err := getPatientByID(c, db)
+	if err != nil {
+		return
+	}
+
+	therapist, ok := findApprovedTherapist(c, db, req.TherapistID)
+	if !ok {
+		return
+	}
+
+	if err := db.Model(&patient).Update("preferred_therapist_id", therapist.ID).Error; err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to update preferred therapist",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Preferred therapist updated",
+		Data: model.PatientInfo{Patient: patient, PreferredTherapistName: therapist.FullName},
+	})
+}
+
+// ClearPatientPreferredTherapist godoc
+// @Summary      Clear a patient's preferred therapist
+// @Description  Remove the preferred therapist of a patient.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Patient ID"
+// @Success      200 {object} util.APIResponse{data=model.PatientInfo} "Preferred therapist cleared"
+// @Failure      400 {object} util.APIResponse "Patient not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{id}/preferred-therapist [delete]
+func ClearPatientPreferredTherapist(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, patient, err := getPatientByID(c, db)
+	if err != nil {
+		return
+	}
+
+	if err := db.Model(&patient).Update("preferred_therapist_id", nil).Error; err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to clear preferred therapist",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Preferred therapist cleared",
+		Data: model.PatientInfo{Patient: patient},
+	})
+}
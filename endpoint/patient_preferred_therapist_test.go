@@ -0,0 +1,87 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatientPreferredTherapist(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id", GetPatientInfo)
+	r.PUT("/patient/:id/preferred-therapist", SetPatientPreferredTherapist)
+	r.DELETE("/patient/:id/preferred-therapist", ClearPatientPreferredTherapist)
+
+	patient := model.Patient{FullName: "Continuity", PatientCode: "PREF1"}
+	assert.NoError(t, db.Create(&patient).Error)
+	approved := createTestTherapist(db, t, true)
+	path := fmt.Sprintf("/patient/%d/preferred-therapist", patient.ID)
+
+	getInfo := func() model.PatientInfo {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/patient/%d", patient.ID)})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data model.PatientInfo `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	assert.Nil(t, getInfo().PreferredTherapistID)
+
+	w, response, err := performRequest(r, requestSpec{method: http.MethodPut, requestPath: path, body: map[string]uint{"therapist_id": approved.ID}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Equal(t, float64(approved.ID), response["data"].(map[string]interface{})["preferred_therapist_id"])
+	info := getInfo()
+	if assert.NotNil(t, info.PreferredTherapistID) {
+		assert.Equal(t, approved.ID, *info.PreferredTherapistID)
+	}
+	assert.Equal(t, approved.FullName, info.PreferredTherapistName)
+
+	w, response, err = performRequest(r, requestSpec{method: http.MethodDelete, requestPath: path})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Nil(t, response["data"].(map[string]interface{})["preferred_therapist_id"])
+	info = getInfo()
+	assert.Nil(t, info.PreferredTherapistID)
+	assert.Empty(t, info.PreferredTherapistName)
+	var stored model.Patient
+	assert.NoError(t, db.First(&stored, patient.ID).Error)
+	assert.Nil(t, stored.PreferredTherapistID)
+}
+
+func TestSetPatientPreferredTherapist_RejectsInvalidTherapist(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.PUT("/patient/:id/preferred-therapist", SetPatientPreferredTherapist)
+
+	patient := model.Patient{FullName: "Continuity", PatientCode: "PREF2"}
+	assert.NoError(t, db.Create(&patient).Error)
+	unapproved := createTestTherapist(db, t, false)
+	deleted := createTestTherapist(db, t, true)
+	assert.NoError(t, db.Delete(&deleted).Error)
+	path := fmt.Sprintf("/patient/%d/preferred-therapist", patient.ID)
+
+	for name, body := range map[string]interface{}{
+		"unapproved":   map[string]uint{"therapist_id": unapproved.ID},
+		"deleted":      map[string]uint{"therapist_id": deleted.ID},
+		"unknown":      map[string]uint{"therapist_id": 99999},
+		"missing body": map[string]string{},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w, _, err := performRequest(r, requestSpec{method: http.MethodPut, requestPath: path, body: body})
+			assertStatusWithError(t, w, http.StatusBadRequest, err)
+		})
+	}
+
+	var stored model.Patient
+	assert.NoError(t, db.First(&stored, patient.ID).Error)
+	assert.Nil(t, stored.PreferredTherapistID)
+
+	approved := createTestTherapist(db, t, true)
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPut, requestPath: "/patient/99999/preferred-therapist", body: map[string]uint{"therapist_id": approved.ID}})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
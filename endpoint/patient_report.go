@@ -0,0 +1,92 @@
+package endpoint
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/ariebrainware/basis-data-ltt/util/report"
+	"github.com/gin-gonic/gin"
+)
+
+// GetPatientReportPDF godoc
+// @Summary      Download a patient's report as PDF
+// @Description  Render the patient's details and treatment history as a printable PDF. Admin or the patient's linked user only.
+// @Tags         Patient
+// @Produce      application/pdf
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Patient ID"
+// @Success      200 {file} file "Patient report PDF"
+// @Failure      400 {object} util.APIResponse "Patient not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{id}/report.pdf [get]
+func GetPatientReportPDF(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, patient, err := getPatientByID(c, db)
+	if err != nil {
+		return
+	}
+
+	allowed, err := canViewPatient(c, db, patient)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to verify patient access",
+			Err: err,
+		})
+		return
+	}
+	if !allowed {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{
+			Msg: "Insufficient permissions to access this resource",
+			Err: fmt.Errorf("user is neither admin nor linked to patient"),
+		})
+		return
+	}
+
+	summary, err := buildTreatmentSummary(db, patient)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to build treatment summary",
+			Err: err,
+		})
+		return
+	}
+
+	// Render fully before writing so a failure can still be reported as JSON.
+	var buf bytes.Buffer
+	if err := report.WritePatientPDF(&buf, patient, summary); err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to render patient report",
+			Err: err,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="patient-%s.pdf"`, patientReportFilename(patient)))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// patientReportFilename returns the patient code reduced to characters that
+// are safe in a Content-Disposition filename, falling back to the ID.
+func patientReportFilename(patient model.Patient) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x80 && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_') {
+			return r
+		}
+		return -1
+	}, patient.PatientCode)
+	if name == "" {
+		return fmt.Sprintf("%d", patient.ID)
+	}
+	return name
+}
@@ -0,0 +1,90 @@
+package endpoint
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+)
+
+func TestGetPatientReportPDF(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.Use(withAuthContext(1, model.RoleAdmin))
+	r.GET("/patient/:id/report.pdf", GetPatientReportPDF)
+
+	patient := model.Patient{FullName: "Report Patient", PatientCode: "R/1 x", Email: "report@test.com"}
+	if err := db.Create(&patient).Error; err != nil {
+		t.Fatalf("create patient: %v", err)
+	}
+	seedSummaryTreatments(t, db, patient.PatientCode)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, fmt.Sprintf("/patient/%d/report.pdf", patient.ID), nil))
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+	if cd := rr.Header().Get("Content-Disposition"); cd != `attachment; filename="patient-R1x.pdf"` {
+		t.Errorf("unexpected content disposition %q", cd)
+	}
+	if !bytes.HasPrefix(rr.Body.Bytes(), []byte("%PDF-")) {
+		t.Errorf("response is not a PDF: %q", rr.Body.Bytes()[:min(16, rr.Body.Len())])
+	}
+
+	missing, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/99999/report.pdf"})
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if missing.Code == http.StatusOK {
+		t.Errorf("expected an error for a missing patient, got 200")
+	}
+}
+
+func TestGetPatientReportPDF_Gzip(t *testing.T) {
+	t.Setenv("GZIP_ENABLED", "true")
+	t.Setenv("GZIP_MIN_SIZE", "256")
+
+	r, db := setupEndpointTest(t)
+	r.Use(middleware.GzipMiddleware(), withAuthContext(1, model.RoleAdmin))
+	r.GET("/patient/:id/report.pdf", GetPatientReportPDF)
+
+	patient := model.Patient{FullName: "Gzip Patient", PatientCode: "G1", Email: "gzip-report@test.com"}
+	if err := db.Create(&patient).Error; err != nil {
+		t.Fatalf("create patient: %v", err)
+	}
+	seedSummaryTreatments(t, db, patient.PatientCode)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/patient/%d/report.pdf", patient.ID), nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, req)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ce := rr.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("expected gzip content encoding, got %q", ce)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "application/pdf" {
+		t.Errorf("unexpected content type %q", ct)
+	}
+
+	zr, err := gzip.NewReader(rr.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	body, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if !bytes.HasPrefix(body, []byte("%PDF-")) {
+		t.Errorf("decompressed response is not a PDF: %q", body[:min(16, len(body))])
+	}
+}
@@ -0,0 +1,156 @@
+package endpoint
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// highRiskPatientsQuery selects patients at or above minLevel with the date
+// of their latest visit, empty when they have none. Only completed treatments
+// are visits.
+func highRiskPatientsQuery(db *gorm.DB, minLevel int) *gorm.DB {
+	return db.Model(&model.Patient{}).
+		Select("patients.id, patients.patient_code, patients.full_name, patients.phone_number, patients.risk_level, COALESCE(MAX(treatments.treatment_date), '') AS last_treatment_date").
+		Joins("LEFT JOIN treatments ON treatments.patient_code = patients.patient_code AND treatments.deleted_at IS NULL AND "+attendedTreatmentCondition,
+			model.TreatmentStatusCompleted).
+		Where("patients.risk_level >= ?", minLevel).
+		Group("patients.id, patients.patient_code, patients.full_name, patients.phone_number, patients.risk_level")
+}
+
+// fetchHighRiskPatients returns one page of patients at or above minLevel,
+// highest risk first, and the total number of matches.
+func fetchHighRiskPatients(db *gorm.DB, minLevel, limit, offset int) ([]model.HighRiskPatient, int64, error) {
+	var total int64
+	if err := db.Model(&model.Patient{}).Where("risk_level >= ?", minLevel).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	patients := []model.HighRiskPatient{}
+	query := applyPagination(highRiskPatientsQuery(db, minLevel).Order("patients.risk_level DESC, patients.id ASC"), limit, offset)
+	if err := query.Scan(&patients).Error; err != nil {
+		return nil, 0, err
+	}
+	return patients, total, nil
+}
+
+// parseMinRiskLevel reads min_level, defaulting to high. Level 0 is rejected
+// because it would list every patient.
+func parseMinRiskLevel(c *gin.Context) (int, error) {
+	s := queryString(c, "min_level")
+	if s == "" {
+		return model.RiskLevelHigh, nil
+	}
+	level, err := strconv.Atoi(s)
+	if err != nil || level <= model.RiskLevelNone || !model.IsValidRiskLevel(level) {
+		return 0, fmt.Errorf("min_level must be between %d and %d", model.RiskLevelLow, model.RiskLevelHigh)
+	}
+	return level, nil
+}
+
+// ListHighRiskPatients godoc
+// @Summary      List high-risk patients
+// @Description  Get patients whose risk level is at least min_level (1 low, 2 medium, 3 high; default 3), highest risk first, with the date of their latest visit. Only completed treatments count as visits.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        min_level query int false "Minimum risk level (1-3)" default(3)
+// @Param        limit query int false "Limit number of results"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200 {object} util.APIResponse{data=object{patients=[]model.HighRiskPatient,total=int,total_fetched=int}} "High-risk patients retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid min_level"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/high-risk [get]
+func ListHighRiskPatients(c *gin.Context) {
+	minLevel, err := parseMinRiskLevel(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: err.Error(),
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	offset := parseQueryInt(c, "offset", 0)
+	patients, total, err := fetchHighRiskPatients(db, minLevel, parseQueryInt(c, "limit", 0), offset)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve high-risk patients",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "High-risk patients retrieved",
+		Data: util.NewPageResponse(patients, total, len(patients), util.OffsetHasMore(offset, len(patients), total), nil).Named("patients"),
+	})
+}
+
+// SetPatientRiskLevel godoc
+// @Summary      Set a patient's risk level
+// @Description  Set the risk level of a patient: 0 none, 1 low, 2 medium, 3 high.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Patient ID"
+// @Param        request body model.SetRiskLevelRequest true "Risk level"
+// @Success      200 {object} util.APIResponse{data=model.Patient} "Risk level updated"
+// @Failure      400 {object} util.APIResponse "Invalid risk level or patient not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{id}/risk-level [put]
+func SetPatientRiskLevel(c *gin.Context) {
+	var req model.SetRiskLevelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+	if !model.IsValidRiskLevel(*req.RiskLevel) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("risk_level must be between %d and %d", model.RiskLevelNone, model.RiskLevelHigh),
+			Err: fmt.Errorf("invalid risk level %d", *req.RiskLevel),
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, patient, err := getPatientByID(c, db)
+	if err != nil {
+		return
+	}
+
+	if err := db.Model(&patient).Update("risk_level", *req.RiskLevel).Error; err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to update risk level",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Risk level updated",
+		Data: patient,
+	})
+}
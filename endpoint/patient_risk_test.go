@@ -0,0 +1,99 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetPatientRiskLevel(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.PUT("/patient/:id/risk-level", SetPatientRiskLevel)
+
+	patient := model.Patient{FullName: "Risky", PatientCode: "RISK1"}
+	assert.NoError(t, db.Create(&patient).Error)
+	path := fmt.Sprintf("/patient/%d/risk-level", patient.ID)
+
+	w, response, err := performRequest(r, requestSpec{method: http.MethodPut, requestPath: path, body: map[string]int{"risk_level": model.RiskLevelHigh}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Equal(t, float64(model.RiskLevelHigh), response["data"].(map[string]interface{})["risk_level"])
+	var stored model.Patient
+	assert.NoError(t, db.First(&stored, patient.ID).Error)
+	assert.Equal(t, model.RiskLevelHigh, stored.RiskLevel)
+
+	// Zero clears the flag rather than being ignored.
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPut, requestPath: path, body: map[string]int{"risk_level": model.RiskLevelNone}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.NoError(t, db.First(&stored, patient.ID).Error)
+	assert.Equal(t, model.RiskLevelNone, stored.RiskLevel)
+
+	for _, body := range []interface{}{map[string]int{"risk_level": 4}, map[string]int{"risk_level": -1}, map[string]string{}} {
+		w, _, err = performRequest(r, requestSpec{method: http.MethodPut, requestPath: path, body: body})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPut, requestPath: "/patient/99999/risk-level", body: map[string]int{"risk_level": 1}})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
+
+func TestListHighRiskPatients(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/high-risk", ListHighRiskPatients)
+
+	for _, p := range []model.Patient{
+		{FullName: "None", PatientCode: "HR0", RiskLevel: model.RiskLevelNone},
+		{FullName: "Low", PatientCode: "HR1", RiskLevel: model.RiskLevelLow},
+		{FullName: "Medium", PatientCode: "HR2", RiskLevel: model.RiskLevelMedium},
+		{FullName: "High", PatientCode: "HR3", RiskLevel: model.RiskLevelHigh, PhoneNumber: "0811"},
+	} {
+		assert.NoError(t, db.Create(&p).Error)
+	}
+	for _, tr := range []struct{ code, date, status string }{
+		{"HR3", "2025-01-06", model.TreatmentStatusCompleted},
+		{"HR3", "2025-02-03", model.TreatmentStatusCompleted},
+		{"HR3", "2025-03-03", model.TreatmentStatusNoShow},
+		{"HR1", "2025-01-10", model.TreatmentStatusCompleted},
+	} {
+		treatment := model.Treatment{PatientCode: tr.code, TherapistID: 1, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", Status: tr.status}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+
+	list := func(query string) ([]model.HighRiskPatient, int64) {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/high-risk" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data struct {
+				Total    int64                   `json:"total"`
+				Patients []model.HighRiskPatient `json:"patients"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.Patients, resp.Data.Total
+	}
+
+	patients, total := list("")
+	assert.Equal(t, int64(1), total)
+	if assert.Len(t, patients, 1) {
+		assert.Equal(t, "HR3", patients[0].PatientCode)
+		assert.Equal(t, "0811", patients[0].PhoneNumber)
+		assert.Equal(t, "2025-02-03", patients[0].LastTreatmentDate, "no-shows are not visits")
+	}
+
+	patients, total = list("?min_level=1")
+	assert.Equal(t, int64(3), total)
+	if assert.Len(t, patients, 3) {
+		assert.Equal(t, []string{"HR3", "HR2", "HR1"}, []string{patients[0].PatientCode, patients[1].PatientCode, patients[2].PatientCode})
+		assert.Equal(t, "", patients[1].LastTreatmentDate)
+		assert.Equal(t, "2025-01-10", patients[2].LastTreatmentDate)
+	}
+
+	for _, query := range []string{"?min_level=0", "?min_level=4", "?min_level=high"} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/high-risk" + query})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+}
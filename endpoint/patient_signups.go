@@ -0,0 +1,135 @@
+package endpoint
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSignupDays = 30
+	maxSignupDays     = 366
+)
+
+// parseSignupRange reads from and to (YYYY-MM-DD, inclusive) as dates in loc.
+// When omitted, the range ends today and covers defaultSignupDays days.
+func parseSignupRange(c *gin.Context, loc *time.Location, now time.Time) (time.Time, time.Time, error) {
+	now = now.In(loc)
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	if s := queryString(c, "to"); s != "" {
+		t, err := time.ParseInLocation(cadenceDateLayout, s, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid to, expected YYYY-MM-DD")
+		}
+		to = t
+	}
+
+	from := to.AddDate(0, 0, -defaultSignupDays+1)
+	if s := queryString(c, "from"); s != "" {
+		t, err := time.ParseInLocation(cadenceDateLayout, s, loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid from, expected YYYY-MM-DD")
+		}
+		from = t
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("from must not be after to")
+	}
+	if to.Sub(from) >= maxSignupDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("the range may span at most %d days", maxSignupDays)
+	}
+	return from, to, nil
+}
+
+// countPatientSignupsByDay counts patients created on each day from from to
+// to inclusive, with a zero for days without signups. Days are calendar days
+// in from's location; creation times are bucketed in Go because SQLite and
+// MySQL disagree on the zone DATE() uses.
+func countPatientSignupsByDay(db *gorm.DB, from, to time.Time) (model.PatientSignupsReport, error) {
+	loc := from.Location()
+	var createdAt []time.Time
+	err := db.Model(&model.Patient{}).
+		Where("created_at >= ? AND created_at < ?", from, to.AddDate(0, 0, 1)).
+		Pluck("created_at", &createdAt).Error
+	if err != nil {
+		return model.PatientSignupsReport{}, err
+	}
+
+	report := model.PatientSignupsReport{
+		From: from.Format(cadenceDateLayout),
+		To:   to.Format(cadenceDateLayout),
+		Days: []model.PatientSignupDay{},
+	}
+	index := make(map[string]int)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		date := d.Format(cadenceDateLayout)
+		index[date] = len(report.Days)
+		report.Days = append(report.Days, model.PatientSignupDay{Date: date})
+	}
+	for _, t := range createdAt {
+		if i, ok := index[t.In(loc).Format(cadenceDateLayout)]; ok {
+			report.Days[i].Count++
+			report.Total++
+		}
+	}
+	return report, nil
+}
+
+// GetPatientSignupsByDay godoc
+// @Summary      Count patient signups per day
+// @Description  Count patients created on each day between from and to (inclusive, in the clinic timezone), with zero for days without signups. Defaults to the last 30 days; the range may span at most 366 days.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        from query string false "First day (YYYY-MM-DD)"
+// @Param        to query string false "Last day (YYYY-MM-DD), defaults to today"
+// @Success      200 {object} util.APIResponse{data=model.PatientSignupsReport} "Patient signups retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/signups-by-day [get]
+func GetPatientSignupsByDay(c *gin.Context) {
+	loc, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to load timezone",
+			Err: err,
+		})
+		return
+	}
+
+	from, to, err := parseSignupRange(c, loc, time.Now())
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: err.Error(),
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := countPatientSignupsByDay(db, from, to)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to count patient signups",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Patient signups retrieved",
+		Data: report,
+	})
+}
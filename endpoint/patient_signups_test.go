@@ -0,0 +1,62 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPatientSignupsByDay(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/signups-by-day", GetPatientSignupsByDay)
+
+	loc, err := time.LoadLocation(config.Timezone)
+	assert.NoError(t, err)
+	for i, createdAt := range []time.Time{
+		time.Date(2025, 1, 1, 23, 59, 0, 0, time.UTC), // 2 January in the clinic timezone
+		time.Date(2025, 1, 2, 8, 0, 0, 0, loc),
+		time.Date(2025, 1, 2, 9, 0, 0, 0, loc),
+		time.Date(2025, 1, 4, 0, 30, 0, 0, loc),
+		time.Date(2025, 1, 6, 10, 0, 0, 0, loc), // after the range
+	} {
+		p := model.Patient{FullName: "Signup", PatientCode: fmt.Sprintf("SU%d", i)}
+		p.CreatedAt = createdAt
+		assert.NoError(t, db.Create(&p).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/signups-by-day?from=2025-01-01&to=2025-01-05"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.PatientSignupsReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.PatientSignupsReport{
+		From:  "2025-01-01",
+		To:    "2025-01-05",
+		Total: 4,
+		Days: []model.PatientSignupDay{
+			{Date: "2025-01-01", Count: 0},
+			{Date: "2025-01-02", Count: 3},
+			{Date: "2025-01-03", Count: 0},
+			{Date: "2025-01-04", Count: 1},
+			{Date: "2025-01-05", Count: 0},
+		},
+	}, resp.Data)
+}
+
+func TestGetPatientSignupsByDay_InvalidRange(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+	r.GET("/patient/signups-by-day", GetPatientSignupsByDay)
+
+	for _, query := range []string{"from=2025-02-01&to=2025-01-01", "from=yesterday", "from=2024-01-01&to=2025-06-01"} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/signups-by-day?" + query})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+}
@@ -269,8 +269,8 @@ func assertFetchOrder(t *testing.T, db *gorm.DB, q listQuery, extract func(model
 		t.Fatalf("expected %d patients, got %d", len(expected), len(patients))
 	}
 	for i := range expected {
-		if extract(patients[i]) != expected[i] {
-			t.Errorf("expected %q at pos %d, got %q", expected[i], i, extract(patients[i]))
+		if extract(patients[i].Patient) != expected[i] {
+			t.Errorf("expected %q at pos %d, got %q", expected[i], i, extract(patients[i].Patient))
 		}
 	}
 }
@@ -313,3 +313,315 @@ func TestUpdatePatientPhoneNumbers(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdatePatientPhoneNumbers_Validation(t *testing.T) {
+	db := setupTestDB(t)
+	r := setupRouterWithDB(db)
+
+	t.Run("valid multi-number update", func(t *testing.T) {
+		patient := createTestPatient(t, db)
+		rr := doPatchPatient(t, r, patient.ID, map[string]interface{}{"phone_number": []string{"+6281234567890", "0812-3456-789", "021 555 1234"}})
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+
+	t.Run("invalid entry rejects whole update", func(t *testing.T) {
+		patient := createTestPatient(t, db)
+		rr := doPatchPatient(t, r, patient.ID, map[string]interface{}{"phone_number": []string{"081234567890", "08-abc", "12"}})
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400, got %d: %s", rr.Code, rr.Body.String())
+		}
+
+		var resp struct {
+			Data struct {
+				Invalid []PhoneNumberError `json:"invalid_phone_numbers"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		if len(resp.Data.Invalid) != 2 || resp.Data.Invalid[0].PhoneNumber != "08-abc" || resp.Data.Invalid[1].PhoneNumber != "12" {
+			t.Errorf("expected both invalid numbers to be reported, got %+v", resp.Data.Invalid)
+		}
+
+		var reloaded model.Patient
+		if err := db.First(&reloaded, patient.ID).Error; err != nil {
+			t.Fatalf("reload patient: %v", err)
+		}
+		if reloaded.PhoneNumber != patient.PhoneNumber {
+			t.Errorf("expected phone numbers to be unchanged, got %q", reloaded.PhoneNumber)
+		}
+	})
+
+	t.Run("configurable pattern", func(t *testing.T) {
+		t.Setenv("PATIENT_PHONE_PATTERN", `^08[0-9]{8,11}$`)
+		patient := createTestPatient(t, db)
+		rr := doPatchPatient(t, r, patient.ID, map[string]interface{}{"phone_number": []string{"+6281234567890"}})
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected status 400 with custom pattern, got %d: %s", rr.Code, rr.Body.String())
+		}
+	})
+}
+
+func setupTreatmentSummaryTest(t *testing.T, userID uint, roleID uint32) (*gin.Engine, *gorm.DB) {
+	t.Helper()
+	r, db := setupEndpointTest(t)
+	r.Use(withAuthContext(userID, roleID))
+	r.GET("/patient/:id/treatment-summary", GetPatientTreatmentSummary)
+	return r, db
+}
+
+func seedSummaryTreatments(t *testing.T, db *gorm.DB, patientCode string) {
+	t.Helper()
+	first := model.Therapist{FullName: "Dr. Alpha", NIK: "NIK-ALPHA", Email: "alpha@test.com"}
+	second := model.Therapist{FullName: "Dr. Beta", NIK: "NIK-BETA", Email: "beta@test.com"}
+	for _, th := range []*model.Therapist{&first, &second} {
+		if err := db.Create(th).Error; err != nil {
+			t.Fatalf("create therapist: %v", err)
+		}
+	}
+
+	// Insert out of chronological order to verify the summary sorts by date.
+	treatments := []model.Treatment{
+		{TreatmentDate: "2025-03-10", PatientCode: patientCode, TherapistID: second.ID, Issues: "Knee pain", Treatment: "Massage", NextVisit: "2025-03-17"},
+		{TreatmentDate: "2025-01-05", PatientCode: patientCode, TherapistID: first.ID, Issues: "Back pain", Treatment: "Exercise", NextVisit: "2025-01-12"},
+		{TreatmentDate: "2025-02-01", PatientCode: patientCode, TherapistID: first.ID, Issues: "Back pain", Treatment: "Massage,Exercise", NextVisit: "2025-02-08"},
+		{TreatmentDate: "2025-02-02", PatientCode: "OTHER1", TherapistID: first.ID, Issues: "Other", Treatment: "Other", NextVisit: "2025-02-09"},
+	}
+	for _, tr := range treatments {
+		if err := db.Create(&tr).Error; err != nil {
+			t.Fatalf("create treatment: %v", err)
+		}
+	}
+}
+
+func decodeTreatmentSummary(t *testing.T, rr *httptest.ResponseRecorder) model.PatientTreatmentSummary {
+	t.Helper()
+	var resp struct {
+		Data model.PatientTreatmentSummary `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v; body: %s", err, rr.Body.String())
+	}
+	return resp.Data
+}
+
+func TestGetPatientTreatmentSummary_OrderingAndTotals(t *testing.T) {
+	r, db := setupTreatmentSummaryTest(t, 1, model.RoleAdmin)
+
+	patient := model.Patient{FullName: "Summary Patient", PatientCode: "S1", Email: "summary@test.com"}
+	if err := db.Create(&patient).Error; err != nil {
+		t.Fatalf("create patient: %v", err)
+	}
+	seedSummaryTreatments(t, db, patient.PatientCode)
+
+	rr, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/patient/%d/treatment-summary", patient.ID)})
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	summary := decodeTreatmentSummary(t, rr)
+	if summary.TotalTreatments != 3 {
+		t.Errorf("expected 3 treatments, got %d", summary.TotalTreatments)
+	}
+	if summary.TotalTherapists != 2 {
+		t.Errorf("expected 2 therapists, got %d", summary.TotalTherapists)
+	}
+	if summary.FirstTreatmentDate != "2025-01-05" || summary.LastTreatmentDate != "2025-03-10" {
+		t.Errorf("unexpected date range %s..%s", summary.FirstTreatmentDate, summary.LastTreatmentDate)
+	}
+
+	wantDates := []string{"2025-01-05", "2025-02-01", "2025-03-10"}
+	wantTherapists := []string{"Dr. Alpha", "Dr. Alpha", "Dr. Beta"}
+	if len(summary.Treatments) != len(wantDates) {
+		t.Fatalf("expected %d rows, got %d", len(wantDates), len(summary.Treatments))
+	}
+	for i, item := range summary.Treatments {
+		if item.TreatmentDate != wantDates[i] {
+			t.Errorf("row %d: expected date %s, got %s", i, wantDates[i], item.TreatmentDate)
+		}
+		if item.TherapistName != wantTherapists[i] {
+			t.Errorf("row %d: expected therapist %s, got %s", i, wantTherapists[i], item.TherapistName)
+		}
+	}
+}
+
+func TestGetPatientTreatmentSummary_Access(t *testing.T) {
+	tests := []struct {
+		name       string
+		userEmail  string
+		wantStatus int
+	}{
+		{"linked patient user", "Linked@Test.com", http.StatusOK},
+		{"unrelated user", "someone@test.com", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, db := setupEndpointTest(t)
+			user := model.User{Name: "Patient User", Email: tt.userEmail, Password: "hash", RoleID: model.RoleUser}
+			if err := db.Create(&user).Error; err != nil {
+				t.Fatalf("create user: %v", err)
+			}
+			patient := model.Patient{FullName: "Linked Patient", PatientCode: "L1", Email: "linked@test.com"}
+			if err := db.Create(&patient).Error; err != nil {
+				t.Fatalf("create patient: %v", err)
+			}
+
+			r := gin.New()
+			r.Use(middleware.DatabaseMiddleware(db), withAuthContext(user.ID, model.RoleUser))
+			r.GET("/patient/:id/treatment-summary", GetPatientTreatmentSummary)
+
+			rr, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/patient/%d/treatment-summary", patient.ID)})
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func TestListPatients_WithCounts(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient", ListPatients)
+
+	for _, p := range []model.Patient{
+		{FullName: "Two Visits", PatientCode: "TC1"},
+		{FullName: "One Visit", PatientCode: "TC2"},
+		{FullName: "No Visits", PatientCode: "TC3"},
+	} {
+		if err := db.Create(&p).Error; err != nil {
+			t.Fatalf("create patient: %v", err)
+		}
+	}
+	for _, code := range []string{"TC1", "TC1", "TC2", "TC2"} {
+		tr := model.Treatment{PatientCode: code, TherapistID: 1, TreatmentDate: "2025-01-01", Issues: "-", Treatment: "-", NextVisit: "-"}
+		if err := db.Create(&tr).Error; err != nil {
+			t.Fatalf("create treatment: %v", err)
+		}
+	}
+	// Deleted treatments are not counted.
+	var last model.Treatment
+	db.Where("patient_code = ?", "TC2").Last(&last)
+	db.Delete(&last)
+
+	decode := func(path string) []map[string]interface{} {
+		t.Helper()
+		rr, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: path})
+		if err != nil || rr.Code != http.StatusOK {
+			t.Fatalf("list patients: %v %d %s", err, rr.Code, rr.Body.String())
+		}
+		var resp struct {
+			Data struct {
+				Patients []map[string]interface{} `json:"patients"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp.Data.Patients
+	}
+
+	counts := map[string]float64{}
+	for _, p := range decode("/patient?with_counts=true&keyword=TC") {
+		counts[p["patient_code"].(string)] = p["treatment_count"].(float64)
+	}
+	want := map[string]float64{"TC1": 2, "TC2": 1, "TC3": 0}
+	if len(counts) != len(want) {
+		t.Fatalf("got counts %v, want %v", counts, want)
+	}
+	for code, n := range want {
+		if counts[code] != n {
+			t.Errorf("treatment_count for %s = %v, want %v", code, counts[code], n)
+		}
+	}
+
+	for _, p := range decode("/patient") {
+		if _, ok := p["treatment_count"]; ok {
+			t.Errorf("treatment_count present without with_counts for %v", p["patient_code"])
+		}
+	}
+}
+
+func TestListPatients_PageShape(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient", ListPatients)
+
+	for _, p := range []model.Patient{
+		{FullName: "Page One", PatientCode: "PG1"},
+		{FullName: "Page Two", PatientCode: "PG2"},
+		{FullName: "Page Three", PatientCode: "PG3"},
+		{FullName: "Other", PatientCode: "OT1"},
+	} {
+		if err := db.Create(&p).Error; err != nil {
+			t.Fatalf("create patient: %v", err)
+		}
+	}
+
+	page := func(path string) map[string]interface{} {
+		t.Helper()
+		rr, response, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: path})
+		if err != nil || rr.Code != http.StatusOK {
+			t.Fatalf("list patients: %v %d %s", err, rr.Code, rr.Body.String())
+		}
+		data, _ := response["data"].(map[string]interface{})
+		return data
+	}
+
+	// total counts only the patients matching the keyword.
+	data := page("/patient?keyword=Page&limit=2")
+	if data["total"] != float64(3) || data["total_fetched"] != float64(2) || data["has_more"] != true {
+		t.Errorf("first page = total %v, total_fetched %v, has_more %v; want 3, 2, true", data["total"], data["total_fetched"], data["has_more"])
+	}
+	if cursor, ok := data["next_cursor"]; !ok || cursor != nil {
+		t.Errorf("expected a null next_cursor, got %v (present %v)", cursor, ok)
+	}
+	if patients, _ := data["patients"].([]interface{}); len(patients) != 2 {
+		t.Errorf("expected 2 patients, got %d", len(patients))
+	}
+
+	data = page("/patient?keyword=Page&limit=2&offset=2")
+	if data["total_fetched"] != float64(1) || data["has_more"] != false {
+		t.Errorf("last page = total_fetched %v, has_more %v; want 1, false", data["total_fetched"], data["has_more"])
+	}
+}
+
+func TestParseQueryParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	parse := func(rawQuery string) listQuery {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/patient?"+rawQuery, nil)
+		return parseQueryParams(c)
+	}
+
+	missing := parse("")
+	if missing != (listQuery{}) {
+		t.Fatalf("expected zero values for missing params, got %+v", missing)
+	}
+
+	tests := []struct {
+		name     string
+		rawQuery string
+		want     listQuery
+	}{
+		{"empty", "limit=&offset=&keyword=&group_by_date=&therapist_id=", listQuery{}},
+		{"blank", "limit=%20&offset=%20&keyword=%20%20&group_by_date=%20&therapist_id=%20", listQuery{}},
+		{"valid", "limit=10&offset=20&keyword=%20Jane%20&group_by_date=last_2_days&therapist_id=7",
+			listQuery{Limit: 10, Offset: 20, Keyword: "Jane", GroupByDate: "last_2_days", TherapistID: 7}},
+		{"valid date", "group_by_date=2025-01-15", listQuery{GroupByDate: "2025-01-15"}},
+		{"non-numeric", "limit=ten&offset=x&therapist_id=abc", listQuery{}},
+		{"negative", "limit=-5&offset=-1&therapist_id=-3", listQuery{}},
+		{"sort", "sort=full_name&sort_dir=DESC&with_counts=true", listQuery{SortBy: "full_name", SortDir: "desc", WithCounts: true}},
+	}
+	for _, tt := range tests {
+		if got := parse(tt.rawQuery); got != tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
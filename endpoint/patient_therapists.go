@@ -0,0 +1,68 @@
+package endpoint
+
+import (
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// listPatientTherapists returns each therapist who has treated the patient
+// with their number of attended visits, most visits first. Only completed
+// treatments are visits.
+func listPatientTherapists(db *gorm.DB, patientCode string) ([]model.PatientTherapist, error) {
+	therapists := []model.PatientTherapist{}
+	err := db.Model(&model.Treatment{}).
+		Select("treatments.therapist_id, COALESCE(therapists.full_name, '') AS therapist_name, COUNT(*) AS visit_count, MIN(treatments.treatment_date) AS first_visit, MAX(treatments.treatment_date) AS last_visit").
+		Joins("LEFT JOIN therapists ON therapists.id = treatments.therapist_id").
+		Scopes(attendedStatusScope).
+		Where("treatments.patient_code = ?", patientCode).
+		Group("treatments.therapist_id, therapists.full_name").
+		Order("visit_count DESC, last_visit DESC, treatments.therapist_id ASC").
+		Scan(&therapists).Error
+	return therapists, err
+}
+
+// GetPatientTherapists godoc
+// @Summary      List a patient's therapists
+// @Description  Return the distinct therapists who have treated a patient, with the number of attended visits and the first and last visit dates with each, most visits first. Only completed treatments are visits; scheduled, cancelled and no-show treatments are not.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        code path string true "Patient code"
+// @Success      200 {object} util.APIResponse{data=model.PatientTherapists} "Patient therapists retrieved"
+// @Failure      400 {object} util.APIResponse "Missing patient code"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Patient not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{code}/therapists [get]
+func GetPatientTherapists(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	patient, ok := getPatientByCodeParam(c, db)
+	if !ok {
+		return
+	}
+
+	therapists, err := listPatientTherapists(db, patient.PatientCode)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve patient therapists",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg: "Patient therapists retrieved",
+		Data: model.PatientTherapists{
+			PatientCode: patient.PatientCode,
+			Therapists:  therapists,
+		},
+	})
+}
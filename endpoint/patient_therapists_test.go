@@ -0,0 +1,54 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPatientTherapists(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id/therapists", GetPatientTherapists)
+
+	_ = createPatientIfNotExists(db, t, "PT001", "pt001@test.com")
+	ann := model.Therapist{FullName: "Ann", Email: "ann@test.com"}
+	bob := model.Therapist{FullName: "Bob", Email: "bob@test.com"}
+	assert.NoError(t, db.Create(&ann).Error)
+	assert.NoError(t, db.Create(&bob).Error)
+
+	for _, tr := range []struct {
+		code      string
+		therapist uint
+		date      string
+		status    string
+	}{
+		{"PT001", ann.ID, "2025-01-05", model.TreatmentStatusCompleted},
+		{"PT001", bob.ID, "2025-01-10", model.TreatmentStatusCompleted},
+		{"PT001", bob.ID, "2025-01-17", model.TreatmentStatusCompleted},
+		{"PT001", bob.ID, "2025-01-24", model.TreatmentStatusNoShow},
+		{"PT001", ann.ID, "2025-02-01", model.TreatmentStatusCancelled},
+		{"OTHER", ann.ID, "2025-01-05", model.TreatmentStatusCompleted},
+	} {
+		treatment := model.Treatment{PatientCode: tr.code, TherapistID: tr.therapist, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", Status: tr.status}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/PT001/therapists"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.PatientTherapists `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "PT001", resp.Data.PatientCode)
+	assert.Equal(t, []model.PatientTherapist{
+		{TherapistID: bob.ID, TherapistName: "Bob", VisitCount: 2, FirstVisit: "2025-01-10", LastVisit: "2025-01-17"},
+		{TherapistID: ann.ID, TherapistName: "Ann", VisitCount: 1, FirstVisit: "2025-01-05", LastVisit: "2025-01-05"},
+	}, resp.Data.Therapists)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/patient/NOPE/therapists"})
+	assertStatusWithError(t, w, http.StatusNotFound, err)
+}
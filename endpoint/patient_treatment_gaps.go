@@ -0,0 +1,105 @@
+package endpoint
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+const defaultTreatmentGapDays = 30
+
+// treatmentGapThreshold returns the gap length in days above which a gap is
+// reported: the threshold_days query parameter, else TREATMENT_GAP_DAYS, else
+// 30.
+func treatmentGapThreshold(c *gin.Context) (int, error) {
+	if s := queryString(c, "threshold_days"); s != "" {
+		days, err := strconv.Atoi(s)
+		if err != nil || days <= 0 {
+			return 0, fmt.Errorf("threshold_days must be a positive integer")
+		}
+		return days, nil
+	}
+	if days, err := strconv.Atoi(os.Getenv("TREATMENT_GAP_DAYS")); err == nil && days > 0 {
+		return days, nil
+	}
+	return defaultTreatmentGapDays, nil
+}
+
+// findTreatmentGaps returns the gaps between consecutive visits, which must
+// be sorted and distinct, that are longer than thresholdDays.
+func findTreatmentGaps(visits []time.Time, thresholdDays int) []model.TreatmentGap {
+	gaps := []model.TreatmentGap{}
+	for i := 1; i < len(visits); i++ {
+		days := int(visits[i].Sub(visits[i-1]).Hours() / 24)
+		if days <= thresholdDays {
+			continue
+		}
+		gaps = append(gaps, model.TreatmentGap{
+			StartDate: visits[i-1].Format(cadenceDateLayout),
+			EndDate:   visits[i].Format(cadenceDateLayout),
+			Days:      days,
+		})
+	}
+	return gaps
+}
+
+// GetPatientTreatmentGaps godoc
+// @Summary      List gaps in a patient's treatments
+// @Description  Return the intervals between consecutive attended treatments of a patient that are longer than threshold_days, with their start and end dates and length in days. The threshold defaults to TREATMENT_GAP_DAYS, or 30. Only completed treatments are visits.
+// @Tags         Patient
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        code path string true "Patient code"
+// @Param        threshold_days query int false "Report gaps longer than this many days"
+// @Success      200 {object} util.APIResponse{data=model.PatientTreatmentGaps} "Treatment gaps retrieved"
+// @Failure      400 {object} util.APIResponse "Missing patient code or invalid threshold"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Patient not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /patient/{code}/treatment-gaps [get]
+func GetPatientTreatmentGaps(c *gin.Context) {
+	threshold, err := treatmentGapThreshold(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid threshold",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	patient, ok := getPatientByCodeParam(c, db)
+	if !ok {
+		return
+	}
+
+	visits, err := attendedVisitDates(db, patient.PatientCode)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve treatment history",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg: "Treatment gaps retrieved",
+		Data: model.PatientTreatmentGaps{
+			PatientCode:   patient.PatientCode,
+			ThresholdDays: threshold,
+			VisitCount:    len(visits),
+			Gaps:          findTreatmentGaps(visits, threshold),
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func treatmentGaps(t *testing.T, r *gin.Engine, path string, wantStatus int) model.PatientTreatmentGaps {
+	t.Helper()
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: path})
+	assertStatusWithError(t, w, wantStatus, err)
+
+	var resp struct {
+		Data model.PatientTreatmentGaps `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestGetPatientTreatmentGaps(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id/treatment-gaps", GetPatientTreatmentGaps)
+
+	seedVisits(t, db, "GAP001", map[string]string{
+		"2025-01-01": "",
+		"2025-01-20": "",                             // 19 days
+		"2025-03-01": "",                             // 40 days
+		"2025-03-15": model.TreatmentStatusNoShow,    // not a visit
+		"2025-03-31": model.TreatmentStatusCompleted, // 30 days, not above the threshold
+		"2025-06-30": model.TreatmentStatusCompleted, // 91 days
+	})
+	seedVisits(t, db, "REG002", map[string]string{
+		"2025-01-01": "",
+		"2025-01-15": "",
+		"2025-01-29": "",
+	})
+
+	gaps := treatmentGaps(t, r, "/patient/GAP001/treatment-gaps", http.StatusOK)
+	assert.Equal(t, "GAP001", gaps.PatientCode)
+	assert.Equal(t, 30, gaps.ThresholdDays)
+	assert.Equal(t, 5, gaps.VisitCount)
+	assert.Equal(t, []model.TreatmentGap{
+		{StartDate: "2025-01-20", EndDate: "2025-03-01", Days: 40},
+		{StartDate: "2025-03-31", EndDate: "2025-06-30", Days: 91},
+	}, gaps.Gaps)
+
+	gaps = treatmentGaps(t, r, "/patient/GAP001/treatment-gaps?threshold_days=60", http.StatusOK)
+	assert.Equal(t, []model.TreatmentGap{{StartDate: "2025-03-31", EndDate: "2025-06-30", Days: 91}}, gaps.Gaps)
+
+	t.Setenv("TREATMENT_GAP_DAYS", "10")
+	gaps = treatmentGaps(t, r, "/patient/GAP001/treatment-gaps", http.StatusOK)
+	assert.Equal(t, 10, gaps.ThresholdDays)
+	assert.Len(t, gaps.Gaps, 4)
+
+	t.Setenv("TREATMENT_GAP_DAYS", "")
+	gaps = treatmentGaps(t, r, "/patient/REG002/treatment-gaps", http.StatusOK)
+	assert.Equal(t, 3, gaps.VisitCount)
+	assert.Empty(t, gaps.Gaps)
+	assert.NotNil(t, gaps.Gaps)
+}
+
+func TestGetPatientTreatmentGaps_Errors(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/patient/:id/treatment-gaps", GetPatientTreatmentGaps)
+	seedVisits(t, db, "GAP002", map[string]string{"2025-01-01": ""})
+
+	treatmentGaps(t, r, "/patient/UNKNOWN/treatment-gaps", http.StatusNotFound)
+	for _, threshold := range []string{"0", "-5", "month"} {
+		treatmentGaps(t, r, "/patient/GAP002/treatment-gaps?threshold_days="+threshold, http.StatusBadRequest)
+	}
+}
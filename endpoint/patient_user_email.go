@@ -0,0 +1,111 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// PATIENT_USER_EMAIL_SYNC values: "sync" (default) carries a patient email
+// change over to the linked user account, "reject" refuses it.
+const (
+	patientEmailSync   = "sync"
+	patientEmailReject = "reject"
+)
+
+// patientEmailSyncMode returns how a patient email change is applied to the
+// patient's linked user account.
+func patientEmailSyncMode() string {
+	if strings.EqualFold(strings.TrimSpace(os.Getenv("PATIENT_USER_EMAIL_SYNC")), patientEmailReject) {
+		return patientEmailReject
+	}
+	return patientEmailSync
+}
+
+// findUserByEmail returns the user whose email matches case-insensitively,
+// or nil when there is none.
+func findUserByEmail(db *gorm.DB, email string) (*model.User, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, nil
+	}
+	var user model.User
+	err := db.Where("LOWER(email) = ?", strings.ToLower(email)).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// linkedUserForEmailChange checks a patient email change from oldEmail to
+// newEmail against the user account linked by oldEmail. It returns the user
+// with its email updated when the change must be synced, nil when no user is
+// affected, and false after writing an error response when the change is
+// rejected or the new email belongs to another user.
+func linkedUserForEmailChange(c *gin.Context, db *gorm.DB, oldEmail, newEmail string) (*model.User, bool) {
+	if strings.EqualFold(strings.TrimSpace(oldEmail), strings.TrimSpace(newEmail)) {
+		return nil, true
+	}
+
+	user, err := findUserByEmail(db, oldEmail)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to look up linked user",
+			Err: err,
+		})
+		return nil, false
+	}
+	if user == nil {
+		return nil, true
+	}
+
+	if patientEmailSyncMode() == patientEmailReject {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Patient email is linked to a user account and cannot be changed",
+			Err: fmt.Errorf("patient email %q belongs to user %d", oldEmail, user.ID),
+		})
+		return nil, false
+	}
+
+	other, err := findUserByEmail(db, newEmail)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to validate email uniqueness",
+			Err: err,
+		})
+		return nil, false
+	}
+	if other != nil && other.ID != user.ID {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Email already exists",
+			Err: ErrUserEmailAlreadyExists,
+		})
+		return nil, false
+	}
+
+	user.Email = strings.TrimSpace(newEmail)
+	return user, true
+}
+
+// savePatientAndLinkedUser saves the patient and, when user is not nil, the
+// user's new email in one transaction.
+func savePatientAndLinkedUser(db *gorm.DB, patient *model.Patient, user *model.User) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(patient).Error; err != nil {
+			return err
+		}
+		if user == nil {
+			return nil
+		}
+		return tx.Model(user).Update("email", user.Email).Error
+	})
+}
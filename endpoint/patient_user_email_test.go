@@ -0,0 +1,69 @@
+package endpoint
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func createLinkedPatient(t *testing.T, db *gorm.DB, email string) (model.Patient, model.User) {
+	t.Helper()
+	user := model.User{Name: "Linked User", Email: email, Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	assert.NoError(t, db.Create(&user).Error)
+	patient := createTestPatient(t, db)
+	assert.NoError(t, db.Model(&patient).Update("email", email).Error)
+	return patient, user
+}
+
+func TestUpdatePatient_SyncsLinkedUserEmail(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.PATCH("/patient/:id", UpdatePatient)
+	patient, user := createLinkedPatient(t, db, "linked@example.com")
+
+	rr := doPatchPatient(t, r, patient.ID, map[string]interface{}{"email": "moved@example.com"})
+	assertStatus(t, rr, http.StatusOK)
+
+	var reloadedUser model.User
+	assert.NoError(t, db.First(&reloadedUser, user.ID).Error)
+	assert.Equal(t, "moved@example.com", reloadedUser.Email)
+	var reloadedPatient model.Patient
+	assert.NoError(t, db.First(&reloadedPatient, patient.ID).Error)
+	assert.Equal(t, "moved@example.com", reloadedPatient.Email)
+}
+
+func TestUpdatePatient_LinkedUserEmailConflict(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.PATCH("/patient/:id", UpdatePatient)
+	patient, user := createLinkedPatient(t, db, "owner@example.com")
+	taken := model.User{Name: "Other User", Email: "taken@example.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	assert.NoError(t, db.Create(&taken).Error)
+
+	rr := doPatchPatient(t, r, patient.ID, map[string]interface{}{"email": "Taken@example.com"})
+	assertStatus(t, rr, http.StatusBadRequest)
+
+	// Neither record changes.
+	var reloadedUser model.User
+	assert.NoError(t, db.First(&reloadedUser, user.ID).Error)
+	assert.Equal(t, "owner@example.com", reloadedUser.Email)
+	var reloadedPatient model.Patient
+	assert.NoError(t, db.First(&reloadedPatient, patient.ID).Error)
+	assert.Equal(t, "owner@example.com", reloadedPatient.Email)
+}
+
+func TestUpdatePatient_RejectLinkedUserEmailChange(t *testing.T) {
+	t.Setenv("PATIENT_USER_EMAIL_SYNC", "reject")
+	r, db := setupEndpointTest(t)
+	r.PATCH("/patient/:id", UpdatePatient)
+	patient, _ := createLinkedPatient(t, db, "locked@example.com")
+
+	rr := doPatchPatient(t, r, patient.ID, map[string]interface{}{"email": "new@example.com"})
+	assertStatus(t, rr, http.StatusBadRequest)
+
+	// Patients without an account can still change their email.
+	unlinked := createTestPatient(t, db)
+	rr = doPatchPatient(t, r, unlinked.ID, map[string]interface{}{"email": "free@example.com"})
+	assertStatus(t, rr, http.StatusOK)
+}
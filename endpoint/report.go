@@ -0,0 +1,423 @@
+package endpoint
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// diseaseLookup maps a lowercased disease name or codename to the index of its
+// row in the report.
+func diseaseLookup(diseases []model.Disease) ([]model.DiseaseTreatmentCount, map[string]int) {
+	counts := make([]model.DiseaseTreatmentCount, 0, len(diseases))
+	index := make(map[string]int, len(diseases)*2)
+	for i, d := range diseases {
+		counts = append(counts, model.DiseaseTreatmentCount{DiseaseID: d.ID, Name: d.Name, Codename: d.Codename})
+		index[strings.ToLower(strings.TrimSpace(d.Name))] = i
+		index[strings.ToLower(strings.TrimSpace(d.Codename))] = i
+	}
+	return counts, index
+}
+
+// patientDiseaseIndexes resolves the comma-separated health history of a
+// patient to report rows. Entries that match no known disease are ignored.
+func patientDiseaseIndexes(healthHistory string, index map[string]int) []int {
+	seen := make(map[int]bool)
+	var matches []int
+	for _, entry := range strings.Split(healthHistory, ",") {
+		i, ok := index[strings.ToLower(strings.TrimSpace(entry))]
+		if !ok || seen[i] {
+			continue
+		}
+		seen[i] = true
+		matches = append(matches, i)
+	}
+	return matches
+}
+
+// computeTreatmentsByDisease counts treatments between start and end
+// (inclusive) per disease in the treated patient's health history. A
+// treatment for a patient with several diseases counts towards each of them.
+func computeTreatmentsByDisease(db *gorm.DB, start, end time.Time) (model.TreatmentsByDiseaseReport, error) {
+	report := model.TreatmentsByDiseaseReport{
+		StartDate: start.Format(cadenceDateLayout),
+		EndDate:   end.Format(cadenceDateLayout),
+	}
+
+	var diseases []model.Disease
+	if err := db.Order("name ASC").Find(&diseases).Error; err != nil {
+		return report, err
+	}
+	counts, index := diseaseLookup(diseases)
+
+	var rows []struct {
+		PatientCode   string
+		HealthHistory string
+	}
+	err := db.Model(&model.Treatment{}).
+		Select("treatments.patient_code, patients.health_history").
+		Joins("JOIN patients ON patients.patient_code = treatments.patient_code AND patients.deleted_at IS NULL").
+		Where("treatments.treatment_date BETWEEN ? AND ?", report.StartDate, report.EndDate).
+		Scan(&rows).Error
+	if err != nil {
+		return report, err
+	}
+
+	patients := make([]map[string]bool, len(counts))
+	for _, row := range rows {
+		for _, i := range patientDiseaseIndexes(row.HealthHistory, index) {
+			counts[i].TreatmentCount++
+			if patients[i] == nil {
+				patients[i] = make(map[string]bool)
+			}
+			patients[i][row.PatientCode] = true
+		}
+	}
+
+	report.Diseases = make([]model.DiseaseTreatmentCount, 0, len(counts))
+	for i, count := range counts {
+		if count.TreatmentCount == 0 {
+			continue
+		}
+		count.PatientCount = len(patients[i])
+		report.Diseases = append(report.Diseases, count)
+	}
+	sort.SliceStable(report.Diseases, func(a, b int) bool {
+		return report.Diseases[a].TreatmentCount > report.Diseases[b].TreatmentCount
+	})
+	return report, nil
+}
+
+// GetTreatmentsByDisease godoc
+// @Summary      Treatment counts by disease
+// @Description  Count treatments in a date range grouped by the diseases listed in each patient's health history. Diseases are matched by name or codename, case-insensitively. Defaults to the last 12 weeks.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success      200 {object} util.APIResponse{data=model.TreatmentsByDiseaseReport} "Report generated"
+// @Failure      400 {object} util.APIResponse "Invalid date range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /report/treatments-by-disease [get]
+func GetTreatmentsByDisease(c *gin.Context) {
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid date range",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := computeTreatmentsByDisease(db, start, end)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to generate report",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Report generated",
+		Data: report,
+	})
+}
+
+// noShowRate returns noShow / (completed + noShow) rounded to four decimals,
+// or 0 when nothing was attended or missed.
+func noShowRate(completed, noShow int64) float64 {
+	if completed+noShow == 0 {
+		return 0
+	}
+	return math.Round(float64(noShow)/float64(completed+noShow)*10000) / 10000
+}
+
+// addNoShowCount adds count treatments with the given status to the totals.
+// Scheduled treatments have no outcome yet and are skipped.
+func addNoShowCount(completed, noShow, cancelled *int64, status string, count int64) {
+	switch status {
+	case model.TreatmentStatusCompleted:
+		*completed += count
+	case model.TreatmentStatusNoShow:
+		*noShow += count
+	case model.TreatmentStatusCancelled:
+		*cancelled += count
+	}
+}
+
+// computeNoShowReport counts treatment statuses between start and end
+// (inclusive) per therapist and overall.
+func computeNoShowReport(db *gorm.DB, start, end time.Time) (model.NoShowReport, error) {
+	report := model.NoShowReport{
+		StartDate:  start.Format(cadenceDateLayout),
+		EndDate:    end.Format(cadenceDateLayout),
+		Therapists: []model.TherapistNoShowRate{},
+	}
+
+	var rows []struct {
+		TherapistID   uint
+		TherapistName string
+		Status        string
+		Count         int64
+	}
+	err := db.Model(&model.Treatment{}).
+		Select("treatments.therapist_id, therapists.full_name AS therapist_name, treatments.status, COUNT(*) AS count").
+		Joins("LEFT JOIN therapists ON therapists.id = treatments.therapist_id").
+		Where("treatments.treatment_date BETWEEN ? AND ?", report.StartDate, report.EndDate).
+		Group("treatments.therapist_id, therapists.full_name, treatments.status").
+		Order("treatments.therapist_id ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return report, err
+	}
+
+	index := make(map[uint]int)
+	for _, row := range rows {
+		i, ok := index[row.TherapistID]
+		if !ok {
+			i = len(report.Therapists)
+			index[row.TherapistID] = i
+			report.Therapists = append(report.Therapists, model.TherapistNoShowRate{TherapistID: row.TherapistID, TherapistName: row.TherapistName})
+		}
+		t := &report.Therapists[i]
+		addNoShowCount(&t.Completed, &t.NoShow, &t.Cancelled, row.Status, row.Count)
+		addNoShowCount(&report.Completed, &report.NoShow, &report.Cancelled, row.Status, row.Count)
+	}
+
+	for i := range report.Therapists {
+		t := &report.Therapists[i]
+		t.NoShowRate = noShowRate(t.Completed, t.NoShow)
+	}
+	report.NoShowRate = noShowRate(report.Completed, report.NoShow)
+	return report, nil
+}
+
+// GetNoShowReport godoc
+// @Summary      No-show statistics
+// @Description  No-show rate per therapist and overall for treatments in a date range. The rate is no_show / (completed + no_show); cancelled treatments are counted but excluded from the rate. Defaults to the last 12 weeks.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success      200 {object} util.APIResponse{data=model.NoShowReport} "Report generated"
+// @Failure      400 {object} util.APIResponse "Invalid date range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /report/no-shows [get]
+func GetNoShowReport(c *gin.Context) {
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid date range",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := computeNoShowReport(db, start, end)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to generate report",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Report generated",
+		Data: report,
+	})
+}
+
+// computeTherapistRetention counts, per therapist, the patients with two or
+// more attended treatments between start and end (inclusive) and those with
+// exactly one. Only completed treatments are visits.
+func computeTherapistRetention(db *gorm.DB, start, end time.Time) (model.TherapistRetentionReport, error) {
+	report := model.TherapistRetentionReport{
+		StartDate:  start.Format(cadenceDateLayout),
+		EndDate:    end.Format(cadenceDateLayout),
+		Therapists: []model.TherapistRetention{},
+	}
+
+	visits := db.Model(&model.Treatment{}).
+		Select("treatments.therapist_id, treatments.patient_code, COUNT(*) AS visits").
+		Joins("JOIN patients ON patients.patient_code = treatments.patient_code AND patients.deleted_at IS NULL").
+		Where("treatments.treatment_date BETWEEN ? AND ?", report.StartDate, report.EndDate).
+		Scopes(attendedStatusScope).
+		Group("treatments.therapist_id, treatments.patient_code")
+
+	err := db.Table("(?) AS visits", visits).
+		Select(`visits.therapist_id, therapists.full_name AS therapist_name,
+			SUM(CASE WHEN visits.visits >= 2 THEN 1 ELSE 0 END) AS returning_patients,
+			SUM(CASE WHEN visits.visits = 1 THEN 1 ELSE 0 END) AS one_time_patients`).
+		Joins("LEFT JOIN therapists ON therapists.id = visits.therapist_id").
+		Group("visits.therapist_id, therapists.full_name").
+		Order("visits.therapist_id ASC").
+		Scan(&report.Therapists).Error
+	if err != nil {
+		return report, err
+	}
+
+	for i := range report.Therapists {
+		t := &report.Therapists[i]
+		if total := t.ReturningPatients + t.OneTimePatients; total > 0 {
+			t.RetentionRate = math.Round(float64(t.ReturningPatients)/float64(total)*10000) / 10000
+		}
+	}
+	return report, nil
+}
+
+// GetTherapistRetention godoc
+// @Summary      Therapist patient retention
+// @Description  Per therapist, the number of patients with two or more attended treatments in a date range versus exactly one, and the share that returned. Only completed treatments are counted. Defaults to the last 12 weeks.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success      200 {object} util.APIResponse{data=model.TherapistRetentionReport} "Report generated"
+// @Failure      400 {object} util.APIResponse "Invalid date range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /report/therapist-retention [get]
+func GetTherapistRetention(c *gin.Context) {
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid date range",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := computeTherapistRetention(db, start, end)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to generate report",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Report generated",
+		Data: report,
+	})
+}
+
+// computeRevenue sums the cost of attended treatments between start and end
+// (inclusive) per therapist and per calendar month.
+func computeRevenue(db *gorm.DB, start, end time.Time) (model.RevenueReport, error) {
+	report := model.RevenueReport{
+		StartDate:  start.Format(cadenceDateLayout),
+		EndDate:    end.Format(cadenceDateLayout),
+		Therapists: []model.TherapistRevenue{},
+		Months:     []model.MonthlyRevenue{},
+	}
+
+	attended := func() *gorm.DB {
+		return db.Model(&model.Treatment{}).
+			Where("treatments.treatment_date BETWEEN ? AND ?", report.StartDate, report.EndDate).
+			Scopes(attendedStatusScope)
+	}
+
+	err := attended().
+		Select("treatments.therapist_id, therapists.full_name AS therapist_name, SUM(treatments.cost) AS total, COUNT(*) AS treatment_count").
+		Joins("LEFT JOIN therapists ON therapists.id = treatments.therapist_id").
+		Group("treatments.therapist_id, therapists.full_name").
+		Order("treatments.therapist_id ASC").
+		Scan(&report.Therapists).Error
+	if err != nil {
+		return report, err
+	}
+
+	err = attended().
+		Select("SUBSTR(treatments.treatment_date, 1, 7) AS month, SUM(treatments.cost) AS total, COUNT(*) AS treatment_count").
+		Group("SUBSTR(treatments.treatment_date, 1, 7)").
+		Order("month ASC").
+		Scan(&report.Months).Error
+	if err != nil {
+		return report, err
+	}
+
+	for _, m := range report.Months {
+		report.Total += m.Total
+	}
+	return report, nil
+}
+
+// GetRevenueReport godoc
+// @Summary      Treatment revenue
+// @Description  Sum the cost of treatments in a date range per therapist and per month (YYYY-MM), with the number of treatments. Only completed treatments are counted. Defaults to the last 12 weeks.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success      200 {object} util.APIResponse{data=model.RevenueReport} "Report generated"
+// @Failure      400 {object} util.APIResponse "Invalid date range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /report/revenue [get]
+func GetRevenueReport(c *gin.Context) {
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid date range",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := computeRevenue(db, start, end)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to generate report",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Report generated",
+		Data: report,
+	})
+}
@@ -0,0 +1,257 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func seedDiseaseReportData(t *testing.T, db *gorm.DB) {
+	t.Helper()
+	for _, d := range []model.Disease{
+		{Name: "Diabetes", Codename: "diabetes"},
+		{Name: "Hypertension", Codename: "hypertension"},
+		{Name: "Asthma", Codename: "asthma"},
+	} {
+		assert.NoError(t, db.Create(&d).Error)
+	}
+
+	patients := []model.Patient{
+		{FullName: "Ann", PatientCode: "A001", HealthHistory: "Diabetes, hypertension"},
+		{FullName: "Bob", PatientCode: "B001", HealthHistory: "DIABETES"},
+		{FullName: "Cid", PatientCode: "C001", HealthHistory: "Unknown condition"},
+	}
+	for i := range patients {
+		assert.NoError(t, db.Create(&patients[i]).Error)
+	}
+
+	therapist := model.Therapist{FullName: "Dr. Report", NIK: "NIK-REPORT"}
+	assert.NoError(t, db.Create(&therapist).Error)
+
+	for _, tr := range []struct{ code, date string }{
+		{"A001", "2025-01-06"},
+		{"A001", "2025-01-20"},
+		{"B001", "2025-01-10"},
+		{"B001", "2025-03-01"}, // outside the range
+		{"C001", "2025-01-15"},
+	} {
+		treatment := model.Treatment{PatientCode: tr.code, TherapistID: therapist.ID, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-"}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+}
+
+func TestGetTreatmentsByDisease_GroupsCounts(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	seedDiseaseReportData(t, db)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/treatments-by-disease", requestPath: "/report/treatments-by-disease?start_date=2025-01-01&end_date=2025-01-31", handler: GetTreatmentsByDisease})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data model.TreatmentsByDiseaseReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "2025-01-01", resp.Data.StartDate)
+	assert.Equal(t, "2025-01-31", resp.Data.EndDate)
+
+	got := map[string][2]int{}
+	for _, d := range resp.Data.Diseases {
+		got[d.Name] = [2]int{d.TreatmentCount, d.PatientCount}
+	}
+	assert.Equal(t, map[string][2]int{
+		"Diabetes":     {3, 2},
+		"Hypertension": {2, 1},
+	}, got)
+	if assert.Len(t, resp.Data.Diseases, 2) {
+		assert.Equal(t, "Diabetes", resp.Data.Diseases[0].Name)
+	}
+}
+
+func TestGetTreatmentsByDisease_InvalidRange(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+	r.GET("/report/treatments-by-disease", GetTreatmentsByDisease)
+
+	for _, query := range []string{"?start_date=2025-13-01", "?start_date=2025-02-01&end_date=2025-01-01"} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/report/treatments-by-disease" + query})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetNoShowReport_ComputesRates(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	ann := model.Therapist{FullName: "Dr. Ann", NIK: "NIK-NOSHOW-1"}
+	bob := model.Therapist{FullName: "Dr. Bob", NIK: "NIK-NOSHOW-2"}
+	assert.NoError(t, db.Create(&ann).Error)
+	assert.NoError(t, db.Create(&bob).Error)
+
+	for _, tr := range []struct {
+		therapist uint
+		date      string
+		status    string
+	}{
+		{ann.ID, "2025-01-06", ""}, // defaults to completed
+		{ann.ID, "2025-01-07", model.TreatmentStatusCompleted},
+		{ann.ID, "2025-01-08", model.TreatmentStatusCompleted},
+		{ann.ID, "2025-01-09", model.TreatmentStatusNoShow},
+		{ann.ID, "2025-01-10", model.TreatmentStatusCancelled},
+		{bob.ID, "2025-01-06", model.TreatmentStatusNoShow},
+		{bob.ID, "2025-01-07", model.TreatmentStatusCompleted},
+		{bob.ID, "2025-01-08", model.TreatmentStatusScheduled}, // no outcome yet
+		{bob.ID, "2025-03-01", model.TreatmentStatusNoShow},    // outside the range
+	} {
+		treatment := model.Treatment{PatientCode: "N001", TherapistID: tr.therapist, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", Status: tr.status}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/no-shows", requestPath: "/report/no-shows?start_date=2025-01-01&end_date=2025-01-31", handler: GetNoShowReport})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data model.NoShowReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(4), resp.Data.Completed)
+	assert.Equal(t, int64(2), resp.Data.NoShow)
+	assert.Equal(t, int64(1), resp.Data.Cancelled)
+	assert.Equal(t, 0.3333, resp.Data.NoShowRate)
+
+	if assert.Len(t, resp.Data.Therapists, 2) {
+		assert.Equal(t, model.TherapistNoShowRate{TherapistID: ann.ID, TherapistName: "Dr. Ann", Completed: 3, NoShow: 1, Cancelled: 1, NoShowRate: 0.25}, resp.Data.Therapists[0])
+		assert.Equal(t, model.TherapistNoShowRate{TherapistID: bob.ID, TherapistName: "Dr. Bob", Completed: 1, NoShow: 1, NoShowRate: 0.5}, resp.Data.Therapists[1])
+	}
+}
+
+func TestGetNoShowReport_EmptyRange(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/no-shows", requestPath: "/report/no-shows?start_date=2025-01-01&end_date=2025-01-31", handler: GetNoShowReport})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data model.NoShowReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Zero(t, resp.Data.NoShowRate)
+	assert.Empty(t, resp.Data.Therapists)
+}
+
+func TestGetTherapistRetention_SplitsReturningPatients(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	ann := model.Therapist{FullName: "Dr. Ann", NIK: "NIK-RETAIN-1"}
+	bob := model.Therapist{FullName: "Dr. Bob", NIK: "NIK-RETAIN-2"}
+	assert.NoError(t, db.Create(&ann).Error)
+	assert.NoError(t, db.Create(&bob).Error)
+	for _, code := range []string{"R001", "R002", "R003", "R004", "R005"} {
+		assert.NoError(t, db.Create(&model.Patient{FullName: "Patient " + code, PatientCode: code, Email: code + "@test.com"}).Error)
+	}
+
+	for _, tr := range []struct {
+		therapist uint
+		patient   string
+		date      string
+		status    string
+	}{
+		{ann.ID, "R001", "2025-01-06", ""},
+		{ann.ID, "R001", "2025-01-13", ""}, // R001 returned to Ann
+		{ann.ID, "R002", "2025-01-07", ""}, // one visit
+		{ann.ID, "R003", "2025-01-08", ""},
+		{ann.ID, "R003", "2025-01-15", model.TreatmentStatusNoShow}, // missed, so still one visit
+		{ann.ID, "R004", "2024-12-01", ""},                          // outside the range
+		{ann.ID, "R004", "2025-01-09", ""},
+		{bob.ID, "R001", "2025-01-20", ""}, // Ann's returning patient, once with Bob
+		{bob.ID, "R005", "2025-01-06", ""},
+		{bob.ID, "R005", "2025-01-13", ""},
+		{bob.ID, "R005", "2025-01-20", ""},
+	} {
+		treatment := model.Treatment{PatientCode: tr.patient, TherapistID: tr.therapist, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", Status: tr.status}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/therapist-retention", requestPath: "/report/therapist-retention?start_date=2025-01-01&end_date=2025-01-31", handler: GetTherapistRetention})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data model.TherapistRetentionReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Data.Therapists, 2) {
+		assert.Equal(t, model.TherapistRetention{TherapistID: ann.ID, TherapistName: "Dr. Ann", ReturningPatients: 1, OneTimePatients: 3, RetentionRate: 0.25}, resp.Data.Therapists[0])
+		assert.Equal(t, model.TherapistRetention{TherapistID: bob.ID, TherapistName: "Dr. Bob", ReturningPatients: 1, OneTimePatients: 1, RetentionRate: 0.5}, resp.Data.Therapists[1])
+	}
+}
+
+func TestGetTherapistRetention_InvalidRange(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/therapist-retention", requestPath: "/report/therapist-retention?start_date=2025-02-01&end_date=2025-01-01", handler: GetTherapistRetention})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
+
+func TestGetRevenueReport_SumsCosts(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	ann := model.Therapist{FullName: "Dr. Ann", NIK: "NIK-REVENUE-1"}
+	bob := model.Therapist{FullName: "Dr. Bob", NIK: "NIK-REVENUE-2"}
+	assert.NoError(t, db.Create(&ann).Error)
+	assert.NoError(t, db.Create(&bob).Error)
+
+	for _, tr := range []struct {
+		therapist uint
+		date      string
+		cost      int64
+		status    string
+	}{
+		{ann.ID, "2025-01-06", 100000, ""},
+		{ann.ID, "2025-01-20", 150000, ""},
+		{ann.ID, "2025-02-03", 120000, ""},
+		{ann.ID, "2025-02-10", 90000, model.TreatmentStatusCancelled}, // not billed
+		{bob.ID, "2025-01-15", 200000, ""},
+		{bob.ID, "2025-02-14", 80000, model.TreatmentStatusNoShow}, // not billed
+		{bob.ID, "2025-03-01", 300000, ""},                         // outside the range
+	} {
+		treatment := model.Treatment{PatientCode: "V001", TherapistID: tr.therapist, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", Status: tr.status, Cost: tr.cost}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/revenue", requestPath: "/report/revenue?start_date=2025-01-01&end_date=2025-02-28", handler: GetRevenueReport})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.RevenueReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.RevenueReport{
+		StartDate: "2025-01-01",
+		EndDate:   "2025-02-28",
+		Total:     570000,
+		Therapists: []model.TherapistRevenue{
+			{TherapistID: ann.ID, TherapistName: "Dr. Ann", Total: 370000, TreatmentCount: 3},
+			{TherapistID: bob.ID, TherapistName: "Dr. Bob", Total: 200000, TreatmentCount: 1},
+		},
+		Months: []model.MonthlyRevenue{
+			{Month: "2025-01", Total: 450000, TreatmentCount: 3},
+			{Month: "2025-02", Total: 120000, TreatmentCount: 1},
+		},
+	}, resp.Data)
+}
+
+func TestGetRevenueReport_Empty(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/revenue", requestPath: "/report/revenue?start_date=2025-01-01&end_date=2025-01-31", handler: GetRevenueReport})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Contains(t, w.Body.String(), `"therapists":[]`)
+	assert.Contains(t, w.Body.String(), `"months":[]`)
+	assert.Contains(t, w.Body.String(), `"total":0`)
+}
@@ -0,0 +1,86 @@
+package endpoint
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// cancelOnQuery registers a callback that cancels the request context right
+// before GORM runs a SELECT, simulating a client that disconnects mid-query.
+// The error seen by the query is reported through the returned channel.
+func cancelOnQuery(t *testing.T, db *gorm.DB, cancel context.CancelFunc) <-chan error {
+	t.Helper()
+	errs := make(chan error, 16)
+	assert.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:cancel_request", func(tx *gorm.DB) {
+		cancel()
+	}))
+	assert.NoError(t, db.Callback().Query().After("gorm:query").Register("test:capture_error", func(tx *gorm.DB) {
+		errs <- tx.Error
+	}))
+	return errs
+}
+
+func TestGetDBOrAbort_BindsRequestContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupEndpointTestDB(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	c.Set(middleware.DBKey, db)
+
+	reqDB, ok := getDBOrAbort(c)
+	if !assert.True(t, ok) {
+		return
+	}
+	cancel()
+
+	var patients []model.Patient
+	err := reqDB.Find(&patients).Error
+	assert.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+}
+
+func TestHandlersAbortQueryOnCancelledRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		handler gin.HandlerFunc
+	}{
+		{"patients", "/patient", ListPatients},
+		{"treatments", "/treatment", ListTreatments},
+		{"users", "/user", ListUsers},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, db := setupEndpointTest(t)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			errs := cancelOnQuery(t, db, cancel)
+			r.GET(tt.path, tt.handler)
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+			select {
+			case err := <-errs:
+				assert.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+			default:
+				t.Fatal("expected the handler to run a query")
+			}
+		})
+	}
+}
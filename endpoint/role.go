@@ -0,0 +1,60 @@
+package endpoint
+
+import (
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+// ListRoleConstants godoc
+// @Summary      List role constants
+// @Description  Return the canonical role IDs and names the server uses for authorization
+// @Tags         Role
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=[]model.RoleConstant} "Role constants retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Router       /role/constants [get]
+func ListRoleConstants(c *gin.Context) {
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Role constants retrieved",
+		Data: model.RoleConstants(),
+	})
+}
+
+// GetCurrentUserPermissions godoc
+// @Summary      Get the current user's permissions
+// @Description  Return the caller's role and the permissions it grants, from the same table the routes use for authorization
+// @Tags         Role
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=model.UserPermissions} "Permissions retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Router       /user/me/permissions [get]
+func GetCurrentUserPermissions(c *gin.Context) {
+	roleID, ok := middleware.GetRoleID(c)
+	if !ok {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{
+			Msg: "Role information not available",
+			Err: fmt.Errorf("role id not found in context"),
+		})
+		return
+	}
+
+	permissions := model.UserPermissions{RoleID: roleID, Permissions: model.PermissionsForRole(roleID)}
+	for _, role := range model.RoleConstants() {
+		if role.ID == roleID {
+			permissions.Role = role.Name
+		}
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Permissions retrieved",
+		Data: permissions,
+	})
+}
@@ -0,0 +1,81 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListRoleConstants_MatchesSeededRoles(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, model.SeedRoles(db))
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/role/constants", requestPath: "/role/constants", handler: ListRoleConstants})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data []model.RoleConstant `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+
+	var seeded []model.Role
+	assert.NoError(t, db.Order("id").Find(&seeded).Error)
+	assert.Len(t, resp.Data, len(seeded))
+	for i, role := range seeded {
+		if i >= len(resp.Data) {
+			break
+		}
+		assert.Equal(t, uint32(role.ID), resp.Data[i].ID)
+		assert.Equal(t, role.Name, resp.Data[i].Name)
+	}
+
+	ids := map[string]uint32{}
+	for _, rc := range resp.Data {
+		ids[rc.Name] = rc.ID
+	}
+	assert.Equal(t, model.RoleAdmin, ids["Admin"])
+	assert.Equal(t, model.RoleUser, ids["User"])
+	assert.Equal(t, model.RoleTherapist, ids["Therapist"])
+}
+
+func TestGetCurrentUserPermissions_DependsOnRole(t *testing.T) {
+	permissionsFor := func(roleID uint32) model.UserPermissions {
+		t.Helper()
+		r, _ := setupEndpointTest(t)
+		r.Use(withAuthContext(1, roleID))
+		// Registered next to /user/:id as in main.go.
+		r.GET("/user/:id", func(c *gin.Context) { c.Status(http.StatusTeapot) })
+		r.GET("/user/me/permissions", GetCurrentUserPermissions)
+
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/user/me/permissions"})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data model.UserPermissions `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	admin := permissionsFor(model.RoleAdmin)
+	therapist := permissionsFor(model.RoleTherapist)
+
+	assert.Equal(t, "Admin", admin.Role)
+	assert.Equal(t, "Therapist", therapist.Role)
+	assert.Contains(t, admin.Permissions, model.PermissionManageUsers)
+	assert.Contains(t, admin.Permissions, model.PermissionManageTreatments)
+	assert.NotContains(t, therapist.Permissions, model.PermissionManageUsers)
+	assert.NotContains(t, therapist.Permissions, model.PermissionViewReports)
+	assert.Equal(t, []model.Permission{
+		model.PermissionViewClinicHours,
+		model.PermissionViewPatientHistory,
+		model.PermissionViewTags,
+		model.PermissionViewTherapists,
+		model.PermissionManageTreatments,
+	}, therapist.Permissions)
+	assert.Greater(t, len(admin.Permissions), len(therapist.Permissions))
+}
@@ -0,0 +1,118 @@
+package endpoint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSearchLimit = 5
+	maxSearchLimit     = 20
+)
+
+// searchPatients returns up to limit patients matching q with the patient
+// list's keyword filter.
+func searchPatients(db *gorm.DB, q string, limit int) ([]model.SearchResult, error) {
+	var patients []model.Patient
+	query := db.Model(&model.Patient{}).Select("patients.id, patients.full_name, patients.patient_code, patients.phone_number")
+	err := applyPatientKeywordFilter(query, q).Order("patients.full_name ASC, patients.id ASC").Limit(limit).Find(&patients).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]model.SearchResult, 0, len(patients))
+	for _, p := range patients {
+		results = append(results, model.SearchResult{
+			Type:        model.SearchPatient,
+			ID:          p.ID,
+			FullName:    p.FullName,
+			Code:        p.PatientCode,
+			PhoneNumber: p.PhoneNumber,
+		})
+	}
+	return results, nil
+}
+
+// searchTherapists returns up to limit therapists matching q with the
+// therapist list's keyword filter.
+func searchTherapists(db *gorm.DB, q string, limit int) ([]model.SearchResult, error) {
+	var therapists []model.Therapist
+	query := db.Model(&model.Therapist{}).Select("id, full_name, phone_number")
+	err := applyTherapistKeywordFilter(query, q).Order("full_name ASC, id ASC").Limit(limit).Find(&therapists).Error
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]model.SearchResult, 0, len(therapists))
+	for _, t := range therapists {
+		results = append(results, model.SearchResult{
+			Type:        model.SearchTherapist,
+			ID:          t.ID,
+			FullName:    t.FullName,
+			PhoneNumber: t.PhoneNumber,
+		})
+	}
+	return results, nil
+}
+
+// Search godoc
+// @Summary      Search patients and therapists
+// @Description  Find patients by name, code, address or phone and therapists by name or NIK in one call. Each list holds at most limit results (default 5, max 20), ordered by name.
+// @Tags         Search
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        q query string true "Search text"
+// @Param        limit query int false "Results per type (default 5, max 20)"
+// @Success      200 {object} util.APIResponse{data=model.SearchResults} "Search completed"
+// @Failure      400 {object} util.APIResponse "Missing search text"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /search [get]
+func Search(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Search text is required",
+			Err: fmt.Errorf("q is empty"),
+		})
+		return
+	}
+	limit := parseQueryInt(c, "limit", defaultSearchLimit)
+	if limit == 0 || limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	patients, err := searchPatients(db, q, limit)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to search patients",
+			Err: err,
+		})
+		return
+	}
+	therapists, err := searchTherapists(db, q, limit)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to search therapists",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Search completed",
+		Data: model.SearchResults{Patients: patients, Therapists: therapists},
+	})
+}
@@ -0,0 +1,61 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch_PatientsAndTherapists(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/search", Search)
+
+	for i := 1; i <= 3; i++ {
+		assert.NoError(t, db.Create(&model.Patient{FullName: fmt.Sprintf("Budi Patient %d", i), PatientCode: fmt.Sprintf("B%03d", i), PhoneNumber: "0811"}).Error)
+	}
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Someone Else", PatientCode: "S001"}).Error)
+	assert.NoError(t, db.Create(&model.Therapist{FullName: "Budi Therapist", NIK: "3201000000000001", PhoneNumber: "0812"}).Error)
+	assert.NoError(t, db.Create(&model.Therapist{FullName: "Other Therapist", NIK: "3201000000000002"}).Error)
+
+	search := func(query string) model.SearchResults {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/search?" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data model.SearchResults `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	results := search("q=budi")
+	if assert.Len(t, results.Patients, 3) {
+		assert.Equal(t, model.SearchPatient, results.Patients[0].Type)
+		assert.Equal(t, "B001", results.Patients[0].Code)
+	}
+	if assert.Len(t, results.Therapists, 1) {
+		assert.Equal(t, model.SearchTherapist, results.Therapists[0].Type)
+		assert.Equal(t, "Budi Therapist", results.Therapists[0].FullName)
+		assert.Empty(t, results.Therapists[0].Code)
+	}
+
+	// The limit applies to each type separately.
+	results = search("q=budi&limit=2")
+	assert.Len(t, results.Patients, 2)
+	assert.Len(t, results.Therapists, 1)
+
+	// Therapists also match by NIK.
+	results = search("q=3201000000000002")
+	assert.Empty(t, results.Patients)
+	assert.Len(t, results.Therapists, 1)
+	results = search("q=someone")
+	assert.Len(t, results.Patients, 1)
+	assert.Empty(t, results.Therapists)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/search?q=+"})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
@@ -0,0 +1,92 @@
+package endpoint
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findConcurrentGeoAnomalies returns, ordered by user ID, the users whose
+// sessions that expire after now resolve to at least two countries. Sessions
+// whose IP cannot be resolved are ignored.
+func findConcurrentGeoAnomalies(db *gorm.DB, now time.Time) ([]model.ConcurrentGeoAnomaly, error) {
+	var sessions []model.Session
+	err := db.Where("expires_at > ?", now).Order("user_id ASC, created_at DESC, id DESC").Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	countries := map[string]string{}
+	byUser := map[uint][]model.AdminSessionInfo{}
+	var userIDs []uint
+	for _, s := range sessions {
+		country, ok := countries[s.ClientIP]
+		if !ok {
+			country = sessionCountry(db, s.ClientIP)
+			countries[s.ClientIP] = country
+		}
+		if country == "" {
+			continue
+		}
+		if _, seen := byUser[s.UserID]; !seen {
+			userIDs = append(userIDs, s.UserID)
+		}
+		byUser[s.UserID] = append(byUser[s.UserID], adminSessionInfo(s, country))
+	}
+
+	anomalies := []model.ConcurrentGeoAnomaly{}
+	for _, userID := range userIDs {
+		distinct := map[string]bool{}
+		for _, info := range byUser[userID] {
+			distinct[info.Country] = true
+		}
+		if len(distinct) < 2 {
+			continue
+		}
+		anomaly := model.ConcurrentGeoAnomaly{UserID: userID, Sessions: byUser[userID]}
+		for country := range distinct {
+			anomaly.Countries = append(anomaly.Countries, country)
+		}
+		sort.Strings(anomaly.Countries)
+		if err := attachSessionEmails(db, anomaly.Sessions); err != nil {
+			return nil, err
+		}
+		anomaly.Email = anomaly.Sessions[0].Email
+		anomalies = append(anomalies, anomaly)
+	}
+	return anomalies, nil
+}
+
+// GetConcurrentGeoAnomalies godoc
+// @Summary      List users with active sessions in different countries (admin only)
+// @Description  List users whose unexpired sessions come from client IPs that resolve to more than one country (GeoIP, falling back to the newest security log location for the IP), with the countries and the sessions involved. Session tokens are never returned. Admin-only access.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=[]model.ConcurrentGeoAnomaly} "Concurrent geo anomalies retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /security/concurrent-geo-anomalies [get]
+func GetConcurrentGeoAnomalies(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	anomalies, err := findConcurrentGeoAnomalies(db, time.Now())
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to detect concurrent geo anomalies", Err: err})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Concurrent geo anomalies retrieved",
+		Data: anomalies,
+	})
+}
@@ -0,0 +1,65 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetConcurrentGeoAnomalies(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, db.AutoMigrate(&model.SecurityLog{}))
+	r.GET("/security/concurrent-geo-anomalies", GetConcurrentGeoAnomalies)
+
+	countries := map[string]string{
+		"198.51.100.1": "Indonesia",
+		"198.51.100.2": "Indonesia",
+		"203.0.113.9":  "Singapore",
+	}
+	orig := ipLocationLookup
+	ipLocationLookup = func(ip string) util.IPLocation { return util.IPLocation{Country: countries[ip]} }
+	t.Cleanup(func() { ipLocationLookup = orig })
+
+	traveller := model.User{Name: "Traveller", Email: "traveller@example.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	local := model.User{Name: "Local", Email: "local@example.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	stale := model.User{Name: "Stale", Email: "stale@example.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	for _, u := range []*model.User{&traveller, &local, &stale} {
+		assert.NoError(t, db.Create(u).Error)
+	}
+
+	now := time.Now()
+	sessions := []model.Session{
+		{SessionToken: "tok-t1", UserID: traveller.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "198.51.100.1", Browser: "Firefox"},
+		{SessionToken: "tok-t2", UserID: traveller.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "203.0.113.9", Browser: "Chrome"},
+		// Two IPs in the same country are not an anomaly.
+		{SessionToken: "tok-l1", UserID: local.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "198.51.100.1", Browser: "Firefox"},
+		{SessionToken: "tok-l2", UserID: local.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "198.51.100.2", Browser: "Safari"},
+		// Expired sessions are ignored.
+		{SessionToken: "tok-s1", UserID: stale.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "198.51.100.1", Browser: "Firefox"},
+		{SessionToken: "tok-s2", UserID: stale.ID, ExpiresAt: now.Add(-time.Hour), ClientIP: "203.0.113.9", Browser: "Chrome"},
+	}
+	for i := range sessions {
+		assert.NoError(t, db.Create(&sessions[i]).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/security/concurrent-geo-anomalies"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.NotContains(t, w.Body.String(), "tok-")
+
+	var resp struct {
+		Data []model.ConcurrentGeoAnomaly `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Data, 1) {
+		got := resp.Data[0]
+		assert.Equal(t, traveller.ID, got.UserID)
+		assert.Equal(t, "traveller@example.com", got.Email)
+		assert.Equal(t, []string{"Indonesia", "Singapore"}, got.Countries)
+		assert.Len(t, got.Sessions, 2)
+	}
+}
@@ -0,0 +1,79 @@
+package endpoint
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSecurityLogsByIPLimit = 100
+	maxSecurityLogsByIPLimit     = 500
+)
+
+// findSecurityLogsByIP returns one page of the security events recorded for
+// ip, newest first, with the total number of events and the IP's location.
+// The location is resolved once rather than per event.
+func findSecurityLogsByIP(db *gorm.DB, ip string, limit, offset int) (model.SecurityLogsByIP, error) {
+	result := model.SecurityLogsByIP{IP: ip, Events: []model.SecurityLog{}}
+	if err := db.Model(&model.SecurityLog{}).Where("ip = ?", ip).Count(&result.Total).Error; err != nil {
+		return result, err
+	}
+	query := applyPagination(db.Where("ip = ?", ip).Order("created_at DESC, id DESC"), limit, offset)
+	if err := query.Find(&result.Events).Error; err != nil {
+		return result, err
+	}
+
+	loc := ipLocationLookup(ip)
+	result.Location = model.SecurityLogLocation{City: loc.City, Country: loc.Country}
+	return result, nil
+}
+
+// ListSecurityLogsByIP godoc
+// @Summary      List security events for an IP address (admin only)
+// @Description  Get the security events recorded for one IP address, newest first, with the IP's GeoIP location resolved once for the whole list. The location fields are empty when the IP cannot be resolved. Admin-only access.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        ip query string true "IPv4 or IPv6 address"
+// @Param        limit query int false "Maximum events to return (default 100, max 500)"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200 {object} util.APIResponse{data=model.SecurityLogsByIP} "Security logs retrieved"
+// @Failure      400 {object} util.APIResponse "Missing or invalid IP"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /security-log/by-ip [get]
+func ListSecurityLogsByIP(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	ip := strings.TrimSpace(c.Query("ip"))
+	if net.ParseIP(ip) == nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Query parameter ip must be a valid IP address",
+			Err: fmt.Errorf("invalid ip %q", ip),
+		})
+		return
+	}
+
+	limit := parsePositiveInt(c.Query("limit"), defaultSecurityLogsByIPLimit, maxSecurityLogsByIPLimit)
+	result, err := findSecurityLogsByIP(db, ip, limit, parseQueryInt(c, "offset", 0))
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to retrieve security logs", Err: err})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Security logs retrieved",
+		Data: result,
+	})
+}
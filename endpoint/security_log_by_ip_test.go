@@ -0,0 +1,99 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListSecurityLogsByIP(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, db.AutoMigrate(&model.SecurityLog{}))
+	r.GET("/security-log/by-ip", ListSecurityLogsByIP)
+
+	lookups := 0
+	orig := ipLocationLookup
+	ipLocationLookup = func(ip string) util.IPLocation {
+		lookups++
+		if ip == "203.0.113.9" {
+			return util.IPLocation{City: "Singapore", Country: "Singapore"}
+		}
+		return util.IPLocation{}
+	}
+	t.Cleanup(func() { ipLocationLookup = orig })
+
+	logs := []model.SecurityLog{
+		{EventType: "LOGIN_FAILURE", IP: "203.0.113.9", Email: "a@example.com", Message: "first"},
+		{EventType: "LOGIN_FAILURE", IP: "198.51.100.1", Email: "b@example.com", Message: "other ip"},
+		{EventType: "LOGIN_SUCCESS", IP: "203.0.113.9", Email: "a@example.com", Message: "second"},
+		{EventType: "ENDPOINT_CALL", IP: "203.0.113.9", Email: "a@example.com", Message: "third"},
+	}
+	for i := range logs {
+		assert.NoError(t, db.Create(&logs[i]).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/security-log/by-ip?ip=203.0.113.9"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.SecurityLogsByIP `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "203.0.113.9", resp.Data.IP)
+	assert.Equal(t, int64(3), resp.Data.Total)
+	assert.Equal(t, model.SecurityLogLocation{City: "Singapore", Country: "Singapore"}, resp.Data.Location)
+	assert.Equal(t, 1, lookups, "location should be resolved once per request")
+	if assert.Len(t, resp.Data.Events, 3) {
+		// Newest first; rows created in the same instant fall back to id.
+		assert.Equal(t, "third", resp.Data.Events[0].Message)
+		assert.Equal(t, "first", resp.Data.Events[2].Message)
+		for _, e := range resp.Data.Events {
+			assert.Equal(t, "203.0.113.9", e.IP)
+		}
+	}
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/security-log/by-ip?ip=203.0.113.9&limit=1&offset=1"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(3), resp.Data.Total)
+	if assert.Len(t, resp.Data.Events, 1) {
+		assert.Equal(t, "second", resp.Data.Events[0].Message)
+	}
+}
+
+func TestListSecurityLogsByIP_UnknownIPReturnsEmptyList(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, db.AutoMigrate(&model.SecurityLog{}))
+	r.GET("/security-log/by-ip", ListSecurityLogsByIP)
+
+	orig := ipLocationLookup
+	ipLocationLookup = func(string) util.IPLocation { return util.IPLocation{} }
+	t.Cleanup(func() { ipLocationLookup = orig })
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/security-log/by-ip?ip=2001:db8::1"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.SecurityLogsByIP `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(0), resp.Data.Total)
+	assert.NotNil(t, resp.Data.Events)
+	assert.Empty(t, resp.Data.Events)
+	assert.Equal(t, model.SecurityLogLocation{}, resp.Data.Location)
+}
+
+func TestListSecurityLogsByIP_InvalidIP(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, db.AutoMigrate(&model.SecurityLog{}))
+	r.GET("/security-log/by-ip", ListSecurityLogsByIP)
+
+	for _, path := range []string{"/security-log/by-ip", "/security-log/by-ip?ip=not-an-ip"} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: path})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+}
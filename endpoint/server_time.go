@@ -0,0 +1,53 @@
+package endpoint
+
+import (
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+// serverTimeNow is the clock used by GetServerTime; tests replace it.
+var serverTimeNow = time.Now
+
+// ServerTime is the server's current time in the configured timezone.
+type ServerTime struct {
+	Time             string `json:"time" example:"2025-01-15T17:04:05+07:00"`
+	Unix             int64  `json:"unix" example:"1736935445"`
+	Timezone         string `json:"timezone" example:"Asia/Jakarta"`
+	UTCOffset        string `json:"utc_offset" example:"+07:00"`
+	UTCOffsetSeconds int    `json:"utc_offset_seconds" example:"25200"`
+}
+
+// GetServerTime godoc
+// @Summary      Get server time
+// @Description  Return the server's current time in its configured timezone, with the zone name and UTC offset, so clients can correct their clocks
+// @Tags         Time
+// @Produce      json
+// @Success      200 {object} util.APIResponse{data=ServerTime} "Server time retrieved"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /time [get]
+func GetServerTime(c *gin.Context) {
+	loc, err := time.LoadLocation(config.Timezone)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to load timezone",
+			Err: err,
+		})
+		return
+	}
+
+	now := serverTimeNow().In(loc)
+	_, offset := now.Zone()
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg: "Server time retrieved",
+		Data: ServerTime{
+			Time:             now.Format(time.RFC3339),
+			Unix:             now.Unix(),
+			Timezone:         loc.String(),
+			UTCOffset:        now.Format("-07:00"),
+			UTCOffsetSeconds: offset,
+		},
+	})
+}
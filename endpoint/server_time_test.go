@@ -0,0 +1,34 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetServerTime(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	original := serverTimeNow
+	serverTimeNow = func() time.Time { return time.Date(2025, 1, 15, 10, 4, 5, 0, time.UTC) }
+	t.Cleanup(func() { serverTimeNow = original })
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/time", requestPath: "/time", handler: GetServerTime})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data ServerTime `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, config.Timezone, resp.Data.Timezone)
+	assert.Equal(t, "2025-01-15T17:04:05+07:00", resp.Data.Time)
+	assert.Equal(t, int64(1736935445), resp.Data.Unix)
+	assert.Equal(t, "+07:00", resp.Data.UTCOffset)
+	assert.Equal(t, 7*60*60, resp.Data.UTCOffsetSeconds)
+}
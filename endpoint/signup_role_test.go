@@ -0,0 +1,87 @@
+package endpoint
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignup_DefaultSignupRole(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, model.SeedRoles(db))
+	r.POST("/signup", Signup)
+
+	signup := func(email string) model.User {
+		t.Helper()
+		body := map[string]string{"name": "Role Signup", "email": email, "password": "password123"}
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/signup", body: body})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var user model.User
+		assert.NoError(t, db.Where("email = ?", email).First(&user).Error)
+		return user
+	}
+
+	t.Setenv("DEFAULT_SIGNUP_ROLE", "")
+	assert.Equal(t, model.RoleAdmin, signup("unset-role@example.com").RoleID)
+
+	t.Setenv("DEFAULT_SIGNUP_ROLE", "user")
+	assert.Equal(t, model.RoleUser, signup("user-role@example.com").RoleID)
+
+	t.Setenv("DEFAULT_SIGNUP_ROLE", "3")
+	assert.Equal(t, model.RoleTherapist, signup("therapist-role@example.com").RoleID)
+}
+
+func TestValidateSignupRole(t *testing.T) {
+	_, db := setupEndpointTest(t)
+	assert.NoError(t, model.SeedRoles(db))
+
+	t.Setenv("DEFAULT_SIGNUP_ROLE", "Therapist")
+	assert.NoError(t, ValidateSignupRole(db))
+
+	t.Setenv("DEFAULT_SIGNUP_ROLE", "Patient")
+	assert.Error(t, ValidateSignupRole(db))
+}
+
+func TestSignup_InviteCode(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, model.SeedRoles(db))
+	r.POST("/signup", Signup)
+
+	signup := func(email, inviteCode string) int {
+		t.Helper()
+		body := map[string]string{"name": "Invited", "email": email, "password": "password123"}
+		if inviteCode != "" {
+			body["invite_code"] = inviteCode
+		}
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/signup", body: body})
+		assert.NoError(t, err)
+		return w.Code
+	}
+	registered := func(email string) bool {
+		var count int64
+		assert.NoError(t, db.Model(&model.User{}).Where("email = ?", email).Count(&count).Error)
+		return count > 0
+	}
+
+	t.Run("not required", func(t *testing.T) {
+		t.Setenv("SIGNUP_INVITE_CODE", "")
+		assert.Equal(t, http.StatusOK, signup("open@example.com", ""))
+		assert.Equal(t, http.StatusOK, signup("open-with-code@example.com", "anything"))
+	})
+
+	t.Run("required and correct", func(t *testing.T) {
+		t.Setenv("SIGNUP_INVITE_CODE", "welcome-2025")
+		assert.Equal(t, http.StatusOK, signup("invited@example.com", "welcome-2025"))
+		assert.True(t, registered("invited@example.com"))
+	})
+
+	t.Run("required and wrong", func(t *testing.T) {
+		t.Setenv("SIGNUP_INVITE_CODE", "welcome-2025")
+		assert.Equal(t, http.StatusUnauthorized, signup("wrong-code@example.com", "welcome-2024"))
+		assert.Equal(t, http.StatusUnauthorized, signup("no-code@example.com", ""))
+		assert.False(t, registered("wrong-code@example.com"))
+		assert.False(t, registered("no-code@example.com"))
+	})
+}
@@ -0,0 +1,110 @@
+package endpoint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+// unknownFieldsError lists the top-level body fields that the target struct
+// does not have.
+type unknownFieldsError struct {
+	fields []string
+}
+
+func (e *unknownFieldsError) Error() string {
+	return fmt.Sprintf("unknown fields: %s", strings.Join(e.fields, ", "))
+}
+
+// jsonFieldNames collects the lowercased JSON names of t's fields, descending
+// into embedded structs such as gorm.Model the way encoding/json does.
+func jsonFieldNames(t reflect.Type, names map[string]bool) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			jsonFieldNames(field.Type, names)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		// encoding/json matches keys case-insensitively.
+		names[strings.ToLower(name)] = true
+	}
+}
+
+// unknownJSONFields returns the sorted top-level keys of body that dst has no
+// field for. Bodies that are not JSON objects yield nil and are left to the
+// regular binding to reject.
+func unknownJSONFields(body []byte, dst interface{}) []string {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil
+	}
+	known := make(map[string]bool)
+	jsonFieldNames(reflect.TypeOf(dst), known)
+
+	var unknown []string
+	for key := range raw {
+		if !known[strings.ToLower(key)] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// bindStrictJSON binds the request body into dst like ShouldBindJSON, but
+// fails with an *unknownFieldsError when the body has fields dst does not
+// declare, so a typo such as "note" for "notes" is not silently dropped.
+func bindStrictJSON(c *gin.Context, dst interface{}) error {
+	if c.Request.Body != nil {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return err
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		if unknown := unknownJSONFields(body, dst); len(unknown) > 0 {
+			return &unknownFieldsError{fields: unknown}
+		}
+	}
+	return c.ShouldBindJSON(dst)
+}
+
+// bindStrictJSONOrRespond binds the body with bindStrictJSON and writes a 400
+// on failure. Unknown fields are listed under unknown_fields in the response
+// data.
+func bindStrictJSONOrRespond(c *gin.Context, dst interface{}, msg string) bool {
+	err := bindStrictJSON(c, dst)
+	if err == nil {
+		return true
+	}
+	params := util.APIErrorParams{Msg: msg, Err: err}
+	if unknownErr, ok := err.(*unknownFieldsError); ok {
+		params.Msg = "Unknown fields in request body"
+		params.Data = map[string]interface{}{"unknown_fields": unknownErr.fields}
+	}
+	util.CallUserError(c, params)
+	return false
+}
@@ -0,0 +1,93 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnknownJSONFields(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"known fields", `{"remarks": "ok", "status": "completed"}`, nil},
+		{"embedded gorm.Model fields", `{"ID": 1, "UpdatedAt": null}`, nil},
+		{"case-insensitive match", `{"Remarks": "ok"}`, nil},
+		{"unknown fields sorted", `{"remarks": "ok", "notes": "x", "note": "y"}`, []string{"note", "notes"}},
+		{"not an object", `"remarks"`, nil},
+		{"invalid json", `{`, nil},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, unknownJSONFields([]byte(tt.body), &model.Treatment{}), tt.name)
+	}
+	assert.Equal(t, []string{"role_id"}, unknownJSONFields([]byte(`{"name": "A", "role_id": 1}`), &UpdateUserRequest{}))
+}
+
+func TestUpdateTreatment_RejectsUnknownFields(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	treatment := createTestTreatment(db, t, "STRICT01", 1)
+
+	body := map[string]interface{}{"remarks": "Should not be saved", "note": "typo"}
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPatch, registerPath: "/treatment/:id", requestPath: fmt.Sprintf("/treatment/%d", treatment.ID), handler: UpdateTreatment, body: body})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+
+	var resp struct {
+		Msg  string `json:"msg"`
+		Data struct {
+			UnknownFields []string `json:"unknown_fields"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, "Unknown fields in request body", resp.Msg)
+	assert.Equal(t, []string{"note"}, resp.Data.UnknownFields)
+
+	var stored model.Treatment
+	assert.NoError(t, db.First(&stored, treatment.ID).Error)
+	assert.NotEqual(t, "Should not be saved", stored.Remarks)
+}
+
+func TestUpdateTreatment_CannotMoveClinic(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	treatment := createTestTreatment(db, t, "STRICT02", 1)
+	assert.NoError(t, db.Model(&treatment).Update("clinic_id", 1).Error)
+
+	r.PATCH("/treatment/:id", UpdateTreatment)
+	for _, body := range []map[string]interface{}{
+		{"remarks": "Moved", "clinic_id": 2},
+		{"remarks": "Moved", "ID": treatment.ID + 100},
+	} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPatch, requestPath: fmt.Sprintf("/treatment/%d", treatment.ID), body: body})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+
+	var stored model.Treatment
+	assert.NoError(t, db.First(&stored, treatment.ID).Error)
+	assert.Equal(t, uint(1), stored.ClinicID)
+	assert.NotEqual(t, "Moved", stored.Remarks)
+}
+
+func TestUpdateUser_RejectsUnknownFields(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	user := model.User{Name: "Strict User", Email: "strict@example.com", Password: "x", RoleID: model.RoleUser}
+	assert.NoError(t, db.Create(&user).Error)
+	r.Use(withAuthContext(user.ID, model.RoleUser))
+	r.PATCH("/user", UpdateUser)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPatch, requestPath: "/user", body: map[string]interface{}{"name": "Renamed", "role_id": 1}})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+	assert.Contains(t, w.Body.String(), `"unknown_fields":["role_id"]`)
+
+	var stored model.User
+	assert.NoError(t, db.First(&stored, user.ID).Error)
+	assert.Equal(t, "Strict User", stored.Name)
+	assert.Equal(t, model.RoleUser, stored.RoleID)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPatch, requestPath: "/user", body: map[string]interface{}{"name": "Renamed"}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+}
@@ -0,0 +1,155 @@
+package endpoint
+
+import (
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// normalizeTagNames normalizes and de-duplicates tag names, dropping empty ones.
+func normalizeTagNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		tag := model.NormalizeTagName(n)
+		if tag == "" {
+			continue
+		}
+		if _, exists := seen[tag]; exists {
+			continue
+		}
+		seen[tag] = struct{}{}
+		result = append(result, tag)
+	}
+	return result
+}
+
+// replaceTreatmentTags sets the tags of a treatment to names, creating missing
+// tags and removing links that are no longer present.
+func replaceTreatmentTags(db *gorm.DB, treatmentID uint, names []string) ([]model.Tag, error) {
+	tags := make([]model.Tag, 0, len(names))
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, name := range names {
+			tag := model.Tag{Name: name}
+			if err := tx.Where(model.Tag{Name: name}).FirstOrCreate(&tag).Error; err != nil {
+				return err
+			}
+			tags = append(tags, tag)
+		}
+
+		if err := tx.Where("treatment_id = ?", treatmentID).Delete(&model.TreatmentTag{}).Error; err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if err := tx.Create(&model.TreatmentTag{TreatmentID: treatmentID, TagID: tag.ID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return tags, err
+}
+
+// applyTagFilter restricts a treatment query to treatments carrying the given tag.
+func applyTagFilter(query *gorm.DB, tag string) *gorm.DB {
+	tag = model.NormalizeTagName(tag)
+	if tag == "" {
+		return query
+	}
+	return query.Where(`treatments.id IN (
+		SELECT treatment_tags.treatment_id FROM treatment_tags
+		INNER JOIN tags ON tags.id = treatment_tags.tag_id
+		WHERE tags.name = ? AND tags.deleted_at IS NULL
+	)`, tag)
+}
+
+// ListTags godoc
+// @Summary      List treatment tags
+// @Description  Get all tags available for categorizing treatments, ordered by name
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=[]model.Tag} "Tags retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /tag [get]
+func ListTags(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	var tags []model.Tag
+	if err := db.Order("name ASC").Find(&tags).Error; err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve tags",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Tags retrieved",
+		Data: tags,
+	})
+}
+
+// SetTreatmentTags godoc
+// @Summary      Set treatment tags
+// @Description  Replace the structured tags of a treatment. Unknown tags are created. The free-text issues field is left untouched.
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Treatment ID"
+// @Param        request body model.SetTreatmentTagsRequest true "Tags to assign"
+// @Success      200 {object} util.APIResponse{data=[]model.Tag} "Treatment tags updated"
+// @Failure      400 {object} util.APIResponse "Invalid request or treatment not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /treatment/{id}/tags [put]
+func SetTreatmentTags(c *gin.Context) {
+	treatmentID, ok := validateTreatmentID(c)
+	if !ok {
+		return
+	}
+
+	var req model.SetTreatmentTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	treatment, ok := findTreatmentOrAbort(c, db, treatmentID)
+	if !ok {
+		return
+	}
+
+	tags, err := replaceTreatmentTags(db, treatment.ID, normalizeTagNames(req.Tags))
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to update treatment tags",
+			Err: fmt.Errorf("treatment %d: %w", treatment.ID, err),
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Treatment tags updated",
+		Data: tags,
+	})
+}
@@ -0,0 +1,102 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTreatmentTags(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	treatment := createTestTreatment(db, t, "TAG001", 1)
+
+	path := fmt.Sprintf("/treatment/%d/tags", treatment.ID)
+	body := map[string]interface{}{"tags": []string{"Back  Pain", "posture", "back pain", " "}}
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPut, registerPath: "/treatment/:id/tags", requestPath: path, handler: SetTreatmentTags, body: body})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var links []model.TreatmentTag
+	assert.NoError(t, db.Where("treatment_id = ?", treatment.ID).Find(&links).Error)
+	assert.Len(t, links, 2)
+
+	var names []string
+	assert.NoError(t, db.Model(&model.Tag{}).Order("name").Pluck("name", &names).Error)
+	assert.Equal(t, []string{"back pain", "posture"}, names)
+
+	// Free-text issues stay untouched.
+	var reloaded model.Treatment
+	assert.NoError(t, db.First(&reloaded, treatment.ID).Error)
+	assert.Equal(t, treatment.Issues, reloaded.Issues)
+
+	// Setting tags again replaces the previous set and reuses existing tags.
+	r.PUT("/again/:id/tags", SetTreatmentTags)
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPut, requestPath: fmt.Sprintf("/again/%d/tags", treatment.ID), body: map[string]interface{}{"tags": []string{"posture"}}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, db.Where("treatment_id = ?", treatment.ID).Find(&links).Error)
+	assert.Len(t, links, 1)
+	var tagCount int64
+	db.Model(&model.Tag{}).Count(&tagCount)
+	assert.Equal(t, int64(2), tagCount)
+}
+
+func TestSetTreatmentTags_TreatmentNotFound(t *testing.T) {
+	r, _ := setupTreatmentTest(t)
+
+	body := map[string]interface{}{"tags": []string{"posture"}}
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPut, registerPath: "/treatment/:id/tags", requestPath: "/treatment/9999/tags", handler: SetTreatmentTags, body: body})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestListTreatments_FilterByTag(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	tagged := createTestTreatment(db, t, "TAG010", 1)
+	other := createTestTreatment(db, t, "TAG011", 1)
+	_ = createTestTreatment(db, t, "TAG012", 1)
+
+	_, err := replaceTreatmentTags(db, tagged.ID, []string{"back pain"})
+	assert.NoError(t, err)
+	_, err = replaceTreatmentTags(db, other.ID, []string{"knee"})
+	assert.NoError(t, err)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/treatment", requestPath: "/treatment?tag=Back%20Pain", handler: ListTreatments})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data struct {
+			Total      int                            `json:"total"`
+			Treatments []model.ListTreatementResponse `json:"treatments"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Total)
+	if assert.Len(t, resp.Data.Treatments, 1) {
+		assert.Equal(t, tagged.ID, resp.Data.Treatments[0].ID)
+	}
+}
+
+func TestListTags(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	assert.NoError(t, db.Create(&model.Tag{Name: "posture"}).Error)
+	assert.NoError(t, db.Create(&model.Tag{Name: "back pain"}).Error)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/tag", requestPath: "/tag", handler: ListTags})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data []model.Tag `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Data, 2) {
+		assert.Equal(t, "back pain", resp.Data[0].Name)
+		assert.Equal(t, "posture", resp.Data[1].Name)
+	}
+}
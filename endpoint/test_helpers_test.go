@@ -45,7 +45,7 @@ func SetupTestServer(t *testing.T) (*gin.Engine, *gorm.DB, func()) {
 
 	// Protected routes used by tests
 	auth := r.Group("/")
-	auth.Use(middleware.ValidateLoginToken())
+	auth.Use(middleware.ValidateLoginToken(), middleware.AuditAdminMutations())
 	{
 		auth.DELETE("/logout", endpoint.Logout)
 		auth.PATCH("/user", endpoint.UpdateUser)
@@ -57,6 +57,7 @@ func SetupTestServer(t *testing.T) (*gin.Engine, *gorm.DB, func()) {
 			userAdmin.GET("/:id", endpoint.GetUserInfo)
 			userAdmin.PATCH("/:id", endpoint.UpdateUserByID)
 			userAdmin.DELETE("/:id", endpoint.DeleteUser)
+			userAdmin.POST("/:id/anonymize", endpoint.AnonymizeUser)
 		}
 	}
 
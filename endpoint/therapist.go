@@ -1,8 +1,10 @@
 package endpoint
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
@@ -10,40 +12,49 @@ import (
 	"gorm.io/gorm"
 )
 
+// applyTherapistKeywordFilter matches keyword against a therapist's name or NIK.
+func applyTherapistKeywordFilter(query *gorm.DB, keyword string) *gorm.DB {
+	if keyword == "" {
+		return query
+	}
+	kw := "%" + keyword + "%"
+	return query.Where("full_name LIKE ? OR NIK LIKE ?", kw, kw)
+}
+
 func fetchTherapist(db *gorm.DB, q listQuery) ([]model.Therapist, int64, error) {
 	var therapist []model.Therapist
 	var totalTherapist int64
 
-	query := db.Offset(q.Offset).Order("created_at ASC")
+	query := db.Order("created_at ASC")
 	if q.Limit > 0 {
 		query = query.Limit(q.Limit)
 	}
 	if q.Offset > 0 {
 		query = query.Offset(q.Offset)
 	}
-	if q.Keyword != "" {
-		kw := "%" + q.Keyword + "%"
-		query = query.Where("full_name LIKE ? OR NIK LIKE ?", kw, kw)
-	}
+	query = applyTherapistKeywordFilter(query, q.Keyword)
 	query = applyCreatedAtFilter(query, q.GroupByDate)
 
 	if err := query.Find(&therapist).Error; err != nil {
 		return nil, 0, err
 	}
 
-	db.Model(&model.Therapist{}).Count(&totalTherapist)
+	countQuery := applyCreatedAtFilter(applyTherapistKeywordFilter(db.Model(&model.Therapist{}), q.Keyword), q.GroupByDate)
+	if err := countQuery.Count(&totalTherapist).Error; err != nil {
+		return nil, 0, err
+	}
 	return therapist, totalTherapist, nil
 }
 
 // ListTherapist godoc
 // @Summary      List all therapists
-// @Description  Get a paginated list of therapists with optional filtering
+// @Description  Get a paginated list of therapists with optional filtering. total counts every therapist matching the filters.
 // @Tags         Therapist
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Security     SessionToken
-// @Param        limit query int false "Limit number of results"
+// @Param        limit query int false "Limit number of results (max 100)"
 // @Param        offset query int false "Offset for pagination"
 // @Param        keyword query string false "Search keyword for therapist name or NIK"
 // @Param        group_by_date query string false "Filter by date range (last_2_days, last_3_months, last_6_months)"
@@ -53,6 +64,7 @@ func fetchTherapist(db *gorm.DB, q listQuery) ([]model.Therapist, int64, error)
 // @Router       /therapist [get]
 func ListTherapist(c *gin.Context) {
 	q := parseQueryParams(c)
+	q.Limit, q.Offset = parseListPagination(c)
 
 	db, ok := getDBOrAbort(c)
 	if !ok {
@@ -70,7 +82,7 @@ func ListTherapist(c *gin.Context) {
 
 	util.CallSuccessOK(c, util.APISuccessParams{
 		Msg:  "Therapist retrieved",
-		Data: map[string]interface{}{"total": totalTherapist, "therapists": therapist},
+		Data: util.NewPageResponse(therapist, totalTherapist, len(therapist), util.OffsetHasMore(q.Offset, len(therapist), totalTherapist), nil).Named("therapists"),
 	})
 }
 
@@ -147,17 +159,19 @@ func GetTherapistInfo(c *gin.Context) {
 }
 
 type createTherapistRequest struct {
-	FullName    string `json:"full_name" example:"Dr. John Smith"`
-	Email       string `json:"email" example:"dr.john@example.com"`
-	Password    string `json:"password" example:"password123"`
-	PhoneNumber string `json:"phone_number" example:"081234567890"`
-	Address     string `json:"address" example:"123 Main St"`
-	DateOfBirth string `json:"date_of_birth" example:"1980-01-01"`
-	NIK         string `json:"nik" example:"1234567890123456"`
-	Weight      int    `json:"weight" example:"70"`
-	Height      int    `json:"height" example:"175"`
-	Role        string `json:"role" example:"Physical Therapist"`
-	IsApproved  bool   `json:"is_approved" example:"false"`
+	FullName    string   `json:"full_name" example:"Dr. John Smith"`
+	Email       string   `json:"email" example:"dr.john@example.com"`
+	Password    string   `json:"password" example:"password123"`
+	PhoneNumber string   `json:"phone_number" example:"081234567890"`
+	Address     string   `json:"address" example:"123 Main St"`
+	DateOfBirth string   `json:"date_of_birth" example:"1980-01-01"`
+	NIK         string   `json:"nik" example:"1234567890123456"`
+	Weight      int      `json:"weight" example:"70"`
+	Height      int      `json:"height" example:"175"`
+	Role        string   `json:"role" example:"Physical Therapist"`
+	IsApproved  bool     `json:"is_approved" example:"false"`
+	Latitude    *float64 `json:"latitude,omitempty" example:"-6.2088"`
+	Longitude   *float64 `json:"longitude,omitempty" example:"106.8456"`
 }
 
 func validateTherapistRequest(req createTherapistRequest) error {
@@ -180,11 +194,16 @@ func createTherapistInDB(db *gorm.DB, req createTherapistRequest) error {
 		hashedPassword = util.HashPassword(req.Password)
 	}
 
-	var existingTherapist model.Therapist
-	return db.Transaction(func(tx *gorm.DB) error {
-		// Check if email or NIK already registered (detect either duplicate email or duplicate NIK)
-		if err := tx.Where("email = ? OR nik = ?", req.Email, req.NIK).First(&existingTherapist).Error; err == nil {
-			return fmt.Errorf("therapist already registered")
+	var approvedAt *time.Time
+	if req.IsApproved {
+		now := time.Now()
+		approvedAt = &now
+	}
+
+	var reusedUserID uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureTherapistNotRegistered(tx, req); err != nil {
+			return err
 		}
 
 		if err := tx.Create(&model.Therapist{
@@ -199,20 +218,116 @@ func createTherapistInDB(db *gorm.DB, req createTherapistRequest) error {
 			Height:      req.Height,
 			Role:        req.Role,
 			IsApproved:  req.IsApproved,
+			ApprovedAt:  approvedAt,
+			Latitude:    req.Latitude,
+			Longitude:   req.Longitude,
 		}).Error; err != nil {
 			return err
 		}
 
-		if err := tx.Create(&model.User{
-			Name:     req.FullName,
-			Email:    req.Email,
-			Password: hashedPassword,
-			RoleID:   3,
-		}).Error; err != nil {
+		var err error
+		reusedUserID, err = ensureTherapistUser(tx, req, hashedPassword)
+		return err
+	})
+	if isDuplicateKeyError(err) {
+		return errTherapistEmailTaken
+	}
+	if err == nil && reusedUserID != 0 {
+		_ = util.InvalidateUserSessions(reusedUserID)
+	}
+	return err
+}
+
+var (
+	errTherapistEmailTaken        = errors.New("email already registered")
+	errTherapistAlreadyRegistered = errors.New("therapist already registered")
+)
+
+// ensureTherapistNotRegistered rejects a new therapist whose email or NIK is
+// already used by an active therapist. Empty emails are not compared.
+func ensureTherapistNotRegistered(tx *gorm.DB, req createTherapistRequest) error {
+	var existing model.Therapist
+	if req.Email != "" {
+		if err := tx.Where("email = ?", req.Email).First(&existing).Error; err == nil {
+			return errTherapistEmailTaken
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	if err := tx.Where("nik = ?", req.NIK).First(&existing).Error; err == nil {
+		return errTherapistAlreadyRegistered
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// deleteTherapistWithUser soft-deletes the therapist together with their
+// therapist login account and its sessions, and returns the account's ID (0
+// when there is none).
+func deleteTherapistWithUser(db *gorm.DB, therapist model.Therapist) (uint, error) {
+	var userID uint
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&therapist).Error; err != nil {
 			return err
 		}
-		return nil
+		if therapist.Email == "" {
+			return nil
+		}
+		var user model.User
+		err := tx.Where("email = ? AND role_id = ?", therapist.Email, model.RoleTherapist).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		userID = user.ID
+		return deleteUserWithSessions(tx, user.ID)
 	})
+	return userID, err
+}
+
+// ensureTherapistUser creates the login account for a new therapist. A
+// therapist account left behind by a deleted therapist with
+// the same email is taken over: it is restored with the new therapist's name
+// and password, its lock state is cleared and its sessions are revoked, so
+// the previous holder loses access; its ID is returned so cached sessions can
+// be dropped too. An account with any other role means the email is taken.
+func ensureTherapistUser(tx *gorm.DB, req createTherapistRequest, hashedPassword string) (uint, error) {
+	var existing model.User
+	err := gorm.ErrRecordNotFound
+	if req.Email != "" {
+		err = tx.Unscoped().Where("email = ?", req.Email).First(&existing).Error
+	}
+	if err == nil {
+		if existing.RoleID != model.RoleTherapist {
+			return 0, errTherapistEmailTaken
+		}
+		if err := tx.Where("user_id = ?", existing.ID).Delete(&model.Session{}).Error; err != nil {
+			return 0, err
+		}
+		if err := tx.Unscoped().Model(&existing).Updates(map[string]interface{}{
+			"name":            req.FullName,
+			"password":        hashedPassword,
+			"password_salt":   "",
+			"failed_attempts": 0,
+			"locked_until":    nil,
+			"deleted_at":      nil,
+		}).Error; err != nil {
+			return 0, err
+		}
+		return existing.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	return 0, tx.Create(&model.User{
+		Name:     req.FullName,
+		Email:    req.Email,
+		Password: hashedPassword,
+		RoleID:   model.RoleTherapist,
+	}).Error
 }
 
 // CreateTherapist godoc
@@ -255,7 +370,14 @@ func CreateTherapist(c *gin.Context) {
 
 	if err := createTherapistInDB(db, therapistRequest); err != nil {
 		// Duplicate therapist (email or NIK) is a user error (400). Other errors are server errors.
-		if err.Error() == "therapist already registered" {
+		if errors.Is(err, errTherapistEmailTaken) {
+			util.CallUserError(c, util.APIErrorParams{
+				Msg: "Email already registered",
+				Err: err,
+			})
+			return
+		}
+		if errors.Is(err, errTherapistAlreadyRegistered) {
 			util.CallUserError(c, util.APIErrorParams{
 				Msg: "Therapist already registered",
 				Err: err,
@@ -353,6 +475,13 @@ func handleTherapistUpdate(c *gin.Context, db *gorm.DB, id string, therapist mod
 			})
 			return
 		}
+		if isDuplicateKeyError(err) {
+			util.CallUserError(c, util.APIErrorParams{
+				Msg: "Email already registered",
+				Err: errTherapistEmailTaken,
+			})
+			return
+		}
 		util.CallServerError(c, util.APIErrorParams{
 			Msg: "Failed to update therapist",
 			Err: err,
@@ -366,6 +495,16 @@ func handleTherapistUpdate(c *gin.Context, db *gorm.DB, id string, therapist mod
 	})
 }
 
+// approvalColumns sets is_approved and stamps approved_at when approving, or
+// clears it when approval is revoked.
+func approvalColumns(approved bool, now time.Time) map[string]interface{} {
+	columns := map[string]interface{}{"is_approved": approved, "approved_at": nil}
+	if approved {
+		columns["approved_at"] = now
+	}
+	return columns
+}
+
 func updateTherapistInDB(db *gorm.DB, id string, therapist model.Therapist) error {
 	var existingTherapist model.Therapist
 	if err := db.Where("id = ?", id).First(&existingTherapist, id).Error; err != nil {
@@ -376,6 +515,8 @@ func updateTherapistInDB(db *gorm.DB, id string, therapist model.Therapist) erro
 	// That means attempting to set IsApproved=false via Updates(struct) will be skipped.
 	// Save the original value before Updates() modifies the struct in memory.
 	originalIsApproved := existingTherapist.IsApproved
+	// approved_at follows is_approved and is never taken from the request.
+	therapist.ApprovedAt = nil
 
 	// First perform a general struct update (non-zero fields), then explicitly
 	// update the `is_approved` column if the caller provided a differing boolean value.
@@ -385,7 +526,7 @@ func updateTherapistInDB(db *gorm.DB, id string, therapist model.Therapist) erro
 
 	// If the requested IsApproved differs from the original stored value, ensure we persist it.
 	if originalIsApproved != therapist.IsApproved {
-		if err := db.Model(&existingTherapist).Update("is_approved", therapist.IsApproved).Error; err != nil {
+		if err := db.Model(&existingTherapist).Updates(approvalColumns(therapist.IsApproved, time.Now())).Error; err != nil {
 			return err
 		}
 	}
@@ -413,7 +554,7 @@ func getTherapistAndBindJSON(c *gin.Context) (string, model.Therapist, error) {
 
 // DeleteTherapist godoc
 // @Summary      Delete a therapist
-// @Description  Soft delete a therapist by ID
+// @Description  Soft delete a therapist by ID along with their login account, revoking its sessions
 // @Tags         Therapist
 // @Accept       json
 // @Produce      json
@@ -445,13 +586,17 @@ func DeleteTherapist(c *gin.Context) {
 		return
 	}
 
-	if err := db.Delete(&existingTherapist).Error; err != nil {
+	userID, err := deleteTherapistWithUser(db, existingTherapist)
+	if err != nil {
 		util.CallServerError(c, util.APIErrorParams{
 			Msg: "Failed to delete therapist",
 			Err: err,
 		})
 		return
 	}
+	if userID != 0 {
+		_ = util.InvalidateUserSessions(userID)
+	}
 
 	util.CallSuccessOK(c, util.APISuccessParams{
 		Msg:  "Therapist deleted",
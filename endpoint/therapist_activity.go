@@ -0,0 +1,132 @@
+package endpoint
+
+import (
+	"math"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const activityMonthLayout = "2006-01"
+
+// activityRatio returns active / total rounded to four decimals, or 0 when
+// total is 0.
+func activityRatio(active, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(active)/float64(total)*10000) / 10000
+}
+
+// computeTherapistActivity counts, for each calendar month overlapping start
+// to end, the therapists on the roster (registered before the month ended and
+// not deleted before it began) and how many of them had an attended
+// treatment between start and end in that month.
+func computeTherapistActivity(db *gorm.DB, start, end time.Time) (model.TherapistActivityReport, error) {
+	report := model.TherapistActivityReport{
+		StartDate: start.Format(cadenceDateLayout),
+		EndDate:   end.Format(cadenceDateLayout),
+		Months:    []model.TherapistActivityMonth{},
+	}
+	firstMonth := time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	afterLast := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+
+	var roster []struct {
+		ID        uint
+		CreatedAt time.Time
+		DeletedAt gorm.DeletedAt
+	}
+	err := db.Unscoped().Model(&model.Therapist{}).
+		Select("id, created_at, deleted_at").
+		Where("created_at < ? AND (deleted_at IS NULL OR deleted_at >= ?)", afterLast, firstMonth).
+		Scan(&roster).Error
+	if err != nil {
+		return report, err
+	}
+
+	var visits []struct {
+		TherapistID uint
+		Month       string
+	}
+	err = db.Model(&model.Treatment{}).
+		Select("DISTINCT therapist_id, SUBSTR(treatment_date, 1, 7) AS month").
+		Where("treatment_date BETWEEN ? AND ?", report.StartDate, report.EndDate).
+		Scopes(attendedStatusScope).
+		Scan(&visits).Error
+	if err != nil {
+		return report, err
+	}
+	active := make(map[string]map[uint]bool)
+	for _, v := range visits {
+		if active[v.Month] == nil {
+			active[v.Month] = make(map[uint]bool)
+		}
+		active[v.Month][v.TherapistID] = true
+	}
+
+	for month := firstMonth; month.Before(afterLast); month = month.AddDate(0, 1, 0) {
+		next := month.AddDate(0, 1, 0)
+		entry := model.TherapistActivityMonth{Month: month.Format(activityMonthLayout)}
+		for _, t := range roster {
+			if !t.CreatedAt.Before(next) || (t.DeletedAt.Valid && t.DeletedAt.Time.Before(month)) {
+				continue
+			}
+			entry.Therapists++
+			if active[entry.Month][t.ID] {
+				entry.Active++
+			}
+		}
+		entry.Inactive = entry.Therapists - entry.Active
+		entry.ActiveRatio = activityRatio(entry.Active, entry.Therapists)
+		report.Months = append(report.Months, entry)
+	}
+	return report, nil
+}
+
+// GetTherapistActivity godoc
+// @Summary      Active versus inactive therapists
+// @Description  For each month (YYYY-MM) overlapping a date range, count the therapists on the roster (registered before the month ended and not deleted before it began), those with at least one treatment in the range that month, those without, and the active ratio. Only completed treatments make a therapist active. Defaults to the last 12 weeks.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success      200 {object} util.APIResponse{data=model.TherapistActivityReport} "Report generated"
+// @Failure      400 {object} util.APIResponse "Invalid date range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /report/therapist-activity [get]
+func GetTherapistActivity(c *gin.Context) {
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid date range",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := computeTherapistActivity(db, start, end)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to generate report",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Report generated",
+		Data: report,
+	})
+}
@@ -0,0 +1,63 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestGetTherapistActivity(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/report/therapist-activity", GetTherapistActivity)
+
+	therapist := func(name string, createdAt time.Time) model.Therapist {
+		th := model.Therapist{FullName: name, NIK: "NIK-ACT-" + name}
+		th.CreatedAt = createdAt
+		assert.NoError(t, db.Create(&th).Error)
+		return th
+	}
+	longAgo := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	ann := therapist("Ann", longAgo)
+	bob := therapist("Bob", longAgo)
+	cat := therapist("Cat", time.Date(2025, 2, 10, 0, 0, 0, 0, time.UTC)) // joins in February
+	dan := therapist("Dan", longAgo)
+	// Dan leaves in January and is off the roster from February.
+	assert.NoError(t, db.Model(&dan).Update("deleted_at", gorm.DeletedAt{Time: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Valid: true}).Error)
+	therapist("Eve", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)) // after the range
+
+	for _, tr := range []struct {
+		therapist uint
+		date      string
+		status    string
+	}{
+		{ann.ID, "2025-01-06", model.TreatmentStatusCompleted},
+		{ann.ID, "2025-01-20", model.TreatmentStatusCompleted},
+		{bob.ID, "2025-01-08", model.TreatmentStatusNoShow}, // not attended
+		{dan.ID, "2025-01-10", model.TreatmentStatusCompleted},
+		{ann.ID, "2025-02-03", model.TreatmentStatusCompleted},
+		{bob.ID, "2025-02-04", model.TreatmentStatusCompleted},
+		{cat.ID, "2025-02-20", model.TreatmentStatusScheduled}, // booked, not attended
+		{bob.ID, "2025-04-02", model.TreatmentStatusCompleted}, // after the range
+	} {
+		treatment := model.Treatment{PatientCode: "ACT001", TherapistID: tr.therapist, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", Status: tr.status}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/report/therapist-activity?start_date=2025-01-01&end_date=2025-03-31"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.TherapistActivityReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []model.TherapistActivityMonth{
+		{Month: "2025-01", Therapists: 3, Active: 2, Inactive: 1, ActiveRatio: 0.6667},
+		{Month: "2025-02", Therapists: 3, Active: 2, Inactive: 1, ActiveRatio: 0.6667},
+		{Month: "2025-03", Therapists: 3, Active: 0, Inactive: 3, ActiveRatio: 0},
+	}, resp.Data.Months)
+}
@@ -0,0 +1,269 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// uniqueTherapistIDs drops duplicate IDs while keeping the request order and
+// rejects zero IDs.
+func uniqueTherapistIDs(ids []uint) ([]uint, error) {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 {
+			return nil, fmt.Errorf("therapist id must be a positive integer")
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
+
+// approveTherapists approves the given therapists in a single transaction and
+// returns one result per ID. Already-approved and missing therapists are
+// reported rather than treated as errors.
+func approveTherapists(db *gorm.DB, ids []uint) ([]model.TherapistApprovalResult, error) {
+	results := make([]model.TherapistApprovalResult, 0, len(ids))
+	err := config.WithRetry(db, func(tx *gorm.DB) error {
+		results = results[:0] // discard results from a retried attempt
+		var therapists []model.Therapist
+		if err := tx.Where("id IN ?", ids).Find(&therapists).Error; err != nil {
+			return err
+		}
+		byID := make(map[uint]model.Therapist, len(therapists))
+		for _, therapist := range therapists {
+			byID[therapist.ID] = therapist
+		}
+
+		var toApprove []uint
+		for _, id := range ids {
+			therapist, found := byID[id]
+			switch {
+			case !found:
+				results = append(results, model.TherapistApprovalResult{ID: id, Status: model.TherapistApprovalNotFound, Note: "Therapist not found"})
+			case therapist.IsApproved:
+				results = append(results, model.TherapistApprovalResult{ID: id, Status: model.TherapistApprovalAlreadyApproved, Note: "Therapist was already approved"})
+			default:
+				toApprove = append(toApprove, id)
+				results = append(results, model.TherapistApprovalResult{ID: id, Status: model.TherapistApprovalApproved})
+			}
+		}
+
+		if len(toApprove) == 0 {
+			return nil
+		}
+		return tx.Model(&model.Therapist{}).Where("id IN ?", toApprove).Updates(approvalColumns(true, time.Now())).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// BulkApproveTherapists godoc
+// @Summary      Approve therapists in bulk
+// @Description  Approve several therapist accounts in one transaction. Already-approved and unknown IDs are skipped and reported per ID.
+// @Tags         Therapist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        request body model.BulkApproveTherapistRequest true "Therapist IDs to approve"
+// @Success      200 {object} util.APIResponse{data=[]model.TherapistApprovalResult} "Therapists processed"
+// @Failure      400 {object} util.APIResponse "Invalid request body"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/bulk-approve [post]
+func BulkApproveTherapists(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	var req model.BulkApproveTherapistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+	if len(req.IDs) == 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: errors.New("ids must not be empty"),
+		})
+		return
+	}
+
+	ids, err := uniqueTherapistIDs(req.IDs)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid therapist ID",
+			Err: err,
+		})
+		return
+	}
+
+	results, err := approveTherapists(db, ids)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to approve therapists",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Therapists processed",
+		Data: results,
+	})
+}
+
+// computeTherapistApprovalStats counts therapists per approval status in one
+// grouped query and looks up when the longest-waiting pending therapist
+// registered.
+func computeTherapistApprovalStats(db *gorm.DB, now time.Time) (model.TherapistApprovalStats, error) {
+	var stats model.TherapistApprovalStats
+	var rows []struct {
+		IsApproved bool
+		Count      int64
+	}
+	err := db.Model(&model.Therapist{}).
+		Select("is_approved, COUNT(*) AS count").
+		Group("is_approved").
+		Scan(&rows).Error
+	if err != nil {
+		return stats, err
+	}
+	for _, row := range rows {
+		if row.IsApproved {
+			stats.Approved += row.Count
+		} else {
+			stats.Pending += row.Count
+		}
+	}
+	stats.Total = stats.Approved + stats.Pending
+	if stats.Pending == 0 {
+		return stats, nil
+	}
+
+	var oldest model.Therapist
+	if err := db.Where("is_approved = ?", false).Order("created_at ASC").First(&oldest).Error; err != nil {
+		return stats, err
+	}
+	days := int(now.Sub(oldest.CreatedAt).Hours() / 24)
+	stats.OldestPendingSince = &oldest.CreatedAt
+	stats.OldestPendingAgeDays = &days
+	return stats, nil
+}
+
+// GetTherapistStats godoc
+// @Summary      Therapist approval statistics
+// @Description  Count approved and pending therapists, and report when the oldest pending therapist registered and how many whole days ago that was.
+// @Tags         Therapist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=model.TherapistApprovalStats} "Therapist stats retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/stats [get]
+func GetTherapistStats(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	stats, err := computeTherapistApprovalStats(db, time.Now())
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve therapist stats",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Therapist stats retrieved",
+		Data: stats,
+	})
+}
+
+// listPendingTherapists returns a page of unapproved therapists, longest
+// waiting first, and the number of unapproved therapists.
+func listPendingTherapists(db *gorm.DB, limit, offset int, now time.Time) ([]model.PendingTherapist, int64, error) {
+	query := db.Model(&model.Therapist{}).Where("is_approved = ?", false)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var therapists []model.Therapist
+	if err := query.Order("created_at ASC, id ASC").Offset(offset).Limit(limit).Find(&therapists).Error; err != nil {
+		return nil, 0, err
+	}
+
+	pending := make([]model.PendingTherapist, 0, len(therapists))
+	for _, therapist := range therapists {
+		wait := now.Sub(therapist.CreatedAt)
+		pending = append(pending, model.PendingTherapist{
+			ID:          therapist.ID,
+			FullName:    therapist.FullName,
+			Email:       therapist.Email,
+			PhoneNumber: therapist.PhoneNumber,
+			CreatedAt:   therapist.CreatedAt,
+			WaitSeconds: int64(wait / time.Second),
+			WaitDays:    int(wait.Hours() / 24),
+		})
+	}
+	return pending, total, nil
+}
+
+// ListPendingTherapists godoc
+// @Summary      Therapist approval queue
+// @Description  List unapproved therapists, longest waiting first, with how long each has waited since registering in seconds and whole days.
+// @Tags         Therapist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        limit query int false "Page size (default 10, max 100)"
+// @Param        offset query int false "Number of therapists to skip"
+// @Success      200 {object} util.APIResponse{data=object{therapists=[]model.PendingTherapist,total=int,total_fetched=int,has_more=bool}} "Pending therapists retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/pending [get]
+func ListPendingTherapists(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	limit, _, offset := parsePaginationParams(c)
+	therapists, total, err := listPendingTherapists(db, limit, offset, time.Now())
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve pending therapists",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Pending therapists retrieved",
+		Data: util.NewPageResponse(therapists, total, len(therapists), util.OffsetHasMore(offset, len(therapists), total), nil).Named("therapists"),
+	})
+}
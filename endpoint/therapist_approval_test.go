@@ -0,0 +1,162 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBulkApproveTherapists_MixedBatch(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	pending1 := createTestTherapist(db, t, false)
+	pending2 := createTestTherapist(db, t, false)
+	approved := createTestTherapist(db, t, true)
+
+	body := map[string]interface{}{"ids": []uint{pending1.ID, approved.ID, pending2.ID, pending1.ID, 9999}}
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPost, registerPath: "/therapist/bulk-approve", requestPath: "/therapist/bulk-approve", handler: BulkApproveTherapists, body: body})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data []model.TherapistApprovalResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []model.TherapistApprovalResult{
+		{ID: pending1.ID, Status: model.TherapistApprovalApproved},
+		{ID: approved.ID, Status: model.TherapistApprovalAlreadyApproved, Note: "Therapist was already approved"},
+		{ID: pending2.ID, Status: model.TherapistApprovalApproved},
+		{ID: 9999, Status: model.TherapistApprovalNotFound, Note: "Therapist not found"},
+	}, resp.Data)
+
+	for _, id := range []uint{pending1.ID, pending2.ID, approved.ID} {
+		var therapist model.Therapist
+		assert.NoError(t, db.First(&therapist, id).Error)
+		assert.True(t, therapist.IsApproved, "therapist %d should be approved", id)
+		if id != approved.ID {
+			assert.NotNil(t, therapist.ApprovedAt, "therapist %d should have an approval time", id)
+		}
+	}
+}
+
+func TestBulkApproveTherapists_InvalidRequest(t *testing.T) {
+	r, _ := setupTherapistTest(t)
+	r.POST("/therapist/bulk-approve", BulkApproveTherapists)
+
+	cases := []struct {
+		name string
+		body interface{}
+	}{
+		{name: "missing ids", body: map[string]interface{}{}},
+		{name: "empty ids", body: map[string]interface{}{"ids": []uint{}}},
+		{name: "zero id", body: map[string]interface{}{"ids": []uint{0}}},
+		{name: "non-numeric id", body: `{"ids":["abc"]}`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/therapist/bulk-approve", body: tc.body})
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
+func TestGetTherapistStats_CountsByStatus(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	createTestTherapist(db, t, true)
+	createTestTherapist(db, t, true)
+	createTestTherapist(db, t, true)
+	oldest := createTestTherapist(db, t, false)
+	createTestTherapist(db, t, false)
+	deleted := createTestTherapist(db, t, false)
+
+	registered := time.Now().Add(-10*24*time.Hour - time.Hour)
+	assert.NoError(t, db.Model(&oldest).Update("created_at", registered).Error)
+	assert.NoError(t, db.Model(&deleted).Update("created_at", registered.AddDate(0, 0, -30)).Error)
+	assert.NoError(t, db.Delete(&deleted).Error)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/therapist/stats", requestPath: "/therapist/stats", handler: GetTherapistStats})
+	assert.NoError(t, err)
+	if !assert.Equal(t, http.StatusOK, w.Code, w.Body.String()) {
+		return
+	}
+
+	var resp struct {
+		Data model.TherapistApprovalStats `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	stats := resp.Data
+	assert.Equal(t, int64(5), stats.Total)
+	assert.Equal(t, int64(3), stats.Approved)
+	assert.Equal(t, int64(2), stats.Pending)
+	if assert.NotNil(t, stats.OldestPendingSince) && assert.NotNil(t, stats.OldestPendingAgeDays) {
+		assert.WithinDuration(t, registered, *stats.OldestPendingSince, time.Second)
+		assert.Equal(t, 10, *stats.OldestPendingAgeDays)
+	}
+}
+
+func TestGetTherapistStats_NothingPending(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	createTestTherapist(db, t, true)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/therapist/stats", requestPath: "/therapist/stats", handler: GetTherapistStats})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data model.TherapistApprovalStats `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.TherapistApprovalStats{Total: 1, Approved: 1}, resp.Data)
+	assert.NotContains(t, w.Body.String(), "oldest_pending")
+}
+
+func TestListPendingTherapists_OldestFirst(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	r.GET("/therapist/pending", ListPendingTherapists)
+
+	createTestTherapist(db, t, true)
+	newest := createTestTherapist(db, t, false)
+	oldest := createTestTherapist(db, t, false)
+	middle := createTestTherapist(db, t, false)
+
+	now := time.Now()
+	assert.NoError(t, db.Model(&oldest).Update("created_at", now.Add(-5*24*time.Hour-2*time.Hour)).Error)
+	assert.NoError(t, db.Model(&middle).Update("created_at", now.Add(-36*time.Hour)).Error)
+	assert.NoError(t, db.Model(&newest).Update("created_at", now.Add(-time.Hour)).Error)
+
+	list := func(query string) ([]model.PendingTherapist, int64) {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/therapist/pending" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		assert.NotContains(t, w.Body.String(), "password")
+		var resp struct {
+			Data struct {
+				Therapists []model.PendingTherapist `json:"therapists"`
+				Total      int64                    `json:"total"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.Therapists, resp.Data.Total
+	}
+
+	pending, total := list("")
+	assert.Equal(t, int64(3), total)
+	if assert.Len(t, pending, 3) {
+		assert.Equal(t, []uint{oldest.ID, middle.ID, newest.ID}, []uint{pending[0].ID, pending[1].ID, pending[2].ID})
+		assert.Equal(t, 5, pending[0].WaitDays)
+		assert.InDelta(t, (5*24+2)*3600, pending[0].WaitSeconds, 5)
+		assert.Equal(t, 1, pending[1].WaitDays)
+		assert.Equal(t, 0, pending[2].WaitDays)
+		assert.InDelta(t, 3600, pending[2].WaitSeconds, 5)
+	}
+
+	page, total := list("?limit=1&offset=1")
+	assert.Equal(t, int64(3), total)
+	if assert.Len(t, page, 1) {
+		assert.Equal(t, middle.ID, page[0].ID)
+	}
+}
@@ -0,0 +1,147 @@
+package endpoint
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	cadenceDateLayout   = "2006-01-02"
+	defaultCadenceWeeks = 12
+)
+
+// parseCadenceRange reads start_date and end_date (YYYY-MM-DD). When omitted,
+// the range ends today and covers the previous defaultCadenceWeeks weeks.
+func parseCadenceRange(c *gin.Context) (time.Time, time.Time, error) {
+	end := time.Now()
+	if s := c.Query("end_date"); s != "" {
+		t, err := time.Parse(cadenceDateLayout, s)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date, expected YYYY-MM-DD")
+		}
+		end = t
+	}
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+
+	start := end.AddDate(0, 0, -7*defaultCadenceWeeks+1)
+	if s := c.Query("start_date"); s != "" {
+		t, err := time.Parse(cadenceDateLayout, s)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start_date, expected YYYY-MM-DD")
+		}
+		start = t
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("start_date must not be after end_date")
+	}
+	return start, end, nil
+}
+
+// isoWeekStart returns the Monday of the ISO week containing t.
+func isoWeekStart(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset)
+}
+
+// buildWeeklyBuckets returns one zero-count bucket for every ISO week that
+// overlaps [start, end], keyed by the week label for easy counting.
+func buildWeeklyBuckets(start, end time.Time) ([]model.TherapistWeeklyCount, map[string]int) {
+	var weeks []model.TherapistWeeklyCount
+	index := make(map[string]int)
+	for w := isoWeekStart(start); !w.After(end); w = w.AddDate(0, 0, 7) {
+		year, week := w.ISOWeek()
+		label := fmt.Sprintf("%d-W%02d", year, week)
+		index[label] = len(weeks)
+		weeks = append(weeks, model.TherapistWeeklyCount{Week: label, WeekStart: w.Format(cadenceDateLayout)})
+	}
+	return weeks, index
+}
+
+// computeTherapistCadence buckets the therapist's treatments between start and
+// end (inclusive) by ISO week and averages them over every week in the range.
+func computeTherapistCadence(db *gorm.DB, therapist model.Therapist, start, end time.Time) (model.TherapistCadence, error) {
+	var dates []string
+	err := db.Model(&model.Treatment{}).
+		Where("therapist_id = ? AND treatment_date BETWEEN ? AND ?", therapist.ID, start.Format(cadenceDateLayout), end.Format(cadenceDateLayout)).
+		Pluck("treatment_date", &dates).Error
+	if err != nil {
+		return model.TherapistCadence{}, err
+	}
+
+	weeks, index := buildWeeklyBuckets(start, end)
+	total := 0
+	for _, d := range dates {
+		t, err := time.Parse(cadenceDateLayout, d)
+		if err != nil {
+			continue
+		}
+		year, week := t.ISOWeek()
+		if i, ok := index[fmt.Sprintf("%d-W%02d", year, week)]; ok {
+			weeks[i].Count++
+			total++
+		}
+	}
+
+	average := 0.0
+	if len(weeks) > 0 {
+		average = math.Round(float64(total)/float64(len(weeks))*100) / 100
+	}
+
+	return model.TherapistCadence{
+		TherapistID:     therapist.ID,
+		TherapistName:   therapist.FullName,
+		StartDate:       start.Format(cadenceDateLayout),
+		EndDate:         end.Format(cadenceDateLayout),
+		TotalTreatments: total,
+		AveragePerWeek:  average,
+		Weeks:           weeks,
+	}, nil
+}
+
+// GetTherapistCadence godoc
+// @Summary      Get therapist treatment cadence
+// @Description  Get a therapist's treatment count per ISO week over a date range and the weekly average. Defaults to the last 12 weeks.
+// @Tags         Therapist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Therapist ID"
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD)"
+// @Success      200 {object} util.APIResponse{data=model.TherapistCadence} "Therapist cadence retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid date range or therapist not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/{id}/cadence [get]
+func GetTherapistCadence(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, therapist, err := getTherapistByID(c, db)
+	if err != nil {
+		return
+	}
+
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{Msg: "Invalid date range", Err: err})
+		return
+	}
+
+	cadence, err := computeTherapistCadence(db, therapist, start, end)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to compute therapist cadence", Err: err})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{Msg: "Therapist cadence retrieved", Data: cadence})
+}
@@ -0,0 +1,73 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTherapistCadence_WeeklyBucketsAndAverage(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	therapist := createTestTherapist(db, t, true)
+	other := createTestTherapist(db, t, true)
+
+	dates := []string{
+		"2025-01-05",               // Sunday before the range
+		"2025-01-06", "2025-01-12", // W02 Monday and Sunday
+		"2025-01-20", "2025-01-22", "2025-01-24", // W04
+		"2025-02-02", // W05 Sunday, last day of the range
+		"2025-02-03", // after the range
+	}
+	for _, d := range dates {
+		assert.NoError(t, db.Create(&model.Treatment{TreatmentDate: d, PatientCode: "C1", TherapistID: therapist.ID}).Error)
+	}
+	assert.NoError(t, db.Create(&model.Treatment{TreatmentDate: "2025-01-14", PatientCode: "C2", TherapistID: other.ID}).Error)
+
+	path := fmt.Sprintf("/therapist/%d/cadence?start_date=2025-01-06&end_date=2025-02-02", therapist.ID)
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/therapist/:id/cadence", requestPath: path, handler: GetTherapistCadence})
+	assert.NoError(t, err)
+	if !assert.Equal(t, http.StatusOK, w.Code, w.Body.String()) {
+		return
+	}
+
+	var resp struct {
+		Data model.TherapistCadence `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	cadence := resp.Data
+
+	assert.Equal(t, therapist.ID, cadence.TherapistID)
+	assert.Equal(t, 6, cadence.TotalTreatments)
+	assert.InDelta(t, 1.5, cadence.AveragePerWeek, 0.001)
+	assert.Equal(t, []model.TherapistWeeklyCount{
+		{Week: "2025-W02", WeekStart: "2025-01-06", Count: 2},
+		{Week: "2025-W03", WeekStart: "2025-01-13", Count: 0},
+		{Week: "2025-W04", WeekStart: "2025-01-20", Count: 3},
+		{Week: "2025-W05", WeekStart: "2025-01-27", Count: 1},
+	}, cadence.Weeks)
+}
+
+func TestGetTherapistCadence_InvalidRange(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	therapist := createTestTherapist(db, t, true)
+
+	r.GET("/therapist/:id/cadence", GetTherapistCadence)
+	for _, query := range []string{"start_date=2025-02-01&end_date=2025-01-01", "start_date=01-01-2025", "end_date=bad"} {
+		path := fmt.Sprintf("/therapist/%d/cadence?%s", therapist.ID, query)
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: path})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestGetTherapistCadence_TherapistNotFound(t *testing.T) {
+	r, _ := setupTherapistTest(t)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/therapist/:id/cadence", requestPath: "/therapist/9999/cadence", handler: GetTherapistCadence})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,142 @@
+package endpoint
+
+import (
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultCaseloadLimit = 20
+	maxCaseloadLimit     = 100
+)
+
+// caseloadRow is one treatment of the caseload joined with its patient.
+type caseloadRow struct {
+	model.CaseloadTreatment
+	PatientCode string
+	FullName    string
+	PhoneNumber string
+}
+
+// fetchCaseload returns one page of the therapist's patients, most recently
+// treated first, each with all of the therapist's treatments for them, and
+// the total number of patients. The page's treatments are loaded with a
+// single join and grouped in memory.
+func fetchCaseload(db *gorm.DB, therapistID uint, limit, offset int) ([]model.CaseloadPatient, int64, error) {
+	var total int64
+	err := db.Model(&model.Treatment{}).Where("therapist_id = ?", therapistID).Distinct("patient_code").Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var codes []string
+	err = applyPagination(db.Model(&model.Treatment{}).
+		Select("patient_code").
+		Where("therapist_id = ?", therapistID).
+		Group("patient_code").
+		Order("MAX(treatment_date) DESC, patient_code ASC"), limit, offset).
+		Pluck("patient_code", &codes).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	patients := make([]model.CaseloadPatient, 0, len(codes))
+	if len(codes) == 0 {
+		return patients, total, nil
+	}
+
+	var rows []caseloadRow
+	err = db.Model(&model.Treatment{}).
+		Select("treatments.id, treatments.treatment_date, treatments.status, treatments.issues, treatments.treatment, treatments.remarks, treatments.next_visit, treatments.patient_code, COALESCE(patients.full_name, '') AS full_name, COALESCE(patients.phone_number, '') AS phone_number").
+		Joins("LEFT JOIN patients ON patients.patient_code = treatments.patient_code AND patients.deleted_at IS NULL").
+		Where("treatments.therapist_id = ? AND treatments.patient_code IN ?", therapistID, codes).
+		Order("treatments.treatment_date DESC, treatments.id DESC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	index := make(map[string]int, len(codes))
+	for i, code := range codes {
+		index[code] = i
+		patients = append(patients, model.CaseloadPatient{PatientCode: code, Treatments: []model.CaseloadTreatment{}})
+	}
+	seen := map[uint]bool{}
+	for _, row := range rows {
+		// A code shared by several patient records would repeat the treatment.
+		if seen[row.ID] {
+			continue
+		}
+		seen[row.ID] = true
+		p := &patients[index[row.PatientCode]]
+		if p.FullName == "" {
+			p.FullName, p.PhoneNumber = row.FullName, row.PhoneNumber
+		}
+		p.Treatments = append(p.Treatments, row.CaseloadTreatment)
+	}
+	return patients, total, nil
+}
+
+// GetMyCaseload godoc
+// @Summary      Get the current therapist's caseload
+// @Description  For a therapist session, list the patients the therapist has treated, most recently treated first, each with all of that therapist's treatments for them (any status, newest first). Paginated by patient. Other roles get 400.
+// @Tags         Therapist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        limit query int false "Patients per page (default 20, max 100)"
+// @Param        offset query int false "Offset for pagination, in patients"
+// @Success      200 {object} util.APIResponse{data=object} "Caseload retrieved"
+// @Failure      400 {object} util.APIResponse "Not a therapist or session error"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/me/caseload [get]
+func GetMyCaseload(c *gin.Context) {
+	roleID, ok := middleware.GetRoleID(c)
+	if !ok {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{
+			Msg: "Role information not available",
+			Err: fmt.Errorf("role id not found in context"),
+		})
+		return
+	}
+	if roleID != model.RoleTherapist {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Only therapists have a caseload",
+			Err: fmt.Errorf("role %d is not a therapist", roleID),
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	therapistID, err := getTherapistIDFromSession(db, c.GetHeader("session-token"))
+	if err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	limit := parsePositiveInt(c.Query("limit"), defaultCaseloadLimit, maxCaseloadLimit)
+	offset := parseQueryInt(c, "offset", 0)
+	patients, total, err := fetchCaseload(db, therapistID, limit, offset)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve caseload",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Caseload retrieved",
+		Data: util.NewPageResponse(patients, total, len(patients), util.OffsetHasMore(offset, len(patients), total), nil).Named("patients"),
+	})
+}
@@ -0,0 +1,79 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMyCaseload(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	user, therapist, session := createUserWithSession(db, t, CreateUserSessionOpts{RoleID: model.RoleTherapist, Email: "caseload@example.com", Token: "caseload-token", CreateTherapist: true})
+	r.GET("/therapist/me/caseload", withAuthContext(user.ID, model.RoleTherapist), GetMyCaseload)
+
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Alice", PatientCode: "CL001", PhoneNumber: "0811"}).Error)
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Bob", PatientCode: "CL002", PhoneNumber: "0812"}).Error)
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Carol", PatientCode: "CL003", PhoneNumber: "0813"}).Error)
+	treatments := []model.Treatment{
+		{TreatmentDate: "2025-01-10", PatientCode: "CL001", TherapistID: therapist.ID, Status: model.TreatmentStatusCompleted},
+		{TreatmentDate: "2025-01-24", PatientCode: "CL001", TherapistID: therapist.ID, Status: model.TreatmentStatusNoShow},
+		{TreatmentDate: "2025-01-20", PatientCode: "CL002", TherapistID: therapist.ID, Status: model.TreatmentStatusCompleted},
+		// Another therapist's treatments are not part of the caseload, even
+		// for a patient the caller also treats.
+		{TreatmentDate: "2025-01-30", PatientCode: "CL002", TherapistID: therapist.ID + 100, Status: model.TreatmentStatusCompleted},
+		{TreatmentDate: "2025-01-31", PatientCode: "CL003", TherapistID: therapist.ID + 100, Status: model.TreatmentStatusCompleted},
+	}
+	for i := range treatments {
+		treatments[i].Issues, treatments[i].Treatment, treatments[i].NextVisit = "-", "-", "-"
+		assert.NoError(t, db.Create(&treatments[i]).Error)
+	}
+
+	headers := map[string]string{"session-token": session.SessionToken}
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/therapist/me/caseload", headers: headers})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data struct {
+			Total    int64                   `json:"total"`
+			HasMore  bool                    `json:"has_more"`
+			Patients []model.CaseloadPatient `json:"patients"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+	if assert.Len(t, resp.Data.Patients, 2) {
+		alice, bob := resp.Data.Patients[0], resp.Data.Patients[1]
+		assert.Equal(t, "CL001", alice.PatientCode)
+		assert.Equal(t, "Alice", alice.FullName)
+		assert.Equal(t, "0811", alice.PhoneNumber)
+		if assert.Len(t, alice.Treatments, 2) {
+			assert.Equal(t, treatments[1].ID, alice.Treatments[0].ID)
+			assert.Equal(t, model.TreatmentStatusNoShow, alice.Treatments[0].Status)
+			assert.Equal(t, treatments[0].ID, alice.Treatments[1].ID)
+		}
+		assert.Equal(t, "CL002", bob.PatientCode)
+		if assert.Len(t, bob.Treatments, 1) {
+			assert.Equal(t, treatments[2].ID, bob.Treatments[0].ID)
+		}
+	}
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/therapist/me/caseload?limit=1&offset=1", headers: headers})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+	assert.False(t, resp.Data.HasMore)
+	if assert.Len(t, resp.Data.Patients, 1) {
+		assert.Equal(t, "CL002", resp.Data.Patients[0].PatientCode)
+	}
+}
+
+func TestGetMyCaseload_NonTherapistRejected(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+	r.GET("/therapist/me/caseload", withAuthContext(1, model.RoleAdmin), GetMyCaseload)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/therapist/me/caseload"})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
@@ -0,0 +1,127 @@
+package endpoint
+
+import (
+	"encoding/csv"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// therapistExportFlushEvery is how many CSV rows are buffered before they
+// are flushed to the client.
+const therapistExportFlushEvery = 100
+
+var therapistExportHeader = []string{"full_name", "nik", "email", "is_approved", "treatment_count"}
+
+// therapistExportRow is one therapist with the number of treatments
+// recorded for them.
+type therapistExportRow struct {
+	FullName       string
+	NIK            string
+	Email          string
+	IsApproved     bool
+	TreatmentCount int64
+}
+
+func (row therapistExportRow) record() []string {
+	return []string{
+		csvSafe(row.FullName),
+		csvSafe(row.NIK),
+		csvSafe(row.Email),
+		strconv.FormatBool(row.IsApproved),
+		strconv.FormatInt(row.TreatmentCount, 10),
+	}
+}
+
+// csvSafe prefixes values that spreadsheets would evaluate as formulas with
+// a single quote.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// therapistExportQuery selects every therapist with the count of their
+// treatments, ordered by name. A non-zero clinicID counts only that clinic's
+// treatments; ClinicScope does not reach the joined table.
+func therapistExportQuery(db *gorm.DB, clinicID uint) *gorm.DB {
+	join := "LEFT JOIN treatments ON treatments.therapist_id = therapists.id AND treatments.deleted_at IS NULL"
+	var args []interface{}
+	if clinicID != 0 {
+		join += " AND treatments.clinic_id = ?"
+		args = append(args, clinicID)
+	}
+	return db.Model(&model.Therapist{}).
+		Select("therapists.full_name, therapists.nik, therapists.email, therapists.is_approved, COUNT(treatments.id) AS treatment_count").
+		Joins(join, args...).
+		Group("therapists.id, therapists.full_name, therapists.nik, therapists.email, therapists.is_approved").
+		Order("therapists.full_name ASC").
+		Order("therapists.id ASC")
+}
+
+// ExportTherapists godoc
+// @Summary      Export therapists as CSV
+// @Description  Stream every therapist as CSV with columns full_name, nik, email, is_approved and treatment_count (treatments recorded for the therapist in the caller's clinic, any status), ordered by name. Values that spreadsheets would treat as formulas are prefixed with a single quote.
+// @Tags         Therapist
+// @Produce      text/csv
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {string} string "CSV file"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/export [get]
+func ExportTherapists(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	rows, err := therapistExportQuery(db, middleware.GetScopedClinicID(c)).Rows()
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to export therapists",
+			Err: err,
+		})
+		return
+	}
+	defer rows.Close()
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="therapists.csv"`)
+	c.Status(http.StatusOK)
+
+	// Headers are sent with the first flush, so later failures can only be
+	// logged and end the file early.
+	w := csv.NewWriter(c.Writer)
+	if err := w.Write(therapistExportHeader); err != nil {
+		config.Logger().Error("Therapist export failed", "error", err)
+		return
+	}
+	for n := 1; rows.Next(); n++ {
+		var row therapistExportRow
+		if err := db.ScanRows(rows, &row); err != nil {
+			config.Logger().Error("Therapist export failed", "error", err)
+			break
+		}
+		if err := w.Write(row.record()); err != nil {
+			config.Logger().Error("Therapist export failed", "error", err)
+			return
+		}
+		if n%therapistExportFlushEvery == 0 {
+			w.Flush()
+			c.Writer.Flush()
+		}
+	}
+	if err := rows.Err(); err != nil {
+		config.Logger().Error("Therapist export failed", "error", err)
+	}
+	w.Flush()
+}
@@ -0,0 +1,41 @@
+package endpoint
+
+import (
+	"encoding/csv"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTherapists(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/therapist/export", ExportTherapists)
+
+	busy := model.Therapist{FullName: "Budi", NIK: "3201000000000001", Email: "budi@example.com", IsApproved: true}
+	idle := model.Therapist{FullName: "=Ani", NIK: "3201000000000002", Email: "ani@example.com"}
+	assert.NoError(t, db.Create(&busy).Error)
+	assert.NoError(t, db.Create(&idle).Error)
+	for i := 0; i < 3; i++ {
+		createTestTreatment(db, t, "EXP001", busy.ID)
+	}
+	deleted := createTestTreatment(db, t, "EXP001", busy.ID)
+	assert.NoError(t, db.Delete(&deleted).Error)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/therapist/export", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "therapists.csv")
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"full_name", "nik", "email", "is_approved", "treatment_count"},
+		{"'=Ani", "3201000000000002", "ani@example.com", "false", "0"},
+		{"Budi", "3201000000000001", "budi@example.com", "true", "3"},
+	}, records)
+}
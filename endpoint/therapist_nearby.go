@@ -0,0 +1,121 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var errPatientWithoutCoordinates = errors.New("patient has no coordinates")
+
+// nearbyTherapists returns approved therapists with coordinates ordered by
+// distance to the patient, closest first. Therapists without coordinates are
+// skipped. A limit of 0 returns all of them.
+func nearbyTherapists(db *gorm.DB, patient model.Patient, limit int) ([]model.NearbyTherapist, error) {
+	if patient.Latitude == nil || patient.Longitude == nil {
+		return nil, errPatientWithoutCoordinates
+	}
+
+	var therapists []model.Therapist
+	err := db.Where("is_approved = ? AND latitude IS NOT NULL AND longitude IS NOT NULL", true).
+		Find(&therapists).Error
+	if err != nil {
+		return nil, err
+	}
+
+	nearby := make([]model.NearbyTherapist, 0, len(therapists))
+	for _, t := range therapists {
+		distance := util.Haversine(*patient.Latitude, *patient.Longitude, *t.Latitude, *t.Longitude)
+		nearby = append(nearby, model.NearbyTherapist{
+			ID:          t.ID,
+			FullName:    t.FullName,
+			PhoneNumber: t.PhoneNumber,
+			Address:     t.Address,
+			Latitude:    *t.Latitude,
+			Longitude:   *t.Longitude,
+			DistanceKm:  math.Round(distance*100) / 100,
+		})
+	}
+	sort.SliceStable(nearby, func(i, j int) bool { return nearby[i].DistanceKm < nearby[j].DistanceKm })
+
+	if limit > 0 && len(nearby) > limit {
+		nearby = nearby[:limit]
+	}
+	return nearby, nil
+}
+
+// ListNearbyTherapists godoc
+// @Summary      List therapists near a patient
+// @Description  Get approved therapists ordered by straight-line (haversine) distance to the patient, for home-visit assignment. Therapists without coordinates are skipped.
+// @Tags         Therapist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        patient_id query int true "Patient ID"
+// @Param        limit query int false "Maximum number of therapists to return"
+// @Success      200 {object} util.APIResponse{data=[]model.NearbyTherapist} "Nearby therapists retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid patient ID or patient without coordinates"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Patient not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/nearby [get]
+func ListNearbyTherapists(c *gin.Context) {
+	patientID, err := strconv.ParseUint(c.Query("patient_id"), 10, 64)
+	if err != nil || patientID == 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid patient ID",
+			Err: fmt.Errorf("patient_id must be a positive integer"),
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	var patient model.Patient
+	if err := db.First(&patient, patientID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallErrorNotFound(c, util.APIErrorParams{
+				Msg: "Patient not found",
+				Err: err,
+			})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve patient",
+			Err: err,
+		})
+		return
+	}
+
+	therapists, err := nearbyTherapists(db, patient, parseQueryInt(c, "limit", 0))
+	if errors.Is(err, errPatientWithoutCoordinates) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Patient has no coordinates",
+			Err: err,
+		})
+		return
+	}
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve nearby therapists",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Nearby therapists retrieved",
+		Data: therapists,
+	})
+}
@@ -0,0 +1,81 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func coord(v float64) *float64 { return &v }
+
+func TestListNearbyTherapists_OrdersByDistance(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/therapist/nearby", ListNearbyTherapists)
+
+	// Patient in central Jakarta.
+	patient := model.Patient{FullName: "Home Visit", PatientCode: "H001", Latitude: coord(-6.2088), Longitude: coord(106.8456)}
+	assert.NoError(t, db.Create(&patient).Error)
+
+	for i, th := range []model.Therapist{
+		{FullName: "Bandung", Latitude: coord(-6.9175), Longitude: coord(107.6191), IsApproved: true},
+		{FullName: "Depok", Latitude: coord(-6.4025), Longitude: coord(106.7942), IsApproved: true},
+		{FullName: "Menteng", Latitude: coord(-6.1950), Longitude: coord(106.8300), IsApproved: true},
+		{FullName: "No Coordinates", IsApproved: true},
+		{FullName: "Unapproved", Latitude: coord(-6.2090), Longitude: coord(106.8450)},
+	} {
+		th.NIK = fmt.Sprintf("NIK-NEAR-%d", i)
+		assert.NoError(t, db.Create(&th).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/therapist/nearby?patient_id=%d", patient.ID)})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data []model.NearbyTherapist `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	var names []string
+	for _, th := range resp.Data {
+		names = append(names, th.FullName)
+	}
+	assert.Equal(t, []string{"Menteng", "Depok", "Bandung"}, names)
+	if assert.Len(t, resp.Data, 3) {
+		assert.InDelta(t, 116, resp.Data[2].DistanceKm, 2)
+		assert.Less(t, resp.Data[0].DistanceKm, resp.Data[1].DistanceKm)
+	}
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/therapist/nearby?patient_id=%d&limit=1", patient.ID)})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Data, 1) {
+		assert.Equal(t, "Menteng", resp.Data[0].FullName)
+	}
+}
+
+func TestListNearbyTherapists_Errors(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/therapist/nearby", ListNearbyTherapists)
+
+	noCoords := model.Patient{FullName: "No Coords", PatientCode: "NC01"}
+	assert.NoError(t, db.Create(&noCoords).Error)
+
+	tests := []struct {
+		query      string
+		wantStatus int
+	}{
+		{"", http.StatusBadRequest},
+		{"?patient_id=abc", http.StatusBadRequest},
+		{"?patient_id=99999", http.StatusNotFound},
+		{fmt.Sprintf("?patient_id=%d", noCoords.ID), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/therapist/nearby" + tt.query})
+		assert.NoError(t, err)
+		assert.Equal(t, tt.wantStatus, w.Code, tt.query)
+	}
+}
@@ -0,0 +1,119 @@
+package endpoint
+
+import (
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// scheduleOverlaps reports whether the therapist already has a schedule that
+// overlaps [start, end). Cancelled schedules free their slot.
+func scheduleOverlaps(db *gorm.DB, therapistID uint, start, end time.Time) (bool, error) {
+	var count int64
+	err := db.Model(&model.Schedule{}).
+		Scopes(model.ActiveSchedules).
+		Where("therapist_id = ? AND start_time < ? AND end_time > ?", therapistID, end, start).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// createRecurringSchedules saves the slots for a therapist in one transaction,
+// skipping those outside the clinic's opening hours or overlapping an
+// existing schedule.
+func createRecurringSchedules(db *gorm.DB, therapistID, clinicID uint, slots []model.Schedule) (model.RecurringScheduleResult, error) {
+	var result model.RecurringScheduleResult
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result = model.RecurringScheduleResult{}
+		for _, slot := range slots {
+			open, err := model.WithinClinicHours(tx, clinicID, slot.StartTime, slot.EndTime)
+			if err != nil {
+				return err
+			}
+			if !open {
+				result.SkippedClinicHours++
+				continue
+			}
+			overlaps, err := scheduleOverlaps(tx, therapistID, slot.StartTime, slot.EndTime)
+			if err != nil {
+				return err
+			}
+			if overlaps {
+				result.SkippedOverlap++
+				continue
+			}
+			slot.TherapistID = therapistID
+			slot.ClinicID = clinicID
+			slot.Status = model.ScheduleStatusActive
+			if err := tx.Create(&slot).Error; err != nil {
+				return err
+			}
+			result.Created++
+		}
+		return nil
+	})
+	return result, err
+}
+
+// CreateRecurringSchedules godoc
+// @Summary      Create recurring therapist schedules
+// @Description  Expand a weekly slot (day_of_week 0 = Sunday, HH:MM start and end times) into one schedule per week from start_date (defaults to today) to end_date, at most one year. Slots outside the clinic's opening hours or overlapping an existing schedule of the therapist are skipped.
+// @Tags         Therapist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Therapist ID"
+// @Param        request body model.RecurringScheduleRequest true "Recurring slot"
+// @Success      200 {object} util.APIResponse{data=model.RecurringScheduleResult} "Schedules created"
+// @Failure      400 {object} util.APIResponse "Invalid request or therapist not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/{id}/schedules/recurring [post]
+func CreateRecurringSchedules(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	_, therapist, err := getTherapistByID(c, db)
+	if err != nil {
+		return
+	}
+
+	var req model.RecurringScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+
+	slots, err := model.ExpandRecurringSchedule(req, time.Now())
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid recurring schedule",
+			Err: err,
+		})
+		return
+	}
+
+	clinicID, _ := middleware.GetClinicID(c)
+	result, err := createRecurringSchedules(db, therapist.ID, clinicID, slots)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to create schedules",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Schedules created",
+		Data: result,
+	})
+}
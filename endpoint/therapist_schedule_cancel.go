@@ -0,0 +1,117 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errScheduleAlreadyCancelled is returned when cancelling a cancelled schedule.
+var errScheduleAlreadyCancelled = errors.New("schedule is already cancelled")
+
+// cancelSchedule marks the schedule cancelled with the reason, when and by
+// whom, keeping the row for the audit trail.
+func cancelSchedule(db *gorm.DB, schedule *model.Schedule, reason string, userID *uint, now time.Time) error {
+	if schedule.Status == model.ScheduleStatusCancelled {
+		return errScheduleAlreadyCancelled
+	}
+	schedule.Status = model.ScheduleStatusCancelled
+	schedule.CancelReason = reason
+	schedule.CancelledAt = &now
+	schedule.CancelledByUserID = userID
+	return db.Model(schedule).
+		Select("status", "cancel_reason", "cancelled_at", "cancelled_by_user_id").
+		Updates(schedule).Error
+}
+
+// CancelTherapistSchedule godoc
+// @Summary      Cancel a therapist schedule
+// @Description  Mark a schedule slot cancelled with a reason instead of deleting it. The reason, time and cancelling user are kept for audit; cancelled slots no longer count as availability, block new schedules or appear as appointments.
+// @Tags         Therapist
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Schedule ID"
+// @Param        request body model.CancelScheduleRequest true "Cancellation reason"
+// @Success      200 {object} util.APIResponse{data=model.Schedule} "Schedule cancelled"
+// @Failure      400 {object} util.APIResponse "Invalid request or schedule already cancelled"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "Schedule not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /therapist/schedule/{id}/cancel [post]
+func CancelTherapistSchedule(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 0)
+	if err != nil || id == 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid schedule ID",
+			Err: fmt.Errorf("schedule ID must be a positive integer"),
+		})
+		return
+	}
+
+	var req model.CancelScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		if err == nil {
+			err = fmt.Errorf("reason must not be blank")
+		}
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "A cancellation reason is required",
+			Err: err,
+		})
+		return
+	}
+
+	var schedule model.Schedule
+	if err := db.First(&schedule, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallErrorNotFound(c, util.APIErrorParams{
+				Msg: "Schedule not found",
+				Err: err,
+			})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve schedule",
+			Err: err,
+		})
+		return
+	}
+
+	var userID *uint
+	if uid, ok := middleware.GetUserID(c); ok {
+		userID = &uid
+	}
+	if err := cancelSchedule(db, &schedule, strings.TrimSpace(req.Reason), userID, time.Now()); err != nil {
+		if errors.Is(err, errScheduleAlreadyCancelled) {
+			util.CallUserError(c, util.APIErrorParams{
+				Msg: "Schedule is already cancelled",
+				Err: err,
+			})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to cancel schedule",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Schedule cancelled",
+		Data: schedule,
+	})
+}
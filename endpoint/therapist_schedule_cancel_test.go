@@ -0,0 +1,74 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCancelTherapistSchedule(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	r.POST("/therapist/schedule/:id/cancel", CancelTherapistSchedule)
+	therapist := createTestTherapist(db, t, true)
+
+	start := time.Date(2025, 1, 13, 9, 0, 0, 0, time.UTC)
+	schedule := model.Schedule{TherapistID: therapist.ID, StartTime: start, EndTime: start.Add(time.Hour), Status: model.ScheduleStatusActive}
+	assert.NoError(t, db.Create(&schedule).Error)
+	path := fmt.Sprintf("/therapist/schedule/%d/cancel", schedule.ID)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: `{"reason": "  "}`})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: `{"reason": "Therapist on sick leave"}`})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	var resp struct {
+		Data model.Schedule `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.ScheduleStatusCancelled, resp.Data.Status)
+
+	var stored model.Schedule
+	assert.NoError(t, db.First(&stored, schedule.ID).Error, "the schedule is kept, not deleted")
+	assert.Equal(t, model.ScheduleStatusCancelled, stored.Status)
+	assert.Equal(t, "Therapist on sick leave", stored.CancelReason)
+	assert.NotNil(t, stored.CancelledAt)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: `{"reason": "again"}`})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/therapist/schedule/9999/cancel", body: `{"reason": "gone"}`})
+	assertStatusWithError(t, w, http.StatusNotFound, err)
+}
+
+func TestCancelledSchedulesAreNotAvailability(t *testing.T) {
+	_, db := setupTherapistTest(t)
+	therapist := createTestTherapist(db, t, true)
+	_ = createPatientIfNotExists(db, t, "CS001", "cs001@test.com")
+
+	start := clinicWallClock(time.Now()).Truncate(time.Hour).AddDate(0, 0, 1)
+	active := model.Schedule{TherapistID: therapist.ID, PatientCode: "CS001", StartTime: start.AddDate(0, 0, 1), EndTime: start.AddDate(0, 0, 1).Add(time.Hour)}
+	cancelled := model.Schedule{TherapistID: therapist.ID, PatientCode: "CS001", StartTime: start, EndTime: start.Add(time.Hour)}
+	assert.NoError(t, db.Create(&active).Error)
+	assert.NoError(t, db.Create(&cancelled).Error)
+	assert.NoError(t, cancelSchedule(db, &cancelled, "Clinic closed", nil, time.Now()))
+
+	overlaps, err := scheduleOverlaps(db, therapist.ID, start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.False(t, overlaps, "a cancelled slot can be scheduled again")
+
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	report, err := computeTherapistUtilization(db, day, day.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	if assert.Len(t, report.Therapists, 1) {
+		assert.Equal(t, int64(1), report.Therapists[0].ScheduledSlots)
+	}
+
+	next, err := findNextAppointment(db, "CS001", time.Now())
+	assert.NoError(t, err)
+	assert.Equal(t, active.ID, next.ScheduleID)
+}
@@ -0,0 +1,101 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateRecurringSchedules_SkipsOverlaps(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	therapist := createTestTherapist(db, t, true)
+	other := createTestTherapist(db, t, true)
+
+	at := func(date, hhmm string) time.Time {
+		tm, err := time.Parse("2006-01-02 15:04", date+" "+hhmm)
+		assert.NoError(t, err)
+		return tm
+	}
+	// Overlaps the 2025-01-13 slot; the other therapist's schedule does not count.
+	assert.NoError(t, db.Create(&model.Schedule{TherapistID: therapist.ID, StartTime: at("2025-01-13", "09:30"), EndTime: at("2025-01-13", "10:30")}).Error)
+	assert.NoError(t, db.Create(&model.Schedule{TherapistID: other.ID, StartTime: at("2025-01-20", "09:00"), EndTime: at("2025-01-20", "10:00")}).Error)
+	// Touches the 2025-01-27 slot without overlapping it.
+	assert.NoError(t, db.Create(&model.Schedule{TherapistID: therapist.ID, StartTime: at("2025-01-27", "08:00"), EndTime: at("2025-01-27", "09:00")}).Error)
+
+	body := `{"day_of_week": 1, "start_time": "09:00", "end_time": "10:00", "start_date": "2025-01-01", "end_date": "2025-01-31"}`
+	path := fmt.Sprintf("/therapist/%d/schedules/recurring", therapist.ID)
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPost, registerPath: "/therapist/:id/schedules/recurring", requestPath: path, body: body, handler: CreateRecurringSchedules})
+	assert.NoError(t, err)
+	if !assert.Equal(t, http.StatusOK, w.Code, w.Body.String()) {
+		return
+	}
+
+	var resp struct {
+		Data model.RecurringScheduleResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.RecurringScheduleResult{Created: 3, SkippedOverlap: 1}, resp.Data)
+
+	var count int64
+	assert.NoError(t, db.Model(&model.Schedule{}).Where("therapist_id = ?", therapist.ID).Count(&count).Error)
+	assert.Equal(t, int64(5), count)
+
+	// Repeating the request creates nothing new.
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: body})
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, model.RecurringScheduleResult{SkippedOverlap: 4}, resp.Data)
+}
+
+func TestCreateRecurringSchedules_SkipsClosedSlots(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	therapist := createTestTherapist(db, t, true)
+
+	// Open Mondays from 10:00, so a 09:00 slot is outside opening hours.
+	assert.NoError(t, db.Create(&model.ClinicHours{DayOfWeek: 1, Open: "10:00", Close: "17:00"}).Error)
+
+	path := fmt.Sprintf("/therapist/%d/schedules/recurring", therapist.ID)
+	r.POST("/therapist/:id/schedules/recurring", CreateRecurringSchedules)
+
+	var resp struct {
+		Data model.RecurringScheduleResult `json:"data"`
+	}
+	for _, tt := range []struct {
+		start, end string
+		want       model.RecurringScheduleResult
+	}{
+		{"09:00", "10:00", model.RecurringScheduleResult{SkippedClinicHours: 2}},
+		{"10:00", "11:00", model.RecurringScheduleResult{Created: 2}},
+	} {
+		body := fmt.Sprintf(`{"day_of_week": 1, "start_time": %q, "end_time": %q, "start_date": "2025-01-06", "end_date": "2025-01-13"}`, tt.start, tt.end)
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: path, body: body})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, tt.want, resp.Data, tt.start)
+	}
+}
+
+func TestCreateRecurringSchedules_InvalidRequest(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	therapist := createTestTherapist(db, t, true)
+
+	r.POST("/therapist/:id/schedules/recurring", CreateRecurringSchedules)
+	for _, tt := range []struct {
+		path string
+		body string
+	}{
+		{fmt.Sprintf("/therapist/%d/schedules/recurring", therapist.ID), `{"start_time": "09:00", "end_time": "10:00", "end_date": "2025-01-31"}`},
+		{fmt.Sprintf("/therapist/%d/schedules/recurring", therapist.ID), `{"day_of_week": 1, "start_time": "11:00", "end_time": "10:00", "end_date": "2025-01-31"}`},
+		{"/therapist/99999/schedules/recurring", `{"day_of_week": 1, "start_time": "09:00", "end_time": "10:00", "end_date": "2025-01-31"}`},
+	} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: tt.path, body: tt.body})
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, w.Code, tt.body)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
@@ -55,6 +56,33 @@ func TestListTherapist_WithPagination(t *testing.T) {
 	w, response, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/therapist", requestPath: "/therapist?limit=2&offset=1", handler: ListTherapist})
 	assert.NoError(t, err)
 	assertSuccessResponse(t, w, response)
+	data, _ := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(5), data["total"])
+	assert.Equal(t, float64(2), data["total_fetched"])
+	assert.Equal(t, true, data["has_more"])
+	assert.Contains(t, data, "next_cursor")
+	assert.Nil(t, data["next_cursor"])
+}
+
+func TestListTherapist_InvalidPagination(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	r.GET("/therapist", ListTherapist)
+
+	for i := 0; i < 3; i++ {
+		createTestTherapist(db, t, true)
+	}
+
+	for query, want := range map[string]int{
+		"limit=-5&offset=-2": 3,
+		"limit=2&offset=-1":  2,
+		"limit=100000":       3,
+	} {
+		w, response, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/therapist?" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		data, _ := response["data"].(map[string]interface{})
+		therapists, _ := data["therapists"].([]interface{})
+		assert.Len(t, therapists, want, query)
+	}
 }
 
 func TestListTherapist_WithKeyword(t *testing.T) {
@@ -68,10 +96,15 @@ func TestListTherapist_WithKeyword(t *testing.T) {
 		IsApproved: true,
 	}
 	db.Create(&therapist)
+	createTestTherapist(db, t, true)
 
 	w, response, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/therapist", requestPath: "/therapist?keyword=John", handler: ListTherapist})
 	assert.NoError(t, err)
 	assertSuccessResponse(t, w, response)
+	// total counts only the therapists matching the keyword.
+	data, _ := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["total"])
+	assert.Equal(t, false, data["has_more"])
 }
 
 func TestListTherapist_WithGroupByDate(t *testing.T) {
@@ -270,3 +303,105 @@ func TestDeleteTherapist_InvalidID(t *testing.T) {
 	assert.NoError(t, err)
 	assertStatus(t, w, http.StatusBadRequest)
 }
+
+func TestCreateTherapist_DuplicateEmail(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	r.POST("/therapist", CreateTherapist)
+
+	first := map[string]interface{}{"full_name": "First", "nik": "NIK-EMAIL-1", "email": "dup@test.com"}
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/therapist", body: first})
+	assert.NoError(t, err)
+	assertStatus(t, w, http.StatusOK)
+
+	second := map[string]interface{}{"full_name": "Second", "nik": "NIK-EMAIL-2", "email": "dup@test.com"}
+	w, response, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/therapist", body: second})
+	assert.NoError(t, err)
+	assertStatus(t, w, http.StatusBadRequest)
+	assert.Equal(t, "Email already registered", response["msg"])
+
+	// The index rejects duplicates that bypass the handler's pre-check.
+	err = db.Create(&model.Therapist{FullName: "Direct", NIK: "NIK-EMAIL-3", Email: "dup@test.com"}).Error
+	assert.True(t, isDuplicateKeyError(err), "expected unique index violation, got %v", err)
+
+	// Therapists without an email do not collide with each other.
+	assert.NoError(t, db.Create(&model.Therapist{FullName: "No Email 1", NIK: "NIK-EMAIL-4"}).Error)
+	assert.NoError(t, db.Create(&model.Therapist{FullName: "No Email 2", NIK: "NIK-EMAIL-5"}).Error)
+}
+
+func TestCreateTherapist_ReusesEmailOfDeletedTherapist(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	r.POST("/therapist", CreateTherapist)
+	r.DELETE("/therapist/:id", DeleteTherapist)
+
+	body := map[string]interface{}{"full_name": "Returning", "nik": "NIK-REUSE-1", "email": "reuse@test.com", "password": "old-password"}
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/therapist", body: body})
+	assert.NoError(t, err)
+	assertStatus(t, w, http.StatusOK)
+
+	var original model.Therapist
+	assert.NoError(t, db.Where("email = ?", "reuse@test.com").First(&original).Error)
+	var oldUser model.User
+	assert.NoError(t, db.Where("email = ?", "reuse@test.com").First(&oldUser).Error)
+	lockedUntil := time.Now().Add(time.Hour).Unix()
+	assert.NoError(t, db.Model(&oldUser).Updates(map[string]interface{}{"failed_attempts": 5, "locked_until": lockedUntil}).Error)
+	assert.NoError(t, db.Create(&model.Session{UserID: oldUser.ID, SessionToken: "old-therapist-session", ExpiresAt: time.Now().Add(time.Hour)}).Error)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodDelete, requestPath: fmt.Sprintf("/therapist/%d", original.ID)})
+	assert.NoError(t, err)
+	assertStatus(t, w, http.StatusOK)
+
+	// Deleting the therapist disables their login account and sessions.
+	assert.ErrorIs(t, db.First(&model.User{}, oldUser.ID).Error, gorm.ErrRecordNotFound)
+	var sessions int64
+	db.Model(&model.Session{}).Where("user_id = ?", oldUser.ID).Count(&sessions)
+	assert.Zero(t, sessions)
+
+	body["nik"] = "NIK-REUSE-2"
+	body["full_name"] = "Newcomer"
+	body["password"] = "new-password"
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/therapist", body: body})
+	assert.NoError(t, err)
+	assertStatus(t, w, http.StatusOK)
+
+	var active int64
+	db.Model(&model.Therapist{}).Where("email = ?", "reuse@test.com").Count(&active)
+	assert.Equal(t, int64(1), active)
+	var users []model.User
+	assert.NoError(t, db.Unscoped().Where("email = ?", "reuse@test.com").Find(&users).Error)
+	if assert.Len(t, users, 1) {
+		reused := users[0]
+		assert.Equal(t, oldUser.ID, reused.ID)
+		assert.False(t, reused.DeletedAt.Valid)
+		assert.Equal(t, "Newcomer", reused.Name)
+		assert.Equal(t, util.HashPassword("new-password"), reused.Password)
+		assert.Zero(t, reused.FailedAttempts)
+		assert.Nil(t, reused.LockedUntil)
+	}
+}
+
+func TestCreateTherapist_EmailUsedByNonTherapistUser(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	assert.NoError(t, db.Create(&model.User{Name: "Admin", Email: "admin-taken@test.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleAdmin}).Error)
+
+	body := map[string]interface{}{"full_name": "Therapist", "nik": "NIK-TAKEN", "email": "admin-taken@test.com"}
+	w, response, err := doRequestWithHandler(r, requestSpec{method: http.MethodPost, registerPath: "/therapist", requestPath: "/therapist", handler: CreateTherapist, body: body})
+	assert.NoError(t, err)
+	assertStatus(t, w, http.StatusBadRequest)
+	assert.Equal(t, "Email already registered", response["msg"])
+
+	var count int64
+	db.Model(&model.Therapist{}).Where("nik = ?", "NIK-TAKEN").Count(&count)
+	assert.Zero(t, count)
+}
+
+func TestUpdateTherapist_DuplicateEmail(t *testing.T) {
+	r, db := setupTherapistTest(t)
+	first := createTestTherapist(db, t, true)
+	second := createTestTherapist(db, t, true)
+
+	body := map[string]interface{}{"email": first.Email}
+	w, response, err := doRequestWithHandler(r, requestSpec{method: http.MethodPatch, registerPath: "/therapist/:id", requestPath: fmt.Sprintf("/therapist/%d", second.ID), handler: UpdateTherapist, body: body})
+	assert.NoError(t, err)
+	assertStatus(t, w, http.StatusBadRequest)
+	assert.Equal(t, "Email already registered", response["msg"])
+}
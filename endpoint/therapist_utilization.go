@@ -0,0 +1,133 @@
+package endpoint
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// computeTherapistUtilization counts, per therapist, the schedule slots
+// starting between start and end (inclusive days) and the completed
+// treatments on those days. Therapists with neither are left out.
+func computeTherapistUtilization(db *gorm.DB, start, end time.Time) (model.TherapistUtilizationReport, error) {
+	report := model.TherapistUtilizationReport{
+		StartDate:  start.Format(cadenceDateLayout),
+		EndDate:    end.Format(cadenceDateLayout),
+		Therapists: []model.TherapistUtilization{},
+	}
+
+	type therapistCount struct {
+		TherapistID uint
+		Count       int64
+	}
+	var slots []therapistCount
+	err := db.Model(&model.Schedule{}).
+		Scopes(model.ActiveSchedules).
+		Select("therapist_id, COUNT(*) AS count").
+		Where("start_time >= ? AND start_time < ?", start, end.AddDate(0, 0, 1)).
+		Group("therapist_id").
+		Scan(&slots).Error
+	if err != nil {
+		return report, err
+	}
+	var completed []therapistCount
+	err = db.Model(&model.Treatment{}).
+		Select("therapist_id, COUNT(*) AS count").
+		Where("treatment_date BETWEEN ? AND ?", report.StartDate, report.EndDate).
+		Scopes(attendedStatusScope).
+		Group("therapist_id").
+		Scan(&completed).Error
+	if err != nil {
+		return report, err
+	}
+
+	byID := map[uint]*model.TherapistUtilization{}
+	entry := func(id uint) *model.TherapistUtilization {
+		if byID[id] == nil {
+			byID[id] = &model.TherapistUtilization{TherapistID: id}
+		}
+		return byID[id]
+	}
+	for _, s := range slots {
+		entry(s.TherapistID).ScheduledSlots = s.Count
+	}
+	for _, t := range completed {
+		entry(t.TherapistID).CompletedTreatments = t.Count
+	}
+	if len(byID) == 0 {
+		return report, nil
+	}
+
+	ids := make([]uint, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	var therapists []model.Therapist
+	if err := db.Select("id", "full_name").Where("id IN ?", ids).Find(&therapists).Error; err != nil {
+		return report, err
+	}
+	for _, therapist := range therapists {
+		byID[therapist.ID].TherapistName = therapist.FullName
+	}
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	for _, id := range ids {
+		u := byID[id]
+		if u.ScheduledSlots > 0 {
+			pct := math.Round(float64(u.CompletedTreatments)/float64(u.ScheduledSlots)*10000) / 100
+			u.UtilizationPercent = &pct
+		}
+		report.Therapists = append(report.Therapists, *u)
+	}
+	return report, nil
+}
+
+// GetTherapistUtilization godoc
+// @Summary      Therapist utilization
+// @Description  Per therapist, the number of schedule slots and completed treatments in a date range, and completed treatments as a percentage of slots (null when nothing was scheduled; may exceed 100 when treatments were recorded outside the schedule). Defaults to the last 12 weeks.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success      200 {object} util.APIResponse{data=model.TherapistUtilizationReport} "Report generated"
+// @Failure      400 {object} util.APIResponse "Invalid date range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /report/therapist-utilization [get]
+func GetTherapistUtilization(c *gin.Context) {
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid date range",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := computeTherapistUtilization(db, start, end)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to generate report",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Report generated",
+		Data: report,
+	})
+}
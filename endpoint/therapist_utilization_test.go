@@ -0,0 +1,72 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTherapistUtilization(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	ann := model.Therapist{FullName: "Dr. Ann", NIK: "NIK-UTIL-1"}
+	bob := model.Therapist{FullName: "Dr. Bob", NIK: "NIK-UTIL-2"}
+	cat := model.Therapist{FullName: "Dr. Cat", NIK: "NIK-UTIL-3"}
+	for _, th := range []*model.Therapist{&ann, &bob, &cat} {
+		assert.NoError(t, db.Create(th).Error)
+	}
+
+	slot := func(therapistID uint, day string) {
+		start, err := time.Parse(time.DateOnly, day)
+		assert.NoError(t, err)
+		start = start.Add(9 * time.Hour)
+		assert.NoError(t, db.Create(&model.Schedule{TherapistID: therapistID, StartTime: start, EndTime: start.Add(time.Hour)}).Error)
+	}
+	// Ann: four slots in range, one after it.
+	for _, day := range []string{"2025-01-06", "2025-01-13", "2025-01-20", "2025-01-31", "2025-02-03"} {
+		slot(ann.ID, day)
+	}
+	// Bob: one slot, no completed treatment.
+	slot(bob.ID, "2025-01-08")
+
+	for _, tr := range []struct {
+		therapist uint
+		date      string
+		status    string
+	}{
+		{ann.ID, "2025-01-06", model.TreatmentStatusCompleted},
+		{ann.ID, "2025-01-13", model.TreatmentStatusCompleted},
+		{ann.ID, "2025-01-31", model.TreatmentStatusCompleted},
+		{ann.ID, "2025-01-20", model.TreatmentStatusNoShow},    // not completed
+		{ann.ID, "2025-02-03", model.TreatmentStatusCompleted}, // outside the range
+		{cat.ID, "2025-01-15", model.TreatmentStatusCompleted}, // treated without a schedule
+	} {
+		treatment := model.Treatment{PatientCode: "U001", TherapistID: tr.therapist, TreatmentDate: tr.date, Issues: "-", Treatment: "-", NextVisit: "-", Status: tr.status}
+		assert.NoError(t, db.Create(&treatment).Error)
+	}
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/therapist-utilization", requestPath: "/report/therapist-utilization?start_date=2025-01-01&end_date=2025-01-31", handler: GetTherapistUtilization})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.TherapistUtilizationReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	pct := func(v float64) *float64 { return &v }
+	assert.Equal(t, []model.TherapistUtilization{
+		{TherapistID: ann.ID, TherapistName: "Dr. Ann", ScheduledSlots: 4, CompletedTreatments: 3, UtilizationPercent: pct(75)},
+		{TherapistID: bob.ID, TherapistName: "Dr. Bob", ScheduledSlots: 1, CompletedTreatments: 0, UtilizationPercent: pct(0)},
+		{TherapistID: cat.ID, TherapistName: "Dr. Cat", ScheduledSlots: 0, CompletedTreatments: 1, UtilizationPercent: nil},
+	}, resp.Data.Therapists)
+}
+
+func TestGetTherapistUtilization_InvalidRange(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/therapist-utilization", requestPath: "/report/therapist-utilization?start_date=2025-02-01&end_date=2025-01-01", handler: GetTherapistUtilization})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
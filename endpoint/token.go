@@ -1,13 +1,16 @@
 package endpoint
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ariebrainware/basis-data-ltt/middleware"
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
 )
 
 // ValidateToken godoc
@@ -59,3 +62,119 @@ func ValidateToken(c *gin.Context) {
 		Data: result,
 	})
 }
+
+// sessionTTL returns how long a session expiring at expiresAt has left at
+// now, in whole seconds rounded down.
+func sessionTTL(expiresAt, now time.Time) model.SessionTTL {
+	return model.SessionTTL{
+		ExpiresAt: expiresAt,
+		ExpiresIn: int64(expiresAt.Sub(now) / time.Second),
+	}
+}
+
+// GetSessionTTL godoc
+// @Summary      Session remaining lifetime
+// @Description  Return when the current session expires and the seconds remaining, for an expiry countdown. Checking does not extend the session.
+// @Tags         Authentication
+// @Produce      json
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=model.SessionTTL} "Session is valid"
+// @Failure      401 {object} util.APIResponse "Invalid or expired session token"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /token/ttl [get]
+func GetSessionTTL(c *gin.Context) {
+	sessionToken := c.GetHeader("session-token")
+	if sessionToken == "" {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{Msg: "Invalid session token", Err: errors.New("session token not provided")})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	var session model.Session
+	err := db.Model(&model.Session{}).
+		Joins("JOIN users ON sessions.user_id = users.id AND users.deleted_at IS NULL").
+		Where("sessions.session_token = ? AND sessions.expires_at > ?", sessionToken, now).
+		Take(&session).Error
+	if err != nil {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{Msg: "Invalid or expired session token", Err: errors.New("session not found")})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Session is valid",
+		Data: sessionTTL(session.ExpiresAt, now),
+	})
+}
+
+// bearerOrSessionToken returns the token from "Authorization: Bearer <jwt>",
+// falling back to the session-token header.
+func bearerOrSessionToken(c *gin.Context) string {
+	if auth := c.GetHeader("Authorization"); auth != "" {
+		if token, ok := strings.CutPrefix(auth, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	return c.GetHeader("session-token")
+}
+
+// parseJWTClaims verifies the HS256 signature and expiry of tokenString with
+// the configured secret and returns its claims. Tokens without "exp" are
+// rejected.
+func parseJWTClaims(tokenString string, now time.Time) (model.JWTClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return util.GetJWTSecretByte(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return model.JWTClaims{}, err
+	}
+	if !claims.VerifyExpiresAt(now.Unix(), true) {
+		return model.JWTClaims{}, errors.New("token is expired")
+	}
+
+	result := model.JWTClaims{}
+	result.Subject, _ = claims["sub"].(string)
+	result.Issuer, _ = claims["iss"].(string)
+	result.Email, _ = claims["email"].(string)
+	if exp, ok := claims["exp"].(float64); ok {
+		result.ExpiresAt = int64(exp)
+	}
+	// Signup tokens carry the role as role_id.
+	role, ok := claims["role"].(float64)
+	if !ok {
+		role, _ = claims["role_id"].(float64)
+	}
+	result.Role = uint32(role)
+	return result, nil
+}
+
+// IntrospectJWT godoc
+// @Summary      Introspect JWT
+// @Description  Verify a JWT's signature and expiry with the configured secret and return its claims, without a database lookup. The token is read from the Authorization bearer header, or the session-token header.
+// @Tags         Authentication
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=model.JWTClaims} "Valid token"
+// @Failure      401 {object} util.APIResponse "Invalid or expired token"
+// @Router       /token/jwt/introspect [get]
+func IntrospectJWT(c *gin.Context) {
+	tokenString := bearerOrSessionToken(c)
+	if tokenString == "" {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{Msg: "Invalid token", Err: errors.New("missing token")})
+		return
+	}
+
+	claims, err := parseJWTClaims(tokenString, time.Now())
+	if err != nil {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{Msg: "Invalid or expired token", Err: err})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{Msg: "Valid token", Data: claims})
+}
@@ -2,12 +2,16 @@ package endpoint
 
 import (
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/ariebrainware/basis-data-ltt/middleware"
 	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/stretchr/testify/assert"
 	"gorm.io/gorm"
 )
@@ -180,3 +184,126 @@ func TestValidateToken_NoDatabaseConnection(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	assert.Contains(t, response["error"].(string), "Database connection not available")
 }
+
+func signTestJWT(t *testing.T, claims jwt.MapClaims, secret []byte) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func introspect(t *testing.T, headers map[string]string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w, response, err := doRequestWithHandler(gin.New(), requestSpec{method: http.MethodGet, registerPath: "/token/jwt/introspect", requestPath: "/token/jwt/introspect", handler: IntrospectJWT, headers: headers})
+	assert.NoError(t, err)
+	return w, response
+}
+
+func TestIntrospectJWT_ValidToken(t *testing.T) {
+	token, err := createJWTToken(model.User{Model: gorm.Model{ID: 42}, Email: "jwt@test.com", RoleID: model.RoleTherapist})
+	assert.NoError(t, err)
+
+	w, response := introspect(t, map[string]string{"Authorization": "Bearer " + token})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "42", data["sub"])
+	assert.Equal(t, float64(model.RoleTherapist), data["role"])
+	assert.Equal(t, jwtIssuer(), data["iss"])
+	assert.Equal(t, "jwt@test.com", data["email"])
+	assert.InDelta(t, float64(time.Now().Add(time.Hour).Unix()), data["exp"], 5)
+}
+
+func TestIntrospectJWT_SessionTokenHeader(t *testing.T) {
+	token := signTestJWT(t, jwt.MapClaims{"sub": "7", "role_id": 1, "exp": time.Now().Add(time.Minute).Unix()}, util.GetJWTSecretByte())
+
+	w, response := introspect(t, map[string]string{"session-token": token})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, float64(1), response["data"].(map[string]interface{})["role"])
+}
+
+func TestIntrospectJWT_ExpiredToken(t *testing.T) {
+	token := signTestJWT(t, jwt.MapClaims{"sub": "1", "role": 1, "exp": time.Now().Add(-time.Minute).Unix()}, util.GetJWTSecretByte())
+
+	w, response := introspect(t, map[string]string{"Authorization": "Bearer " + token})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.False(t, response["success"].(bool))
+}
+
+func TestIntrospectJWT_MissingExpiry(t *testing.T) {
+	token := signTestJWT(t, jwt.MapClaims{"sub": "1", "role": 1}, util.GetJWTSecretByte())
+
+	w, _ := introspect(t, map[string]string{"Authorization": "Bearer " + token})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestIntrospectJWT_TamperedToken(t *testing.T) {
+	token := signTestJWT(t, jwt.MapClaims{"sub": "1", "role": 3, "exp": time.Now().Add(time.Hour).Unix()}, util.GetJWTSecretByte())
+	// Swap in a payload claiming the admin role while keeping the original signature.
+	parts := strings.Split(token, ".")
+	forged := signTestJWT(t, jwt.MapClaims{"sub": "1", "role": 1, "exp": time.Now().Add(time.Hour).Unix()}, util.GetJWTSecretByte())
+	parts[1] = strings.Split(forged, ".")[1]
+	tampered := strings.Join(parts, ".")
+
+	w, _ := introspect(t, map[string]string{"Authorization": "Bearer " + tampered})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	wrongKey := signTestJWT(t, jwt.MapClaims{"sub": "1", "role": 1, "exp": time.Now().Add(time.Hour).Unix()}, []byte("some-other-secret"))
+	w, _ = introspect(t, map[string]string{"Authorization": "Bearer " + wrongKey})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestIntrospectJWT_MissingToken(t *testing.T) {
+	w, _ := introspect(t, nil)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func requestSessionTTL(t *testing.T, expiresAt time.Time) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	db := setupTokenTestDB(t)
+	r := gin.New()
+	r.Use(middleware.DatabaseMiddleware(db))
+
+	user := model.User{Name: "TTL User", Email: "ttl@test.com", Password: "hash"}
+	assert.NoError(t, db.Create(&user).Error)
+	assert.NoError(t, db.Create(&model.Session{UserID: user.ID, SessionToken: "ttl-token", ExpiresAt: expiresAt}).Error)
+
+	w, response, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/token/ttl", requestPath: "/token/ttl", handler: GetSessionTTL, headers: map[string]string{"session-token": "ttl-token"}})
+	assert.NoError(t, err)
+	return w, response
+}
+
+func TestGetSessionTTL_FreshSession(t *testing.T) {
+	w, response := requestSessionTTL(t, time.Now().Add(time.Hour))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	data := response["data"].(map[string]interface{})
+	ttl := data["expires_in"].(float64)
+	assert.Greater(t, ttl, float64(0))
+	assert.LessOrEqual(t, ttl, float64(3600))
+	assert.NotEmpty(t, data["expires_at"])
+}
+
+func TestGetSessionTTL_ExpiredSession(t *testing.T) {
+	w, _ := requestSessionTTL(t, time.Now().Add(-time.Minute))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestGetSessionTTL_MissingToken(t *testing.T) {
+	db := setupTokenTestDB(t)
+	r := gin.New()
+	r.Use(middleware.DatabaseMiddleware(db))
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/token/ttl", requestPath: "/token/ttl", handler: GetSessionTTL})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestSessionTTL_RoundsDown(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	ttl := sessionTTL(now.Add(90*time.Second+900*time.Millisecond), now)
+	assert.Equal(t, int64(90), ttl.ExpiresIn)
+}
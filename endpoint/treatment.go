@@ -3,16 +3,25 @@ package endpoint
 import (
 	"errors"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/middleware"
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
+const (
+	invalidTreatmentStatusMsg = "status must be 'scheduled', 'completed', 'no_show', or 'cancelled'"
+	negativeTreatmentCostMsg  = "cost must not be negative"
+	futureTreatmentDateMsg    = "treatment_date must not be in the future"
+)
+
 // treatmentUserError represents a user-facing (HTTP 400) error in treatment operations.
 type treatmentUserError struct {
 	msg string
@@ -25,9 +34,20 @@ type treatmentQueryParams struct {
 	limit       int
 	offset      int
 	therapistID int
+	createdBy   int
 	keyword     string
 	groupByDate string
+	tag         string
 	jakartaLoc  *time.Location
+	// nextVisitFrom and nextVisitTo bound next_visit (YYYY-MM-DD, inclusive)
+	// for follow-up call lists; empty means unbounded.
+	nextVisitFrom string
+	nextVisitTo   string
+	// modifiedSince limits the list to treatments changed after it, for
+	// incremental sync; zero means no limit. includeDeleted adds treatments
+	// soft-deleted after modifiedSince as tombstones.
+	modifiedSince  time.Time
+	includeDeleted bool
 }
 
 func validateTreatmentID(c *gin.Context) (string, bool) {
@@ -110,7 +130,7 @@ func buildTreatmentBaseQuery(db *gorm.DB) *gorm.DB {
 				AND latest_pricings.max_id = p1.id
 			WHERE p1.deleted_at IS NULL
 		) AS pricings ON pricings.therapist_id = treatments.therapist_id`).
-		Select("treatments.*, therapists.full_name as therapist_name, patients.full_name as patient_name, patients.age as age, COALESCE(pricings.price, 0) as price").
+		Select("treatments.*, therapists.full_name as therapist_name, patients.full_name as patient_name, patients.phone_number as phone_number, patients.age as age, COALESCE(pricings.price, 0) as price").
 		Where("patients.deleted_at IS NULL")
 }
 
@@ -145,6 +165,13 @@ func applyTherapistFilter(query *gorm.DB, therapistID int) *gorm.DB {
 	return query
 }
 
+func applyCreatedByFilter(query *gorm.DB, userID int) *gorm.DB {
+	if userID > 0 {
+		return query.Where("treatments.created_by_user_id = ?", userID)
+	}
+	return query
+}
+
 func applyDateFilter(query *gorm.DB, groupByDate string, jakartaLoc *time.Location) *gorm.DB {
 	if groupByDate == "" {
 		return query
@@ -168,6 +195,65 @@ func applyDateFilter(query *gorm.DB, groupByDate string, jakartaLoc *time.Locati
 	return query
 }
 
+// parseNextVisitWindow reads next_visit_from and next_visit_to, both optional
+// YYYY-MM-DD dates, and rejects a window that ends before it starts.
+func parseNextVisitWindow(c *gin.Context) (string, string, error) {
+	from, to := queryString(c, "next_visit_from"), queryString(c, "next_visit_to")
+	for _, value := range []string{from, to} {
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return "", "", fmt.Errorf("next_visit_from and next_visit_to must be YYYY-MM-DD dates")
+		}
+	}
+	if from != "" && to != "" && to < from {
+		return "", "", fmt.Errorf("next_visit_to must not be before next_visit_from")
+	}
+	return from, to, nil
+}
+
+// applyNextVisitFilter keeps treatments whose next_visit falls in the window.
+// next_visit is stored as YYYY-MM-DD, so string comparison orders by date and
+// treatments without a next visit never match.
+func applyNextVisitFilter(query *gorm.DB, from, to string) *gorm.DB {
+	if from != "" {
+		query = query.Where("treatments.next_visit >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("treatments.next_visit <= ? AND treatments.next_visit <> ''", to)
+	}
+	return query
+}
+
+// parseModifiedSince reads the optional modified_since RFC 3339 timestamp and
+// include_deleted flag. include_deleted is only honoured together with
+// modified_since.
+func parseModifiedSince(c *gin.Context) (time.Time, bool, error) {
+	value := queryString(c, "modified_since")
+	if value == "" {
+		return time.Time{}, false, nil
+	}
+	since, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("modified_since must be an RFC 3339 timestamp")
+	}
+	return since, c.Query("include_deleted") == "true", nil
+}
+
+// applyModifiedSinceFilter keeps treatments updated after since. With
+// includeDeleted, treatments soft-deleted after since are kept too; deleting
+// does not touch updated_at, so deleted_at is checked separately.
+func applyModifiedSinceFilter(query *gorm.DB, since time.Time, includeDeleted bool) *gorm.DB {
+	if since.IsZero() {
+		return query
+	}
+	if includeDeleted {
+		return query.Unscoped().Where("(treatments.updated_at > ? OR treatments.deleted_at > ?)", since, since)
+	}
+	return query.Where("treatments.updated_at > ? AND treatments.deleted_at IS NULL", since)
+}
+
 func fetchTreatments(db *gorm.DB, params treatmentQueryParams) ([]model.ListTreatementResponse, int64, error) {
 	var treatments []model.ListTreatementResponse
 	var totalTreatments int64
@@ -177,17 +263,28 @@ func fetchTreatments(db *gorm.DB, params treatmentQueryParams) ([]model.ListTrea
 	query = applyPagination(query, params.limit, params.offset)
 	query = applyKeywordFilter(query, params.keyword)
 	query = applyTherapistFilter(query, params.therapistID)
+	query = applyCreatedByFilter(query, params.createdBy)
 	query = applyDateFilter(query, params.groupByDate, params.jakartaLoc)
+	query = applyTagFilter(query, params.tag)
+	query = applyNextVisitFilter(query, params.nextVisitFrom, params.nextVisitTo)
+	query = applyModifiedSinceFilter(query, params.modifiedSince, params.includeDeleted)
 
 	if err := query.Find(&treatments).Error; err != nil {
 		return nil, 0, err
 	}
+	for i := range treatments {
+		treatments[i].Deleted = treatments[i].DeletedAt.Valid
+	}
 
 	// Build and execute count query (same filters, no pagination)
 	countQuery := buildCountQuery(db)
 	countQuery = applyKeywordFilter(countQuery, params.keyword)
 	countQuery = applyTherapistFilter(countQuery, params.therapistID)
+	countQuery = applyCreatedByFilter(countQuery, params.createdBy)
 	countQuery = applyDateFilter(countQuery, params.groupByDate, params.jakartaLoc)
+	countQuery = applyTagFilter(countQuery, params.tag)
+	countQuery = applyNextVisitFilter(countQuery, params.nextVisitFrom, params.nextVisitTo)
+	countQuery = applyModifiedSinceFilter(countQuery, params.modifiedSince, params.includeDeleted)
 
 	if err := countQuery.Count(&totalTreatments).Error; err != nil {
 		return nil, 0, err
@@ -196,8 +293,10 @@ func fetchTreatments(db *gorm.DB, params treatmentQueryParams) ([]model.ListTrea
 	return treatments, totalTreatments, nil
 }
 
+// parseQueryInt returns the non-negative integer in a query parameter, or
+// defaultVal when it is missing, blank, negative or not a number.
 func parseQueryInt(c *gin.Context, key string, defaultVal int) int {
-	if s := c.Query(key); s != "" {
+	if s := queryString(c, key); s != "" {
 		if v, err := strconv.Atoi(s); err == nil && v >= 0 {
 			return v
 		}
@@ -257,12 +356,18 @@ func handleSessionError(c *gin.Context, err error) {
 // @Produce      json
 // @Security     BearerAuth
 // @Security     SessionToken
-// @Param        limit query int false "Limit number of results"
+// @Param        limit query int false "Limit number of results (max 100)"
 // @Param        offset query int false "Offset for pagination"
 // @Param        therapist_id query int false "Filter by therapist ID"
+// @Param        created_by query int false "Filter by the ID of the user who entered the treatment"
 // @Param        keyword query string false "Search keyword for patient name or patient code"
 // @Param        group_by_date query string false "Filter by specific date (YYYY-MM-DD format)"
 // @Param        filter_by_therapist query boolean false "Filter by logged-in therapist"
+// @Param        tag query string false "Filter by treatment tag name"
+// @Param        next_visit_from query string false "Only treatments with next_visit on or after this date (YYYY-MM-DD)"
+// @Param        next_visit_to query string false "Only treatments with next_visit on or before this date (YYYY-MM-DD)"
+// @Param        modified_since query string false "Only treatments updated after this RFC 3339 timestamp, for incremental sync"
+// @Param        include_deleted query boolean false "With modified_since, also return treatments deleted since then, flagged deleted=true"
 // @Success      200 {object} util.APIResponse{data=object} "Treatments fetched successfully"
 // @Failure      400 {object} util.APIResponse "Invalid request or session error"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
@@ -274,7 +379,7 @@ func ListTreatments(c *gin.Context) {
 		return
 	}
 
-	jakartaLoc, err := time.LoadLocation("Asia/Jakarta")
+	jakartaLoc, err := time.LoadLocation(config.Timezone)
 	if err != nil {
 		util.CallServerError(c, util.APIErrorParams{
 			Msg: "Failed to load timezone",
@@ -283,13 +388,39 @@ func ListTreatments(c *gin.Context) {
 		return
 	}
 
+	nextVisitFrom, nextVisitTo, err := parseNextVisitWindow(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid next visit window",
+			Err: err,
+		})
+		return
+	}
+
+	modifiedSince, includeDeleted, err := parseModifiedSince(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid modified_since",
+			Err: err,
+		})
+		return
+	}
+
+	q := parseQueryParams(c)
+	limit, offset := parseListPagination(c)
 	params := treatmentQueryParams{
-		limit:       parseQueryInt(c, "limit", 0),
-		offset:      parseQueryInt(c, "offset", 0),
-		therapistID: parseQueryInt(c, "therapist_id", 0),
-		keyword:     c.Query("keyword"),
-		groupByDate: c.Query("group_by_date"),
-		jakartaLoc:  jakartaLoc,
+		limit:          limit,
+		offset:         offset,
+		therapistID:    q.TherapistID,
+		createdBy:      parseQueryInt(c, "created_by", 0),
+		keyword:        q.Keyword,
+		groupByDate:    q.GroupByDate,
+		tag:            queryString(c, "tag"),
+		jakartaLoc:     jakartaLoc,
+		nextVisitFrom:  nextVisitFrom,
+		nextVisitTo:    nextVisitTo,
+		modifiedSince:  modifiedSince,
+		includeDeleted: includeDeleted,
 	}
 
 	if c.Query("filter_by_therapist") == "true" {
@@ -312,13 +443,169 @@ func ListTreatments(c *gin.Context) {
 
 	util.CallSuccessOK(c, util.APISuccessParams{
 		Msg:  "Treatments fetched successfully",
-		Data: map[string]interface{}{"total": totalTreatments, "total_fetched": len(treatments), "treatments": treatments},
+		Data: util.NewPageResponse(treatments, totalTreatments, len(treatments), util.OffsetHasMore(params.offset, len(treatments), totalTreatments), nil).Named("treatments"),
 	})
 }
 
-func checkDuplicateTreatment(c *gin.Context, db *gorm.DB, date string, patientCode string) bool {
+// requirePatientUserForTreatment reports whether treatments may only be created
+// for patients that have a linked user account (REQUIRE_PATIENT_USER_FOR_TREATMENT=true).
+func requirePatientUserForTreatment() bool {
+	return os.Getenv("REQUIRE_PATIENT_USER_FOR_TREATMENT") == "true"
+}
+
+// allowFutureTreatmentDate reports whether completed treatments may be dated
+// after today (ALLOW_FUTURE_TREATMENT_DATE=true).
+func allowFutureTreatmentDate() bool {
+	return os.Getenv("ALLOW_FUTURE_TREATMENT_DATE") == "true"
+}
+
+// treatmentDateInFuture reports whether date (YYYY-MM-DD) is after today in
+// the clinic's timezone. Dates in another format are left alone.
+func treatmentDateInFuture(date string, now time.Time) bool {
+	d, err := time.Parse(cadenceDateLayout, date)
+	if err != nil {
+		return false
+	}
+	return d.Format(cadenceDateLayout) > clinicWallClock(now).Format(cadenceDateLayout)
+}
+
+// checkTreatmentDateNotFuture rejects a treatment_date after today unless
+// ALLOW_FUTURE_TREATMENT_DATE is set. Scheduled treatments are upcoming by
+// definition, so their dates are not checked. It responds with 400 and
+// returns false when the date is rejected.
+func checkTreatmentDateNotFuture(c *gin.Context, date, status string) bool {
+	if status == model.TreatmentStatusScheduled || allowFutureTreatmentDate() || !treatmentDateInFuture(date, time.Now()) {
+		return true
+	}
+	util.CallUserError(c, util.APIErrorParams{
+		Msg: futureTreatmentDateMsg,
+		Err: fmt.Errorf("treatment_date %s is in the future", date),
+	})
+	return false
+}
+
+// patientCodeStrict reports whether a new treatment's patient_code must match
+// the stored code exactly after normalization (PATIENT_CODE_STRICT=true).
+// Otherwise a code differing only in case resolves to the patient it names.
+func patientCodeStrict() bool {
+	return os.Getenv("PATIENT_CODE_STRICT") == "true"
+}
+
+// patientCodeMismatchError is returned in strict mode when the requested
+// patient code only matches a stored code that differs in case.
+type patientCodeMismatchError struct {
+	requested string
+	stored    string
+}
+
+func (e *patientCodeMismatchError) Error() string {
+	return fmt.Sprintf("patient_code %q does not exactly match stored patient_code %q", e.requested, e.stored)
+}
+
+// findTreatmentPatient returns the patient with the normalized code. Codes
+// are compared ignoring case because patients created before normalization
+// may be stored in lower case; an exact match is preferred. In strict mode a
+// match that differs in case is a patientCodeMismatchError.
+func findTreatmentPatient(db *gorm.DB, code string) (model.Patient, error) {
+	var candidates []model.Patient
+	if err := db.Where("UPPER(TRIM(patient_code)) = ? AND deleted_at IS NULL", code).Order("id ASC").Find(&candidates).Error; err != nil {
+		return model.Patient{}, err
+	}
+	if len(candidates) == 0 {
+		return model.Patient{}, gorm.ErrRecordNotFound
+	}
+	for _, p := range candidates {
+		if p.PatientCode == code {
+			return p, nil
+		}
+	}
+	if patientCodeStrict() {
+		return model.Patient{}, &patientCodeMismatchError{requested: code, stored: candidates[0].PatientCode}
+	}
+	return candidates[0], nil
+}
+
+// defaultTreatmentStatus is the status of new treatments that do not set one:
+// scheduled when SCHEDULE_NEW_TREATMENTS=true, completed otherwise.
+func defaultTreatmentStatus() string {
+	if os.Getenv("SCHEDULE_NEW_TREATMENTS") == "true" {
+		return model.TreatmentStatusScheduled
+	}
+	return model.TreatmentStatusCompleted
+}
+
+// attendedTreatmentCondition is the SQL condition for a treatment that counts
+// as an attended visit. It takes model.TreatmentStatusCompleted as argument
+// and is meant for JOIN clauses; use attendedStatusScope elsewhere.
+const attendedTreatmentCondition = "treatments.status = ?"
+
+// attendedStatusScope is a GORM scope that limits treatments to attended
+// visits, those that are completed. Scheduled treatments have not happened
+// yet, and cancellations and no-shows are not visits.
+func attendedStatusScope(db *gorm.DB) *gorm.DB {
+	return db.Where(attendedTreatmentCondition, model.TreatmentStatusCompleted)
+}
+
+// patientHasLinkedUser reports whether a user account exists with the patient's email.
+func patientHasLinkedUser(db *gorm.DB, patient model.Patient) (bool, error) {
+	email := strings.TrimSpace(patient.Email)
+	if email == "" {
+		return false, nil
+	}
+	var count int64
+	if err := db.Model(&model.User{}).Where("LOWER(email) = ?", strings.ToLower(email)).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ensurePatientUserLinked rejects the request when the deployment requires a
+// linked user account and the patient has none. It returns false after writing
+// an error response.
+func ensurePatientUserLinked(c *gin.Context, db *gorm.DB, patient model.Patient) bool {
+	if !requirePatientUserForTreatment() {
+		return true
+	}
+	linked, err := patientHasLinkedUser(db, patient)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Database error",
+			Err: err,
+		})
+		return false
+	}
+	if !linked {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Patient must have a linked user account before treatment can be recorded",
+			Err: fmt.Errorf("patient %s has no linked user", patient.PatientCode),
+		})
+		return false
+	}
+	return true
+}
+
+// treatmentExists reports whether the patient already has a treatment on date.
+func treatmentExists(db *gorm.DB, date string, patientCode string) (bool, error) {
 	var existingTreatment model.Treatment
-	if err := db.Where("treatment_date = ? AND patient_code = ?", date, patientCode).First(&existingTreatment).Error; err == nil {
+	err := db.Where("treatment_date = ? AND patient_code = ?", date, patientCode).First(&existingTreatment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// checkDuplicateTreatment responds and returns false when the patient already
+// has a treatment on date or the lookup fails.
+func checkDuplicateTreatment(c *gin.Context, db *gorm.DB, date string, patientCode string) bool {
+	exists, err := treatmentExists(db, date, patientCode)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to check existing treatments",
+			Err: err,
+		})
+		return false
+	}
+	if exists {
 		util.CallUserError(c, util.APIErrorParams{
 			Msg: "Treatment with this date already exists for this patient",
 			Err: fmt.Errorf("duplicate treatment date"),
@@ -328,8 +615,11 @@ func checkDuplicateTreatment(c *gin.Context, db *gorm.DB, date string, patientCo
 	return true
 }
 
-func createTreatmentAndTransaction(c *gin.Context, db *gorm.DB, req model.TreatementRequest) error {
-	return db.Transaction(func(tx *gorm.DB) error {
+// createTreatmentAndTransaction records the treatment in the patient's clinic
+// together with its transaction and returns the created treatment.
+func createTreatmentAndTransaction(c *gin.Context, db *gorm.DB, req model.TreatementRequest, clinicID uint) (model.Treatment, error) {
+	var treatment model.Treatment
+	err := db.Transaction(func(tx *gorm.DB) error {
 		therapistID, err := resolveTherapistID(c, tx, req)
 		if err != nil {
 			return &treatmentUserError{msg: err.Error()}
@@ -347,7 +637,23 @@ func createTreatmentAndTransaction(c *gin.Context, db *gorm.DB, req model.Treate
 			return err
 		}
 
-		treatment := model.Treatment{
+		cost := pricing.Price
+		if req.Cost != nil {
+			if *req.Cost < 0 {
+				return &treatmentUserError{msg: negativeTreatmentCostMsg}
+			}
+			cost = *req.Cost
+		}
+
+		status := req.Status
+		if status == "" {
+			status = defaultTreatmentStatus()
+		}
+		if !model.IsValidTreatmentStatus(status) {
+			return &treatmentUserError{msg: invalidTreatmentStatusMsg}
+		}
+
+		treatment = model.Treatment{
 			TreatmentDate: req.TreatmentDate,
 			PatientCode:   req.PatientCode,
 			TherapistID:   therapistID,
@@ -355,6 +661,12 @@ func createTreatmentAndTransaction(c *gin.Context, db *gorm.DB, req model.Treate
 			Treatment:     strings.Join(req.Treatment, ","),
 			Remarks:       req.Remarks,
 			NextVisit:     req.NextVisit,
+			ClinicID:      clinicID,
+			Status:        status,
+			Cost:          cost,
+		}
+		if userID, ok := middleware.GetUserID(c); ok {
+			treatment.CreatedByUserID = &userID
 		}
 		if err := tx.Create(&treatment).Error; err != nil {
 			return err
@@ -372,7 +684,7 @@ func createTreatmentAndTransaction(c *gin.Context, db *gorm.DB, req model.Treate
 		transaction := model.Transaction{
 			TreatmentID:   treatment.ID,
 			TherapistID:   therapistID,
-			Amount:        pricing.Price,
+			Amount:        cost,
 			Remarks:       req.Transaction.Remarks,
 			PaymentMethod: req.Transaction.PaymentMethod,
 			PaymentStatus: paymentStatus,
@@ -383,19 +695,37 @@ func createTreatmentAndTransaction(c *gin.Context, db *gorm.DB, req model.Treate
 
 		return nil
 	})
+	return treatment, err
+}
+
+// respondCreateTreatmentError reports a createTreatmentAndTransaction failure,
+// as 400 for treatmentUserError and 500 otherwise.
+func respondCreateTreatmentError(c *gin.Context, err error) {
+	var ue *treatmentUserError
+	if errors.As(err, &ue) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: ue.msg,
+			Err: err,
+		})
+		return
+	}
+	util.CallServerError(c, util.APIErrorParams{
+		Msg: "Failed to create treatment",
+		Err: err,
+	})
 }
 
 // CreateTreatment godoc
 // @Summary      Create a new treatment
-// @Description  Add a new treatment record
+// @Description  Add a new treatment record. cost defaults to the therapist's current price and is also the amount of the created transaction. Unusual but allowed input, such as a visit fewer than TREATMENT_MIN_INTERVAL_DAYS days after the previous one, is reported in data.warnings.
 // @Tags         Treatment
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Security     SessionToken
 // @Param        request body model.TreatementRequest true "Treatment information"
-// @Success      200 {object} util.APIResponse "Treatment created successfully"
-// @Failure      400 {object} util.APIResponse "Invalid request or duplicate treatment"
+// @Success      200 {object} util.APIResponse{data=model.CreateTreatmentResult} "Treatment created successfully"
+// @Failure      400 {object} util.APIResponse "Invalid request, duplicate treatment, or patient without linked user"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /treatment [post]
@@ -409,25 +739,48 @@ func CreateTreatment(c *gin.Context) {
 		return
 	}
 
+	status := req.Status
+	if status == "" {
+		status = defaultTreatmentStatus()
+	}
+	if !checkTreatmentDateNotFuture(c, req.TreatmentDate, status) {
+		return
+	}
+
 	db, ok := getDBOrAbort(c)
 	if !ok {
 		return
 	}
 
 	// Ensure the patient exists before proceeding
-	var patient model.Patient
-	if err := db.Where("patient_code = ? AND deleted_at IS NULL", req.PatientCode).First(&patient).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
+	req.PatientCode = util.NormalizePatientCode(req.PatientCode)
+	patient, err := findTreatmentPatient(db, req.PatientCode)
+	if err != nil {
+		var mismatch *patientCodeMismatchError
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
 			util.CallUserError(c, util.APIErrorParams{
 				Msg: "Patient not found",
 				Err: err,
 			})
-			return
+		case errors.As(err, &mismatch):
+			util.CallUserError(c, util.APIErrorParams{
+				Msg:  "Patient code does not exactly match the stored patient code",
+				Err:  err,
+				Data: map[string]string{"stored_patient_code": mismatch.stored},
+			})
+		default:
+			util.CallServerError(c, util.APIErrorParams{
+				Msg: "Database error",
+				Err: err,
+			})
 		}
-		util.CallServerError(c, util.APIErrorParams{
-			Msg: "Database error",
-			Err: err,
-		})
+		return
+	}
+	// Store the patient's own code so joins on patient_code match.
+	req.PatientCode = patient.PatientCode
+
+	if !ensurePatientUserLinked(c, db, patient) {
 		return
 	}
 
@@ -435,38 +788,31 @@ func CreateTreatment(c *gin.Context) {
 		return
 	}
 
-	if err := createTreatmentAndTransaction(c, db, req); err != nil {
-		var ue *treatmentUserError
-		if errors.As(err, &ue) {
-			util.CallUserError(c, util.APIErrorParams{
-				Msg: ue.msg,
-				Err: err,
-			})
-		} else {
-			util.CallServerError(c, util.APIErrorParams{
-				Msg: "Failed to create treatment",
-				Err: err,
-			})
-		}
+	treatment, err := createTreatmentAndTransaction(c, db, req, patient.ClinicID)
+	if err != nil {
+		respondCreateTreatmentError(c, err)
 		return
 	}
 
 	util.CallSuccessOK(c, util.APISuccessParams{
-		Msg:  "Treatment created successfully",
-		Data: nil,
+		Msg: "Treatment created successfully",
+		Data: model.CreateTreatmentResult{
+			TreatmentID: treatment.ID,
+			Warnings:    treatmentWarnings(db, treatment),
+		},
 	})
 }
 
 // UpdateTreatment godoc
 // @Summary      Update treatment information
-// @Description  Update an existing treatment record
+// @Description  Update an existing treatment record. Only the editable fields can be set; the clinic, ID, timestamps and creator are not editable, and any other field is rejected and listed in data.unknown_fields.
 // @Tags         Treatment
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
 // @Security     SessionToken
 // @Param        id path string true "Treatment ID"
-// @Param        request body model.Treatment true "Updated treatment information"
+// @Param        request body model.UpdateTreatmentRequest true "Updated treatment information"
 // @Success      200 {object} util.APIResponse{data=model.Treatment} "Treatment updated successfully"
 // @Failure      400 {object} util.APIResponse "Invalid request or treatment not found"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
@@ -478,11 +824,23 @@ func UpdateTreatment(c *gin.Context) {
 		return
 	}
 
-	var updates model.Treatment
-	if err := c.ShouldBindJSON(&updates); err != nil {
+	var req model.UpdateTreatmentRequest
+	if !bindStrictJSONOrRespond(c, &req, "Invalid input data") {
+		return
+	}
+	updates := req.ToTreatment()
+	if updates.Status != "" && !model.IsValidTreatmentStatus(updates.Status) {
 		util.CallUserError(c, util.APIErrorParams{
-			Msg: "Invalid input data",
-			Err: err,
+			Msg: invalidTreatmentStatusMsg,
+			Err: fmt.Errorf("invalid status %q", updates.Status),
+		})
+		return
+	}
+
+	if updates.Cost < 0 {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: negativeTreatmentCostMsg,
+			Err: fmt.Errorf("negative cost %d", updates.Cost),
 		})
 		return
 	}
@@ -497,6 +855,24 @@ func UpdateTreatment(c *gin.Context) {
 		return
 	}
 
+	if updates.Status != "" && !model.CanTransitionTreatmentStatus(existingTreatment.Status, updates.Status) {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: fmt.Sprintf("Cannot change status from '%s' to '%s'", existingTreatment.Status, updates.Status),
+			Err: fmt.Errorf("illegal status transition %q -> %q", existingTreatment.Status, updates.Status),
+		})
+		return
+	}
+
+	if updates.TreatmentDate != "" {
+		status := updates.Status
+		if status == "" {
+			status = existingTreatment.Status
+		}
+		if !checkTreatmentDateNotFuture(c, updates.TreatmentDate, status) {
+			return
+		}
+	}
+
 	if err := db.Model(existingTreatment).Updates(updates).Error; err != nil {
 		util.CallServerError(c, util.APIErrorParams{
 			Msg: "Failed to update treatment",
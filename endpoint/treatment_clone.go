@@ -0,0 +1,105 @@
+package endpoint
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// cloneTreatmentRequest builds a request for a follow-up of source on the
+// given date, copying the clinical notes but not the date, status or IDs.
+func cloneTreatmentRequest(source model.Treatment, req model.CloneTreatmentRequest) model.TreatementRequest {
+	var treatments []string
+	if source.Treatment != "" {
+		treatments = strings.Split(source.Treatment, ",")
+	}
+	return model.TreatementRequest{
+		TreatmentDate: req.TreatmentDate,
+		PatientCode:   source.PatientCode,
+		TherapistID:   source.TherapistID,
+		Issues:        source.Issues,
+		Treatment:     treatments,
+		Remarks:       source.Remarks,
+		NextVisit:     req.NextVisit,
+	}
+}
+
+// CloneTreatment godoc
+// @Summary      Clone a treatment as a follow-up
+// @Description  Create a new treatment for the same patient on a new date, copying issues, treatment and remarks from an existing one. Therapists get the clone assigned to themselves. The new date must not already have a treatment for the patient.
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Treatment ID to copy"
+// @Param        request body model.CloneTreatmentRequest true "Follow-up date"
+// @Success      200 {object} util.APIResponse{data=model.Treatment} "Treatment cloned"
+// @Failure      400 {object} util.APIResponse "Invalid request, treatment not found or duplicate treatment date"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /treatment/{id}/clone [post]
+func CloneTreatment(c *gin.Context) {
+	treatmentID, ok := validateTreatmentID(c)
+	if !ok {
+		return
+	}
+
+	var req model.CloneTreatmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid input data",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	source, ok := findTreatmentOrAbort(c, db, treatmentID)
+	if !ok {
+		return
+	}
+
+	var patient model.Patient
+	if err := db.Where("patient_code = ?", source.PatientCode).First(&patient).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallUserError(c, util.APIErrorParams{
+				Msg: "Patient not found",
+				Err: err,
+			})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Database error",
+			Err: err,
+		})
+		return
+	}
+
+	if !ensurePatientUserLinked(c, db, patient) {
+		return
+	}
+
+	if !checkDuplicateTreatment(c, db, req.TreatmentDate, source.PatientCode) {
+		return
+	}
+
+	clone, err := createTreatmentAndTransaction(c, db, cloneTreatmentRequest(*source, req), patient.ClinicID)
+	if err != nil {
+		respondCreateTreatmentError(c, err)
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Treatment cloned",
+		Data: clone,
+	})
+}
@@ -0,0 +1,91 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func seedCloneSource(t *testing.T, db *gorm.DB) model.Treatment {
+	t.Helper()
+	therapist := model.Therapist{FullName: "Clone Therapist", Email: "clone@test.com"}
+	assert.NoError(t, db.Create(&therapist).Error)
+	assert.NoError(t, db.Create(&model.Pricing{TherapistID: therapist.ID, Price: 150000}).Error)
+	_ = createPatientIfNotExists(db, t, "CLONE01", "clone-patient@test.com")
+
+	source := model.Treatment{
+		PatientCode:   "CLONE01",
+		TherapistID:   therapist.ID,
+		TreatmentDate: "2025-01-15",
+		Issues:        "Shoulder pain",
+		Treatment:     "Massage,Stretching",
+		Remarks:       "Improving",
+		NextVisit:     "2025-01-22",
+		Status:        model.TreatmentStatusCompleted,
+	}
+	assert.NoError(t, db.Create(&source).Error)
+	return source
+}
+
+func TestCloneTreatment_Success(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	source := seedCloneSource(t, db)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPost, registerPath: "/treatment/:id/clone", requestPath: fmt.Sprintf("/treatment/%d/clone", source.ID), handler: CloneTreatment, body: map[string]interface{}{"treatment_date": "2025-01-22"}})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp struct {
+		Data model.Treatment `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	clone := resp.Data
+	assert.NotZero(t, clone.ID)
+	assert.NotEqual(t, source.ID, clone.ID)
+	assert.Equal(t, "2025-01-22", clone.TreatmentDate)
+	assert.Equal(t, source.PatientCode, clone.PatientCode)
+	assert.Equal(t, source.TherapistID, clone.TherapistID)
+	assert.Equal(t, source.Issues, clone.Issues)
+	assert.Equal(t, source.Treatment, clone.Treatment)
+	assert.Equal(t, source.Remarks, clone.Remarks)
+	assert.Empty(t, clone.NextVisit)
+
+	var count int64
+	db.Model(&model.Treatment{}).Where("patient_code = ?", source.PatientCode).Count(&count)
+	assert.Equal(t, int64(2), count)
+
+	var transaction model.Transaction
+	assert.NoError(t, db.Where("treatment_id = ?", clone.ID).First(&transaction).Error)
+	assert.Equal(t, int64(150000), transaction.Amount)
+}
+
+func TestCloneTreatment_Rejected(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	source := seedCloneSource(t, db)
+	r.POST("/treatment/:id/clone", CloneTreatment)
+
+	tests := []struct {
+		name string
+		path string
+		body interface{}
+	}{
+		{"duplicate date", fmt.Sprintf("/treatment/%d/clone", source.ID), map[string]interface{}{"treatment_date": source.TreatmentDate}},
+		{"missing date", fmt.Sprintf("/treatment/%d/clone", source.ID), map[string]interface{}{}},
+		{"unknown treatment", "/treatment/99999/clone", map[string]interface{}{"treatment_date": "2025-02-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: tt.path, body: tt.body})
+			assertStatusWithError(t, w, http.StatusBadRequest, err)
+		})
+	}
+
+	var count int64
+	db.Model(&model.Treatment{}).Where("patient_code = ?", source.PatientCode).Count(&count)
+	assert.Equal(t, int64(1), count)
+}
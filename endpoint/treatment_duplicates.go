@@ -0,0 +1,86 @@
+package endpoint
+
+import (
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findTreatmentCollisions returns, in batch order, the entries that creating
+// the batch would reject as duplicates, using the same patient and date rule
+// as CreateTreatment.
+func findTreatmentCollisions(db *gorm.DB, items []model.TreatmentDuplicateCheckItem) ([]model.TreatmentDuplicateCollision, error) {
+	collisions := []model.TreatmentDuplicateCollision{}
+	seen := map[model.TreatmentDuplicateCheckItem]bool{}
+	for i, item := range items {
+		item.PatientCode = strings.TrimSpace(item.PatientCode)
+		item.TreatmentDate = strings.TrimSpace(item.TreatmentDate)
+		collision := model.TreatmentDuplicateCollision{Index: i, PatientCode: item.PatientCode, TreatmentDate: item.TreatmentDate}
+
+		exists, err := treatmentExists(db, item.TreatmentDate, item.PatientCode)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case exists:
+			collision.Reason = "exists"
+		case seen[item]:
+			collision.Reason = "repeated_in_batch"
+		}
+		seen[item] = true
+		if collision.Reason != "" {
+			collisions = append(collisions, collision)
+		}
+	}
+	return collisions, nil
+}
+
+// CheckTreatmentDuplicates godoc
+// @Summary      Check planned treatments for duplicates
+// @Description  Check a batch of up to 500 patient_code and treatment_date pairs before submitting them. Returns the entries that would be rejected as duplicates, by their index in the batch: "exists" when the patient already has a treatment on that date, "repeated_in_batch" when an earlier entry has the same patient and date. Nothing is created.
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        request body model.TreatmentDuplicateCheckRequest true "Planned treatments"
+// @Success      200 {object} util.APIResponse{data=model.TreatmentDuplicateCheckResult} "Duplicate check completed"
+// @Failure      400 {object} util.APIResponse "Invalid request"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /treatment/check-duplicates [post]
+func CheckTreatmentDuplicates(c *gin.Context) {
+	var req model.TreatmentDuplicateCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid input data",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	collisions, err := findTreatmentCollisions(db, req.Treatments)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to check treatments",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg: "Duplicate check completed",
+		Data: model.TreatmentDuplicateCheckResult{
+			Checked:    len(req.Treatments),
+			Collisions: collisions,
+		},
+	})
+}
@@ -0,0 +1,56 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func checkDuplicates(t *testing.T, body string) model.TreatmentDuplicateCheckResult {
+	t.Helper()
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, db.Create(&model.Treatment{TreatmentDate: "2025-01-15", PatientCode: "D001", TherapistID: 1, Issues: "-", Treatment: "-", NextVisit: "-"}).Error)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPost, registerPath: "/treatment/check-duplicates", requestPath: "/treatment/check-duplicates", body: body, handler: CheckTreatmentDuplicates})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.TreatmentDuplicateCheckResult `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp.Data
+}
+
+func TestCheckTreatmentDuplicates_AllClear(t *testing.T) {
+	got := checkDuplicates(t, `{"treatments": [
+		{"patient_code": "D001", "treatment_date": "2025-01-16"},
+		{"patient_code": "D002", "treatment_date": "2025-01-15"}
+	]}`)
+	assert.Equal(t, 2, got.Checked)
+	assert.Empty(t, got.Collisions)
+}
+
+func TestCheckTreatmentDuplicates_SomeColliding(t *testing.T) {
+	got := checkDuplicates(t, `{"treatments": [
+		{"patient_code": "D002", "treatment_date": "2025-01-15"},
+		{"patient_code": "D001", "treatment_date": "2025-01-15"},
+		{"patient_code": "D002", "treatment_date": "2025-01-15"}
+	]}`)
+	assert.Equal(t, 3, got.Checked)
+	assert.Equal(t, []model.TreatmentDuplicateCollision{
+		{Index: 1, PatientCode: "D001", TreatmentDate: "2025-01-15", Reason: "exists"},
+		{Index: 2, PatientCode: "D002", TreatmentDate: "2025-01-15", Reason: "repeated_in_batch"},
+	}, got.Collisions)
+}
+
+func TestCheckTreatmentDuplicates_InvalidBody(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+	r.POST("/treatment/check-duplicates", CheckTreatmentDuplicates)
+	for _, body := range []string{`{"treatments": []}`, `{"treatments": [{"patient_code": "D001"}]}`} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment/check-duplicates", body: body})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,49 @@
+package endpoint
+
+import (
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/ariebrainware/basis-data-ltt/util/interop"
+	"github.com/gin-gonic/gin"
+)
+
+// GetTreatmentFHIR godoc
+// @Summary      Get a treatment as a FHIR-lite Encounter
+// @Description  Map a treatment to a minimal FHIR Encounter-style resource for partner systems: subject references the patient by code (Patient/J001), performer references the therapist by ID (Practitioner/1), period is the treatment date and reasonCode holds the issues. Patient and therapist names are added as reference displays when they still exist.
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path string true "Treatment ID"
+// @Success      200 {object} util.APIResponse{data=interop.Encounter} "Treatment encounter retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid request or treatment not found"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Router       /treatment/{id}/fhir [get]
+func GetTreatmentFHIR(c *gin.Context) {
+	treatmentID, ok := validateTreatmentID(c)
+	if !ok {
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	treatment, ok := findTreatmentOrAbort(c, db, treatmentID)
+	if !ok {
+		return
+	}
+
+	// Names are only displays; a missing patient or therapist keeps the reference.
+	var patient model.Patient
+	db.Select("full_name").Where("patient_code = ?", treatment.PatientCode).Limit(1).Find(&patient)
+	var therapist model.Therapist
+	db.Select("full_name").Where("id = ?", treatment.TherapistID).Limit(1).Find(&therapist)
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Treatment encounter retrieved",
+		Data: interop.EncounterFromTreatment(*treatment, patient.FullName, therapist.FullName),
+	})
+}
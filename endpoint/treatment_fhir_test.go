@@ -0,0 +1,51 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util/interop"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTreatmentFHIR(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/treatment/:id/fhir", GetTreatmentFHIR)
+
+	therapist := createTestTherapist(db, t, true)
+	patient := model.Patient{FullName: "Fhir Patient", PatientCode: "F001"}
+	assert.NoError(t, db.Create(&patient).Error)
+	treatment := model.Treatment{TreatmentDate: "2025-01-15", PatientCode: "F001", TherapistID: therapist.ID, Issues: "Back pain", Treatment: "-", NextVisit: "-", Status: model.TreatmentStatusCompleted}
+	assert.NoError(t, db.Create(&treatment).Error)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/treatment/%d/fhir", treatment.ID)})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data interop.Encounter `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	enc := resp.Data
+	assert.Equal(t, "Encounter", enc.ResourceType)
+	assert.Equal(t, fmt.Sprint(treatment.ID), enc.ID)
+	assert.Equal(t, "finished", enc.Status)
+	assert.Equal(t, interop.Reference{Reference: "Patient/F001", Display: "Fhir Patient"}, enc.Subject)
+	assert.Equal(t, []interop.Reference{{Reference: fmt.Sprintf("Practitioner/%d", therapist.ID), Display: therapist.FullName}}, enc.Performer)
+	assert.Equal(t, interop.Period{Start: "2025-01-15", End: "2025-01-15"}, enc.Period)
+	assert.Equal(t, []interop.CodeableConcept{{Text: "Back pain"}}, enc.ReasonCode)
+
+	// A deleted therapist keeps the reference without a display.
+	assert.NoError(t, db.Delete(&therapist).Error)
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/treatment/%d/fhir", treatment.ID)})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	resp.Data = interop.Encounter{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, fmt.Sprintf("Practitioner/%d", therapist.ID), resp.Data.Performer[0].Reference)
+	assert.Empty(t, resp.Data.Performer[0].Display)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment/99999/fhir"})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
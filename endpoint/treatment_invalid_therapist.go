@@ -0,0 +1,159 @@
+package endpoint
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// invalidTherapistTreatmentsQuery selects treatments whose therapist_id
+// matches no therapist that is not soft-deleted.
+func invalidTherapistTreatmentsQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.Treatment{}).
+		Joins("LEFT JOIN therapists ON therapists.id = treatments.therapist_id AND therapists.deleted_at IS NULL").
+		Where("therapists.id IS NULL")
+}
+
+// fetchInvalidTherapistTreatments returns one page of treatments with a
+// missing therapist, oldest first, and their total number.
+func fetchInvalidTherapistTreatments(db *gorm.DB, limit, offset int) ([]model.Treatment, int64, error) {
+	var total int64
+	if err := invalidTherapistTreatmentsQuery(db).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	treatments := []model.Treatment{}
+	query := applyPagination(invalidTherapistTreatmentsQuery(db).Select("treatments.*").Order("treatments.treatment_date ASC, treatments.id ASC"), limit, offset)
+	if err := query.Find(&treatments).Error; err != nil {
+		return nil, 0, err
+	}
+	return treatments, total, nil
+}
+
+// reassignInvalidTherapistTreatments moves treatments with a missing
+// therapist, limited to ids when given, and their transactions to
+// therapistID. Treatments whose therapist exists are left alone.
+func reassignInvalidTherapistTreatments(db *gorm.DB, therapistID uint, ids []uint) (int64, error) {
+	var reassigned int64
+	err := db.Transaction(func(tx *gorm.DB) error {
+		query := invalidTherapistTreatmentsQuery(tx)
+		if len(ids) > 0 {
+			query = query.Where("treatments.id IN ?", ids)
+		}
+		var treatmentIDs []uint
+		if err := query.Pluck("treatments.id", &treatmentIDs).Error; err != nil {
+			return err
+		}
+		if len(treatmentIDs) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&model.Treatment{}).Where("id IN ?", treatmentIDs).Update("therapist_id", therapistID)
+		if result.Error != nil {
+			return result.Error
+		}
+		reassigned = result.RowsAffected
+		return tx.Model(&model.Transaction{}).Where("treatment_id IN ?", treatmentIDs).Update("therapist_id", therapistID).Error
+	})
+	return reassigned, err
+}
+
+// ListInvalidTherapistTreatments godoc
+// @Summary      List treatments with a missing therapist
+// @Description  Get treatments whose therapist_id has no matching therapist, or only a deleted one, so they can be reassigned
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        limit query int false "Limit number of results"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200 {object} util.APIResponse{data=object} "Treatments with a missing therapist retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /treatment/invalid-therapist [get]
+func ListInvalidTherapistTreatments(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	offset := parseQueryInt(c, "offset", 0)
+	treatments, total, err := fetchInvalidTherapistTreatments(db, parseQueryInt(c, "limit", 0), offset)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve treatments with a missing therapist",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Treatments with a missing therapist retrieved",
+		Data: util.NewPageResponse(treatments, total, len(treatments), util.OffsetHasMore(offset, len(treatments), total), nil).Named("treatments"),
+	})
+}
+
+// ReassignInvalidTherapistTreatments godoc
+// @Summary      Reassign treatments with a missing therapist
+// @Description  Move treatments whose therapist no longer exists, and their transactions, to an existing therapist. Limit the change with treatment_ids; IDs of treatments whose therapist exists are ignored.
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        request body model.ReassignTherapistRequest true "Target therapist and optional treatment IDs"
+// @Success      200 {object} util.APIResponse{data=object} "Treatments reassigned"
+// @Failure      400 {object} util.APIResponse "Invalid request or unknown therapist"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /treatment/invalid-therapist/reassign [post]
+func ReassignInvalidTherapistTreatments(c *gin.Context) {
+	var req model.ReassignTherapistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid request body",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	var therapist model.Therapist
+	if err := db.First(&therapist, req.TherapistID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallUserError(c, util.APIErrorParams{
+				Msg: "Therapist not found",
+				Err: fmt.Errorf("therapist_id %d is not registered", req.TherapistID),
+			})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve therapist",
+			Err: err,
+		})
+		return
+	}
+
+	reassigned, err := reassignInvalidTherapistTreatments(db, therapist.ID, req.TreatmentIDs)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to reassign treatments",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Treatments reassigned",
+		Data: map[string]interface{}{"therapist_id": therapist.ID, "reassigned": reassigned},
+	})
+}
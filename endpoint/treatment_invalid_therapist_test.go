@@ -0,0 +1,68 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidTherapistTreatments_DetectAndReassign(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/treatment/invalid-therapist", ListInvalidTherapistTreatments)
+	r.POST("/treatment/invalid-therapist/reassign", ReassignInvalidTherapistTreatments)
+
+	active := model.Therapist{FullName: "Active Therapist", Email: "active-ref@test.com"}
+	gone := model.Therapist{FullName: "Gone Therapist", Email: "gone-ref@test.com"}
+	assert.NoError(t, db.Create(&active).Error)
+	assert.NoError(t, db.Create(&gone).Error)
+
+	valid := createTestTreatment(db, t, "REF-OK", active.ID)
+	deletedTherapist := createTestTreatment(db, t, "REF-DEL", gone.ID)
+	assert.NoError(t, db.Delete(&gone).Error)
+	missing := model.Treatment{PatientCode: "REF-NONE", TherapistID: 99999, TreatmentDate: "2025-01-01", Issues: "-", Treatment: "-", NextVisit: "-"}
+	assert.NoError(t, db.Create(&missing).Error)
+	assert.NoError(t, db.Create(&model.Transaction{TreatmentID: missing.ID, TherapistID: 99999, Amount: 1000, PaymentStatus: "unpaid"}).Error)
+
+	list := func() []uint {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment/invalid-therapist"})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data struct {
+				Total      int64             `json:"total"`
+				Treatments []model.Treatment `json:"treatments"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		ids := []uint{}
+		for _, tr := range resp.Data.Treatments {
+			ids = append(ids, tr.ID)
+		}
+		assert.Equal(t, int64(len(ids)), resp.Data.Total)
+		return ids
+	}
+
+	assert.Equal(t, []uint{missing.ID, deletedTherapist.ID}, list())
+
+	// Reassigning to an unknown therapist is rejected.
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment/invalid-therapist/reassign", body: map[string]interface{}{"therapist_id": 99999}})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+
+	// Only the listed IDs with a missing therapist move; valid ones are ignored.
+	body := map[string]interface{}{"therapist_id": active.ID, "treatment_ids": []uint{missing.ID, valid.ID}}
+	w, resp, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment/invalid-therapist/reassign", body: body})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Equal(t, float64(1), resp["data"].(map[string]interface{})["reassigned"])
+	assert.Equal(t, []uint{deletedTherapist.ID}, list())
+
+	var moved model.Transaction
+	assert.NoError(t, db.Where("treatment_id = ?", missing.ID).First(&moved).Error)
+	assert.Equal(t, active.ID, moved.TherapistID)
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment/invalid-therapist/reassign", body: map[string]interface{}{"therapist_id": active.ID}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Empty(t, list())
+}
@@ -0,0 +1,171 @@
+package endpoint
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultIssueTermLimit = 50
+	maxIssueTermLimit     = 200
+	// maxIssueTermRows bounds how many treatments are read per report.
+	maxIssueTermRows = 5000
+)
+
+// issueStopwords are skipped when counting terms. Issues are written in
+// Indonesian and English, so both lists are covered.
+var issueStopwords = map[string]bool{
+	// English
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "but": true, "by": true, "for": true, "from": true, "has": true,
+	"have": true, "in": true, "is": true, "it": true, "its": true, "no": true,
+	"not": true, "of": true, "on": true, "or": true, "since": true, "that": true,
+	"the": true, "this": true, "to": true, "was": true, "when": true, "with": true,
+	// Indonesian
+	"ada": true, "agak": true, "akan": true, "dan": true, "dari": true, "dengan": true,
+	"di": true, "ini": true, "itu": true, "juga": true, "ke": true, "lagi": true,
+	"masih": true, "pada": true, "saat": true, "sejak": true, "sudah": true, "tapi": true,
+	"tidak": true, "untuk": true, "yang": true,
+}
+
+// issueTermCounter tallies words and two-word phrases across issue texts.
+type issueTermCounter map[string]int64
+
+// add counts the terms in one issue text. Words are lower-cased letters and
+// digits; stopwords, single characters and numbers are dropped and break
+// phrases, so "pain in back" yields no "pain back" phrase.
+func (tc issueTermCounter) add(text string) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	prev := ""
+	for _, w := range words {
+		if !isIssueTerm(w) {
+			prev = ""
+			continue
+		}
+		tc[w]++
+		if prev != "" {
+			tc[prev+" "+w]++
+		}
+		prev = w
+	}
+}
+
+func isIssueTerm(w string) bool {
+	if len([]rune(w)) < 2 || issueStopwords[w] {
+		return false
+	}
+	return strings.IndexFunc(w, unicode.IsLetter) >= 0
+}
+
+// top returns up to limit terms by descending count, ties broken
+// alphabetically. Phrases seen only once are left out as noise.
+func (tc issueTermCounter) top(limit int) []model.IssueTerm {
+	terms := make([]model.IssueTerm, 0, len(tc))
+	for term, count := range tc {
+		if count < 2 && strings.Contains(term, " ") {
+			continue
+		}
+		terms = append(terms, model.IssueTerm{Term: term, Count: count})
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if terms[i].Count != terms[j].Count {
+			return terms[i].Count > terms[j].Count
+		}
+		return terms[i].Term < terms[j].Term
+	})
+	if len(terms) > limit {
+		terms = terms[:limit]
+	}
+	return terms
+}
+
+// computeIssueTerms counts terms in the issues of treatments between start
+// and end (inclusive). At most maxIssueTermRows of the most recent
+// treatments are read.
+func computeIssueTerms(db *gorm.DB, start, end time.Time, limit int) (model.IssueTermsReport, error) {
+	report := model.IssueTermsReport{
+		StartDate: start.Format(cadenceDateLayout),
+		EndDate:   end.Format(cadenceDateLayout),
+		Terms:     []model.IssueTerm{},
+	}
+
+	var issues []string
+	err := db.Model(&model.Treatment{}).
+		Where("treatment_date BETWEEN ? AND ?", report.StartDate, report.EndDate).
+		Where("issues <> ''").
+		Order("treatment_date DESC").
+		Order("id DESC").
+		Limit(maxIssueTermRows+1).
+		Pluck("issues", &issues).Error
+	if err != nil {
+		return report, err
+	}
+	if len(issues) > maxIssueTermRows {
+		issues = issues[:maxIssueTermRows]
+		report.Truncated = true
+	}
+
+	counter := issueTermCounter{}
+	for _, text := range issues {
+		counter.add(text)
+	}
+	report.TreatmentCount = int64(len(issues))
+	report.Terms = counter.top(limit)
+	return report, nil
+}
+
+// GetIssueTerms godoc
+// @Summary      Treatment issue term frequencies
+// @Description  Most frequent words and two-word phrases in treatment issues over a date range, for a tag cloud. Common English and Indonesian stopwords are excluded and phrases seen only once are dropped. Defaults to the last 12 weeks; at most 5000 of the most recent treatments are read.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Param        limit query int false "Maximum terms to return (default 50, max 200)"
+// @Success      200 {object} util.APIResponse{data=model.IssueTermsReport} "Issue terms retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid date range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /analytics/issue-terms [get]
+func GetIssueTerms(c *gin.Context) {
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid date range",
+			Err: err,
+		})
+		return
+	}
+	limit := parsePositiveInt(c.Query("limit"), defaultIssueTermLimit, maxIssueTermLimit)
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := computeIssueTerms(db, start, end, limit)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve issue terms",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Issue terms retrieved",
+		Data: report,
+	})
+}
@@ -0,0 +1,56 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetIssueTerms(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/analytics/issue-terms", GetIssueTerms)
+
+	therapist := createTestTherapist(db, t, true)
+	for _, seed := range []struct {
+		date   string
+		issues string
+	}{
+		{"2025-01-06", "Back pain and stiff neck"},
+		{"2025-01-07", "Sakit pinggang, back pain"},
+		{"2025-01-08", "back pain since 2 weeks"},
+		{"2025-01-09", "Pinggang masih sakit"},
+		{"2025-03-01", "Back pain"}, // after the range
+	} {
+		tr := createTestTreatment(db, t, "IT001", therapist.ID)
+		assert.NoError(t, db.Model(&tr).Updates(map[string]interface{}{"treatment_date": seed.date, "issues": seed.issues}).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/analytics/issue-terms?start_date=2025-01-01&end_date=2025-01-31&limit=6"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data model.IssueTermsReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(4), resp.Data.TreatmentCount)
+	assert.False(t, resp.Data.Truncated)
+	assert.Equal(t, []model.IssueTerm{
+		{Term: "back", Count: 3},
+		{Term: "back pain", Count: 3},
+		{Term: "pain", Count: 3},
+		{Term: "pinggang", Count: 2},
+		{Term: "sakit", Count: 2},
+		{Term: "neck", Count: 1},
+	}, resp.Data.Terms)
+}
+
+func TestGetIssueTerms_InvalidRange(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+	r.GET("/analytics/issue-terms", GetIssueTerms)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/analytics/issue-terms?start_date=2025-02-01&end_date=2025-01-01"})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
@@ -0,0 +1,141 @@
+package endpoint
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultNearDuplicateWindowDays = 1
+	maxNearDuplicateWindowDays     = 30
+)
+
+// parseNearDuplicateWindow reads the window query parameter, the most days
+// two treatments may be apart to count as near-duplicates.
+func parseNearDuplicateWindow(c *gin.Context) (int, error) {
+	value := queryString(c, "window")
+	if value == "" {
+		return defaultNearDuplicateWindowDays, nil
+	}
+	window, err := strconv.Atoi(value)
+	if err != nil || window < 1 || window > maxNearDuplicateWindowDays {
+		return 0, fmt.Errorf("window must be a number of days between 1 and %d", maxNearDuplicateWindowDays)
+	}
+	return window, nil
+}
+
+// findNearDuplicateTreatments pairs each treatment with the next one of the
+// same patient that has identical issues and treatment text and is at most
+// window days later. Only groups of such treatments are loaded; the date
+// distance is computed in Go so it is the same on every database. Treatments
+// with neither issues nor treatment text are ignored.
+func findNearDuplicateTreatments(db *gorm.DB, window int) ([]model.TreatmentNearDuplicate, error) {
+	repeated := db.Model(&model.Treatment{}).
+		Select("patient_code, issues, treatment").
+		Where("issues <> '' OR treatment <> ''").
+		Group("patient_code, issues, treatment").
+		Having("COUNT(*) > 1")
+
+	var candidates []model.Treatment
+	err := db.Model(&model.Treatment{}).
+		Joins("JOIN (?) AS repeated ON repeated.patient_code = treatments.patient_code AND repeated.issues = treatments.issues AND repeated.treatment = treatments.treatment", repeated).
+		Select("treatments.id, treatments.patient_code, treatments.treatment_date, treatments.issues, treatments.treatment").
+		Order("treatments.patient_code ASC, treatments.issues ASC, treatments.treatment ASC, treatments.treatment_date ASC, treatments.id ASC").
+		Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := []model.TreatmentNearDuplicate{}
+	for i := 1; i < len(candidates); i++ {
+		prev, cur := candidates[i-1], candidates[i]
+		if prev.PatientCode != cur.PatientCode || prev.Issues != cur.Issues || prev.Treatment != cur.Treatment {
+			continue
+		}
+		prevDate, err := time.Parse(time.DateOnly, prev.TreatmentDate)
+		if err != nil {
+			continue
+		}
+		curDate, err := time.Parse(time.DateOnly, cur.TreatmentDate)
+		if err != nil {
+			continue
+		}
+		days := int(curDate.Sub(prevDate).Hours() / 24)
+		if days > window {
+			continue
+		}
+		duplicates = append(duplicates, model.TreatmentNearDuplicate{
+			PatientCode:         cur.PatientCode,
+			Issues:              cur.Issues,
+			Treatment:           cur.Treatment,
+			FirstTreatmentID:    prev.ID,
+			FirstTreatmentDate:  prev.TreatmentDate,
+			SecondTreatmentID:   cur.ID,
+			SecondTreatmentDate: cur.TreatmentDate,
+			DaysApart:           days,
+		})
+	}
+	return duplicates, nil
+}
+
+// ListNearDuplicateTreatments godoc
+// @Summary      List near-duplicate treatments
+// @Description  Get pairs of treatments of the same patient at most window days apart (default 1, max 30) with identical issues and treatment text, which are likely entered twice, for review
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        window query int false "Most days between the two treatments (default 1)"
+// @Param        limit query int false "Limit number of results"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200 {object} util.APIResponse{data=object{duplicates=[]model.TreatmentNearDuplicate,total=int,total_fetched=int,has_more=bool}} "Near-duplicate treatments retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid window"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /treatment/near-duplicates [get]
+func ListNearDuplicateTreatments(c *gin.Context) {
+	window, err := parseNearDuplicateWindow(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: err.Error(),
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	duplicates, err := findNearDuplicateTreatments(db, window)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve near-duplicate treatments",
+			Err: err,
+		})
+		return
+	}
+
+	total := int64(len(duplicates))
+	limit, offset := parseQueryInt(c, "limit", 0), parseQueryInt(c, "offset", 0)
+	page := []model.TreatmentNearDuplicate{}
+	if offset < len(duplicates) {
+		page = duplicates[offset:]
+		if limit > 0 && limit < len(page) {
+			page = page[:limit]
+		}
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Near-duplicate treatments retrieved",
+		Data: util.NewPageResponse(page, total, len(page), util.OffsetHasMore(offset, len(page), total), nil).Named("duplicates"),
+	})
+}
@@ -0,0 +1,69 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListNearDuplicateTreatments(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/treatment/near-duplicates", ListNearDuplicateTreatments)
+
+	seed := func(code, date, issues, treatment string) uint {
+		tr := model.Treatment{PatientCode: code, TherapistID: 1, TreatmentDate: date, Issues: issues, Treatment: treatment, NextVisit: "-"}
+		assert.NoError(t, db.Create(&tr).Error)
+		return tr.ID
+	}
+	first := seed("ND01", "2025-01-10", "Back pain", "Massage")
+	second := seed("ND01", "2025-01-11", "Back pain", "Massage")
+	_ = seed("ND01", "2025-01-12", "Neck pain", "Massage") // different issues
+	_ = seed("ND02", "2025-01-10", "Back pain", "Massage") // different patient
+	threeDaysLater := seed("ND01", "2025-01-14", "Back pain", "Massage")
+	deleted := seed("ND03", "2025-02-01", "Knee", "Stretching")
+	_ = seed("ND03", "2025-02-02", "Knee", "Stretching")
+	assert.NoError(t, db.Delete(&model.Treatment{}, deleted).Error)
+
+	list := func(query string) (int64, []model.TreatmentNearDuplicate) {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment/near-duplicates" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data struct {
+				Total      int64                          `json:"total"`
+				Duplicates []model.TreatmentNearDuplicate `json:"duplicates"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.Total, resp.Data.Duplicates
+	}
+
+	total, got := list("")
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, []model.TreatmentNearDuplicate{{
+		PatientCode:         "ND01",
+		Issues:              "Back pain",
+		Treatment:           "Massage",
+		FirstTreatmentID:    first,
+		FirstTreatmentDate:  "2025-01-10",
+		SecondTreatmentID:   second,
+		SecondTreatmentDate: "2025-01-11",
+		DaysApart:           1,
+	}}, got)
+
+	total, got = list("?window=3")
+	assert.Equal(t, int64(2), total)
+	if assert.Len(t, got, 2) {
+		assert.Equal(t, second, got[1].FirstTreatmentID)
+		assert.Equal(t, threeDaysLater, got[1].SecondTreatmentID)
+		assert.Equal(t, 3, got[1].DaysApart)
+	}
+
+	for _, window := range []string{"0", "31", "abc"} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment/near-duplicates?window=" + window})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+}
@@ -0,0 +1,68 @@
+package endpoint
+
+import (
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// orphanedTreatmentsQuery selects treatments whose patient_code matches no
+// patient that is not soft-deleted.
+func orphanedTreatmentsQuery(db *gorm.DB) *gorm.DB {
+	return db.Model(&model.Treatment{}).
+		Joins("LEFT JOIN patients ON patients.patient_code = treatments.patient_code AND patients.deleted_at IS NULL").
+		Where("patients.id IS NULL")
+}
+
+// fetchOrphanedTreatments returns one page of orphaned treatments, oldest
+// first, and the total number of orphans.
+func fetchOrphanedTreatments(db *gorm.DB, limit, offset int) ([]model.Treatment, int64, error) {
+	var total int64
+	if err := orphanedTreatmentsQuery(db).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	treatments := []model.Treatment{}
+	query := applyPagination(orphanedTreatmentsQuery(db).Select("treatments.*").Order("treatments.treatment_date ASC, treatments.id ASC"), limit, offset)
+	if err := query.Find(&treatments).Error; err != nil {
+		return nil, 0, err
+	}
+	return treatments, total, nil
+}
+
+// ListOrphanedTreatments godoc
+// @Summary      List orphaned treatments
+// @Description  Get treatments whose patient_code has no matching patient, or only a deleted one, so they can be fixed or deleted
+// @Tags         Treatment
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        limit query int false "Limit number of results"
+// @Param        offset query int false "Offset for pagination"
+// @Success      200 {object} util.APIResponse{data=object} "Orphaned treatments retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /treatment/orphans [get]
+func ListOrphanedTreatments(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	offset := parseQueryInt(c, "offset", 0)
+	treatments, total, err := fetchOrphanedTreatments(db, parseQueryInt(c, "limit", 0), offset)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve orphaned treatments",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Orphaned treatments retrieved",
+		Data: util.NewPageResponse(treatments, total, len(treatments), util.OffsetHasMore(offset, len(treatments), total), nil).Named("treatments"),
+	})
+}
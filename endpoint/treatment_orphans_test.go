@@ -0,0 +1,39 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListOrphanedTreatments(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	valid := createTestTreatment(db, t, "ORPH-OK", 1)
+	deletedPatient := createTestTreatment(db, t, "ORPH-DEL", 1)
+	assert.NoError(t, db.Where("patient_code = ?", "ORPH-DEL").Delete(&model.Patient{}).Error)
+	missing := model.Treatment{PatientCode: "ORPH-NONE", TherapistID: valid.TherapistID, TreatmentDate: "2025-01-01", Issues: "-", Treatment: "-", NextVisit: "-"}
+	assert.NoError(t, db.Create(&missing).Error)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/treatment/orphans", requestPath: "/treatment/orphans", handler: ListOrphanedTreatments})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data struct {
+			Total      int64             `json:"total"`
+			Treatments []model.Treatment `json:"treatments"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, int64(2), resp.Data.Total)
+
+	var ids []uint
+	for _, tr := range resp.Data.Treatments {
+		ids = append(ids, tr.ID)
+	}
+	assert.Equal(t, []uint{missing.ID, deletedPatient.ID}, ids)
+	assert.NotContains(t, ids, valid.ID)
+}
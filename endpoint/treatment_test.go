@@ -1,6 +1,7 @@
 package endpoint
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -8,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ariebrainware/basis-data-ltt/config"
 	"github.com/ariebrainware/basis-data-ltt/middleware"
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
@@ -254,6 +256,94 @@ func TestListTreatments_WithKeyword(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestListTreatments_InvalidPagination(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	r.GET("/treatment", ListTreatments)
+
+	treatments := make([]model.Treatment, maxListLimit+1)
+	for i := range treatments {
+		treatments[i] = model.Treatment{PatientCode: "PAGE001", TherapistID: 1, TreatmentDate: "2025-01-01", Issues: "-", Treatment: "-", NextVisit: "-"}
+	}
+	assert.NoError(t, db.CreateInBatches(&treatments, 50).Error)
+
+	list := func(query string) (int64, int) {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment?" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data struct {
+				Total        int64 `json:"total"`
+				TotalFetched int   `json:"total_fetched"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.Total, resp.Data.TotalFetched
+	}
+
+	total, fetched := list("limit=-5&offset=-1")
+	assert.Equal(t, int64(maxListLimit+1), total)
+	assert.Equal(t, maxListLimit+1, fetched, "negative limit and offset are ignored")
+
+	_, fetched = list("limit=5&offset=-3")
+	assert.Equal(t, 5, fetched)
+
+	_, fetched = list("limit=100000")
+	assert.Equal(t, maxListLimit, fetched, "limit is capped")
+}
+
+func TestListTreatments_ModifiedSince(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	r.GET("/treatment", ListTreatments)
+
+	at := func(day string) time.Time {
+		ts, err := time.Parse("2006-01-02", day)
+		assert.NoError(t, err)
+		return ts
+	}
+	seed := func(code, updatedAt, deletedAt string) uint {
+		treatment := createTestTreatment(db, t, code, 1)
+		cols := map[string]interface{}{"updated_at": at(updatedAt)}
+		if deletedAt != "" {
+			cols["deleted_at"] = at(deletedAt)
+		}
+		assert.NoError(t, db.Unscoped().Model(&model.Treatment{}).Where("id = ?", treatment.ID).UpdateColumns(cols).Error)
+		return treatment.ID
+	}
+	_ = seed("SYNC001", "2025-01-01", "")
+	recent := seed("SYNC002", "2025-03-01", "")
+	deletedRecently := seed("SYNC003", "2025-01-01", "2025-03-02")
+	_ = seed("SYNC004", "2025-01-01", "2025-01-02")
+
+	list := func(query string) (int64, map[uint]bool) {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment?" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data struct {
+				Total      int64                          `json:"total"`
+				Treatments []model.ListTreatementResponse `json:"treatments"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		deleted := map[uint]bool{}
+		for _, tr := range resp.Data.Treatments {
+			deleted[tr.ID] = tr.Deleted
+		}
+		return resp.Data.Total, deleted
+	}
+
+	total, got := list("modified_since=2025-02-01T00:00:00Z")
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, map[uint]bool{recent: false}, got)
+
+	total, got = list("modified_since=2025-02-01T00:00:00Z&include_deleted=true")
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, map[uint]bool{recent: false, deletedRecently: true}, got)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment?modified_since=yesterday"})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+}
+
 func TestListTreatments_WithTherapistFilter(t *testing.T) {
 	r, db := setupTreatmentTest(t)
 
@@ -290,6 +380,88 @@ func TestListTreatments_WithSessionTherapist(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestListTreatments_NextVisitCallList(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+
+	db.Create(&model.Patient{FullName: "Call Me", PatientCode: "CALL001", Email: "call@test.com", PhoneNumber: "081234567890,081298765432"})
+	soon := createTestTreatment(db, t, "CALL001", 1)
+	later := createTestTreatment(db, t, "CALL002", 1)
+	none := createTestTreatment(db, t, "CALL003", 1)
+	db.Model(&soon).Update("next_visit", "2025-03-03")
+	db.Model(&later).Update("next_visit", "2025-03-20")
+	db.Model(&none).Update("next_visit", "")
+
+	w, response, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/treatment", requestPath: "/treatment?next_visit_from=2025-03-01&next_visit_to=2025-03-07", handler: ListTreatments})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["total"])
+	treatments := data["treatments"].([]interface{})
+	if assert.Len(t, treatments, 1) {
+		row := treatments[0].(map[string]interface{})
+		assert.Equal(t, "CALL001", row["patient_code"])
+		assert.Equal(t, "2025-03-03", row["next_visit"])
+		assert.Equal(t, "081234567890,081298765432", row["phone_number"])
+	}
+
+	// An open-ended window still skips treatments without a next visit.
+	w, response, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment?next_visit_to=2025-12-31"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Equal(t, float64(2), response["data"].(map[string]interface{})["total"])
+}
+
+func TestListTreatments_InvalidNextVisitWindow(t *testing.T) {
+	r, _ := setupTreatmentTest(t)
+	r.GET("/treatment", ListTreatments)
+
+	for _, query := range []string{"next_visit_from=03-01-2025", "next_visit_from=2025-03-07&next_visit_to=2025-03-01"} {
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment?" + query})
+		assertStatusWithError(t, w, http.StatusBadRequest, err)
+	}
+}
+
+func TestListTreatments_CreatedBy(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	r.Use(withAuthContext(7, model.RoleAdmin))
+	r.POST("/treatment", CreateTreatment)
+	r.GET("/treatment", ListTreatments)
+
+	therapist := model.Therapist{FullName: "Therapist Creator", Email: "creator@test.com"}
+	assert.NoError(t, db.Create(&therapist).Error)
+	assert.NoError(t, db.Create(&model.Pricing{TherapistID: therapist.ID, Price: 100000}).Error)
+	_ = createPatientIfNotExists(db, t, "BY001", "by001@test.com")
+
+	body := buildTreatmentRequest(TreatmentRequestOpts{PatientCode: "BY001", TherapistID: therapist.ID})
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment", body: body})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var created model.Treatment
+	assert.NoError(t, db.Where("patient_code = ?", "BY001").First(&created).Error)
+	if assert.NotNil(t, created.CreatedByUserID) {
+		assert.Equal(t, uint(7), *created.CreatedByUserID)
+	}
+
+	other := createTestTreatment(db, t, "BY002", therapist.ID)
+	otherUser := uint(8)
+	db.Model(&other).Update("created_by_user_id", otherUser)
+	createTestTreatment(db, t, "BY003", therapist.ID)
+
+	w, response, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment?created_by=7"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, float64(1), data["total"])
+	treatments := data["treatments"].([]interface{})
+	if assert.Len(t, treatments, 1) {
+		row := treatments[0].(map[string]interface{})
+		assert.Equal(t, "BY001", row["patient_code"])
+		assert.Equal(t, float64(7), row["created_by_user_id"])
+	}
+
+	w, response, err = performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/treatment"})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Equal(t, float64(3), response["data"].(map[string]interface{})["total"])
+}
+
 func TestCreateTreatment_Success(t *testing.T) {
 	r, db := setupTreatmentTest(t)
 
@@ -316,6 +488,43 @@ func TestCreateTreatment_Success(t *testing.T) {
 	assert.NoError(t, db.Where("treatment_id = ?", createdTreatment.ID).First(&transaction).Error)
 	assert.Equal(t, therapist.ID, transaction.TherapistID)
 	assert.Equal(t, int64(250000), transaction.Amount)
+	assert.Equal(t, int64(250000), createdTreatment.Cost, "cost defaults to the therapist's price")
+}
+
+func TestCreateTreatment_Cost(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	r.POST("/treatment", CreateTreatment)
+	r.PATCH("/treatment/:id", UpdateTreatment)
+
+	therapist := model.Therapist{FullName: "Therapist Cost", Email: "cost@test.com"}
+	assert.NoError(t, db.Create(&therapist).Error)
+	assert.NoError(t, db.Create(&model.Pricing{TherapistID: therapist.ID, Price: 250000}).Error)
+	_ = createPatientIfNotExists(db, t, "COST001", "cost-patient@test.com")
+
+	reqBody := buildTreatmentRequest(TreatmentRequestOpts{PatientCode: "COST001", TherapistID: therapist.ID})
+	reqBody["cost"] = -1
+	w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment", body: reqBody})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+
+	reqBody["cost"] = 175000
+	w, response, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment", body: reqBody})
+	assert.NoError(t, err)
+	assertTreatmentSuccessResponse(t, w, response)
+
+	var created model.Treatment
+	assert.NoError(t, db.Where("patient_code = ?", "COST001").First(&created).Error)
+	assert.Equal(t, int64(175000), created.Cost)
+	var transaction model.Transaction
+	assert.NoError(t, db.Where("treatment_id = ?", created.ID).First(&transaction).Error)
+	assert.Equal(t, int64(175000), transaction.Amount)
+
+	path := fmt.Sprintf("/treatment/%d", created.ID)
+	w, response, err = performRequest(r, requestSpec{method: http.MethodPatch, requestPath: path, body: map[string]interface{}{"cost": 200000}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+	assert.Equal(t, float64(200000), response["data"].(map[string]interface{})["cost"])
+
+	w, _, err = performRequest(r, requestSpec{method: http.MethodPatch, requestPath: path, body: map[string]interface{}{"cost": -5}})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
 }
 
 func TestCreateTreatment_InvalidJSON(t *testing.T) {
@@ -362,6 +571,17 @@ func TestCreateTreatment_DuplicateEntry(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCheckDuplicateTreatment_LookupError(t *testing.T) {
+	_, db := setupTreatmentTest(t)
+	assert.NoError(t, db.Migrator().DropTable(&model.Treatment{}))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	assert.False(t, checkDuplicateTreatment(c, db, time.Now().Format("2006-01-02"), "DUP001"))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
 func TestCreateTreatment_PatientNotFound(t *testing.T) {
 	r, db := setupTreatmentTest(t)
 	_ = db
@@ -376,6 +596,163 @@ func TestCreateTreatment_PatientNotFound(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCreateTreatment_PatientCodeCase(t *testing.T) {
+	for _, strict := range []bool{false, true} {
+		t.Run(fmt.Sprintf("strict=%v", strict), func(t *testing.T) {
+			t.Setenv("PATIENT_CODE_STRICT", fmt.Sprintf("%v", strict))
+			r, db := setupTreatmentTest(t)
+			r.POST("/treatment", CreateTreatment)
+
+			therapist := model.Therapist{FullName: "Therapist Case", Email: "case@test.com"}
+			assert.NoError(t, db.Create(&therapist).Error)
+			assert.NoError(t, db.Create(&model.Pricing{TherapistID: therapist.ID, Price: 100000}).Error)
+			_ = createPatientIfNotExists(db, t, "CASE001", "case-upper@test.com")
+			// Stored before codes were normalized.
+			_ = createPatientIfNotExists(db, t, "case002", "case-lower@test.com")
+
+			create := func(code string) *httptest.ResponseRecorder {
+				t.Helper()
+				reqBody := buildTreatmentRequest(TreatmentRequestOpts{PatientCode: code, TherapistID: therapist.ID})
+				w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment", body: reqBody})
+				assert.NoError(t, err)
+				return w
+			}
+
+			// Lower-case input normalizes to the stored upper-case code in both modes.
+			assert.Equal(t, http.StatusOK, create(" case001 ").Code)
+			var created model.Treatment
+			assert.NoError(t, db.Where("patient_code = ?", "CASE001").First(&created).Error)
+
+			w := create("case002")
+			if strict {
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Contains(t, w.Body.String(), `"stored_patient_code":"case002"`)
+				var count int64
+				db.Model(&model.Treatment{}).Where("UPPER(patient_code) = ?", "CASE002").Count(&count)
+				assert.Zero(t, count)
+				return
+			}
+			assert.Equal(t, http.StatusOK, w.Code)
+			var lenient model.Treatment
+			assert.NoError(t, db.Where("patient_code = ?", "case002").First(&lenient).Error,
+				"the treatment keeps the stored code so joins match")
+		})
+	}
+}
+
+// clinicDate returns the clinic-timezone date days from today as YYYY-MM-DD.
+func clinicDate(t *testing.T, days int) string {
+	t.Helper()
+	loc, err := time.LoadLocation(config.Timezone)
+	assert.NoError(t, err)
+	return time.Now().In(loc).AddDate(0, 0, days).Format("2006-01-02")
+}
+
+func TestCreateTreatment_FutureTreatmentDate(t *testing.T) {
+	tests := []struct {
+		name        string
+		days        int
+		status      string
+		allowFuture bool
+		wantStatus  int
+	}{
+		{"today", 0, "", false, http.StatusOK},
+		{"past", -3, "", false, http.StatusOK},
+		{"future rejected by default", 1, "", false, http.StatusBadRequest},
+		{"future allowed when enabled", 1, "", true, http.StatusOK},
+		{"future scheduled treatment", 1, model.TreatmentStatusScheduled, false, http.StatusOK},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ALLOW_FUTURE_TREATMENT_DATE", fmt.Sprintf("%v", tt.allowFuture))
+			r, db := setupTreatmentTest(t)
+			r.POST("/treatment", CreateTreatment)
+
+			therapist := model.Therapist{FullName: "Therapist Future", Email: fmt.Sprintf("future%d@test.com", i)}
+			assert.NoError(t, db.Create(&therapist).Error)
+			assert.NoError(t, db.Create(&model.Pricing{TherapistID: therapist.ID, Price: 100000}).Error)
+			code := fmt.Sprintf("FUT%03d", i)
+			_ = createPatientIfNotExists(db, t, code, fmt.Sprintf("future-patient%d@test.com", i))
+
+			reqBody := buildTreatmentRequest(TreatmentRequestOpts{PatientCode: code, TherapistID: therapist.ID, TreatmentDate: clinicDate(t, tt.days)})
+			if tt.status != "" {
+				reqBody["status"] = tt.status
+			}
+			w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment", body: reqBody})
+			assertStatusWithError(t, w, tt.wantStatus, err)
+
+			var count int64
+			db.Model(&model.Treatment{}).Where("patient_code = ?", code).Count(&count)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, int64(1), count)
+			} else {
+				assert.Contains(t, w.Body.String(), futureTreatmentDateMsg)
+				assert.Zero(t, count)
+			}
+		})
+	}
+}
+
+func TestUpdateTreatment_FutureTreatmentDate(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	r.PATCH("/treatment/:id", UpdateTreatment)
+
+	treatment := createTestTreatment(db, t, "FUTUPD1", 1)
+	patch := func(date string) int {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPatch, requestPath: fmt.Sprintf("/treatment/%d", treatment.ID), body: map[string]interface{}{"treatment_date": date}})
+		assert.NoError(t, err)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, patch(clinicDate(t, -1)))
+	assert.Equal(t, http.StatusOK, patch(clinicDate(t, 0)))
+	assert.Equal(t, http.StatusBadRequest, patch(clinicDate(t, 2)))
+	var stored model.Treatment
+	assert.NoError(t, db.First(&stored, treatment.ID).Error)
+	assert.Equal(t, clinicDate(t, 0), stored.TreatmentDate)
+
+	t.Setenv("ALLOW_FUTURE_TREATMENT_DATE", "true")
+	assert.Equal(t, http.StatusOK, patch(clinicDate(t, 2)))
+}
+
+func TestCreateTreatment_RequirePatientUser(t *testing.T) {
+	tests := []struct {
+		name       string
+		flag       string
+		linkUser   bool
+		wantStatus int
+	}{
+		{name: "flag off allows unlinked patient", flag: "", linkUser: false, wantStatus: http.StatusOK},
+		{name: "flag on rejects unlinked patient", flag: "true", linkUser: false, wantStatus: http.StatusBadRequest},
+		{name: "flag on allows linked patient", flag: "true", linkUser: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("REQUIRE_PATIENT_USER_FOR_TREATMENT", tt.flag)
+			r, db := setupTreatmentTest(t)
+
+			therapist := model.Therapist{FullName: "Therapist Link", Email: "link-therapist@test.com"}
+			assert.NoError(t, db.Create(&therapist).Error)
+			assert.NoError(t, db.Create(&model.Pricing{TherapistID: therapist.ID, Price: 100000}).Error)
+			_ = createPatientIfNotExists(db, t, "LINK001", "link@test.com")
+			if tt.linkUser {
+				assert.NoError(t, db.Create(&model.User{Name: "Linked", Email: "Link@Test.com", Password: "x", RoleID: model.RoleUser}).Error)
+			}
+
+			reqBody := buildTreatmentRequest(TreatmentRequestOpts{PatientCode: "LINK001", TherapistID: therapist.ID})
+			w, response, err := doRequestWithHandler(r, requestSpec{method: http.MethodPost, registerPath: "/treatment", requestPath: "/treatment", handler: CreateTreatment, body: reqBody})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusBadRequest {
+				assert.Contains(t, response["msg"], "linked user account")
+			}
+		})
+	}
+}
+
 func TestUpdateTreatment_Success(t *testing.T) {
 	r, db := setupTreatmentTest(t)
 
@@ -395,12 +772,120 @@ func TestUpdateTreatment_Success(t *testing.T) {
 	assert.Equal(t, "Updated remarks", updated.Remarks)
 }
 
+func TestUpdateTreatment_StatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to   string
+		wantStatus int
+	}{
+		{model.TreatmentStatusScheduled, model.TreatmentStatusCompleted, http.StatusOK},
+		{model.TreatmentStatusScheduled, model.TreatmentStatusCancelled, http.StatusOK},
+		{model.TreatmentStatusScheduled, model.TreatmentStatusNoShow, http.StatusOK},
+		{model.TreatmentStatusCompleted, model.TreatmentStatusCompleted, http.StatusOK},
+		{model.TreatmentStatusCompleted, model.TreatmentStatusScheduled, http.StatusBadRequest},
+		{model.TreatmentStatusCompleted, model.TreatmentStatusNoShow, http.StatusBadRequest},
+		{model.TreatmentStatusCompleted, model.TreatmentStatusCancelled, http.StatusBadRequest},
+		{model.TreatmentStatusCancelled, model.TreatmentStatusScheduled, http.StatusBadRequest},
+		{model.TreatmentStatusCancelled, model.TreatmentStatusCompleted, http.StatusBadRequest},
+		{model.TreatmentStatusNoShow, model.TreatmentStatusCompleted, http.StatusBadRequest},
+		{model.TreatmentStatusNoShow, model.TreatmentStatusCancelled, http.StatusBadRequest},
+		{model.TreatmentStatusScheduled, "missed", http.StatusBadRequest},
+	}
+
+	r, db := setupTreatmentTest(t)
+	r.PATCH("/treatment/:id", UpdateTreatment)
+
+	for i, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			treatment := createTestTreatment(db, t, fmt.Sprintf("STS%03d", i), 1)
+			assert.NoError(t, db.Model(&treatment).Update("status", tt.from).Error)
+
+			w, _, err := performRequest(r, requestSpec{method: http.MethodPatch, requestPath: fmt.Sprintf("/treatment/%d", treatment.ID), body: map[string]interface{}{"status": tt.to}})
+			assertStatusWithError(t, w, tt.wantStatus, err)
+
+			var updated model.Treatment
+			assert.NoError(t, db.First(&updated, treatment.ID).Error)
+			if tt.wantStatus == http.StatusOK {
+				assert.Equal(t, tt.to, updated.Status)
+			} else {
+				assert.Equal(t, tt.from, updated.Status)
+			}
+		})
+	}
+}
+
+func TestCreateTreatment_ShortIntervalWarning(t *testing.T) {
+	r, db := setupTreatmentTest(t)
+	r.POST("/treatment", CreateTreatment)
+
+	therapist := model.Therapist{FullName: "Therapist Warn", Email: "warn@test.com"}
+	assert.NoError(t, db.Create(&therapist).Error)
+	assert.NoError(t, db.Create(&model.Pricing{TherapistID: therapist.ID, Price: 100000}).Error)
+	_ = createPatientIfNotExists(db, t, "WARN001", "warn-patient@test.com")
+	assert.NoError(t, db.Create(&model.Treatment{TreatmentDate: "2025-01-14", PatientCode: "WARN001", TherapistID: therapist.ID, Issues: "-", Treatment: "-", NextVisit: "-"}).Error)
+	assert.NoError(t, db.Create(&model.Treatment{TreatmentDate: "2025-01-15", PatientCode: "WARN001", TherapistID: therapist.ID, Issues: "-", Treatment: "-", NextVisit: "-", Status: model.TreatmentStatusNoShow}).Error)
+
+	create := func(date string) model.CreateTreatmentResult {
+		t.Helper()
+		reqBody := buildTreatmentRequest(TreatmentRequestOpts{PatientCode: "WARN001", TherapistID: therapist.ID, TreatmentDate: date})
+		w, _, err := performRequest(r, requestSpec{method: http.MethodPost, requestPath: "/treatment", body: reqBody})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		var resp struct {
+			Data model.CreateTreatmentResult `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	got := create("2025-01-16")
+	assert.NotZero(t, got.TreatmentID)
+	assert.Equal(t, []string{"Only 2 day(s) since the patient's last visit on 2025-01-14"}, got.Warnings)
+	var created model.Treatment
+	assert.NoError(t, db.First(&created, got.TreatmentID).Error, "the treatment is still created")
+	assert.Equal(t, "2025-01-16", created.TreatmentDate)
+
+	got = create("2025-01-25")
+	assert.Empty(t, got.Warnings)
+
+	t.Setenv("TREATMENT_MIN_INTERVAL_DAYS", "0")
+	got = create("2025-01-26")
+	assert.Empty(t, got.Warnings)
+}
+
+func TestCreateTreatment_DefaultStatus(t *testing.T) {
+	for _, tt := range []struct {
+		schedule string
+		want     string
+	}{
+		{"", model.TreatmentStatusCompleted},
+		{"true", model.TreatmentStatusScheduled},
+	} {
+		t.Run("SCHEDULE_NEW_TREATMENTS="+tt.schedule, func(t *testing.T) {
+			t.Setenv("SCHEDULE_NEW_TREATMENTS", tt.schedule)
+			r, db := setupTreatmentTest(t)
+
+			therapist := model.Therapist{FullName: "Therapist Status", Email: "status@test.com"}
+			assert.NoError(t, db.Create(&therapist).Error)
+			assert.NoError(t, db.Create(&model.Pricing{TherapistID: therapist.ID, Price: 100000}).Error)
+			_ = createPatientIfNotExists(db, t, "DEF001", "def@test.com")
+
+			reqBody := buildTreatmentRequest(TreatmentRequestOpts{PatientCode: "DEF001", TherapistID: therapist.ID})
+			w, response, err := doRequestWithHandler(r, requestSpec{method: http.MethodPost, registerPath: "/treatment", requestPath: "/treatment", handler: CreateTreatment, body: reqBody})
+			assert.NoError(t, err)
+			assertTreatmentSuccessResponse(t, w, response)
+
+			var created model.Treatment
+			assert.NoError(t, db.Where("patient_code = ?", "DEF001").First(&created).Error)
+			assert.Equal(t, tt.want, created.Status)
+		})
+	}
+}
+
 func TestUpdateTreatment_NotFound(t *testing.T) {
 	r, db := setupTreatmentTest(t)
 	_ = db
 
 	reqBody := map[string]interface{}{
-		"notes": "Updated",
+		"remarks": "Updated",
 	}
 	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPatch, registerPath: "/treatment/:id", requestPath: "/treatment/99999", handler: UpdateTreatment, body: reqBody})
 
@@ -412,7 +897,7 @@ func TestUpdateTreatment_InvalidID(t *testing.T) {
 	_ = db
 
 	reqBody := map[string]interface{}{
-		"notes": "Updated",
+		"remarks": "Updated",
 	}
 	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodPatch, registerPath: "/treatment/:id", requestPath: "/treatment/invalid", handler: UpdateTreatment, body: reqBody})
 
@@ -0,0 +1,118 @@
+package endpoint
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// weekOverWeekChange returns the percentage change from previous to current
+// rounded to two decimals, or nil when there is no prior week to compare with.
+func weekOverWeekChange(previous, current int64) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	change := math.Round(float64(current-previous)/float64(previous)*10000) / 100
+	return &change
+}
+
+// computeTreatmentTrends totals treatments per ISO week for every week that
+// overlaps [start, end]. The week before the first one is counted too so the
+// first week also has a change percentage.
+func computeTreatmentTrends(db *gorm.DB, start, end time.Time) (model.TreatmentTrendsReport, error) {
+	first := isoWeekStart(start)
+	last := isoWeekStart(end).AddDate(0, 0, 6)
+	report := model.TreatmentTrendsReport{
+		StartDate: first.Format(cadenceDateLayout),
+		EndDate:   last.Format(cadenceDateLayout),
+		Weeks:     []model.TreatmentWeeklyTrend{},
+	}
+
+	var rows []struct {
+		TreatmentDate string
+		Total         int64
+	}
+	err := db.Model(&model.Treatment{}).
+		Select("treatment_date, COUNT(*) AS total").
+		Where("treatment_date BETWEEN ? AND ?", first.AddDate(0, 0, -7).Format(cadenceDateLayout), report.EndDate).
+		Group("treatment_date").
+		Scan(&rows).Error
+	if err != nil {
+		return report, err
+	}
+
+	totals := make(map[string]int64)
+	for _, row := range rows {
+		t, err := time.Parse(cadenceDateLayout, row.TreatmentDate)
+		if err != nil {
+			continue
+		}
+		totals[isoWeekStart(t).Format(cadenceDateLayout)] += row.Total
+	}
+
+	previous := totals[first.AddDate(0, 0, -7).Format(cadenceDateLayout)]
+	for w := first; !w.After(last); w = w.AddDate(0, 0, 7) {
+		year, week := w.ISOWeek()
+		total := totals[w.Format(cadenceDateLayout)]
+		report.Weeks = append(report.Weeks, model.TreatmentWeeklyTrend{
+			Week:          fmt.Sprintf("%d-W%02d", year, week),
+			WeekStart:     w.Format(cadenceDateLayout),
+			Total:         total,
+			PreviousTotal: previous,
+			ChangePercent: weekOverWeekChange(previous, total),
+		})
+		report.Total += total
+		previous = total
+	}
+	return report, nil
+}
+
+// GetTreatmentTrends godoc
+// @Summary      Weekly treatment trends
+// @Description  Treatment totals per ISO week over a date range with the percentage change from the previous week. The range is widened to whole weeks; change_percent is null when the previous week had no treatments. Defaults to the last 12 weeks.
+// @Tags         Report
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        start_date query string false "Start date (YYYY-MM-DD)"
+// @Param        end_date query string false "End date (YYYY-MM-DD), defaults to today"
+// @Success      200 {object} util.APIResponse{data=model.TreatmentTrendsReport} "Report generated"
+// @Failure      400 {object} util.APIResponse "Invalid date range"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /report/treatment-trends [get]
+func GetTreatmentTrends(c *gin.Context) {
+	start, end, err := parseCadenceRange(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Invalid date range",
+			Err: err,
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	report, err := computeTreatmentTrends(db, start, end)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to generate report",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Report generated",
+		Data: report,
+	})
+}
@@ -0,0 +1,83 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTreatmentTrends_WeeklyTotalsAndChange(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	dates := []string{
+		"2024-12-30", "2025-01-05", // W01, the week before the range
+		"2025-01-06", "2025-01-08", "2025-01-08", "2025-01-12", // W02
+		// W03 has no treatments
+		"2025-01-20", "2025-01-21", // W04
+		"2025-01-27", "2025-01-28", "2025-01-29", // W05
+		"2025-02-03", // after the range
+	}
+	for _, d := range dates {
+		assert.NoError(t, db.Create(&model.Treatment{TreatmentDate: d, PatientCode: "T001", TherapistID: 1, Issues: "-", Treatment: "-", NextVisit: "-"}).Error)
+	}
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/treatment-trends", requestPath: "/report/treatment-trends?start_date=2025-01-08&end_date=2025-01-29", handler: GetTreatmentTrends})
+	assert.NoError(t, err)
+	if !assert.Equal(t, http.StatusOK, w.Code, w.Body.String()) {
+		return
+	}
+
+	var resp struct {
+		Data model.TreatmentTrendsReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	report := resp.Data
+
+	assert.Equal(t, "2025-01-06", report.StartDate)
+	assert.Equal(t, "2025-02-02", report.EndDate)
+	assert.Equal(t, int64(9), report.Total)
+
+	pct := func(v float64) *float64 { return &v }
+	assert.Equal(t, []model.TreatmentWeeklyTrend{
+		{Week: "2025-W02", WeekStart: "2025-01-06", Total: 4, PreviousTotal: 2, ChangePercent: pct(100)},
+		{Week: "2025-W03", WeekStart: "2025-01-13", Total: 0, PreviousTotal: 4, ChangePercent: pct(-100)},
+		{Week: "2025-W04", WeekStart: "2025-01-20", Total: 2, PreviousTotal: 0, ChangePercent: nil},
+		{Week: "2025-W05", WeekStart: "2025-01-27", Total: 3, PreviousTotal: 2, ChangePercent: pct(50)},
+	}, report.Weeks)
+}
+
+func TestGetTreatmentTrends_NoPriorData(t *testing.T) {
+	r, db := setupEndpointTest(t)
+
+	assert.NoError(t, db.Create(&model.Treatment{TreatmentDate: "2025-01-07", PatientCode: "T001", TherapistID: 1, Issues: "-", Treatment: "-", NextVisit: "-"}).Error)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/treatment-trends", requestPath: "/report/treatment-trends?start_date=2025-01-06&end_date=2025-01-19", handler: GetTreatmentTrends})
+	assert.NoError(t, err)
+	if !assert.Equal(t, http.StatusOK, w.Code, w.Body.String()) {
+		return
+	}
+
+	var resp struct {
+		Data model.TreatmentTrendsReport `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	if assert.Len(t, resp.Data.Weeks, 2) {
+		assert.Nil(t, resp.Data.Weeks[0].ChangePercent, "no treatments the week before")
+		assert.Equal(t, int64(1), resp.Data.Weeks[0].Total)
+		if assert.NotNil(t, resp.Data.Weeks[1].ChangePercent) {
+			assert.Equal(t, -100.0, *resp.Data.Weeks[1].ChangePercent)
+		}
+	}
+	assert.Contains(t, w.Body.String(), `"change_percent":null`)
+}
+
+func TestGetTreatmentTrends_InvalidRange(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/report/treatment-trends", requestPath: "/report/treatment-trends?start_date=2025-02-01&end_date=2025-01-01", handler: GetTreatmentTrends})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,82 @@
+package endpoint
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"gorm.io/gorm"
+)
+
+// defaultMinVisitIntervalDays is the visit interval below which a new
+// treatment is flagged when TREATMENT_MIN_INTERVAL_DAYS is not set.
+const defaultMinVisitIntervalDays = 3
+
+// treatmentWarningCheck inspects a created treatment and returns a warning,
+// or "" when there is nothing to report. Warnings never block creation.
+type treatmentWarningCheck func(db *gorm.DB, treatment model.Treatment) (string, error)
+
+// treatmentWarningChecks run, in order, after every created treatment.
+var treatmentWarningChecks = []treatmentWarningCheck{
+	shortVisitIntervalWarning,
+}
+
+// minVisitIntervalDays returns TREATMENT_MIN_INTERVAL_DAYS, defaulting to
+// defaultMinVisitIntervalDays. Zero disables the check.
+func minVisitIntervalDays() int {
+	if days, err := strconv.Atoi(os.Getenv("TREATMENT_MIN_INTERVAL_DAYS")); err == nil && days >= 0 {
+		return days
+	}
+	return defaultMinVisitIntervalDays
+}
+
+// shortVisitIntervalWarning warns when the patient's previous attended visit
+// is fewer than minVisitIntervalDays days before the treatment.
+func shortVisitIntervalWarning(db *gorm.DB, treatment model.Treatment) (string, error) {
+	minDays := minVisitIntervalDays()
+	if minDays == 0 {
+		return "", nil
+	}
+	date, err := time.Parse(cadenceDateLayout, treatment.TreatmentDate)
+	if err != nil {
+		return "", nil
+	}
+
+	var previous model.Treatment
+	err = db.Where("patient_code = ? AND id <> ? AND treatment_date <= ? AND treatment_date > ?",
+		treatment.PatientCode, treatment.ID, treatment.TreatmentDate, date.AddDate(0, 0, -minDays).Format(cadenceDateLayout)).
+		Scopes(attendedStatusScope).
+		Order("treatment_date DESC").
+		Limit(1).
+		Find(&previous).Error
+	if err != nil || previous.ID == 0 {
+		return "", err
+	}
+
+	last, err := time.Parse(cadenceDateLayout, previous.TreatmentDate)
+	if err != nil {
+		return "", nil
+	}
+	days := int(date.Sub(last).Hours() / 24)
+	return fmt.Sprintf("Only %d day(s) since the patient's last visit on %s", days, previous.TreatmentDate), nil
+}
+
+// treatmentWarnings runs every warning check against a created treatment.
+// A failing check is logged and skipped so it cannot fail the request.
+func treatmentWarnings(db *gorm.DB, treatment model.Treatment) []string {
+	warnings := []string{}
+	for _, check := range treatmentWarningChecks {
+		warning, err := check(db, treatment)
+		if err != nil {
+			config.Logger().Warn("Treatment warning check failed", "treatment_id", treatment.ID, "error", err)
+			continue
+		}
+		if warning != "" {
+			warnings = append(warnings, warning)
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,120 @@
+package endpoint
+
+import (
+	"fmt"
+
+	"github.com/ariebrainware/basis-data-ltt/middleware"
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// listTherapistPatients returns the distinct patients with completed
+// treatments by the therapist, most recently seen first. NextVisit is the
+// next_visit recorded on the last treatment.
+func listTherapistPatients(db *gorm.DB, therapistID uint) ([]model.TherapistPatient, error) {
+	var visits []struct {
+		PatientCode string
+		LastVisit   string
+		VisitCount  int64
+	}
+	err := db.Model(&model.Treatment{}).
+		Select("patient_code, MAX(treatment_date) AS last_visit, COUNT(*) AS visit_count").
+		Where("therapist_id = ?", therapistID).
+		Scopes(attendedStatusScope).
+		Group("patient_code").
+		Order("last_visit DESC, patient_code ASC").
+		Scan(&visits).Error
+	if err != nil {
+		return nil, err
+	}
+
+	patients := make([]model.TherapistPatient, 0, len(visits))
+	for _, v := range visits {
+		entry := model.TherapistPatient{PatientCode: v.PatientCode, LastVisit: v.LastVisit, VisitCount: v.VisitCount}
+
+		var patient model.Patient
+		if err := db.Select("full_name", "phone_number").Where("patient_code = ?", v.PatientCode).Limit(1).Find(&patient).Error; err != nil {
+			return nil, err
+		}
+		entry.FullName = patient.FullName
+		entry.PhoneNumber = patient.PhoneNumber
+
+		var last model.Treatment
+		err := db.Select("next_visit").
+			Where("therapist_id = ? AND patient_code = ? AND treatment_date = ?", therapistID, v.PatientCode, v.LastVisit).
+			Scopes(attendedStatusScope).
+			Order("id DESC").Limit(1).Find(&last).Error
+		if err != nil {
+			return nil, err
+		}
+		entry.NextVisit = last.NextVisit
+
+		patients = append(patients, entry)
+	}
+	return patients, nil
+}
+
+// GetMyPatients godoc
+// @Summary      List the current therapist's patients
+// @Description  For a therapist session, list the distinct patients the therapist has completed treatments for, most recently seen first, with the last visit date, its next_visit and the number of visits. Other roles get 400; admins should use GET /patient and GET /treatment?therapist_id= instead.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Success      200 {object} util.APIResponse{data=[]model.TherapistPatient} "Patients retrieved"
+// @Failure      400 {object} util.APIResponse "Not a therapist or session error"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /user/me/patients [get]
+func GetMyPatients(c *gin.Context) {
+	roleID, ok := middleware.GetRoleID(c)
+	if !ok {
+		util.CallUserNotAuthorized(c, util.APIErrorParams{
+			Msg: "Role information not available",
+			Err: fmt.Errorf("role id not found in context"),
+		})
+		return
+	}
+	if roleID == model.RoleAdmin {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Only therapists have assigned patients; use GET /patient or GET /treatment?therapist_id= instead",
+			Err: fmt.Errorf("role %d is not a therapist", roleID),
+		})
+		return
+	}
+	if roleID != model.RoleTherapist {
+		util.CallUserError(c, util.APIErrorParams{
+			Msg: "Only therapists have assigned patients",
+			Err: fmt.Errorf("role %d is not a therapist", roleID),
+		})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	therapistID, err := getTherapistIDFromSession(db, c.GetHeader("session-token"))
+	if err != nil {
+		handleSessionError(c, err)
+		return
+	}
+
+	patients, err := listTherapistPatients(db, therapistID)
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{
+			Msg: "Failed to retrieve patients",
+			Err: err,
+		})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Patients retrieved",
+		Data: patients,
+	})
+}
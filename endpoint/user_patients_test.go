@@ -0,0 +1,52 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMyPatients_Therapist(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	user, therapist, session := createUserWithSession(db, t, CreateUserSessionOpts{RoleID: model.RoleTherapist, Email: "my-patients@example.com", Token: "my-patients-token", CreateTherapist: true})
+	r.GET("/user/me/patients", withAuthContext(user.ID, model.RoleTherapist), GetMyPatients)
+
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Alice", PatientCode: "M001", PhoneNumber: "0811"}).Error)
+	assert.NoError(t, db.Create(&model.Patient{FullName: "Bob", PatientCode: "M002", PhoneNumber: "0812"}).Error)
+	treatments := []model.Treatment{
+		{TreatmentDate: "2025-01-10", PatientCode: "M001", TherapistID: therapist.ID, NextVisit: "2025-01-17", Status: model.TreatmentStatusCompleted},
+		{TreatmentDate: "2025-01-17", PatientCode: "M001", TherapistID: therapist.ID, NextVisit: "2025-01-24", Status: model.TreatmentStatusCompleted},
+		{TreatmentDate: "2025-01-24", PatientCode: "M001", TherapistID: therapist.ID, NextVisit: "-", Status: model.TreatmentStatusNoShow},
+		{TreatmentDate: "2025-01-20", PatientCode: "M002", TherapistID: therapist.ID, NextVisit: "2025-02-03", Status: model.TreatmentStatusCompleted},
+		// Another therapist's patient is not listed.
+		{TreatmentDate: "2025-01-21", PatientCode: "M003", TherapistID: therapist.ID + 100, NextVisit: "-", Status: model.TreatmentStatusCompleted},
+	}
+	for i := range treatments {
+		treatments[i].Issues, treatments[i].Treatment = "-", "-"
+		assert.NoError(t, db.Create(&treatments[i]).Error)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/user/me/patients", headers: map[string]string{"session-token": session.SessionToken}})
+	assertStatusWithError(t, w, http.StatusOK, err)
+
+	var resp struct {
+		Data []model.TherapistPatient `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, []model.TherapistPatient{
+		{PatientCode: "M002", FullName: "Bob", PhoneNumber: "0812", LastVisit: "2025-01-20", NextVisit: "2025-02-03", VisitCount: 1},
+		{PatientCode: "M001", FullName: "Alice", PhoneNumber: "0811", LastVisit: "2025-01-17", NextVisit: "2025-01-24", VisitCount: 2},
+	}, resp.Data)
+}
+
+func TestGetMyPatients_AdminRejected(t *testing.T) {
+	r, _ := setupEndpointTest(t)
+	r.GET("/user/me/patients", withAuthContext(1, model.RoleAdmin), GetMyPatients)
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/user/me/patients"})
+	assertStatusWithError(t, w, http.StatusBadRequest, err)
+	assert.Contains(t, w.Body.String(), "GET /patient")
+}
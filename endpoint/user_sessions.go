@@ -0,0 +1,239 @@
+package endpoint
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// countActiveSessions counts the user's sessions that expire after now and
+// returns the metadata of the most recently created one.
+func countActiveSessions(db *gorm.DB, userID uint, now time.Time) (model.UserSessionCount, error) {
+	result := model.UserSessionCount{UserID: userID}
+	err := db.Model(&model.Session{}).Where("user_id = ? AND expires_at > ?", userID, now).Count(&result.ActiveSessions).Error
+	if err != nil {
+		return result, err
+	}
+	if result.ActiveSessions == 0 {
+		return result, nil
+	}
+
+	var newest model.Session
+	err = db.Where("user_id = ? AND expires_at > ?", userID, now).Order("created_at DESC, id DESC").First(&newest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.Newest = &model.SessionInfo{
+		CreatedAt: newest.CreatedAt,
+		ExpiresAt: newest.ExpiresAt,
+		ClientIP:  newest.ClientIP,
+		Browser:   newest.Browser,
+	}
+	return result, nil
+}
+
+// CountUserSessions godoc
+// @Summary      Count a user's active sessions (admin only)
+// @Description  Return the number of unexpired sessions of a user and the client IP, browser and times of the newest one. Session tokens are never returned. Admin-only access.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path int true "User ID"
+// @Success      200 {object} util.APIResponse{data=model.UserSessionCount} "Session count retrieved"
+// @Failure      400 {object} util.APIResponse "Invalid user id"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "User not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /user/{id}/sessions/count [get]
+func CountUserSessions(c *gin.Context) {
+	uid, err := parseIDParam(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{Msg: err.Error(), Err: err})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	if _, ok := fetchUserByID(c, db, uid); !ok {
+		return
+	}
+
+	count, err := countActiveSessions(db, uid, time.Now())
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to count sessions", Err: err})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{Msg: "Session count retrieved", Data: count})
+}
+
+// ipLocationLookup resolves a client IP through GeoIP. Tests replace it to
+// avoid depending on a GeoIP database.
+var ipLocationLookup = util.GetIPLocation
+
+// sessionListFilter narrows the admin sessions list. Empty fields match all
+// sessions.
+type sessionListFilter struct {
+	ClientIP string
+	Country  string
+	Limit    int
+	Offset   int
+}
+
+// sessionCountry resolves the country of a client IP, first through the GeoIP
+// database and then from the newest security log recorded for that IP.
+// Security log locations are stored as "City/Country" or "Country".
+func sessionCountry(db *gorm.DB, ip string) string {
+	if country := ipLocationLookup(ip).Country; country != "" {
+		return country
+	}
+	var entry model.SecurityLog
+	err := db.Where("ip = ? AND location <> ''", ip).Order("created_at DESC, id DESC").First(&entry).Error
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(entry.Location, "/")
+	return strings.TrimSpace(parts[len(parts)-1])
+}
+
+// listActiveSessions returns a page of the sessions that expire after now,
+// newest first, together with the number of sessions matching the filter.
+// The country filter is compared case-insensitively after resolving each
+// client IP, so it is applied in memory rather than in SQL.
+func listActiveSessions(db *gorm.DB, filter sessionListFilter, now time.Time) ([]model.AdminSessionInfo, int64, error) {
+	query := db.Model(&model.Session{}).Where("expires_at > ?", now)
+	if filter.ClientIP != "" {
+		query = query.Where("client_ip = ?", filter.ClientIP)
+	}
+	query = query.Order("created_at DESC, id DESC")
+
+	var sessions []model.Session
+	var total int64
+	if filter.Country == "" {
+		if err := query.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+		if err := query.Offset(filter.Offset).Limit(filter.Limit).Find(&sessions).Error; err != nil {
+			return nil, 0, err
+		}
+	} else if err := query.Find(&sessions).Error; err != nil {
+		return nil, 0, err
+	}
+
+	countries := map[string]string{}
+	infos := make([]model.AdminSessionInfo, 0, len(sessions))
+	for _, s := range sessions {
+		country, ok := countries[s.ClientIP]
+		if !ok {
+			country = sessionCountry(db, s.ClientIP)
+			countries[s.ClientIP] = country
+		}
+		if filter.Country != "" && !strings.EqualFold(country, filter.Country) {
+			continue
+		}
+		infos = append(infos, adminSessionInfo(s, country))
+	}
+
+	if filter.Country != "" {
+		total = int64(len(infos))
+		start := min(filter.Offset, len(infos))
+		end := min(start+filter.Limit, len(infos))
+		infos = infos[start:end]
+	}
+
+	if err := attachSessionEmails(db, infos); err != nil {
+		return nil, 0, err
+	}
+	return infos, total, nil
+}
+
+// adminSessionInfo converts a session to its admin listing, dropping the token.
+func adminSessionInfo(s model.Session, country string) model.AdminSessionInfo {
+	return model.AdminSessionInfo{
+		ID:        s.ID,
+		UserID:    s.UserID,
+		ClientIP:  s.ClientIP,
+		Country:   country,
+		Browser:   s.Browser,
+		CreatedAt: s.CreatedAt,
+		ExpiresAt: s.ExpiresAt,
+	}
+}
+
+// attachSessionEmails fills in the email of each session's user.
+func attachSessionEmails(db *gorm.DB, infos []model.AdminSessionInfo) error {
+	if len(infos) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(infos))
+	for _, info := range infos {
+		ids = append(ids, info.UserID)
+	}
+	var users []model.User
+	if err := db.Select("id", "email").Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return err
+	}
+	emails := make(map[uint]string, len(users))
+	for _, u := range users {
+		emails[u.ID] = u.Email
+	}
+	for i := range infos {
+		infos[i].Email = emails[infos[i].UserID]
+	}
+	return nil
+}
+
+// ListSessions godoc
+// @Summary      List active sessions (admin only)
+// @Description  List unexpired sessions of all users, newest first, with the owner's email and the country resolved from the client IP (GeoIP, falling back to the newest security log for that IP). Filter by exact client_ip and by country name (case-insensitive). Session tokens are never returned. Admin-only access.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        client_ip query string false "Exact client IP"
+// @Param        country query string false "Country name, e.g. Indonesia"
+// @Param        limit query int false "Page size (default 10, max 100)"
+// @Param        offset query int false "Number of sessions to skip"
+// @Success      200 {object} util.APIResponse{data=object{sessions=[]model.AdminSessionInfo,total=int,total_fetched=int,has_more=bool}} "Sessions retrieved"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /user/sessions [get]
+func ListSessions(c *gin.Context) {
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	limit, _, offset := parsePaginationParams(c)
+	filter := sessionListFilter{
+		ClientIP: queryString(c, "client_ip"),
+		Country:  queryString(c, "country"),
+		Limit:    limit,
+		Offset:   offset,
+	}
+
+	sessions, total, err := listActiveSessions(db, filter, time.Now())
+	if err != nil {
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to retrieve sessions", Err: err})
+		return
+	}
+
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "Sessions retrieved",
+		Data: util.NewPageResponse(sessions, total, len(sessions), util.OffsetHasMore(offset, len(sessions), total), nil).Named("sessions"),
+	})
+}
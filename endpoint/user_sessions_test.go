@@ -0,0 +1,133 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountUserSessions(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	r.GET("/user/:id/sessions/count", CountUserSessions)
+
+	user := model.User{Name: "Session User", Email: "sessions@example.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	assert.NoError(t, db.Create(&user).Error)
+	other := model.User{Name: "Other User", Email: "other-sessions@example.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	assert.NoError(t, db.Create(&other).Error)
+
+	count := func() model.UserSessionCount {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: fmt.Sprintf("/user/%d/sessions/count", user.ID)})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		assert.NotContains(t, w.Body.String(), "tok-")
+		var resp struct {
+			Data model.UserSessionCount `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data
+	}
+
+	got := count()
+	assert.Equal(t, int64(0), got.ActiveSessions)
+	assert.Nil(t, got.Newest)
+
+	now := time.Now()
+	sessions := []model.Session{
+		{SessionToken: "tok-old", UserID: user.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "10.0.0.1", Browser: "Firefox"},
+		{SessionToken: "tok-new", UserID: user.ID, ExpiresAt: now.Add(2 * time.Hour), ClientIP: "10.0.0.2", Browser: "Chrome"},
+		{SessionToken: "tok-expired", UserID: user.ID, ExpiresAt: now.Add(-time.Hour), ClientIP: "10.0.0.3", Browser: "Safari"},
+		{SessionToken: "tok-other", UserID: other.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "10.0.0.4", Browser: "Edge"},
+	}
+	for i := range sessions {
+		sessions[i].CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		assert.NoError(t, db.Create(&sessions[i]).Error)
+	}
+
+	got = count()
+	assert.Equal(t, user.ID, got.UserID)
+	assert.Equal(t, int64(2), got.ActiveSessions)
+	if assert.NotNil(t, got.Newest) {
+		assert.Equal(t, "10.0.0.2", got.Newest.ClientIP)
+		assert.Equal(t, "Chrome", got.Newest.Browser)
+	}
+
+	// Expiring a session drops it from the count.
+	assert.NoError(t, db.Model(&sessions[1]).Update("expires_at", now.Add(-time.Minute)).Error)
+	got = count()
+	assert.Equal(t, int64(1), got.ActiveSessions)
+	if assert.NotNil(t, got.Newest) {
+		assert.Equal(t, "10.0.0.1", got.Newest.ClientIP)
+	}
+
+	w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/user/99999/sessions/count"})
+	assertStatusWithError(t, w, http.StatusNotFound, err)
+}
+
+func TestListSessions_Filters(t *testing.T) {
+	r, db := setupEndpointTest(t)
+	assert.NoError(t, db.AutoMigrate(&model.SecurityLog{}))
+	r.GET("/user/sessions", ListSessions)
+
+	user := model.User{Name: "List User", Email: "list-sessions@example.com", Password: "x", PasswordSalt: "x", RoleID: model.RoleUser}
+	assert.NoError(t, db.Create(&user).Error)
+
+	now := time.Now()
+	sessions := []model.Session{
+		{SessionToken: "tok-a1", UserID: user.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "198.51.100.1", Browser: "Firefox"},
+		{SessionToken: "tok-a2", UserID: user.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "198.51.100.1", Browser: "Chrome"},
+		{SessionToken: "tok-b", UserID: user.ID, ExpiresAt: now.Add(time.Hour), ClientIP: "198.51.100.2", Browser: "Safari"},
+		{SessionToken: "tok-expired", UserID: user.ID, ExpiresAt: now.Add(-time.Hour), ClientIP: "198.51.100.1", Browser: "Edge"},
+	}
+	for i := range sessions {
+		sessions[i].CreatedAt = now.Add(time.Duration(i) * time.Minute)
+		assert.NoError(t, db.Create(&sessions[i]).Error)
+	}
+	assert.NoError(t, db.Create(&model.SecurityLog{EventType: "login_success", IP: "198.51.100.1", Location: "Jakarta/Indonesia"}).Error)
+	assert.NoError(t, db.Create(&model.SecurityLog{EventType: "login_success", IP: "198.51.100.2", Location: "Singapore"}).Error)
+
+	list := func(query string) ([]model.AdminSessionInfo, int64) {
+		t.Helper()
+		w, _, err := performRequest(r, requestSpec{method: http.MethodGet, requestPath: "/user/sessions" + query})
+		assertStatusWithError(t, w, http.StatusOK, err)
+		assert.NotContains(t, w.Body.String(), "tok-")
+		var resp struct {
+			Data struct {
+				Sessions []model.AdminSessionInfo `json:"sessions"`
+				Total    int64                    `json:"total"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		return resp.Data.Sessions, resp.Data.Total
+	}
+
+	all, total := list("")
+	assert.Equal(t, int64(3), total)
+	if assert.Len(t, all, 3) {
+		assert.Equal(t, "Safari", all[0].Browser, "newest first")
+		assert.Equal(t, "Singapore", all[0].Country)
+		assert.Equal(t, "list-sessions@example.com", all[0].Email)
+	}
+
+	byIP, total := list("?client_ip=198.51.100.1")
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, byIP, 2)
+	for _, s := range byIP {
+		assert.Equal(t, "198.51.100.1", s.ClientIP)
+		assert.Equal(t, "Indonesia", s.Country)
+	}
+
+	byCountry, total := list("?country=indonesia&limit=1&offset=1")
+	assert.Equal(t, int64(2), total)
+	if assert.Len(t, byCountry, 1) {
+		assert.Equal(t, "Firefox", byCountry[0].Browser)
+	}
+
+	none, total := list("?client_ip=198.51.100.2&country=Indonesia")
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, none)
+}
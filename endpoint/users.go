@@ -4,11 +4,13 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ariebrainware/basis-data-ltt/middleware"
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
 	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -119,7 +121,7 @@ func performUserUpdate(c *gin.Context, db *gorm.DB, user *model.User, req *Updat
 
 // UpdateUser godoc
 // @Summary      Update current user profile
-// @Description  Update authenticated user's name, email, and/or password
+// @Description  Update authenticated user's name, email, and/or password. Unknown fields are rejected and listed in data.unknown_fields.
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
@@ -133,8 +135,7 @@ func performUserUpdate(c *gin.Context, db *gorm.DB, user *model.User, req *Updat
 // @Router       /user [patch]
 func UpdateUser(c *gin.Context) {
 	var req UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		util.CallUserError(c, util.APIErrorParams{Msg: "Invalid request payload", Err: err})
+	if !bindStrictJSONOrRespond(c, &req, "Invalid request payload") {
 		return
 	}
 
@@ -146,9 +147,8 @@ func UpdateUser(c *gin.Context) {
 		return
 	}
 
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{Msg: "Database connection not available", Err: fmt.Errorf("db is nil")})
+	db, ok := getDBOrAbort(c)
+	if !ok {
 		return
 	}
 
@@ -182,26 +182,36 @@ func UpdateUser(c *gin.Context) {
 // @Param        limit query int false "Limit number of results (default 10, max 100)"
 // @Param        cursor query int false "Cursor for pagination (User ID)"
 // @Param        keyword query string false "Search keyword for name or email"
+// @Param        role_id query int false "Filter by role ID"
+// @Param        role query string false "Filter by role name (ignored when role_id is set)"
 // @Success      200 {object} util.APIResponse{data=object} "Users retrieved with cursor pagination"
+// @Failure      400 {object} util.APIResponse "Invalid role"
 // @Failure      401 {object} util.APIResponse "Unauthorized"
 // @Failure      500 {object} util.APIResponse "Server error"
 // @Router       /user [get]
 func ListUsers(c *gin.Context) {
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{Msg: "Database connection not available", Err: fmt.Errorf("db is nil")})
+	db, ok := getDBOrAbort(c)
+	if !ok {
 		return
 	}
 
 	limit, cursor, offset := parsePaginationParams(c)
 	keyword := c.Query("keyword")
 
+	roleID, ok := resolveRoleFilter(c, db)
+	if !ok {
+		return
+	}
+
 	// Apply filters
 	query := db.Model(&model.User{})
 	filterClause, filterArgs := buildKeywordFilter(keyword)
 	if filterClause != "" {
 		query = query.Where(filterClause, filterArgs...)
 	}
+	if roleID > 0 {
+		query = query.Where("role_id = ?", roleID)
+	}
 
 	// Count total matching users
 	var total int64
@@ -232,20 +242,14 @@ func ListUsers(c *gin.Context) {
 	}
 
 	util.CallSuccessOK(c, util.APISuccessParams{
-		Msg: "Users retrieved",
-		Data: map[string]interface{}{
-			"users":         users,
-			"total":         total,
-			"total_fetched": len(users),
-			"has_more":      hasMore,
-			"next_cursor":   nextCursor,
-		},
+		Msg:  "Users retrieved",
+		Data: util.NewPageResponse(users, total, len(users), hasMore, nextCursor).Named("users"),
 	})
 }
 
 // AdminUpdateUser godoc
 // @Summary      Update other user's profile (admin only)
-// @Description  Admins can update another user's name, email, and password
+// @Description  Admins can update another user's name, email, and password. Unknown fields are rejected and listed in data.unknown_fields.
 // @Tags         Authentication
 // @Accept       json
 // @Produce      json
@@ -280,9 +284,8 @@ func AdminUpdateUser(c *gin.Context) {
 		return
 	}
 
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{Msg: "Database connection not available", Err: fmt.Errorf("db is nil")})
+	db, ok := getDBOrAbort(c)
+	if !ok {
 		return
 	}
 
@@ -330,6 +333,18 @@ func parsePaginationParams(c *gin.Context) (limit int, cursor uint, offset int)
 	return limit, cursor, offset
 }
 
+// maxListLimit caps the limit of the treatment and therapist lists, the same
+// as parsePaginationParams does for users.
+const maxListLimit = 100
+
+// parseListPagination reads limit and offset for the treatment and therapist
+// lists. Invalid or negative values are ignored and limit is capped at
+// maxListLimit; unlike parsePaginationParams, a missing limit still returns
+// every row, as these lists always have.
+func parseListPagination(c *gin.Context) (limit, offset int) {
+	return parsePositiveInt(queryString(c, "limit"), 0, maxListLimit), parsePositiveInt(queryString(c, "offset"), 0, 0)
+}
+
 // parsePositiveInt parses a positive integer from a query value returning a default
 // when the value is missing or invalid. If max > 0 it caps the returned value.
 func parsePositiveInt(q string, defaultVal, max int) int {
@@ -394,6 +409,40 @@ func buildKeywordFilter(keyword string) (string, []interface{}) {
 	return "", nil
 }
 
+// resolveRoleFilter reads the optional role_id or role query parameters and
+// returns the matching role ID, or 0 when no role filter was requested. It
+// writes a 400 response and returns false when the role does not exist.
+func resolveRoleFilter(c *gin.Context, db *gorm.DB) (uint32, bool) {
+	roleIDStr := strings.TrimSpace(c.Query("role_id"))
+	roleName := strings.TrimSpace(c.Query("role"))
+	if roleIDStr == "" && roleName == "" {
+		return 0, true
+	}
+
+	var role model.Role
+	var err error
+	if roleIDStr != "" {
+		id, parseErr := strconv.ParseUint(roleIDStr, 10, 32)
+		if parseErr != nil || id == 0 {
+			util.CallUserError(c, util.APIErrorParams{Msg: "Invalid role_id", Err: fmt.Errorf("role_id must be a positive integer")})
+			return 0, false
+		}
+		err = db.First(&role, id).Error
+	} else {
+		err = db.Where("LOWER(name) = ?", strings.ToLower(roleName)).First(&role).Error
+	}
+
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			util.CallUserError(c, util.APIErrorParams{Msg: "Role not found", Err: err})
+			return 0, false
+		}
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to retrieve role", Err: err})
+		return 0, false
+	}
+	return uint32(role.ID), true
+}
+
 // GetUserInfo godoc
 // @Summary      Get user info (admin only)
 // @Description  Retrieve a user's information by ID. Admin-only access.
@@ -416,9 +465,8 @@ func GetUserInfo(c *gin.Context) {
 		return
 	}
 
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{Msg: "Database connection not available", Err: fmt.Errorf("db is nil")})
+	db, ok := getDBOrAbort(c)
+	if !ok {
 		return
 	}
 
@@ -471,9 +519,8 @@ func DeleteUser(c *gin.Context) {
 		return
 	}
 
-	db := middleware.GetDB(c)
-	if db == nil {
-		util.CallServerError(c, util.APIErrorParams{Msg: "Database connection not available", Err: fmt.Errorf("db is nil")})
+	db, ok := getDBOrAbort(c)
+	if !ok {
 		return
 	}
 
@@ -490,10 +537,157 @@ func DeleteUser(c *gin.Context) {
 	util.CallSuccessOK(c, util.APISuccessParams{Msg: "User deleted"})
 }
 
+// scrubSecurityLogEmail replaces a user's email with pseudonym in the security
+// log: the email column of their own events and any mention in messages or
+// details, such as request bodies recorded by older audit entries.
+func scrubSecurityLogEmail(tx *gorm.DB, userID uint, email, pseudonym string) error {
+	if err := tx.Model(&model.SecurityLog{}).
+		Where("email = ? OR user_id = ?", email, strconv.FormatUint(uint64(userID), 10)).
+		Update("email", pseudonym).Error; err != nil {
+		return err
+	}
+	if email == "" {
+		return nil
+	}
+
+	var logs []model.SecurityLog
+	pattern := "%" + email + "%"
+	if err := tx.Where("message LIKE ? OR details LIKE ?", pattern, pattern).Find(&logs).Error; err != nil {
+		return err
+	}
+	for _, entry := range logs {
+		updates := map[string]interface{}{
+			"message": strings.ReplaceAll(entry.Message, email, pseudonym),
+			"details": datatypes.JSON(strings.ReplaceAll(string(entry.Details), email, pseudonym)),
+		}
+		if len(entry.Details) == 0 {
+			delete(updates, "details")
+		}
+		if err := tx.Model(&entry).UpdateColumns(updates).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// anonymizeLinkedProfiles clears the personal details of the patient and
+// therapist records linked to a user by email, including soft-deleted ones.
+// The rows stay, with the pseudonym as their email, so treatments and other
+// records referring to them remain intact.
+func anonymizeLinkedProfiles(tx *gorm.DB, email, pseudonym string) error {
+	if email == "" {
+		return nil
+	}
+	if err := tx.Unscoped().Model(&model.Patient{}).Where("email = ?", email).Updates(map[string]interface{}{
+		"full_name":    "Anonymized Patient",
+		"email":        pseudonym,
+		"password":     "",
+		"phone_number": "",
+		"address":      "",
+		"job":          "",
+		"latitude":     nil,
+		"longitude":    nil,
+	}).Error; err != nil {
+		return err
+	}
+	return tx.Unscoped().Model(&model.Therapist{}).Where("email = ?", email).Updates(map[string]interface{}{
+		"full_name":     "Anonymized Therapist",
+		"email":         pseudonym,
+		"password":      "",
+		"phone_number":  "",
+		"address":       "",
+		"date_of_birth": "",
+		"nik":           "",
+		"latitude":      nil,
+		"longitude":     nil,
+	}).Error
+}
+
+// anonymizeUserWithSessions replaces a user's name, email and credentials with
+// placeholders, clears the linked patient and therapist details,
+// pseudonymizes their email in the security log and deletes their sessions
+// atomically. The row and its ID stay, so records that
+// reference the user remain intact.
+func anonymizeUserWithSessions(db *gorm.DB, userID uint) (model.User, error) {
+	var user model.User
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, userID).Error; err != nil {
+			return err
+		}
+		// A random password nobody knows keeps the account unusable.
+		randomPassword, err := util.GenerateSalt()
+		if err != nil {
+			return err
+		}
+		if err := hashUserPassword(&user, randomPassword); err != nil {
+			return err
+		}
+		originalEmail := user.Email
+		user.Name = "Anonymized User"
+		user.Email = fmt.Sprintf("anonymized-%d@anonymized.invalid", user.ID)
+		user.FailedAttempts = 0
+		user.LockedUntil = nil
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if err := anonymizeLinkedProfiles(tx, originalEmail, user.Email); err != nil {
+			return err
+		}
+		if err := scrubSecurityLogEmail(tx, user.ID, originalEmail, user.Email); err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&model.Session{}).Error
+	})
+	return user, err
+}
+
+// AnonymizeUser godoc
+// @Summary      Anonymize user (admin only)
+// @Description  Handle a personal data deletion request: overwrite the user's name and email with placeholders, clear the name, contact details and address of the patient or therapist linked by email, pseudonymize the email in the security log, replace their credentials and revoke all sessions. Only the user ID and status are returned. Unlike DELETE /user/{id}, the row is kept so records referencing the user ID stay intact. Admin-only access.
+// @Tags         Authentication
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Security     SessionToken
+// @Param        id path int true "User ID"
+// @Success      200 {object} util.APIResponse "User anonymized"
+// @Failure      400 {object} util.APIResponse "Invalid user id"
+// @Failure      401 {object} util.APIResponse "Unauthorized"
+// @Failure      404 {object} util.APIResponse "User not found"
+// @Failure      500 {object} util.APIResponse "Server error"
+// @Router       /user/{id}/anonymize [post]
+func AnonymizeUser(c *gin.Context) {
+	uid, err := parseIDParam(c)
+	if err != nil {
+		util.CallUserError(c, util.APIErrorParams{Msg: err.Error(), Err: err})
+		return
+	}
+
+	db, ok := getDBOrAbort(c)
+	if !ok {
+		return
+	}
+
+	user, err := anonymizeUserWithSessions(db, uid)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			util.CallErrorNotFound(c, util.APIErrorParams{Msg: "User not found", Err: err})
+			return
+		}
+		util.CallServerError(c, util.APIErrorParams{Msg: "Failed to anonymize user", Err: err})
+		return
+	}
+
+	_ = util.InvalidateUserSessions(uid)
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg:  "User anonymized",
+		Data: map[string]interface{}{"id": user.ID, "status": "anonymized"},
+	})
+}
+
 func bindUpdateUserRequest(c *gin.Context) (UpdateUserRequest, bool) {
 	var req UpdateUserRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		util.CallUserError(c, util.APIErrorParams{Msg: "Invalid request payload", Err: err})
+	if !bindStrictJSONOrRespond(c, &req, "Invalid request payload") {
 		return UpdateUserRequest{}, false
 	}
 	return req, true
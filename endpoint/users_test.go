@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
+	"gorm.io/gorm"
 )
 
 // Admin updates another user's password
@@ -36,6 +38,66 @@ func TestAdminUpdateTargetPassword(t *testing.T) {
 	}
 }
 
+// Admin updates are recorded as ADMIN_ACTION security logs with the changed fields
+func TestAdminUpdateWritesAuditLog(t *testing.T) {
+	r, db, adminToken := SetupServerWithAdmin(t)
+	if err := db.AutoMigrate(&model.SecurityLog{}); err != nil {
+		t.Fatalf("auto migrate security logs: %v", err)
+	}
+	util.SetSecurityLoggerDB(db)
+	t.Cleanup(func() { util.SetSecurityLoggerDB(nil) })
+
+	_, targetID := CreateAndLoginUser(t, r, SignupCreds{Name: "Target User", Email: "target@example.com", Password: "targetpass"})
+
+	path := "/user/" + strconv.Itoa(int(targetID))
+	b, _ := json.Marshal(map[string]string{"name": "Audited Name", "password": "newtargetpass"})
+	rr, err := doRequest(r, requestParams{method: "PATCH", path: path, body: b, headers: map[string]string{"session-token": adminToken}})
+	if err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("admin update failed: %v %d %s", err, rr.Code, rr.Body.String())
+	}
+
+	var entries []model.SecurityLog
+	if err := db.Where("event_type = ?", string(util.EventAdminAction)).Find(&entries).Error; err != nil {
+		t.Fatalf("query audit logs: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(entries))
+	}
+
+	var details struct {
+		ActorUserID uint     `json:"actor_user_id"`
+		Action      string   `json:"action"`
+		TargetType  string   `json:"target_type"`
+		TargetID    string   `json:"target_id"`
+		Fields      []string `json:"changed_fields"`
+	}
+	if err := json.Unmarshal(entries[0].Details, &details); err != nil {
+		t.Fatalf("decode audit details: %v", err)
+	}
+	if details.Action != "update" || details.TargetType != "user" || details.TargetID != strconv.Itoa(int(targetID)) {
+		t.Fatalf("unexpected audit target: %+v", details)
+	}
+	if details.ActorUserID == 0 || entries[0].UserID != strconv.Itoa(int(details.ActorUserID)) {
+		t.Fatalf("audit entry missing actor: %+v user_id=%s", details, entries[0].UserID)
+	}
+	if strings.Join(details.Fields, ",") != "name,password" {
+		t.Fatalf("unexpected audit fields: %+v", details.Fields)
+	}
+	if strings.Contains(string(entries[0].Details), "Audited Name") || strings.Contains(string(entries[0].Details), "newtargetpass") {
+		t.Fatalf("audit details must not contain submitted values: %s", entries[0].Details)
+	}
+
+	// Reads are not audited.
+	if _, err := doRequest(r, requestParams{method: "GET", path: path, headers: map[string]string{"session-token": adminToken}}); err != nil {
+		t.Fatalf("get user failed: %v", err)
+	}
+	var count int64
+	db.Model(&model.SecurityLog{}).Where("event_type = ?", string(util.EventAdminAction)).Count(&count)
+	if count != 1 {
+		t.Fatalf("expected reads not to be audited, got %d entries", count)
+	}
+}
+
 // Admin deletes another user
 func TestAdminDeleteTarget(t *testing.T) {
 	r, _, adminToken := SetupServerWithAdmin(t)
@@ -59,6 +121,135 @@ func TestAdminDeleteTarget(t *testing.T) {
 	}
 }
 
+// Admin anonymizes another user: PII is scrubbed, sessions are revoked and the row stays
+func TestAdminAnonymizeTarget(t *testing.T) {
+	r, db, adminToken := SetupServerWithAdmin(t)
+	if err := db.AutoMigrate(&model.SecurityLog{}); err != nil {
+		t.Fatalf("auto migrate security logs: %v", err)
+	}
+	util.SetSecurityLoggerDB(db)
+	t.Cleanup(func() { util.SetSecurityLoggerDB(nil) })
+	targetToken, targetID := CreateAndLoginUser(t, r, SignupCreds{Name: "Target User", Email: "target@example.com", Password: "targetpass"})
+
+	// A legacy audit entry that stored the raw request body.
+	legacy := model.SecurityLog{EventType: string(util.EventAdminAction), UserID: "1", Message: "Admin update user", Details: []byte(`{"changes":{"email":"target@example.com"}}`)}
+	if err := db.Create(&legacy).Error; err != nil {
+		t.Fatalf("create legacy audit entry: %v", err)
+	}
+
+	path := "/user/" + strconv.Itoa(int(targetID))
+	rr, err := doRequest(r, requestParams{method: "POST", path: path + "/anonymize", headers: map[string]string{"session-token": adminToken}})
+	if err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("anonymize user failed: %v %d %s", err, rr.Code, rr.Body.String())
+	}
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Data) != 2 || resp.Data["id"] != float64(targetID) || resp.Data["status"] != "anonymized" {
+		t.Fatalf("expected only id and status in the response, got %v", resp.Data)
+	}
+
+	var user model.User
+	if err := db.First(&user, targetID).Error; err != nil {
+		t.Fatalf("anonymized user row should remain: %v", err)
+	}
+	if user.Name == "Target User" || user.Email == "target@example.com" {
+		t.Fatalf("PII not scrubbed: name=%q email=%q", user.Name, user.Email)
+	}
+	if ok, _ := util.VerifyPassword("targetpass", user.Password, user.PasswordSalt); ok {
+		t.Fatalf("old password still verifies after anonymization")
+	}
+
+	var leaked int64
+	db.Model(&model.SecurityLog{}).Where("email = ? OR details LIKE ?", "target@example.com", "%target@example.com%").Count(&leaked)
+	if leaked != 0 {
+		t.Fatalf("expected the email to be scrubbed from %d security log entries", leaked)
+	}
+	var ownEvents int64
+	db.Model(&model.SecurityLog{}).Where("user_id = ? AND email = ?", strconv.Itoa(int(targetID)), user.Email).Count(&ownEvents)
+	if ownEvents == 0 {
+		t.Fatalf("expected the user's own events to carry the pseudonymized email")
+	}
+	if err := db.First(&legacy, legacy.ID).Error; err != nil || !strings.Contains(string(legacy.Details), user.Email) {
+		t.Fatalf("expected legacy audit details to be pseudonymized, got %s (%v)", legacy.Details, err)
+	}
+
+	var sessions int64
+	db.Model(&model.Session{}).Where("user_id = ?", targetID).Count(&sessions)
+	if sessions != 0 {
+		t.Fatalf("expected sessions to be revoked, got %d", sessions)
+	}
+	rr, err = doRequest(r, requestParams{method: "PATCH", path: "/user", body: []byte(`{"name":"Back Again"}`), headers: map[string]string{"session-token": targetToken}})
+	if err != nil || rr.Code == http.StatusOK {
+		t.Fatalf("revoked session should be rejected: %v %d", err, rr.Code)
+	}
+
+	// Unlike delete, the user can still be looked up by ID.
+	rr, err = doRequest(r, requestParams{method: "GET", path: path, headers: map[string]string{"session-token": adminToken}})
+	if err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("get anonymized user failed: %v %d %s", err, rr.Code, rr.Body.String())
+	}
+
+	rr, err = doRequest(r, requestParams{method: "POST", path: "/user/99999/anonymize", headers: map[string]string{"session-token": adminToken}})
+	if err != nil || rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing user: %v %d", err, rr.Code)
+	}
+}
+
+// Anonymizing a user also clears the patient and therapist records linked by email
+func TestAdminAnonymizeClearsLinkedProfiles(t *testing.T) {
+	r, db, adminToken := SetupServerWithAdmin(t)
+	if err := db.AutoMigrate(&model.SecurityLog{}); err != nil {
+		t.Fatalf("auto migrate security logs: %v", err)
+	}
+	_, targetID := CreateAndLoginUser(t, r, SignupCreds{Name: "Linked User", Email: "linked@example.com", Password: "linkedpass"})
+
+	lat := -6.2
+	patient := model.Patient{FullName: "Linked Patient", Email: "linked@example.com", PhoneNumber: "0811", Address: "Jl. Patient 1", Job: "Clerk", PatientCode: "L001", Latitude: &lat}
+	therapist := model.Therapist{FullName: "Linked Therapist", Email: "linked@example.com", PhoneNumber: "0822", Address: "Jl. Therapist 2", DateOfBirth: "1990-01-01", NIK: "3201000000000001"}
+	other := model.Patient{FullName: "Other Patient", Email: "other@example.com", PhoneNumber: "0833", PatientCode: "O001"}
+	for _, row := range []interface{}{&patient, &therapist, &other} {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatalf("create linked record: %v", err)
+		}
+	}
+	if err := db.Delete(&therapist).Error; err != nil {
+		t.Fatalf("soft-delete therapist: %v", err)
+	}
+
+	rr, err := doRequest(r, requestParams{method: "POST", path: "/user/" + strconv.Itoa(int(targetID)) + "/anonymize", headers: map[string]string{"session-token": adminToken}})
+	if err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("anonymize user failed: %v %d %s", err, rr.Code, rr.Body.String())
+	}
+
+	var gotPatient model.Patient
+	if err := db.First(&gotPatient, patient.ID).Error; err != nil {
+		t.Fatalf("linked patient row should remain: %v", err)
+	}
+	if gotPatient.FullName == patient.FullName || gotPatient.Email == patient.Email || gotPatient.PhoneNumber != "" || gotPatient.Address != "" || gotPatient.Job != "" || gotPatient.Latitude != nil {
+		t.Fatalf("patient PII not cleared: %+v", gotPatient)
+	}
+	if gotPatient.PatientCode != "L001" {
+		t.Fatalf("patient code should be kept for treatment history, got %q", gotPatient.PatientCode)
+	}
+
+	var gotTherapist model.Therapist
+	if err := db.Unscoped().First(&gotTherapist, therapist.ID).Error; err != nil {
+		t.Fatalf("linked therapist row should remain: %v", err)
+	}
+	if gotTherapist.FullName == therapist.FullName || gotTherapist.Email == therapist.Email || gotTherapist.PhoneNumber != "" || gotTherapist.Address != "" || gotTherapist.DateOfBirth != "" || gotTherapist.NIK != "" {
+		t.Fatalf("therapist PII not cleared: %+v", gotTherapist)
+	}
+
+	var gotOther model.Patient
+	if err := db.First(&gotOther, other.ID).Error; err != nil || gotOther.FullName != "Other Patient" || gotOther.PhoneNumber != "0833" {
+		t.Fatalf("unrelated patient should be untouched: %+v (%v)", gotOther, err)
+	}
+}
+
 func TestSelfPasswordUpdate(t *testing.T) {
 	r, db, userToken, userID := SetupServerWithUser(t, SignupCreds{Name: "Self User", Email: "self@example.com", Password: "initialpass"})
 
@@ -248,3 +439,60 @@ func TestListUsersWithKeywordNoMatches(t *testing.T) {
 	AssertTotal(t, data, 0)
 	AssertTotalFetched(t, data, 0)
 }
+
+// assignRoles sets the role of the given users (by email) directly in the DB,
+// since every signup is created with the same default role.
+func assignRoles(t *testing.T, db *gorm.DB, roleID uint32, emails ...string) {
+	t.Helper()
+	if err := db.Model(&model.User{}).Where("email IN ?", emails).Update("role_id", roleID).Error; err != nil {
+		t.Fatalf("failed to assign roles: %v", err)
+	}
+}
+
+func TestListUsersWithRoleFilter(t *testing.T) {
+	r, db, cleanup := SetupTestServer(t)
+	t.Cleanup(cleanup)
+	adminToken, _ := CreateAdminAndTestUsers(t, r)
+	assignRoles(t, db, model.RoleTherapist, "alice@example.com", "bob@example.com")
+
+	data := ListUsersData(t, r, adminToken, "role_id=3")
+	AssertTotal(t, data, 2)
+
+	data = ListUsersData(t, r, adminToken, "role=therapist")
+	AssertTotal(t, data, 2)
+}
+
+func TestListUsersWithKeywordAndRoleFilter(t *testing.T) {
+	r, db, cleanup := SetupTestServer(t)
+	t.Cleanup(cleanup)
+	adminToken, _ := CreateAdminAndTestUsers(t, r)
+	assignRoles(t, db, model.RoleTherapist, "alice@example.com", "bob@example.com", "charlie@example.com")
+
+	data := ListUsersData(t, r, adminToken, "keyword=alice&role_id=3")
+	AssertTotal(t, data, 1)
+
+	// Keyword matches everyone, role narrows it down; pagination applies on top.
+	data = ListUsersData(t, r, adminToken, "keyword=example&role=Therapist&limit=2")
+	AssertTotal(t, data, 3)
+	AssertTotalFetched(t, data, 2)
+
+	// Admin matches the keyword but not the role.
+	data = ListUsersData(t, r, adminToken, "keyword=admin&role_id=3")
+	AssertTotal(t, data, 0)
+}
+
+func TestListUsersWithInvalidRole(t *testing.T) {
+	r, _, cleanup := SetupTestServer(t)
+	t.Cleanup(cleanup)
+	adminToken, _ := CreateAdminAndTestUsers(t, r)
+
+	for _, query := range []string{"role_id=999", "role_id=abc", "role=superuser"} {
+		rr, err := doRequest(r, requestParams{method: "GET", path: "/user?" + query, headers: map[string]string{"session-token": adminToken}})
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", query, err)
+		}
+		if rr.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d %s", query, rr.Code, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package endpoint
+
+import (
+	"runtime"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+// VersionInfo describes the running build of the API.
+type VersionInfo struct {
+	AppName   string `json:"app_name" example:"basis-data-ltt"`
+	Version   string `json:"version" example:"v1.2.3"`
+	Commit    string `json:"commit" example:"a1b2c3d"`
+	BuildDate string `json:"build_date" example:"2025-01-15T10:00:00Z"`
+	GoVersion string `json:"go_version" example:"go1.25.0"`
+}
+
+// GetVersion godoc
+// @Summary      Get API version
+// @Description  Return the application name, build version, commit and Go runtime version
+// @Tags         Version
+// @Produce      json
+// @Success      200 {object} util.APIResponse{data=VersionInfo} "Version retrieved"
+// @Router       /version [get]
+func GetVersion(c *gin.Context) {
+	util.CallSuccessOK(c, util.APISuccessParams{
+		Msg: "Version retrieved",
+		Data: VersionInfo{
+			AppName:   config.LoadConfig().AppName,
+			Version:   config.Version,
+			Commit:    config.Commit,
+			BuildDate: config.BuildDate,
+			GoVersion: runtime.Version(),
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package endpoint
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+
+	originalVersion, originalCommit := config.Version, config.Commit
+	config.Version, config.Commit = "v9.9.9", "abc1234"
+	t.Cleanup(func() { config.Version, config.Commit = originalVersion, originalCommit })
+
+	w, _, err := doRequestWithHandler(r, requestSpec{method: http.MethodGet, registerPath: "/version", requestPath: "/version", handler: GetVersion})
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	for _, field := range []string{"app_name", "version", "commit", "build_date", "go_version"} {
+		assert.Contains(t, resp.Data, field)
+	}
+	assert.Equal(t, "v9.9.9", resp.Data["version"])
+	assert.Equal(t, "abc1234", resp.Data["commit"])
+	assert.Equal(t, runtime.Version(), resp.Data["go_version"])
+}
@@ -4,7 +4,6 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
 	"net/http"
 	"os"
 	"os/signal"
@@ -54,16 +53,16 @@ func main() {
 	cfg := config.LoadConfig()
 
 	if err := initJWT(cfg); err != nil {
-		log.Fatalf("JWT init failed: %v", err)
+		fatal("JWT init failed", err)
 	}
 
 	if err := setTimezone(); err != nil {
-		log.Fatalf("Timezone init failed: %v", err)
+		fatal("Timezone init failed", err)
 	}
 
 	db, err := initDB()
 	if err != nil {
-		log.Fatalf("Error connecting to DB: %v", err)
+		fatal("Error connecting to DB", err)
 	}
 
 	util.SetSecurityLoggerDB(db)
@@ -73,7 +72,24 @@ func main() {
 	defer util.CloseGeoIP()
 
 	if err := migrateAndSeed(db); err != nil {
-		log.Fatalf("Migration/seed failed: %v", err)
+		fatal("Migration/seed failed", err)
+	}
+
+	if err := endpoint.ValidateSignupRole(db); err != nil {
+		fatal("Invalid signup role", err)
+	}
+
+	if err := middleware.LoadAPITokenScopes(); err != nil {
+		fatal("Invalid API_TOKEN_SCOPES", err)
+	}
+
+	if err := endpoint.ValidateEmailVerification(); err != nil {
+		fatal("Invalid email verification setup", err)
+	}
+
+	if purgeCfg := model.PurgeConfigFromEnv(); purgeCfg.Enabled {
+		model.StartPurgeJob(context.Background(), db, purgeCfg)
+		config.Logger().Info("Soft-delete purge enabled", "retention", purgeCfg.Retention.String(), "interval", purgeCfg.Interval.String())
 	}
 
 	r := setupRouter(cfg, db)
@@ -94,7 +110,7 @@ func initJWT(cfg *config.Config) error {
 }
 
 func setTimezone() error {
-	location, err := time.LoadLocation("Asia/Jakarta")
+	location, err := time.LoadLocation(config.Timezone)
 	if err != nil {
 		return err
 	}
@@ -109,12 +125,23 @@ func initDB() (*gorm.DB, error) {
 func migrateAndSeed(db *gorm.DB) error {
 	applyDiseaseCodenameMigrationFix(db)
 
-	if err := db.AutoMigrate(&model.Patient{}, &model.Disease{}, &model.User{}, &model.Session{}, &model.Therapist{}, &model.Role{}, &model.Treatment{}, &model.Pricing{}, &model.Transaction{}, &model.PatientCode{}, &model.SecurityLog{}, &model.Item{}, &model.Employee{}); err != nil {
+	if err := db.AutoMigrate(model.MigratedModels()...); err != nil {
 		return err
 	}
 
 	runLegacyMigrations(db)
 
+	// Existing duplicate emails prevent the index from being created; the
+	// duplicate check in CreateTherapist still applies in that case.
+	if err := model.EnsureTherapistEmailIndex(db); err != nil {
+		config.Logger().Warn("Failed to create unique therapist email index", "error", err)
+	}
+	// Likewise for duplicate disease names; CreateDisease and UpdateDisease
+	// still reject them.
+	if err := model.EnsureDiseaseNameIndex(db); err != nil {
+		config.Logger().Warn("Failed to create unique disease name index", "error", err)
+	}
+
 	return model.SeedRoles(db)
 }
 
@@ -125,15 +152,15 @@ func applyDiseaseCodenameMigrationFix(db *gorm.DB) {
 	}
 
 	if err := db.Exec("ALTER TABLE diseases MODIFY codename varchar(191) NULL").Error; err != nil {
-		log.Printf("Warning: failed to alter diseases.codename to NULL-able: %v", err)
+		config.Logger().Warn("Failed to alter diseases.codename to NULL-able", "error", err)
 	} else {
-		log.Println("Converted diseases.codename to allow NULLs (if applicable)")
+		config.Logger().Info("Converted diseases.codename to allow NULLs (if applicable)")
 	}
 
 	if err := db.Exec("UPDATE diseases SET codename = NULL WHERE codename = ''").Error; err != nil {
-		log.Printf("Warning: failed to nullify empty codename values: %v", err)
+		config.Logger().Warn("Failed to nullify empty codename values", "error", err)
 	} else {
-		log.Println("Nullified empty codename values in diseases table (if any)")
+		config.Logger().Info("Nullified empty codename values in diseases table (if any)")
 	}
 }
 
@@ -154,17 +181,19 @@ func dropLegacyColumn(db *gorm.DB, model interface{}, columnName, label string)
 	}
 
 	if err := db.Migrator().DropColumn(model, columnName); err != nil {
-		log.Printf("Warning: failed to drop %s: %v", label, err)
+		config.Logger().Warn("Failed to drop legacy column", "column", label, "error", err)
 		return
 	}
 
-	log.Printf("Dropped legacy %s column", label)
+	config.Logger().Info("Dropped legacy column", "column", label)
 }
 
 func setupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 	gin.SetMode(cfg.GinMode)
-	r := gin.Default()
+	r := gin.New()
+	r.Use(middleware.RequestID(), middleware.RequestLogger(), gin.Recovery())
 	r.Use(middleware.CORSMiddleware())
+	r.Use(middleware.GzipMiddleware())
 	r.Use(middleware.DatabaseMiddleware(db))
 	r.Use(middleware.EndpointCallLogger())
 
@@ -175,26 +204,35 @@ func setupRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 }
 
 func registerPublicRoutes(r *gin.Engine, cfg *config.Config) {
-	r.GET("/", func(c *gin.Context) {
+	r.GET("/", middleware.CacheControl(middleware.PublicCacheControl), func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Welcome to %s!", cfg.AppName)})
 	})
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.GET("/version", middleware.CacheControl(middleware.PublicCacheControl), endpoint.GetVersion)
+	r.GET("/time", endpoint.GetServerTime)
 	r.POST("/patient", endpoint.CreatePatient)
 
 	authRateLimit := middleware.RateLimiter(middleware.RateLimitConfig{Limit: 5, Window: 15 * time.Minute})
 	r.POST("/login", authRateLimit, endpoint.Login)
 	r.POST("/signup", authRateLimit, endpoint.Signup)
+	r.GET("/verify-email", authRateLimit, endpoint.VerifyEmail)
+	r.POST("/verify-email/resend", authRateLimit, endpoint.ResendVerificationEmail)
 	r.GET("/token/validate", endpoint.ValidateToken)
+	r.GET("/token/ttl", endpoint.GetSessionTTL)
+	r.GET("/token/jwt/introspect", endpoint.IntrospectJWT)
 }
 
 func registerAuthenticatedRoutes(r *gin.Engine, cfg *config.Config) {
 	auth := r.Group("/")
-	auth.Use(middleware.ValidateLoginToken())
+	auth.Use(middleware.ValidateLoginToken(), middleware.TenantScope(), middleware.AuditAdminMutations())
 
 	auth.DELETE("/logout", endpoint.Logout)
 	auth.PATCH("/user", endpoint.UpdateUser)
 	auth.POST("/verify-password", endpoint.VerifyPassword)
+	auth.GET("/role/constants", middleware.CacheControl(middleware.PrivateCacheControl), endpoint.ListRoleConstants)
+	auth.GET("/user/me/permissions", endpoint.GetCurrentUserPermissions)
+	auth.GET("/user/me/patients", endpoint.GetMyPatients)
 
 	registerUserRoutes(auth)
 	registerPatientRoutes(auth)
@@ -205,53 +243,95 @@ func registerAuthenticatedRoutes(r *gin.Engine, cfg *config.Config) {
 	registerTransactionRoutes(auth)
 	registerTherapistRoutes(auth)
 	registerEmployeeRoutes(auth)
+	registerReportRoutes(auth)
+	registerClinicHoursRoutes(auth)
+	registerSecurityRoutes(auth)
+	registerAdminRoutes(auth)
+	auth.GET("/search", middleware.RequirePermission(model.PermissionSearch), endpoint.Search)
+	auth.GET("/metrics", middleware.RequirePermission(model.PermissionViewSystemStatus), endpoint.Metrics)
 
 	if cfg.AppEnv != "production" {
-		auth.GET("/debug/dbinfo", middleware.RequireRole(model.RoleAdmin), endpoint.DebugDBInfo)
+		auth.GET("/debug/dbinfo", middleware.RequirePermission(model.PermissionDebug), endpoint.DebugDBInfo)
 	}
 }
 
 func registerUserRoutes(auth *gin.RouterGroup) {
 	userAdmin := auth.Group("/user")
-	userAdmin.Use(middleware.RequireRole(model.RoleAdmin))
+	userAdmin.Use(middleware.RequirePermission(model.PermissionManageUsers))
 	userAdmin.GET("", endpoint.ListUsers)
+	userAdmin.GET("/sessions", endpoint.ListSessions)
 	userAdmin.DELETE("/:id", endpoint.DeleteUser)
+	userAdmin.POST("/:id/anonymize", endpoint.AnonymizeUser)
+	userAdmin.GET("/:id/sessions/count", endpoint.CountUserSessions)
 
-	auth.GET("/user/:id", middleware.RequireRoleOrOwner(model.RoleAdmin), endpoint.GetUserInfo)
-	auth.PATCH("/user/:id", middleware.RequireRole(model.RoleAdmin), endpoint.UpdateUserByID)
+	auth.GET("/user/:id", middleware.RequireRoleOrOwner(model.PermissionRoles(model.PermissionManageUsers)...), endpoint.GetUserInfo)
+	auth.PATCH("/user/:id", middleware.RequirePermission(model.PermissionManageUsers), endpoint.UpdateUserByID)
 }
 
 func registerPatientRoutes(auth *gin.RouterGroup) {
 	patient := auth.Group("/patient")
-	patient.Use(middleware.RequireRole(model.RoleAdmin))
+	patient.Use(middleware.RequirePermission(model.PermissionManagePatients))
 	patient.GET("", endpoint.ListPatients)
+	patient.GET("/duplicates", endpoint.ListDuplicatePatients)
+	patient.GET("/inactive", endpoint.ListInactivePatients)
+	patient.GET("/high-risk", endpoint.ListHighRiskPatients)
+	patient.GET("/invalid-emails", endpoint.ListInvalidEmailPatients)
+	patient.GET("/missing-contact", endpoint.ListMissingContactPatients)
+	patient.GET("/signups-by-day", endpoint.GetPatientSignupsByDay)
+	patient.GET("/by-email", endpoint.GetPatientByEmail)
 	patient.GET("/:id", endpoint.GetPatientInfo)
 	patient.PATCH("/:id", endpoint.UpdatePatient)
 	patient.DELETE("/:id", endpoint.DeletePatient)
+	patient.POST("/:id/transfer", endpoint.TransferPatient)
+	patient.PUT("/:id/risk-level", endpoint.SetPatientRiskLevel)
+	patient.PUT("/:id/preferred-therapist", endpoint.SetPatientPreferredTherapist)
+	patient.DELETE("/:id/preferred-therapist", endpoint.ClearPatientPreferredTherapist)
+	patient.POST("/:id/resend-credentials", endpoint.ResendPatientCredentials)
+
+	auth.GET("/patient/:id/treatment-summary", endpoint.GetPatientTreatmentSummary)
+	auth.GET("/patient/:id/report.pdf", endpoint.GetPatientReportPDF)
+	auth.GET("/patient/:id/suggested-next-visit", middleware.RequirePermission(model.PermissionViewPatientHistory), endpoint.GetSuggestedNextVisit)
+	auth.GET("/patient/:id/treatment-gaps", middleware.RequirePermission(model.PermissionViewPatientHistory), endpoint.GetPatientTreatmentGaps)
+	auth.GET("/patient/:id/adherence", middleware.RequirePermission(model.PermissionViewPatientHistory), endpoint.GetPatientAdherence)
+	auth.GET("/patient/:id/therapists", middleware.RequirePermission(model.PermissionViewPatientHistory), endpoint.GetPatientTherapists)
+	auth.GET("/patient/:id/next-appointment", middleware.RequirePermission(model.PermissionViewPatientHistory), endpoint.GetPatientNextAppointment)
+	auth.GET("/patient-code/next", middleware.RequirePermission(model.PermissionManagePatients), endpoint.PreviewNextPatientCode)
 }
 
 func registerTreatmentRoutes(auth *gin.RouterGroup) {
 	treatment := auth.Group("/treatment")
-	treatment.Use(middleware.RequireRole(model.RoleAdmin, model.RoleTherapist))
+	treatment.Use(middleware.RequirePermission(model.PermissionManageTreatments))
 	treatment.GET("", endpoint.ListTreatments)
+	treatment.GET("/orphans", middleware.RequirePermission(model.PermissionRepairTreatments), endpoint.ListOrphanedTreatments)
+	treatment.GET("/invalid-therapist", middleware.RequirePermission(model.PermissionRepairTreatments), endpoint.ListInvalidTherapistTreatments)
+	treatment.POST("/invalid-therapist/reassign", middleware.RequirePermission(model.PermissionRepairTreatments), endpoint.ReassignInvalidTherapistTreatments)
+	treatment.GET("/near-duplicates", middleware.RequirePermission(model.PermissionRepairTreatments), endpoint.ListNearDuplicateTreatments)
+	treatment.POST("/check-duplicates", endpoint.CheckTreatmentDuplicates)
 	treatment.POST("", endpoint.CreateTreatment)
 	treatment.PATCH("/:id", endpoint.UpdateTreatment)
+	treatment.GET("/:id/fhir", endpoint.GetTreatmentFHIR)
+	treatment.POST("/:id/clone", endpoint.CloneTreatment)
 	treatment.DELETE("/:id", endpoint.DeleteTreatment)
+	treatment.PUT("/:id/tags", endpoint.SetTreatmentTags)
+
+	auth.GET("/tag", middleware.RequirePermission(model.PermissionViewTags), endpoint.ListTags)
 }
 
 func registerDiseaseRoutes(auth *gin.RouterGroup) {
 	disease := auth.Group("/disease")
-	disease.Use(middleware.RequireRole(model.RoleAdmin))
+	disease.Use(middleware.RequirePermission(model.PermissionManageDiseases))
 	disease.GET("", endpoint.ListDiseases)
 	disease.POST("", endpoint.CreateDisease)
+	disease.POST("/import", endpoint.ImportDiseases)
 	disease.GET("/:id", endpoint.GetDiseaseInfo)
+	disease.GET("/:id/usage", endpoint.GetDiseaseUsage)
 	disease.PATCH("/:id", endpoint.UpdateDisease)
 	disease.DELETE("/:id", endpoint.DeleteDisease)
 }
 
 func registerPricingRoutes(auth *gin.RouterGroup) {
 	pricing := auth.Group("/pricing")
-	pricing.Use(middleware.RequireRole(model.RoleAdmin))
+	pricing.Use(middleware.RequirePermission(model.PermissionManagePricing))
 	pricing.GET("", endpoint.ListPricings)
 	pricing.POST("", endpoint.CreatePricing)
 	pricing.GET("/:id", endpoint.GetPricingInfo)
@@ -261,7 +341,7 @@ func registerPricingRoutes(auth *gin.RouterGroup) {
 
 func registerItemRoutes(auth *gin.RouterGroup) {
 	item := auth.Group("/item")
-	item.Use(middleware.RequireRole(model.RoleAdmin))
+	item.Use(middleware.RequirePermission(model.PermissionManageItems))
 	item.GET("", endpoint.ListItems)
 	item.POST("", endpoint.CreateItem)
 	item.GET("/:id", endpoint.GetItemInfo)
@@ -271,7 +351,7 @@ func registerItemRoutes(auth *gin.RouterGroup) {
 
 func registerTransactionRoutes(auth *gin.RouterGroup) {
 	transaction := auth.Group("/transaction")
-	transaction.Use(middleware.RequireRole(model.RoleAdmin))
+	transaction.Use(middleware.RequirePermission(model.PermissionManageTransactions))
 	transaction.GET("", endpoint.ListTransactions)
 	transaction.GET("/:id", endpoint.GetTransactionInfo)
 	transaction.PATCH("/:id", endpoint.UpdateTransaction)
@@ -279,17 +359,26 @@ func registerTransactionRoutes(auth *gin.RouterGroup) {
 
 func registerTherapistRoutes(auth *gin.RouterGroup) {
 	therapist := auth.Group("/therapist")
-	therapist.GET("", middleware.RequireRole(model.RoleAdmin, model.RoleTherapist), endpoint.ListTherapist)
-	therapist.GET("/:id", middleware.RequireRole(model.RoleAdmin, model.RoleTherapist), endpoint.GetTherapistInfo)
-	therapist.POST("", middleware.RequireRole(model.RoleAdmin), endpoint.CreateTherapist)
-	therapist.PATCH("/:id", middleware.RequireRole(model.RoleAdmin), endpoint.UpdateTherapist)
-	therapist.DELETE("/:id", middleware.RequireRole(model.RoleAdmin), endpoint.DeleteTherapist)
-	therapist.PUT("/:id", middleware.RequireRole(model.RoleAdmin), endpoint.TherapistApproval)
+	therapist.GET("", middleware.RequirePermission(model.PermissionViewTherapists), endpoint.ListTherapist)
+	therapist.GET("/nearby", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.ListNearbyTherapists)
+	therapist.GET("/stats", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.GetTherapistStats)
+	therapist.GET("/pending", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.ListPendingTherapists)
+	therapist.GET("/export", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.ExportTherapists)
+	therapist.GET("/me/caseload", middleware.RequirePermission(model.PermissionViewPatientHistory), endpoint.GetMyCaseload)
+	therapist.GET("/:id", middleware.RequirePermission(model.PermissionViewTherapists), endpoint.GetTherapistInfo)
+	therapist.GET("/:id/cadence", middleware.RequirePermission(model.PermissionViewTherapists), endpoint.GetTherapistCadence)
+	therapist.POST("/:id/schedules/recurring", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.CreateRecurringSchedules)
+	therapist.POST("/schedule/:id/cancel", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.CancelTherapistSchedule)
+	therapist.POST("", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.CreateTherapist)
+	therapist.POST("/bulk-approve", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.BulkApproveTherapists)
+	therapist.PATCH("/:id", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.UpdateTherapist)
+	therapist.DELETE("/:id", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.DeleteTherapist)
+	therapist.PUT("/:id", middleware.RequirePermission(model.PermissionManageTherapists), endpoint.TherapistApproval)
 }
 
 func registerEmployeeRoutes(auth *gin.RouterGroup) {
 	employee := auth.Group("/employee")
-	employee.Use(middleware.RequireRole(model.RoleAdmin))
+	employee.Use(middleware.RequirePermission(model.PermissionManageEmployees))
 	employee.GET("", endpoint.ListEmployees)
 	employee.POST("", endpoint.CreateEmployee)
 	employee.GET("/:id", endpoint.GetEmployeeInfo)
@@ -297,6 +386,41 @@ func registerEmployeeRoutes(auth *gin.RouterGroup) {
 	employee.DELETE("/:id", endpoint.DeleteEmployee)
 }
 
+func registerReportRoutes(auth *gin.RouterGroup) {
+	report := auth.Group("/report")
+	report.Use(middleware.RequirePermission(model.PermissionViewReports))
+	report.GET("/treatments-by-disease", endpoint.GetTreatmentsByDisease)
+	report.GET("/no-shows", endpoint.GetNoShowReport)
+	report.GET("/therapist-retention", endpoint.GetTherapistRetention)
+	report.GET("/treatment-trends", endpoint.GetTreatmentTrends)
+	report.GET("/revenue", endpoint.GetRevenueReport)
+	report.GET("/therapist-utilization", endpoint.GetTherapistUtilization)
+	report.GET("/therapist-activity", endpoint.GetTherapistActivity)
+
+	auth.GET("/activity", middleware.RequirePermission(model.PermissionViewReports), endpoint.ListActivity)
+	auth.GET("/analytics/issue-terms", middleware.RequirePermission(model.PermissionViewReports), endpoint.GetIssueTerms)
+}
+
+func registerClinicHoursRoutes(auth *gin.RouterGroup) {
+	clinicHours := auth.Group("/clinic-hours")
+	clinicHours.GET("", middleware.RequirePermission(model.PermissionViewClinicHours), endpoint.ListClinicHours)
+	clinicHours.PUT("", middleware.RequirePermission(model.PermissionManageClinicHours), endpoint.SetClinicHours)
+}
+
+func registerSecurityRoutes(auth *gin.RouterGroup) {
+	security := auth.Group("/security")
+	security.Use(middleware.RequirePermission(model.PermissionViewSecurity))
+	security.GET("/concurrent-geo-anomalies", endpoint.GetConcurrentGeoAnomalies)
+	auth.GET("/security-log/by-ip", middleware.RequirePermission(model.PermissionViewSecurity), endpoint.ListSecurityLogsByIP)
+}
+
+func registerAdminRoutes(auth *gin.RouterGroup) {
+	admin := auth.Group("/admin")
+	admin.Use(middleware.RequirePermission(model.PermissionViewSystemStatus))
+	admin.GET("/migration-status", endpoint.GetMigrationStatus)
+	admin.POST("/migrate", middleware.RequirePermission(model.PermissionManageSystem), endpoint.MigrateModel)
+}
+
 func createServer(cfg *config.Config, handler http.Handler) *http.Server {
 	address := fmt.Sprintf(":%d", cfg.AppPort)
 	return &http.Server{
@@ -313,16 +437,16 @@ func startServer(srv *http.Server) {
 	address := srv.Addr
 	enabled, cert, key := isTLSEnabled()
 	if enabled {
-		log.Printf("Starting HTTPS server on %s", address)
+		config.Logger().Info("Starting HTTPS server", "addr", address)
 		if err := srv.ListenAndServeTLS(cert, key); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("error starting HTTPS server: %v", err)
+			fatal("Error starting HTTPS server", err)
 		}
 		return
 	}
 
-	log.Printf("Starting HTTP server on %s", address)
+	config.Logger().Info("Starting HTTP server", "addr", address)
 	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("error starting HTTP server: %v", err)
+		fatal("Error starting HTTP server", err)
 	}
 }
 
@@ -347,15 +471,19 @@ func isTLSEnabled() (bool, string, string) {
 // initServices initializes optional runtime services like GeoIP, user cache, and Redis.
 func initServices() {
 	if err := util.InitGeoIP(os.Getenv("GEOIP_DB_PATH")); err != nil {
-		log.Printf("Warning: could not initialize GeoIP DB: %v", err)
+		config.Logger().Warn("Could not initialize GeoIP DB", "error", err)
+	}
+	if geoCfg, ok := util.GeoIPUpdateConfigFromEnv(); ok {
+		util.StartGeoIPUpdater(context.Background(), geoCfg)
+		config.Logger().Info("GeoIP auto-update enabled", "interval", geoCfg.Interval.String())
 	}
 
 	util.InitUserEmailCacheFromEnv()
 
 	if _, err := config.ConnectRedis(); err != nil {
-		log.Printf("Warning: could not connect to Redis: %v", err)
+		config.Logger().Warn("Could not connect to Redis", "error", err)
 	} else {
-		log.Println("Redis initialization complete")
+		config.Logger().Info("Redis initialization complete")
 	}
 }
 
@@ -363,21 +491,27 @@ func waitForShutdown(srv *http.Server, cfg *config.Config, db *gorm.DB) {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutdown signal received, shutting down server...")
+	config.Logger().Info("Shutdown signal received, shutting down server")
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout)*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Printf("Server forced to shutdown: %v", err)
+		config.Logger().Error("Server forced to shutdown", "error", err)
 	}
 
 	if sqlDB, err := db.DB(); err == nil {
 		if err := sqlDB.Close(); err != nil {
-			log.Printf("Error closing database: %v", err)
+			config.Logger().Error("Error closing database", "error", err)
 		}
 	} else {
-		log.Printf("Failed to get raw DB from GORM: %v", err)
+		config.Logger().Error("Failed to get raw DB from GORM", "error", err)
 	}
 
-	log.Println("Server exiting")
+	config.Logger().Info("Server exiting")
+}
+
+// fatal logs err at error level and exits, replacing log.Fatalf.
+func fatal(msg string, err error) {
+	config.Logger().Error(msg, "error", err)
+	os.Exit(1)
 }
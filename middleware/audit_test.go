@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/ariebrainware/basis-data-ltt/util"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAuditAction(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{http.MethodPost, "/patient", "create"},
+		{http.MethodPatch, "/user/:id", "update"},
+		{http.MethodPut, "/therapist/:id", "update"},
+		{http.MethodDelete, "/disease/:id", "delete"},
+		{http.MethodPost, "/patient/:id/transfer", "transfer"},
+		{http.MethodPost, "/therapist/bulk-approve", "bulk-approve"},
+	}
+	for _, tt := range tests {
+		if got := auditAction(tt.method, tt.path); got != tt.want {
+			t.Errorf("auditAction(%s, %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAuditAdminMutations(t *testing.T) {
+	var buf bytes.Buffer
+	originalLogger := util.GetSecurityLoggerForTest()
+	util.SetSecurityLoggerForTest(log.New(&buf, "[SECURITY] ", log.LstdFlags|log.Lmsgprefix))
+	defer util.SetSecurityLoggerForTest(originalLogger)
+
+	gin.SetMode(gin.TestMode)
+	newRouter := func(roleID uint32, status int) *gin.Engine {
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			c.Set(UserIDKey, uint(7))
+			c.Set(RoleIDKey, roleID)
+			c.Next()
+		}, AuditAdminMutations())
+		r.PATCH("/patient/:id", func(c *gin.Context) {
+			// The handler must still see the full body.
+			body, _ := io.ReadAll(c.Request.Body)
+			c.String(status, string(body))
+		})
+		return r
+	}
+
+	tests := []struct {
+		name      string
+		roleID    uint32
+		status    int
+		wantAudit bool
+	}{
+		{"admin success", model.RoleAdmin, http.StatusOK, true},
+		{"admin failure", model.RoleAdmin, http.StatusBadRequest, false},
+		{"non-admin", model.RoleTherapist, http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			body := `{"full_name":"New Name"}`
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPatch, "/patient/12", strings.NewReader(body))
+			newRouter(tt.roleID, tt.status).ServeHTTP(w, req)
+
+			if w.Body.String() != body {
+				t.Fatalf("handler body = %q, want %q", w.Body.String(), body)
+			}
+			logged := strings.Contains(buf.String(), "Event=ADMIN_ACTION")
+			if logged != tt.wantAudit {
+				t.Fatalf("audit logged = %v, want %v: %s", logged, tt.wantAudit, buf.String())
+			}
+			if tt.wantAudit && !strings.Contains(buf.String(), "Admin update patient 12") {
+				t.Fatalf("unexpected audit message: %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestReadAuditChangedFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name, body string
+		want       []string
+	}{
+		{"object", `{"password":"s3cret","profile":{"token":"abc"},"full_name":"Jane","email":"jane@example.com"}`, []string{"email", "full_name", "password", "profile"}},
+		{"array", `[{"password":"s3cret"}]`, nil},
+		{"invalid", `not json`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPatch, "/patient/1", strings.NewReader(tt.body))
+
+			got := readAuditChangedFields(c)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("readAuditChangedFields() = %v, want %v", got, tt.want)
+			}
+			if body, _ := io.ReadAll(c.Request.Body); string(body) != tt.body {
+				t.Fatalf("body not restored: %q", body)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Cache-Control values for read-only endpoints whose responses rarely change.
+// Routes without CacheControl send no Cache-Control header.
+const (
+	// PublicCacheControl lets browsers and shared proxies cache a response
+	// that is the same for every client.
+	PublicCacheControl = "public, max-age=300"
+	// PrivateCacheControl lets only the client cache a response that needs
+	// authentication, so proxies never serve it to someone else.
+	PrivateCacheControl = "private, max-age=300"
+)
+
+// cacheControlWriter adds the Cache-Control header right before the headers
+// are sent, once the final status is known.
+type cacheControlWriter struct {
+	gin.ResponseWriter
+	value string
+}
+
+// setHeader marks a 2xx response cacheable unless the headers are already
+// sent or the handler chose its own Cache-Control.
+func (w *cacheControlWriter) setHeader() {
+	if w.Written() || w.Status() < http.StatusOK || w.Status() >= http.StatusMultipleChoices {
+		return
+	}
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", w.value)
+	}
+}
+
+func (w *cacheControlWriter) WriteHeaderNow() {
+	w.setHeader()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *cacheControlWriter) Write(data []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *cacheControlWriter) WriteString(s string) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *cacheControlWriter) Flush() {
+	w.setHeader()
+	w.ResponseWriter.Flush()
+}
+
+// CacheControl sets the Cache-Control header of a route's response to value
+// when the route answers with a 2xx status. Errors and rejected requests,
+// whether from the handler or earlier middleware, are never marked cacheable.
+// A Cache-Control header set by the handler itself is kept.
+func CacheControl(value string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		original := c.Writer
+		w := &cacheControlWriter{ResponseWriter: original, value: value}
+		c.Writer = w
+		defer func() { c.Writer = original }()
+		c.Next()
+		// A response without a body is sent after the chain returns.
+		w.setHeader()
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCacheControl_StaticAndDynamicRoutes(t *testing.T) {
+	r := gin.New()
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) }
+	r.GET("/version", CacheControl(PublicCacheControl), ok)
+	r.GET("/patient", ok)
+
+	protected := r.Group("/")
+	protected.Use(func(c *gin.Context) {
+		if c.GetHeader("session-token") == "" {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+		c.Next()
+	})
+	protected.GET("/role/constants", CacheControl(PrivateCacheControl), ok)
+	r.GET("/missing", CacheControl(PublicCacheControl), func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+	r.GET("/broken", CacheControl(PublicCacheControl), func(c *gin.Context) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+	r.GET("/empty", CacheControl(PublicCacheControl), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	r.GET("/custom", CacheControl(PublicCacheControl), func(c *gin.Context) {
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+		want   string
+	}{
+		{"public static", "/version", "", http.StatusOK, PublicCacheControl},
+		{"authenticated static", "/role/constants", "token", http.StatusOK, PrivateCacheControl},
+		{"rejected before handler", "/role/constants", "", http.StatusUnauthorized, ""},
+		{"dynamic", "/patient", "", http.StatusOK, ""},
+		{"handler client error", "/missing", "", http.StatusNotFound, ""},
+		{"handler server error", "/broken", "", http.StatusInternalServerError, ""},
+		{"no body", "/empty", "", http.StatusNoContent, PublicCacheControl},
+		{"handler override", "/custom", "", http.StatusOK, "no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("session-token", tt.token)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d", tt.status, w.Code)
+			}
+			if got := w.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("expected Cache-Control %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -2,8 +2,11 @@ package middleware
 
 import (
 	"fmt"
+	"log/slog"
+	"net/http"
 	"time"
 
+	"github.com/ariebrainware/basis-data-ltt/config"
 	"github.com/ariebrainware/basis-data-ltt/util"
 	"github.com/gin-gonic/gin"
 )
@@ -36,6 +39,9 @@ func EndpointCallLogger() gin.HandlerFunc {
 		if roleID != 0 {
 			details["role_id"] = roleID
 		}
+		if requestID, ok := GetRequestID(c); ok {
+			details["request_id"] = requestID
+		}
 
 		// Attempt to fetch the user's email when userID is present. Use in-memory cache.
 		email := ""
@@ -56,3 +62,36 @@ func EndpointCallLogger() gin.HandlerFunc {
 		})
 	}
 }
+
+// RequestLogger writes one line per request to the application logger,
+// replacing gin's default access log. Successful requests are logged at info,
+// client errors at warn and server errors at error, so LOG_LEVEL=warn keeps
+// only failed requests. The duration is also recorded in the per-route
+// latency metrics. The line carries the request ID when RequestID runs first.
+func RequestLogger() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		duration := time.Since(start)
+		status := c.Writer.Status()
+		util.RecordRouteLatency(c.Request.Method, c.FullPath(), duration)
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		requestID, _ := GetRequestID(c)
+		config.Logger().Log(c.Request.Context(), level, "HTTP request",
+			"request_id", requestID,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", status,
+			"duration_ms", duration.Milliseconds(),
+			"ip", c.ClientIP(),
+		)
+	}
+}
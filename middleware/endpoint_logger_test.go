@@ -3,11 +3,13 @@ package middleware
 import (
 	"bytes"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/ariebrainware/basis-data-ltt/config"
 	"github.com/ariebrainware/basis-data-ltt/util"
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/sqlite"
@@ -247,3 +249,36 @@ func TestEndpointCallLogger_POSTRequest(t *testing.T) {
 		t.Error("Expected log to contain POST method and status 201")
 	}
 }
+
+func TestRequestLogger_LevelByStatus(t *testing.T) {
+	var buf bytes.Buffer
+	prevLevel := config.LogLevel()
+	prev := config.SetLogger(config.NewLogger(&buf))
+	config.SetLogLevel(slog.LevelWarn)
+	defer func() {
+		config.SetLogger(prev)
+		config.SetLogLevel(prevLevel)
+	}()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestLogger())
+	r.GET("/ok", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/missing", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	for _, path := range []string{"/ok", "/missing", "/fail"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	out := buf.String()
+	if strings.Contains(out, "path=/ok") {
+		t.Errorf("expected successful request to be suppressed at warn level, got:\n%s", out)
+	}
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "path=/missing status=404") {
+		t.Errorf("expected 404 logged at warn, got:\n%s", out)
+	}
+	if !strings.Contains(out, "level=ERROR") || !strings.Contains(out, "path=/fail status=500") {
+		t.Errorf("expected 500 logged at error, got:\n%s", out)
+	}
+}
@@ -0,0 +1,220 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const defaultGzipMinSize = 1024
+
+// incompressibleContentTypes are media types that are already compressed, so
+// gzip would only cost CPU. Matched by prefix.
+var incompressibleContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/octet-stream",
+}
+
+// gzipMinSize is the smallest response body worth compressing: GZIP_MIN_SIZE
+// bytes, or 1024 when unset or invalid.
+func gzipMinSize() int {
+	if v, err := strconv.Atoi(os.Getenv("GZIP_MIN_SIZE")); err == nil && v >= 0 {
+		return v
+	}
+	return defaultGzipMinSize
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, either
+// by name or through "*", and does not refuse it with q=0.
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		coding := strings.ToLower(strings.TrimSpace(fields[0]))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		refused := false
+		for _, param := range fields[1:] {
+			if q, ok := strings.CutPrefix(strings.ReplaceAll(param, " ", ""), "q="); ok {
+				if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+					refused = true
+				}
+			}
+		}
+		if !refused {
+			return true
+		}
+	}
+	return false
+}
+
+// isCompressible reports whether a response with these headers and status
+// may be gzipped.
+func isCompressible(header http.Header, status int) bool {
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, prefix := range incompressibleContentTypes {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// gzipResponseWriter holds back the status and the first minSize bytes of
+// the body, then either streams the rest through gzip or writes it as is.
+// Bodies that never reach minSize are sent uncompressed.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	minSize int
+	status  int
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+	started bool
+}
+
+func (w *gzipResponseWriter) WriteHeader(code int) {
+	if !w.started {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow is deferred until the body decides whether to compress.
+func (w *gzipResponseWriter) WriteHeaderNow() {}
+
+func (w *gzipResponseWriter) Status() int {
+	if !w.started {
+		return w.status
+	}
+	return w.ResponseWriter.Status()
+}
+
+func (w *gzipResponseWriter) Written() bool {
+	return w.started || w.buf.Len() > 0
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if w.started {
+		if w.gz != nil {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+	w.buf.Write(data)
+	if w.buf.Len() >= w.minSize {
+		if err := w.start(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// start sends the headers and the buffered body, compressing when
+// compress is set and the response allows it.
+func (w *gzipResponseWriter) start(compress bool) error {
+	w.started = true
+	header := w.ResponseWriter.Header()
+	if compress && isCompressible(header, w.status) {
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	var err error
+	if w.gz != nil {
+		_, err = w.gz.Write(w.buf.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf.Bytes())
+	}
+	w.buf.Reset()
+	return err
+}
+
+// Flush sends what has been written so far, compressing it if the
+// response is already large enough.
+func (w *gzipResponseWriter) Flush() {
+	if !w.started {
+		_ = w.start(w.buf.Len() >= w.minSize)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.started = true
+	return w.ResponseWriter.Hijack()
+}
+
+// finish sends a body that stayed below minSize and closes the gzip stream.
+func (w *gzipResponseWriter) finish() {
+	if !w.started {
+		_ = w.start(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+	}
+}
+
+// GzipMiddleware compresses responses of at least GZIP_MIN_SIZE bytes (default
+// 1024) for clients that accept gzip, when GZIP_ENABLED=true. Responses that
+// are already encoded or have a compressed media type such as images or zip
+// archives are sent as is. A handler panic is passed on untouched so an outer
+// Recovery middleware sets the status.
+func GzipMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if os.Getenv("GZIP_ENABLED") != "true" {
+			c.Next()
+			return
+		}
+		c.Header("Vary", "Accept-Encoding")
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		original := c.Writer
+		w := &gzipResponseWriter{ResponseWriter: original, minSize: gzipMinSize(), status: http.StatusOK}
+		c.Writer = w
+		defer func() {
+			// On a panic, drop the held-back response and hand the original
+			// writer back so Recovery can still answer with a 500.
+			if err := recover(); err != nil {
+				c.Writer = original
+				panic(err)
+			}
+			w.finish()
+			c.Writer = original
+		}()
+		c.Next()
+	}
+}
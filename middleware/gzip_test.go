@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func gzipTestRouter(body []byte, contentType string) *gin.Engine {
+	r := gin.New()
+	r.Use(GzipMiddleware())
+	r.GET("/data", func(c *gin.Context) {
+		c.Data(http.StatusOK, contentType, body)
+	})
+	return r
+}
+
+func doGzipRequest(r *gin.Engine, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/data", nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGzipMiddleware_CompressesLargeResponses(t *testing.T) {
+	t.Setenv("GZIP_ENABLED", "true")
+	t.Setenv("GZIP_MIN_SIZE", "")
+
+	body := []byte(`{"data":"` + strings.Repeat("a", 4096) + `"}`)
+	w := doGzipRequest(gzipTestRouter(body, "application/json"), "deflate, gzip;q=0.8")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	if ce := w.Header().Get("Content-Encoding"); ce != "gzip" {
+		t.Fatalf("expected gzip content encoding, got %q", ce)
+	}
+	if vary := w.Header().Get("Vary"); vary != "Accept-Encoding" {
+		t.Errorf("expected Vary: Accept-Encoding, got %q", vary)
+	}
+	if w.Body.Len() >= len(body) {
+		t.Errorf("expected compressed body smaller than %d bytes, got %d", len(body), w.Body.Len())
+	}
+
+	zr, err := gzip.NewReader(w.Body)
+	if err != nil {
+		t.Fatalf("gzip reader: %v", err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("decompress: %v", err)
+	}
+	if string(got) != string(body) {
+		t.Errorf("decompressed body does not match the original")
+	}
+}
+
+func TestGzipMiddleware_SkipsUncompressibleResponses(t *testing.T) {
+	t.Setenv("GZIP_ENABLED", "true")
+	t.Setenv("GZIP_MIN_SIZE", "1024")
+
+	large := []byte(strings.Repeat("a", 4096))
+	tests := []struct {
+		name           string
+		body           []byte
+		contentType    string
+		acceptEncoding string
+	}{
+		{"below minimum size", []byte(`{"ok":true}`), "application/json", "gzip"},
+		{"client does not accept gzip", large, "application/json", ""},
+		{"gzip refused", large, "application/json", "gzip;q=0"},
+		{"already compressed type", large, "image/png", "gzip"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doGzipRequest(gzipTestRouter(tt.body, tt.contentType), tt.acceptEncoding)
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d", w.Code)
+			}
+			if ce := w.Header().Get("Content-Encoding"); ce != "" {
+				t.Errorf("expected no content encoding, got %q", ce)
+			}
+			if w.Body.String() != string(tt.body) {
+				t.Errorf("expected the body unchanged")
+			}
+		})
+	}
+}
+
+func TestGzipMiddleware_Disabled(t *testing.T) {
+	t.Setenv("GZIP_ENABLED", "")
+
+	body := []byte(strings.Repeat("a", 4096))
+	w := doGzipRequest(gzipTestRouter(body, "text/plain"), "gzip")
+	if ce := w.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("expected no content encoding when disabled, got %q", ce)
+	}
+	if vary := w.Header().Get("Vary"); vary != "" {
+		t.Errorf("expected no Vary header when disabled, got %q", vary)
+	}
+	if w.Body.String() != string(body) {
+		t.Errorf("expected the body unchanged")
+	}
+}
+
+func TestGzipMiddleware_PanicLeavesStatusToRecovery(t *testing.T) {
+	t.Setenv("GZIP_ENABLED", "true")
+	t.Setenv("GZIP_MIN_SIZE", "")
+
+	r := gin.New()
+	r.Use(gin.CustomRecoveryWithWriter(io.Discard, gin.RecoveryFunc(func(c *gin.Context, _ any) {
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})), GzipMiddleware())
+	r.GET("/data", func(c *gin.Context) {
+		c.Writer.WriteString("partial")
+		panic("boom")
+	})
+	w := doGzipRequest(r, "gzip")
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500, got %d", w.Code)
+	}
+	if ce := w.Header().Get("Content-Encoding"); ce != "" {
+		t.Errorf("expected no content encoding, got %q", ce)
+	}
+	if strings.Contains(w.Body.String(), "partial") {
+		t.Errorf("expected the held-back body to be dropped, got %q", w.Body.String())
+	}
+}
@@ -7,9 +7,11 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/ariebrainware/basis-data-ltt/model"
 	"github.com/ariebrainware/basis-data-ltt/util"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
@@ -122,36 +124,60 @@ func tryParseRedisSession(val string) (uint, uint32, bool) {
 	return uint(uid64), uint32(rid64), true
 }
 
-func setCorsHeaders(c *gin.Context) {
-	origin := c.Request.Header.Get("Origin")
-	allowedOriginSetting := getenvOrDefault("CORSALLOWORIGIN", "http://localhost:3000")
-	var allowedOrigin string
+const (
+	defaultCORSAllowHeaders = "X-Requested-With, Content-Type, Authorization, session-token, X-Clinic-Scope, X-Request-ID, traceparent, Origin, Accept, Access-Control-Request-Method, Access-Control-Request-Headers"
+	defaultCORSMaxAge       = "86400"
+)
 
-	if allowedOriginSetting == "*" {
-		if getenvOrDefault("CORSALLOWCREDENTIALS", "true") == "true" {
-			allowedOrigin = origin
-		} else {
-			allowedOrigin = "*"
+// corsAllowHeaders returns the CORSALLOWHEADERS list (comma-separated) or the
+// default list. The session-token header is always included because every
+// authenticated request sends it.
+func corsAllowHeaders() string {
+	var headers []string
+	hasSessionToken := false
+	for _, h := range strings.Split(getenvOrDefault("CORSALLOWHEADERS", defaultCORSAllowHeaders), ",") {
+		h = strings.TrimSpace(h)
+		if h == "" {
+			continue
 		}
-	} else {
-		allowedOrigins := strings.Split(allowedOriginSetting, ",")
-		for _, o := range allowedOrigins {
-			o = strings.TrimSpace(o)
-			if o == origin {
-				allowedOrigin = origin
-				break
-			}
-		}
-		if allowedOrigin == "" && len(allowedOrigins) > 0 {
-			allowedOrigin = strings.TrimSpace(allowedOrigins[0])
+		if strings.EqualFold(h, "session-token") {
+			hasSessionToken = true
 		}
+		headers = append(headers, h)
+	}
+	if !hasSessionToken {
+		headers = append(headers, "session-token")
 	}
+	return strings.Join(headers, ", ")
+}
+
+// corsMaxAge returns how many seconds browsers may cache a preflight response:
+// CORSMAXAGE when it is a non-negative integer, 86400 otherwise.
+func corsMaxAge() string {
+	value := strings.TrimSpace(os.Getenv("CORSMAXAGE"))
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return strconv.Itoa(seconds)
+	}
+	return defaultCORSMaxAge
+}
+
+func setCorsHeaders(c *gin.Context) {
+	allowedOrigin := corsAllowedOrigin(c.Request.Header.Get("Origin"))
 
 	c.Writer.Header().Set("Access-Control-Allow-Origin", allowedOrigin)
 	c.Writer.Header().Set("Access-Control-Allow-Methods", getenvOrDefault("CORSALLOWMETHODS", "POST, PUT, GET, OPTIONS, DELETE, PATCH"))
-	c.Writer.Header().Set("Access-Control-Allow-Headers", getenvOrDefault("CORSALLOWHEADERS", "X-Requested-With, Content-Type, Authorization, session-token, Origin, Accept, Access-Control-Request-Method, Access-Control-Request-Headers"))
-	c.Writer.Header().Set("Access-Control-Max-Age", getenvOrDefault("CORSMAXAGE", "86400"))
-	c.Writer.Header().Set("Access-Control-Allow-Credentials", getenvOrDefault("CORSALLOWCREDENTIALS", "true"))
+	c.Writer.Header().Set("Access-Control-Allow-Headers", corsAllowHeaders())
+	c.Writer.Header().Set("Access-Control-Max-Age", corsMaxAge())
+	c.Writer.Header().Set("Access-Control-Expose-Headers", RequestIDHeader)
+	if corsAllowCredentials() {
+		if allowedOrigin == "*" {
+			corsWildcardCredentialsWarning.Do(func() {
+				config.Logger().Warn("CORSALLOWCREDENTIALS is ignored because CORSALLOWORIGIN is \"*\"; list the allowed origins to accept credentialed requests")
+			})
+		} else {
+			c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+	}
 	c.Writer.Header().Set("Content-Type", getenvOrDefault("CORSCONTENTTYPE", "application/json"))
 
 	// Add HSTS header for HTTPS security. Only set when TLS is present or explicitly enabled
@@ -160,6 +186,35 @@ func setCorsHeaders(c *gin.Context) {
 	}
 }
 
+// corsWildcardCredentialsWarning logs once that credentials are not allowed
+// with a wildcard origin.
+var corsWildcardCredentialsWarning sync.Once
+
+// corsAllowCredentials reports whether credentialed requests are allowed:
+// CORSALLOWCREDENTIALS, default true.
+func corsAllowCredentials() bool {
+	return getenvOrDefault("CORSALLOWCREDENTIALS", "true") == "true"
+}
+
+// corsAllowedOrigin returns the Access-Control-Allow-Origin value for a request
+// from origin. With CORSALLOWORIGIN "*" it is "*": reflecting the origin
+// instead would let any site make credentialed requests. Otherwise it is origin
+// when listed in CORSALLOWORIGIN (comma-separated), and the first listed
+// origin when not, which the browser then rejects.
+func corsAllowedOrigin(origin string) string {
+	setting := getenvOrDefault("CORSALLOWORIGIN", "http://localhost:3000")
+	if strings.TrimSpace(setting) == "*" {
+		return "*"
+	}
+	allowedOrigins := strings.Split(setting, ",")
+	for _, o := range allowedOrigins {
+		if strings.TrimSpace(o) == origin {
+			return origin
+		}
+	}
+	return strings.TrimSpace(allowedOrigins[0])
+}
+
 // CORSMiddleware configures CORS headers for incoming requests.
 func CORSMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -233,6 +288,12 @@ func RequireRole(allowedRoles ...uint32) gin.HandlerFunc {
 	}
 }
 
+// RequirePermission allows access to users whose role is granted p in the
+// model's permission table.
+func RequirePermission(p model.Permission) gin.HandlerFunc {
+	return RequireRole(model.PermissionRoles(p)...)
+}
+
 // RequireRoleOrOwner allows access when the user's role is one of the
 // allowedRoles OR when the authenticated user is the owner of the
 // resource identified by the URL parameter `id`.
@@ -279,6 +340,96 @@ func RequireRoleOrOwner(allowedRoles ...uint32) gin.HandlerFunc {
 	}
 }
 
+var (
+	apiTokenScopesMu sync.RWMutex
+	apiTokenScopes   map[string][]string
+)
+
+// parseAPITokenScopes parses an API_TOKEN_SCOPES value into a session token ->
+// allowed route prefixes map. The format is
+// "token=/prefix,/prefix;token2=/prefix". Entries without a token or prefix,
+// and tokens listed twice, are errors.
+func parseAPITokenScopes(raw string) (map[string][]string, error) {
+	scopes := make(map[string][]string)
+	if strings.TrimSpace(raw) == "" {
+		return scopes, nil
+	}
+	for i, entry := range strings.Split(raw, ";") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		token, prefixList, ok := strings.Cut(entry, "=")
+		token = strings.TrimSpace(token)
+		if !ok || token == "" {
+			return nil, fmt.Errorf("API_TOKEN_SCOPES entry %d: expected token=/prefix", i+1)
+		}
+		if _, dup := scopes[token]; dup {
+			return nil, fmt.Errorf("API_TOKEN_SCOPES entry %d: token listed more than once", i+1)
+		}
+		var prefixes []string
+		for _, prefix := range strings.Split(prefixList, ",") {
+			prefix = strings.TrimRight(strings.TrimSpace(prefix), "/")
+			if prefix == "" {
+				continue
+			}
+			if !strings.HasPrefix(prefix, "/") {
+				prefix = "/" + prefix
+			}
+			prefixes = append(prefixes, prefix)
+		}
+		if len(prefixes) == 0 {
+			return nil, fmt.Errorf("API_TOKEN_SCOPES entry %d: no route prefixes", i+1)
+		}
+		scopes[token] = prefixes
+	}
+	return scopes, nil
+}
+
+// LoadAPITokenScopes reads API_TOKEN_SCOPES once, at startup, restricting
+// the listed session tokens to route prefixes; tokens not listed are
+// unscoped. A malformed value is an error so a typo cannot silently leave a
+// token with full access.
+func LoadAPITokenScopes() error {
+	scopes, err := parseAPITokenScopes(os.Getenv("API_TOKEN_SCOPES"))
+	if err != nil {
+		return err
+	}
+	apiTokenScopesMu.Lock()
+	apiTokenScopes = scopes
+	apiTokenScopesMu.Unlock()
+	return nil
+}
+
+// tokenPathAllowed reports whether path falls under one of the prefixes.
+// A prefix matches the exact path or any sub-path, so "/patient" allows
+// "/patient/1" but not "/patients".
+func tokenPathAllowed(prefixes []string, path string) bool {
+	for _, prefix := range prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// enforceTokenScope aborts with 403 when token is scoped and the request
+// path is outside its allowed prefixes. Unscoped tokens always pass.
+func enforceTokenScope(c *gin.Context, token string) bool {
+	apiTokenScopesMu.RLock()
+	prefixes, scoped := apiTokenScopes[token]
+	apiTokenScopesMu.RUnlock()
+	if !scoped || tokenPathAllowed(prefixes, c.Request.URL.Path) {
+		return true
+	}
+	logUnauthorizedWithUser(c, "Token used outside its allowed scope")
+	util.CallForbidden(c, util.APIErrorParams{
+		Msg: "Token is not allowed to access this resource",
+		Err: fmt.Errorf("token scope does not include %s", c.Request.URL.Path),
+	})
+	c.Abort()
+	return false
+}
+
 func ValidateLoginToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		db := GetDB(c)
@@ -301,6 +452,9 @@ func ValidateLoginToken() gin.HandlerFunc {
 				if uid, rid, ok := tryParseRedisSession(val); ok {
 					c.Set(UserIDKey, uid)
 					c.Set(RoleIDKey, rid)
+					if !enforceTokenScope(c, sessionToken) {
+						return
+					}
 					c.Next()
 					return
 				}
@@ -331,6 +485,9 @@ func ValidateLoginToken() gin.HandlerFunc {
 		// Store user_id and role_id in context for use in handlers
 		c.Set(UserIDKey, result.UserID)
 		c.Set(RoleIDKey, result.RoleID)
+		if !enforceTokenScope(c, sessionToken) {
+			return
+		}
 		c.Next()
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -260,10 +261,8 @@ func TestCORSMiddleware_MultipleOrigins(t *testing.T) {
 }
 
 func TestCORSMiddleware_WildcardOrigin(t *testing.T) {
-	os.Setenv("CORSALLOWORIGIN", "*")
-	os.Setenv("CORSALLOWCREDENTIALS", "true")
-	defer os.Unsetenv("CORSALLOWORIGIN")
-	defer os.Unsetenv("CORSALLOWCREDENTIALS")
+	t.Setenv("CORSALLOWORIGIN", "*")
+	t.Setenv("CORSALLOWCREDENTIALS", "true")
 
 	gin.SetMode(gin.ReleaseMode)
 
@@ -274,13 +273,120 @@ func TestCORSMiddleware_WildcardOrigin(t *testing.T) {
 		c.Status(200)
 	})
 
-	req := httptest.NewRequest("GET", "/test", nil)
+	req := httptest.NewRequest("OPTIONS", "/test", nil)
 	req.Header.Set("Origin", "https://internal.leetittar.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
 	r.ServeHTTP(w, req)
 
-	// Since CORSALLOWCREDENTIALS is true, wildcard "*" should echo the incoming Origin
-	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://internal.leetittar.com" {
-		t.Errorf("expected matched Origin for wildcard with credentials, got %q", got)
+	// A wildcard must not be combined with credentials, and reflecting the
+	// Origin instead would allow credentialed requests from any site.
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("expected wildcard Access-Control-Allow-Origin, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("expected no Access-Control-Allow-Credentials with a wildcard origin, got %q", got)
+	}
+}
+
+func TestCORSMiddleware_CredentialedPreflight(t *testing.T) {
+	t.Setenv("CORSALLOWORIGIN", "https://app.leetittar.com, http://localhost:3000")
+	t.Setenv("CORSALLOWCREDENTIALS", "")
+	t.Setenv("CORSMAXAGE", "600")
+
+	gin.SetMode(gin.ReleaseMode)
+
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(CORSMiddleware())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/test", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected preflight response code 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:3000" {
+		t.Errorf("expected the listed request origin, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("expected credentials allowed by default for a listed origin, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("expected preflight cached for 600 seconds, got %q", got)
+	}
+}
+
+func TestCORSMiddleware_CredentialsDisabled(t *testing.T) {
+	t.Setenv("CORSALLOWORIGIN", "http://localhost:3000")
+	t.Setenv("CORSALLOWCREDENTIALS", "false")
+
+	w := runCORSPreflight(t)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "http://localhost:3000" {
+		t.Errorf("expected the listed request origin, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("expected no Access-Control-Allow-Credentials when disabled, got %q", got)
+	}
+}
+
+func runCORSPreflight(t *testing.T) *httptest.ResponseRecorder {
+	t.Helper()
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(CORSMiddleware())
+	r.GET("/test", func(c *gin.Context) {
+		c.Status(200)
+	})
+
+	req := httptest.NewRequest("OPTIONS", "/test", nil)
+	req.Header.Set("Origin", "http://localhost:3000")
+	req.Header.Set("Access-Control-Request-Headers", "session-token, Idempotency-Key")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected preflight response code 200, got %d", w.Code)
+	}
+	return w
+}
+
+func TestCORSMiddleware_ConfiguredHeadersAndMaxAge(t *testing.T) {
+	t.Setenv("CORSALLOWHEADERS", "Content-Type, Idempotency-Key")
+	t.Setenv("CORSMAXAGE", "600")
+
+	w := runCORSPreflight(t)
+
+	// session-token is appended because authenticated requests depend on it.
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, Idempotency-Key, session-token" {
+		t.Errorf("unexpected Access-Control-Allow-Headers %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != "600" {
+		t.Errorf("expected Access-Control-Max-Age 600, got %q", got)
+	}
+}
+
+func TestCORSMiddleware_HeaderDefaults(t *testing.T) {
+	t.Setenv("CORSALLOWHEADERS", "")
+	t.Setenv("CORSMAXAGE", "not-a-number")
+
+	w := runCORSPreflight(t)
+
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != defaultCORSAllowHeaders {
+		t.Errorf("expected default Access-Control-Allow-Headers, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Max-Age"); got != defaultCORSMaxAge {
+		t.Errorf("expected default Access-Control-Max-Age for an invalid value, got %q", got)
+	}
+}
+
+func TestCorsAllowHeaders_KeepsConfiguredSessionToken(t *testing.T) {
+	t.Setenv("CORSALLOWHEADERS", "Session-Token,Authorization")
+
+	if got := corsAllowHeaders(); got != "Session-Token, Authorization" {
+		t.Errorf("expected configured session-token to be kept once, got %q", got)
 	}
 }
 
@@ -456,3 +562,115 @@ func TestValidateLoginToken_DBFallback_ExpiredSession(t *testing.T) {
 		t.Fatalf("expected 401 when session is expired, got %d", w.Code)
 	}
 }
+
+func runScopedTokenRequest(db *gorm.DB, token, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	_, r := gin.CreateTestContext(w)
+	r.Use(DatabaseMiddleware(db))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/patient/:id", ValidateLoginToken(), ok)
+	r.GET("/patients", ValidateLoginToken(), ok)
+	r.GET("/report/summary", ValidateLoginToken(), ok)
+	req := httptest.NewRequest("GET", path, nil)
+	req.Header.Set("session-token", token)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// setTokenScopes loads API_TOKEN_SCOPES as startup would and clears it after
+// the test.
+func setTokenScopes(t *testing.T, value string) {
+	t.Helper()
+	t.Setenv("API_TOKEN_SCOPES", value)
+	if err := LoadAPITokenScopes(); err != nil {
+		t.Fatalf("LoadAPITokenScopes: %v", err)
+	}
+	t.Cleanup(func() {
+		apiTokenScopesMu.Lock()
+		apiTokenScopes = nil
+		apiTokenScopesMu.Unlock()
+	})
+}
+
+func TestValidateLoginToken_TokenScopes(t *testing.T) {
+	config.ResetRedisClientForTest()
+	defer config.ResetRedisClientForTest()
+	db, user, _ := newTestDBWithUserSession(t, testSessionParams{roleID: 1, token: "scoped-token"})
+	setTokenScopes(t, "scoped-token=/patient, /report/;other-token=/user")
+
+	cases := []struct {
+		name string
+		path string
+		want int
+	}{
+		{name: "in_scope", path: "/patient/1", want: http.StatusOK},
+		{name: "in_scope_second_prefix", path: "/report/summary", want: http.StatusOK},
+		{name: "prefix_is_not_substring_match", path: "/patients", want: http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			w := runScopedTokenRequest(db, "scoped-token", tc.path)
+			if w.Code != tc.want {
+				t.Fatalf("expected %d for %s, got %d", tc.want, tc.path, w.Code)
+			}
+		})
+	}
+
+	// The scope belongs to the token, so the same user's other session keeps
+	// full access.
+	second := model.Session{SessionToken: "second-token", UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := db.Create(&second).Error; err != nil {
+		t.Fatalf("create session: %v", err)
+	}
+	if w := runScopedTokenRequest(db, "second-token", "/patients"); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for the user's unscoped second session, got %d", w.Code)
+	}
+}
+
+func TestValidateLoginToken_UnscopedTokenHasFullAccess(t *testing.T) {
+	config.ResetRedisClientForTest()
+	defer config.ResetRedisClientForTest()
+	db, _, _ := newTestDBWithUserSession(t, testSessionParams{roleID: 1, token: "full-token"})
+	setTokenScopes(t, "other-token=/user")
+
+	for _, path := range []string{"/patient/1", "/patients", "/report/summary"} {
+		if w := runScopedTokenRequest(db, "full-token", path); w.Code != http.StatusOK {
+			t.Fatalf("expected 200 for unscoped token on %s, got %d", path, w.Code)
+		}
+	}
+}
+
+func TestValidateLoginToken_ScopedTokenStillRequiresValidSession(t *testing.T) {
+	config.ResetRedisClientForTest()
+	defer config.ResetRedisClientForTest()
+	setTokenScopes(t, "unknown-token=/patient")
+
+	db := newInMemoryDB(t)
+	if w := runScopedTokenRequest(db, "unknown-token", "/patient/1"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 for scoped but invalid token, got %d", w.Code)
+	}
+}
+
+func TestParseAPITokenScopes(t *testing.T) {
+	scopes, err := parseAPITokenScopes(" a=/patient/ , report ; b=/user;")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(scopes["a"], ","); got != "/patient,/report" {
+		t.Errorf("unexpected prefixes for a: %q", got)
+	}
+	if got := strings.Join(scopes["b"], ","); got != "/user" {
+		t.Errorf("unexpected prefixes for b: %q", got)
+	}
+
+	for _, raw := range []string{"no-equals", "=/patient", "a=", "a= , ", "a=/x;a=/y"} {
+		if _, err := parseAPITokenScopes(raw); err == nil {
+			t.Errorf("expected an error for %q", raw)
+		}
+	}
+
+	t.Setenv("API_TOKEN_SCOPES", "broken")
+	if err := LoadAPITokenScopes(); err == nil {
+		t.Errorf("expected LoadAPITokenScopes to reject a malformed value")
+	}
+}
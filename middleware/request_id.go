@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// RequestIDKey is the context key holding the request's correlation ID.
+	RequestIDKey = "request_id"
+	// RequestIDHeader carries the correlation ID on requests and responses.
+	RequestIDHeader = "X-Request-ID"
+	// TraceparentHeader is the W3C trace context header.
+	TraceparentHeader = "traceparent"
+
+	maxRequestIDLength = 128
+)
+
+// RequestID gives every request a correlation ID and echoes it in the
+// X-Request-ID response header. An incoming X-Request-ID is reused when it is
+// well formed; otherwise the trace ID of a valid traceparent header is used,
+// so logs line up with the caller's trace. A random ID is generated only when
+// neither is present. Set REQUEST_ID_TRUST_INCOMING=false to ignore incoming
+// headers and always generate one. Register it before the loggers so they
+// can include the ID.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := ""
+		if requestIDTrustIncoming() {
+			id = incomingRequestID(c)
+		}
+		if id == "" {
+			id = newRequestID()
+		}
+
+		c.Set(RequestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID retrieves the request's correlation ID from the Gin context
+func GetRequestID(c *gin.Context) (string, bool) {
+	return getTypedValueFromContext[string](c, RequestIDKey)
+}
+
+// requestIDTrustIncoming reports whether caller-supplied IDs are accepted.
+// It defaults to true.
+func requestIDTrustIncoming() bool {
+	v := strings.TrimSpace(os.Getenv("REQUEST_ID_TRUST_INCOMING"))
+	if v == "" {
+		return true
+	}
+	trust, err := strconv.ParseBool(v)
+	return err != nil || trust
+}
+
+// incomingRequestID returns the caller's X-Request-ID, or the trace ID of its
+// traceparent, or "" when neither is usable.
+func incomingRequestID(c *gin.Context) string {
+	if id := strings.TrimSpace(c.GetHeader(RequestIDHeader)); validRequestID(id) {
+		return id
+	}
+	return traceIDFromTraceparent(c.GetHeader(TraceparentHeader))
+}
+
+// validRequestID accepts up to maxRequestIDLength letters, digits and
+// "-_.:", which keeps caller-supplied IDs safe to log and echo.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// traceIDFromTraceparent extracts the trace ID from a version 00 W3C
+// traceparent ("00-<32 hex trace id>-<16 hex parent id>-<2 hex flags>").
+// All-zero IDs are invalid per the spec and yield "".
+func traceIDFromTraceparent(header string) string {
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 || parts[0] != "00" {
+		return ""
+	}
+	traceID, parentID, flags := parts[1], parts[2], parts[3]
+	if !isLowerHex(traceID, 32) || !isLowerHex(parentID, 16) || !isLowerHex(flags, 2) {
+		return ""
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(parentID, "0") == "" {
+		return ""
+	}
+	return traceID
+}
+
+func isLowerHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, r := range s {
+		if (r < '0' || r > '9') && (r < 'a' || r > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns 16 random bytes, hex encoded.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/gin-gonic/gin"
+)
+
+// runRequestIDRequest serves one request through RequestID and returns the
+// response together with the ID the handler saw in the context.
+func runRequestIDRequest(t *testing.T, headers map[string]string) (*httptest.ResponseRecorder, string) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID())
+	var seen string
+	r.GET("/test", func(c *gin.Context) {
+		seen, _ = GetRequestID(c)
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w, seen
+}
+
+func TestRequestID_PassesThroughIncomingID(t *testing.T) {
+	w, seen := runRequestIDRequest(t, map[string]string{RequestIDHeader: "abc-123"})
+
+	if seen != "abc-123" {
+		t.Errorf("expected context request ID abc-123, got %q", seen)
+	}
+	if got := w.Header().Get(RequestIDHeader); got != "abc-123" {
+		t.Errorf("expected X-Request-ID abc-123 to be echoed, got %q", got)
+	}
+}
+
+func TestRequestID_UsesTraceparentTraceID(t *testing.T) {
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	w, seen := runRequestIDRequest(t, map[string]string{
+		TraceparentHeader: "00-" + traceID + "-00f067aa0ba902b7-01",
+	})
+
+	if seen != traceID {
+		t.Errorf("expected request ID from traceparent %s, got %q", traceID, seen)
+	}
+	if got := w.Header().Get(RequestIDHeader); got != traceID {
+		t.Errorf("expected X-Request-ID %s, got %q", traceID, got)
+	}
+}
+
+func TestRequestID_GeneratesWhenAbsent(t *testing.T) {
+	w, seen := runRequestIDRequest(t, nil)
+
+	if len(seen) != 32 || !isLowerHex(seen, 32) {
+		t.Fatalf("expected a generated 32-char hex ID, got %q", seen)
+	}
+	if got := w.Header().Get(RequestIDHeader); got != seen {
+		t.Errorf("expected X-Request-ID %q, got %q", seen, got)
+	}
+
+	_, other := runRequestIDRequest(t, nil)
+	if other == seen {
+		t.Errorf("expected a new ID per request, got %q twice", seen)
+	}
+}
+
+func TestRequestID_RejectsMalformedIncomingIDs(t *testing.T) {
+	cases := map[string]map[string]string{
+		"unsafe characters":   {RequestIDHeader: "abc\x00<script>"},
+		"too long":            {RequestIDHeader: strings.Repeat("a", maxRequestIDLength+1)},
+		"bad traceparent":     {TraceparentHeader: "00-xyz-00f067aa0ba902b7-01"},
+		"zero trace id":       {TraceparentHeader: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		"unknown tp version":  {TraceparentHeader: "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		"uppercase trace hex": {TraceparentHeader: "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+	}
+	for name, headers := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, seen := runRequestIDRequest(t, headers)
+			if !isLowerHex(seen, 32) || strings.Contains(strings.ToLower(headers[TraceparentHeader]), seen) {
+				t.Errorf("expected a generated ID, got %q", seen)
+			}
+		})
+	}
+}
+
+func TestRequestID_TrustIncomingDisabled(t *testing.T) {
+	t.Setenv("REQUEST_ID_TRUST_INCOMING", "false")
+
+	_, seen := runRequestIDRequest(t, map[string]string{RequestIDHeader: "abc-123"})
+	if seen == "abc-123" || !isLowerHex(seen, 32) {
+		t.Errorf("expected incoming ID to be ignored, got %q", seen)
+	}
+}
+
+func TestRequestLogger_IncludesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := config.SetLogger(config.NewLogger(&buf))
+	defer config.SetLogger(prev)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID(), RequestLogger())
+	r.GET("/test", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set(RequestIDHeader, "corr-42")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if out := buf.String(); !strings.Contains(out, "request_id=corr-42") {
+		t.Errorf("expected request log to carry request_id=corr-42, got:\n%s", out)
+	}
+}
@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// Activity feed item types.
+const (
+	ActivityTreatment         = "treatment"
+	ActivityPatient           = "patient"
+	ActivityTherapistApproval = "therapist_approval"
+)
+
+// ActivityItem is one entry of the admin activity feed. ID refers to the
+// treatment, patient or therapist row named by Type.
+// @Description Activity feed entry
+type ActivityItem struct {
+	Type       string    `json:"type" example:"treatment"`
+	ID         uint      `json:"id" example:"42"`
+	OccurredAt time.Time `json:"occurred_at" example:"2025-01-15T09:30:00+07:00"`
+	Summary    string    `json:"summary" example:"Treatment recorded for J001 (John Doe) on 2025-01-15"`
+}
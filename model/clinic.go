@@ -0,0 +1,51 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ClinicScopedTables are the tables that carry a clinic_id column and are
+// filtered by ClinicScope.
+var ClinicScopedTables = map[string]bool{
+	"patients":   true,
+	"treatments": true,
+}
+
+// ClinicScope returns a GORM scope that limits statements on
+// ClinicScopedTables to rows of the given clinic. Transactions have no
+// clinic_id and are limited to those whose treatment belongs to the clinic.
+// Statements on other tables pass through unchanged, so the scope can be
+// attached to a shared *gorm.DB. A clinicID of 0 disables filtering.
+func ClinicScope(clinicID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if clinicID == 0 {
+			return db
+		}
+		table := statementTable(db.Statement)
+		if table == "transactions" {
+			return db.Where("transactions.treatment_id IN (SELECT id FROM treatments WHERE clinic_id = ?)", clinicID)
+		}
+		if !ClinicScopedTables[table] {
+			return db
+		}
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "clinic_id"}, Value: clinicID})
+	}
+}
+
+// statementTable resolves the table a statement targets. Scopes run before
+// GORM parses the model, so the model is parsed here when no explicit table
+// was set.
+func statementTable(stmt *gorm.Statement) string {
+	if stmt.Table != "" {
+		return stmt.Table
+	}
+	target := stmt.Model
+	if target == nil {
+		target = stmt.Dest
+	}
+	if target == nil || stmt.Parse(target) != nil || stmt.Schema == nil {
+		return ""
+	}
+	return stmt.Schema.Table
+}
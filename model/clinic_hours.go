@@ -0,0 +1,115 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ClinicHoursLayout is the HH:MM format of opening and closing times.
+const ClinicHoursLayout = "15:04"
+
+// ClinicHours are a clinic's opening hours on one day of the week. A day
+// without a row is closed. Rows are replaced as a whole week, so they are not
+// soft-deleted.
+// @Description Clinic opening hours for one day of the week
+type ClinicHours struct {
+	ID        uint      `json:"id" gorm:"primarykey" example:"1"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	ClinicID  uint      `json:"clinic_id" gorm:"uniqueIndex:idx_clinic_hours_day;not null;default:0" example:"0"`
+	DayOfWeek int       `json:"day_of_week" gorm:"uniqueIndex:idx_clinic_hours_day;not null" example:"1"`
+	Open      string    `json:"open" gorm:"size:5;not null" example:"08:00"`
+	Close     string    `json:"close" gorm:"size:5;not null" example:"17:00"`
+}
+
+// ClinicHoursEntry is the opening hours of one day in a SetClinicHoursRequest.
+// day_of_week is 0 (Sunday) to 6 (Saturday).
+// @Description Opening hours for one day
+type ClinicHoursEntry struct {
+	DayOfWeek *int   `json:"day_of_week" binding:"required" example:"1"`
+	Open      string `json:"open" binding:"required" example:"08:00"`
+	Close     string `json:"close" binding:"required" example:"17:00"`
+}
+
+// SetClinicHoursRequest replaces a clinic's weekly opening hours. Days that
+// are not listed are closed.
+// @Description Weekly clinic opening hours
+type SetClinicHoursRequest struct {
+	Hours []ClinicHoursEntry `json:"hours" binding:"dive"`
+}
+
+// ValidateClinicHours checks that every day is between Sunday and Saturday
+// and listed once, and that it opens before it closes. Times are rewritten in
+// zero-padded HH:MM form, so "8:00" is stored as "08:00".
+func ValidateClinicHours(entries []ClinicHoursEntry) error {
+	seen := make(map[int]bool, len(entries))
+	for i, e := range entries {
+		if e.DayOfWeek == nil || *e.DayOfWeek < int(time.Sunday) || *e.DayOfWeek > int(time.Saturday) {
+			return fmt.Errorf("day_of_week must be between 0 (Sunday) and 6 (Saturday)")
+		}
+		day := *e.DayOfWeek
+		if seen[day] {
+			return fmt.Errorf("day_of_week %d is listed more than once", day)
+		}
+		seen[day] = true
+
+		open, err := time.Parse(ClinicHoursLayout, e.Open)
+		if err != nil {
+			return fmt.Errorf("open must be HH:MM for day_of_week %d", day)
+		}
+		closing, err := time.Parse(ClinicHoursLayout, e.Close)
+		if err != nil {
+			return fmt.Errorf("close must be HH:MM for day_of_week %d", day)
+		}
+		if !open.Before(closing) {
+			return fmt.Errorf("open must be before close for day_of_week %d", day)
+		}
+		entries[i].Open = open.Format(ClinicHoursLayout)
+		entries[i].Close = closing.Format(ClinicHoursLayout)
+	}
+	return nil
+}
+
+// minuteOfDay returns the minutes since midnight of an HH:MM time.
+func minuteOfDay(hhmm string) (int, error) {
+	t, err := time.Parse(ClinicHoursLayout, hhmm)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// WithinClinicHours reports whether a slot from start to end lies within the
+// clinic's opening hours on that day. A clinic without any configured hours
+// accepts every slot; slots spanning midnight or falling on a closed day are
+// rejected.
+func WithinClinicHours(db *gorm.DB, clinicID uint, start, end time.Time) (bool, error) {
+	var hours []ClinicHours
+	if err := db.Where("clinic_id = ?", clinicID).Find(&hours).Error; err != nil {
+		return false, err
+	}
+	if len(hours) == 0 {
+		return true, nil
+	}
+	if !end.After(start) || start.Format(time.DateOnly) != end.Format(time.DateOnly) {
+		return false, nil
+	}
+
+	for _, h := range hours {
+		if h.DayOfWeek != int(start.Weekday()) {
+			continue
+		}
+		open, err := minuteOfDay(h.Open)
+		if err != nil {
+			return false, fmt.Errorf("invalid open time %q for day_of_week %d: %w", h.Open, h.DayOfWeek, err)
+		}
+		closing, err := minuteOfDay(h.Close)
+		if err != nil {
+			return false, fmt.Errorf("invalid close time %q for day_of_week %d: %w", h.Close, h.DayOfWeek, err)
+		}
+		return start.Hour()*60+start.Minute() >= open && end.Hour()*60+end.Minute() <= closing, nil
+	}
+	return false, nil
+}
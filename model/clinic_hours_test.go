@@ -0,0 +1,97 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestValidateClinicHours(t *testing.T) {
+	valid := []ClinicHoursEntry{
+		{DayOfWeek: intPtr(0), Open: "09:00", Close: "12:00"},
+		{DayOfWeek: intPtr(1), Open: "08:00", Close: "17:30"},
+	}
+	if err := ValidateClinicHours(valid); err != nil {
+		t.Fatalf("expected valid hours, got %v", err)
+	}
+
+	unpadded := []ClinicHoursEntry{{DayOfWeek: intPtr(1), Open: "8:00", Close: "17:00"}}
+	if err := ValidateClinicHours(unpadded); err != nil {
+		t.Fatalf("expected 8:00 to be accepted, got %v", err)
+	}
+	if unpadded[0].Open != "08:00" || unpadded[0].Close != "17:00" {
+		t.Errorf("expected times normalized to HH:MM, got %q-%q", unpadded[0].Open, unpadded[0].Close)
+	}
+
+	for name, entries := range map[string][]ClinicHoursEntry{
+		"missing day":   {{Open: "08:00", Close: "17:00"}},
+		"day too large": {{DayOfWeek: intPtr(7), Open: "08:00", Close: "17:00"}},
+		"duplicate day": {{DayOfWeek: intPtr(2), Open: "08:00", Close: "12:00"}, {DayOfWeek: intPtr(2), Open: "13:00", Close: "17:00"}},
+		"bad open":      {{DayOfWeek: intPtr(1), Open: "8", Close: "17:00"}},
+		"bad close":     {{DayOfWeek: intPtr(1), Open: "08:00", Close: "25:00"}},
+		"closes early":  {{DayOfWeek: intPtr(1), Open: "17:00", Close: "17:00"}},
+	} {
+		if err := ValidateClinicHours(entries); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWithinClinicHours(t *testing.T) {
+	db := setupTestDB(t, "clinic_hours", &ClinicHours{})
+
+	// 2025-01-06 is a Monday.
+	at := func(day int, hhmm string) time.Time {
+		tm, err := time.Parse("2006-01-02 15:04", fmt.Sprintf("2025-01-%02d %s", day, hhmm))
+		if err != nil {
+			t.Fatalf("parse: %v", err)
+		}
+		return tm
+	}
+
+	ok, err := WithinClinicHours(db, 1, at(5, "22:00"), at(5, "23:00"))
+	if err != nil || !ok {
+		t.Fatalf("clinic without hours should accept any slot, got %v, %v", ok, err)
+	}
+
+	if err := db.Create(&ClinicHours{ClinicID: 1, DayOfWeek: int(time.Monday), Open: "08:00", Close: "17:00"}).Error; err != nil {
+		t.Fatalf("create hours: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name       string
+		start, end time.Time
+		want       bool
+	}{
+		{"inside", at(6, "09:00"), at(6, "10:00"), true},
+		{"exactly open to close", at(6, "08:00"), at(6, "17:00"), true},
+		{"starts before opening", at(6, "07:30"), at(6, "08:30"), false},
+		{"ends after closing", at(6, "16:30"), at(6, "17:30"), false},
+		{"closed day", at(7, "09:00"), at(7, "10:00"), false},
+		{"ends before it starts", at(6, "10:00"), at(6, "09:00"), false},
+		{"spans midnight", at(6, "16:00"), at(7, "09:00"), false},
+	} {
+		got, err := WithinClinicHours(db, 1, tt.start, tt.end)
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+
+	// Rows saved before times were normalized still compare as times.
+	if err := db.Create(&ClinicHours{ClinicID: 3, DayOfWeek: int(time.Monday), Open: "8:00", Close: "17:00"}).Error; err != nil {
+		t.Fatalf("create hours: %v", err)
+	}
+	if ok, err := WithinClinicHours(db, 3, at(6, "10:00"), at(6, "11:00")); err != nil || !ok {
+		t.Errorf("expected 10:00-11:00 within 8:00-17:00, got %v, %v", ok, err)
+	}
+	if ok, _ := WithinClinicHours(db, 3, at(6, "07:00"), at(6, "08:00")); ok {
+		t.Errorf("expected 07:00 before an 8:00 opening to be rejected")
+	}
+
+	// Hours are per clinic.
+	if ok, _ := WithinClinicHours(db, 2, at(7, "09:00"), at(7, "10:00")); !ok {
+		t.Errorf("expected clinic 2 without hours to accept the slot")
+	}
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestClinicScope(t *testing.T) {
+	db := setupTestDB(t, "clinic_scope", &Patient{}, &Disease{})
+	for i, clinic := range []uint{1, 1, 2} {
+		p := Patient{FullName: "P", PatientCode: string(rune('A' + i)), ClinicID: clinic}
+		if err := db.Create(&p).Error; err != nil {
+			t.Fatalf("create patient: %v", err)
+		}
+	}
+	if err := db.Create(&Disease{Name: "Flu", Codename: "flu"}).Error; err != nil {
+		t.Fatalf("create disease: %v", err)
+	}
+
+	scoped := db.Scopes(ClinicScope(2)).Session(&gorm.Session{})
+
+	var count int64
+	if err := scoped.Model(&Patient{}).Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("Model count = %d, %v; want 1", count, err)
+	}
+	if err := scoped.Table("patients").Count(&count).Error; err != nil || count != 1 {
+		t.Fatalf("Table count = %d, %v; want 1", count, err)
+	}
+
+	var patients []Patient
+	if err := scoped.Find(&patients).Error; err != nil || len(patients) != 1 || patients[0].ClinicID != 2 {
+		t.Fatalf("Find = %+v, %v; want the clinic 2 patient only", patients, err)
+	}
+
+	// Tables without a clinic_id column are not filtered.
+	var diseases []Disease
+	if err := scoped.Find(&diseases).Error; err != nil || len(diseases) != 1 {
+		t.Fatalf("Find diseases = %d, %v; want 1", len(diseases), err)
+	}
+
+	// Clinic 0 disables filtering.
+	if err := db.Scopes(ClinicScope(0)).Model(&Patient{}).Count(&count).Error; err != nil || count != 3 {
+		t.Fatalf("unscoped count = %d, %v; want 3", count, err)
+	}
+}
+
+func TestClinicScope_TransactionsFollowTheirTreatment(t *testing.T) {
+	db := setupTestDB(t, "clinic_scope_transactions", &Treatment{}, &Transaction{})
+	for _, clinic := range []uint{1, 2} {
+		treatment := Treatment{PatientCode: "P", TherapistID: 1, TreatmentDate: "2025-01-01", ClinicID: clinic}
+		if err := db.Create(&treatment).Error; err != nil {
+			t.Fatalf("create treatment: %v", err)
+		}
+		if err := db.Create(&Transaction{TreatmentID: treatment.ID, TherapistID: 1, Amount: 100}).Error; err != nil {
+			t.Fatalf("create transaction: %v", err)
+		}
+	}
+
+	var transactions []Transaction
+	if err := db.Scopes(ClinicScope(2)).Find(&transactions).Error; err != nil || len(transactions) != 1 {
+		t.Fatalf("Find = %d, %v; want the clinic 2 transaction only", len(transactions), err)
+	}
+	var treatment Treatment
+	if err := db.First(&treatment, transactions[0].TreatmentID).Error; err != nil || treatment.ClinicID != 2 {
+		t.Fatalf("transaction treatment clinic = %d, %v; want 2", treatment.ClinicID, err)
+	}
+}
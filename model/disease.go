@@ -6,7 +6,94 @@ import "gorm.io/gorm"
 // @Description Disease information
 type Disease struct {
 	gorm.Model
-	Name        string `json:"name" example:"Diabetes"`
+	Name        string `json:"name" gorm:"size:191;column:name" example:"Diabetes"`
 	Codename    string `json:"codename" gorm:"size:191;column:codename;uniqueIndex;not null" example:"diabetes"`
 	Description string `json:"description" example:"A metabolic disease"`
+	// Code is the standard code list entry, e.g. ICD "E11"; empty for
+	// diseases entered by hand.
+	Code string `json:"code" gorm:"size:32;column:code;index" example:"E11"`
+}
+
+// DiseaseActiveNameIndex is the unique index that allows one live disease per name.
+const DiseaseActiveNameIndex = "idx_diseases_active_name"
+
+// diseaseLegacyNameIndex is the index an earlier uniqueIndex tag created on
+// diseases.name; it also covered soft-deleted rows.
+const diseaseLegacyNameIndex = "idx_diseases_name"
+
+// EnsureDiseaseNameIndex adds a unique index on diseases.name covering only
+// rows that are not soft-deleted, so a deleted disease can be created again.
+// It follows EnsureTherapistEmailIndex: SQLite gets a partial index and MySQL
+// indexes a generated column that is NULL for deleted rows.
+func EnsureDiseaseNameIndex(db *gorm.DB) error {
+	if db.Migrator().HasIndex(&Disease{}, diseaseLegacyNameIndex) {
+		if err := db.Migrator().DropIndex(&Disease{}, diseaseLegacyNameIndex); err != nil {
+			return err
+		}
+	}
+	switch db.Dialector.Name() {
+	case "sqlite":
+		return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS " + DiseaseActiveNameIndex + " ON diseases(name) WHERE deleted_at IS NULL").Error
+	case "mysql":
+		if !db.Migrator().HasColumn(&Disease{}, "active_name") {
+			if err := db.Exec("ALTER TABLE diseases ADD COLUMN active_name VARCHAR(191) AS (CASE WHEN deleted_at IS NULL THEN name END) VIRTUAL").Error; err != nil {
+				return err
+			}
+		}
+		if db.Migrator().HasIndex(&Disease{}, DiseaseActiveNameIndex) {
+			return nil
+		}
+		return db.Exec("CREATE UNIQUE INDEX " + DiseaseActiveNameIndex + " ON diseases(active_name)").Error
+	}
+	return nil
+}
+
+// DiseaseTreatmentCount is the number of treatments recorded for patients with a disease
+// @Description Treatment count for one disease
+type DiseaseTreatmentCount struct {
+	DiseaseID      uint   `json:"disease_id" example:"1"`
+	Name           string `json:"name" example:"Diabetes"`
+	Codename       string `json:"codename" example:"diabetes"`
+	TreatmentCount int    `json:"treatment_count" example:"12"`
+	PatientCount   int    `json:"patient_count" example:"4"`
+}
+
+// TreatmentsByDiseaseReport groups treatments in a date range by patient disease
+// @Description Treatment counts grouped by disease over a date range
+type TreatmentsByDiseaseReport struct {
+	StartDate string                  `json:"start_date" example:"2025-01-01"`
+	EndDate   string                  `json:"end_date" example:"2025-03-31"`
+	Diseases  []DiseaseTreatmentCount `json:"diseases"`
+}
+
+// DiseaseUsage reports how many patients list a disease in their health history
+// @Description Number of patients linked to a disease
+type DiseaseUsage struct {
+	DiseaseID    uint   `json:"disease_id" example:"1"`
+	Name         string `json:"name" example:"Diabetes"`
+	Codename     string `json:"codename" example:"diabetes"`
+	PatientCount int    `json:"patient_count" example:"4"`
+}
+
+// DiseaseImportItem is one entry of a standard code list.
+// @Description Disease code list entry
+type DiseaseImportItem struct {
+	Code        string `json:"code" binding:"required" example:"E11"`
+	Name        string `json:"name" binding:"required" example:"Type 2 diabetes mellitus"`
+	Description string `json:"description" example:"A metabolic disease"`
+}
+
+// DiseaseImportRequest is a code list to upsert into the diseases, at most
+// 1000 entries.
+// @Description Disease code list to import
+type DiseaseImportRequest struct {
+	Diseases []DiseaseImportItem `json:"diseases" binding:"required,min=1,max=1000,dive"`
+}
+
+// DiseaseImportResult counts what a code list import changed.
+// @Description Outcome of a disease import
+type DiseaseImportResult struct {
+	Inserted  int `json:"inserted" example:"10"`
+	Updated   int `json:"updated" example:"2"`
+	Unchanged int `json:"unchanged" example:"30"`
 }
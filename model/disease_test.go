@@ -192,3 +192,27 @@ func TestDiseaseModel_EmptyName(t *testing.T) {
 	err := db.Create(&disease).Error
 	assert.NoError(t, err)
 }
+
+func TestEnsureDiseaseNameIndex(t *testing.T) {
+	db := setupModelTestDB(t)
+	assert.NoError(t, EnsureDiseaseNameIndex(db))
+	assert.NoError(t, EnsureDiseaseNameIndex(db), "index creation should be idempotent")
+	assert.True(t, db.Migrator().HasIndex(&Disease{}, DiseaseActiveNameIndex))
+
+	first := createDiseaseHelper(t, db, "Asthma", "asthma")
+	assert.Error(t, db.Create(&Disease{Name: "Asthma", Codename: "asthma-2"}).Error)
+
+	assert.NoError(t, db.Delete(&first).Error)
+	createDiseaseHelper(t, db, "Asthma", "asthma-3")
+}
+
+func TestEnsureDiseaseNameIndex_ExistingDuplicates(t *testing.T) {
+	db := setupModelTestDB(t)
+	createDiseaseHelper(t, db, "Asthma", "asthma")
+	createDiseaseHelper(t, db, "Asthma", "asthma-2")
+
+	assert.Error(t, EnsureDiseaseNameIndex(db), "duplicates are reported, not fatal to AutoMigrate")
+	var count int64
+	assert.NoError(t, db.Model(&Disease{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EmailVerification is a pending or completed check that a user who signed
+// up owns their email address. Only the SHA-256 hash of the token sent to the
+// user is stored. VerifiedAt is nil until the token is used.
+type EmailVerification struct {
+	gorm.Model
+	UserID     uint       `gorm:"not null;index"`
+	TokenHash  string     `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt  time.Time  `gorm:"not null"`
+	VerifiedAt *time.Time `gorm:"default:null"`
+}
@@ -0,0 +1,63 @@
+package model
+
+import "gorm.io/gorm"
+
+// MigratedModels returns the models whose tables are created by AutoMigrate
+// at startup, in migration order.
+func MigratedModels() []interface{} {
+	return []interface{}{
+		&Patient{}, &Disease{}, &User{}, &Session{}, &Therapist{}, &Role{},
+		&Treatment{}, &Pricing{}, &Transaction{}, &PatientCode{}, &SecurityLog{},
+		&Item{}, &Employee{}, &Tag{}, &TreatmentTag{}, &ClinicHours{}, &Schedule{},
+		&EmailVerification{},
+	}
+}
+
+// MigratedModelByTable returns the model of MigratedModels whose table is
+// named table.
+func MigratedModelByTable(db *gorm.DB, table string) (interface{}, bool, error) {
+	for _, m := range MigratedModels() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return nil, false, err
+		}
+		if stmt.Schema.Table == table {
+			return m, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// MigrateModelRequest names the table of a migrated model to migrate
+// @Description On-demand migration of one model
+type MigrateModelRequest struct {
+	Model string `json:"model" binding:"required" example:"schedules"`
+}
+
+// MigrationStatus lists which tables of the migrated models exist
+// @Description Presence of the tables created by the startup migration
+type MigrationStatus struct {
+	Complete bool     `json:"complete" example:"false"`
+	Present  []string `json:"present" example:"patients,users"`
+	Missing  []string `json:"missing" example:"schedules"`
+}
+
+// CheckMigrationStatus reports, for each of MigratedModels, whether its table
+// exists in db.
+func CheckMigrationStatus(db *gorm.DB) (MigrationStatus, error) {
+	status := MigrationStatus{Present: []string{}, Missing: []string{}}
+	migrator := db.Migrator()
+	for _, m := range MigratedModels() {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(m); err != nil {
+			return MigrationStatus{}, err
+		}
+		if migrator.HasTable(m) {
+			status.Present = append(status.Present, stmt.Schema.Table)
+		} else {
+			status.Missing = append(status.Missing, stmt.Schema.Table)
+		}
+	}
+	status.Complete = len(status.Missing) == 0
+	return status, nil
+}
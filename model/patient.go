@@ -2,21 +2,57 @@ package model
 
 import "gorm.io/gorm"
 
+// Patient risk levels, from none to high. Staff set them from the patient's
+// disease history and treatment notes.
+const (
+	RiskLevelNone   = 0
+	RiskLevelLow    = 1
+	RiskLevelMedium = 2
+	RiskLevelHigh   = 3
+)
+
+// IsValidRiskLevel reports whether level is one of the patient risk levels.
+func IsValidRiskLevel(level int) bool {
+	return level >= RiskLevelNone && level <= RiskLevelHigh
+}
+
 // Patient represents a patient entity
 // @Description Patient information
 type Patient struct {
 	gorm.Model
-	FullName       string `json:"full_name" gorm:"column:full_name" example:"John Doe"`
-	Password       string `json:"password" gorm:"column:password" example:"hashed_password"`
-	Gender         string `json:"gender" gorm:"column:gender" example:"Male"`
-	Age            int    `json:"age" gorm:"column:age" example:"30"`
-	Job            string `json:"job" gorm:"column:job" example:"Engineer"`
-	Address        string `json:"address" gorm:"column:address" example:"123 Main St"`
-	Email          string `json:"email" gorm:"column:email" example:"john@example.com"`
-	PhoneNumber    string `json:"phone_number" gorm:"column:phone_number" example:"081234567890"`
-	HealthHistory  string `json:"health_history" gorm:"column:health_history" example:"Diabetes,Hypertension"`
-	SurgeryHistory string `json:"surgery_history" gorm:"column:surgery_history" example:"Appendectomy 2020"`
-	PatientCode    string `json:"patient_code" gorm:"column:patient_code" example:"J001"`
+	FullName       string   `json:"full_name" gorm:"column:full_name" example:"John Doe"`
+	Password       string   `json:"password" gorm:"column:password" example:"hashed_password"`
+	Gender         string   `json:"gender" gorm:"column:gender" example:"Male"`
+	Age            int      `json:"age" gorm:"column:age" example:"30"`
+	Job            string   `json:"job" gorm:"column:job" example:"Engineer"`
+	Address        string   `json:"address" gorm:"column:address" example:"123 Main St"`
+	Email          string   `json:"email" gorm:"column:email" example:"john@example.com"`
+	PhoneNumber    string   `json:"phone_number" gorm:"column:phone_number" example:"081234567890"`
+	HealthHistory  string   `json:"health_history" gorm:"column:health_history" example:"Diabetes,Hypertension"`
+	SurgeryHistory string   `json:"surgery_history" gorm:"column:surgery_history" example:"Appendectomy 2020"`
+	PatientCode    string   `json:"patient_code" gorm:"column:patient_code" example:"J001"`
+	ClinicID       uint     `json:"clinic_id" gorm:"column:clinic_id;index;default:0" example:"1"`
+	Latitude       *float64 `json:"latitude" gorm:"column:latitude" example:"-6.2088"`
+	Longitude      *float64 `json:"longitude" gorm:"column:longitude" example:"106.8456"`
+	RiskLevel      int      `json:"risk_level" gorm:"column:risk_level;not null;default:0;index" example:"0"`
+	// PreferredTherapistID is the therapist the patient should see again for
+	// continuity of care; nil when none is set.
+	PreferredTherapistID *uint `json:"preferred_therapist_id" gorm:"column:preferred_therapist_id;index" example:"2"`
+}
+
+// PatientInfo is a patient with the name of their preferred therapist
+// @Description Patient information with the preferred therapist's name
+type PatientInfo struct {
+	Patient
+	PreferredTherapistName string `json:"preferred_therapist_name,omitempty" example:"Jane Smith"`
+}
+
+// ListPatientResponse is a patient in the list response, with the optional
+// treatment count requested via with_counts
+// @Description Patient list entry
+type ListPatientResponse struct {
+	Patient
+	TreatmentCount *int64 `json:"treatment_count,omitempty" gorm:"column:treatment_count" example:"5"`
 }
 
 type UpdatePatientRequest struct {
@@ -31,4 +67,228 @@ type UpdatePatientRequest struct {
 	HealthHistory  string   `json:"health_history" example:"Diabetes,Hypertension"`
 	SurgeryHistory string   `json:"surgery_history" example:"Appendectomy 2020"`
 	PatientCode    string   `json:"patient_code" example:"J001"`
+	Latitude       *float64 `json:"latitude" example:"-6.2088"`
+	Longitude      *float64 `json:"longitude" example:"106.8456"`
+}
+
+// PatientDuplicateGroup is a set of patients that look like the same person
+// @Description Group of likely duplicate patients and the fields they share
+type PatientDuplicateGroup struct {
+	MatchedOn []string  `json:"matched_on" example:"full_name,phone_number"`
+	Patients  []Patient `json:"patients"`
+}
+
+// TransferPatientRequest moves a patient to another clinic (branch)
+// @Description Target clinic for a patient transfer
+type TransferPatientRequest struct {
+	ClinicID uint `json:"clinic_id" binding:"required" example:"2"`
+}
+
+// PatientTransferResult describes a completed patient transfer
+// @Description Patient transfer result
+type PatientTransferResult struct {
+	PatientID       uint   `json:"patient_id" example:"1"`
+	PatientCode     string `json:"patient_code" example:"J001"`
+	FromClinicID    uint   `json:"from_clinic_id" example:"1"`
+	ToClinicID      uint   `json:"to_clinic_id" example:"2"`
+	TreatmentsMoved int64  `json:"treatments_moved" example:"5"`
+}
+
+// PatientCredentials is a freshly generated temporary password for the user
+// account linked to a patient
+// @Description Reset patient login credentials
+type PatientCredentials struct {
+	PatientID         uint   `json:"patient_id" example:"1"`
+	UserID            uint   `json:"user_id" example:"12"`
+	Email             string `json:"email" example:"john@example.com"`
+	TemporaryPassword string `json:"temporary_password" example:"h7Kq2mXp9RtA"`
+}
+
+// InactivePatient is a patient without a visit since a given date, with the
+// contact details needed to reach out to them
+// @Description Patient whose most recent treatment is before the requested date
+type InactivePatient struct {
+	ID                uint   `json:"id" gorm:"column:id" example:"1"`
+	PatientCode       string `json:"patient_code" gorm:"column:patient_code" example:"J001"`
+	FullName          string `json:"full_name" gorm:"column:full_name" example:"John Doe"`
+	Email             string `json:"email" gorm:"column:email" example:"john@example.com"`
+	PhoneNumber       string `json:"phone_number" gorm:"column:phone_number" example:"081234567890"`
+	Address           string `json:"address" gorm:"column:address" example:"123 Main St"`
+	LastTreatmentDate string `json:"last_treatment_date" gorm:"column:last_treatment_date" example:"2024-11-02"`
+}
+
+// InvalidEmailPatient is a patient whose stored email is malformed, with the
+// phone number staff can use to ask for a corrected one
+// @Description Patient with a malformed email address
+type InvalidEmailPatient struct {
+	ID          uint   `json:"id" gorm:"column:id" example:"1"`
+	PatientCode string `json:"patient_code" gorm:"column:patient_code" example:"J001"`
+	FullName    string `json:"full_name" gorm:"column:full_name" example:"John Doe"`
+	Email       string `json:"email" gorm:"column:email" example:"john@example"`
+	PhoneNumber string `json:"phone_number" gorm:"column:phone_number" example:"081234567890"`
+}
+
+// MissingContactPatient is a patient with neither a phone number nor an
+// email, with the address that is left to reach them
+// @Description Patient without any contact details
+type MissingContactPatient struct {
+	ID          uint   `json:"id" gorm:"column:id" example:"1"`
+	PatientCode string `json:"patient_code" gorm:"column:patient_code" example:"J001"`
+	FullName    string `json:"full_name" gorm:"column:full_name" example:"John Doe"`
+	Address     string `json:"address" gorm:"column:address" example:"123 Main St"`
+}
+
+// SuggestedNextVisit proposes a patient's next visit from their treatment
+// cadence. Basis is "history" when the interval is the median gap between past
+// visits and "default" when there were too few visits.
+// @Description Suggested next visit date
+type SuggestedNextVisit struct {
+	PatientCode   string `json:"patient_code" example:"J001"`
+	LastVisit     string `json:"last_visit,omitempty" example:"2025-01-15"`
+	VisitCount    int    `json:"visit_count" example:"4"`
+	IntervalDays  int    `json:"interval_days" example:"14"`
+	Basis         string `json:"basis" example:"history"`
+	SuggestedDate string `json:"suggested_date" example:"2025-01-29"`
+}
+
+// TreatmentGap is a stretch between two consecutive visits of a patient
+// @Description Interval between consecutive treatments
+type TreatmentGap struct {
+	StartDate string `json:"start_date" example:"2025-01-15"`
+	EndDate   string `json:"end_date" example:"2025-03-02"`
+	Days      int    `json:"days" example:"46"`
+}
+
+// PatientTreatmentGaps lists the gaps between a patient's visits that are
+// longer than the threshold
+// @Description Gaps in a patient's care longer than a threshold
+type PatientTreatmentGaps struct {
+	PatientCode   string         `json:"patient_code" example:"J001"`
+	ThresholdDays int            `json:"threshold_days" example:"30"`
+	VisitCount    int            `json:"visit_count" example:"6"`
+	Gaps          []TreatmentGap `json:"gaps"`
+}
+
+// Follow-up outcomes in a patient's adherence report.
+const (
+	FollowUpKept    = "kept"
+	FollowUpMissed  = "missed"
+	FollowUpPending = "pending"
+)
+
+// FollowUp is a next visit recorded on a treatment and whether the patient
+// came back for it. KeptOn is the date of the visit that kept it.
+// @Description Recorded next visit and whether it was kept
+type FollowUp struct {
+	TreatmentDate string `json:"treatment_date" example:"2025-01-15"`
+	NextVisit     string `json:"next_visit" example:"2025-01-22"`
+	Outcome       string `json:"outcome" example:"kept"`
+	KeptOn        string `json:"kept_on,omitempty" example:"2025-01-23"`
+}
+
+// PatientAdherence summarizes how many of a patient's recorded next visits
+// were followed by a visit within the tolerance. Pending follow-ups are not
+// due yet and are left out of the percentage.
+// @Description Patient follow-up adherence
+type PatientAdherence struct {
+	PatientCode      string     `json:"patient_code" example:"J001"`
+	ToleranceDays    int        `json:"tolerance_days" example:"3"`
+	Kept             int        `json:"kept" example:"4"`
+	Missed           int        `json:"missed" example:"1"`
+	Pending          int        `json:"pending" example:"1"`
+	AdherencePercent float64    `json:"adherence_percent" example:"80"`
+	FollowUps        []FollowUp `json:"follow_ups"`
+}
+
+// PatientTherapist is a therapist who has treated a patient, with how often
+// and when
+// @Description Therapist who treated a patient
+type PatientTherapist struct {
+	TherapistID   uint   `json:"therapist_id" gorm:"column:therapist_id" example:"1"`
+	TherapistName string `json:"therapist_name" gorm:"column:therapist_name" example:"Dr. John Smith"`
+	VisitCount    int64  `json:"visit_count" gorm:"column:visit_count" example:"4"`
+	FirstVisit    string `json:"first_visit" gorm:"column:first_visit" example:"2024-11-02"`
+	LastVisit     string `json:"last_visit" gorm:"column:last_visit" example:"2025-01-15"`
+}
+
+// PatientTherapists lists the distinct therapists who have treated a patient
+// @Description Therapists who treated a patient
+type PatientTherapists struct {
+	PatientCode string             `json:"patient_code" example:"J001"`
+	Therapists  []PatientTherapist `json:"therapists"`
+}
+
+// PatientSignupDay is the number of patients created on one day
+// @Description Patient signups on one day
+type PatientSignupDay struct {
+	Date  string `json:"date" example:"2025-01-15"`
+	Count int64  `json:"count" example:"3"`
+}
+
+// PatientSignupsReport counts patient signups per day over a date range,
+// including days without any
+// @Description Patient signups per day
+type PatientSignupsReport struct {
+	From  string             `json:"from" example:"2025-01-01"`
+	To    string             `json:"to" example:"2025-01-30"`
+	Total int64              `json:"total" example:"42"`
+	Days  []PatientSignupDay `json:"days"`
+}
+
+// TherapistPatient is a patient a therapist has treated, with their last
+// completed visit with that therapist
+// @Description Patient treated by the current therapist
+type TherapistPatient struct {
+	PatientCode string `json:"patient_code" example:"J001"`
+	FullName    string `json:"full_name" example:"John Doe"`
+	PhoneNumber string `json:"phone_number" example:"081234567890"`
+	LastVisit   string `json:"last_visit" example:"2025-01-15"`
+	NextVisit   string `json:"next_visit" example:"2025-01-22"`
+	VisitCount  int64  `json:"visit_count" example:"4"`
+}
+
+// CaseloadTreatment is one treatment in a therapist's caseload
+// @Description Treatment in a therapist's caseload
+type CaseloadTreatment struct {
+	ID            uint   `json:"id" example:"12"`
+	TreatmentDate string `json:"treatment_date" example:"2025-01-15"`
+	Status        string `json:"status" example:"completed"`
+	Issues        string `json:"issues" example:"Back pain"`
+	Treatment     string `json:"treatment" example:"Massage therapy,Exercise"`
+	Remarks       string `json:"remarks" example:"Patient showed improvement"`
+	NextVisit     string `json:"next_visit" example:"2025-01-22"`
+}
+
+// CaseloadPatient is a patient in a therapist's caseload with the treatments
+// that therapist gave them, newest first
+// @Description Patient in a therapist's caseload with their treatments
+type CaseloadPatient struct {
+	PatientCode string              `json:"patient_code" example:"J001"`
+	FullName    string              `json:"full_name" example:"John Doe"`
+	PhoneNumber string              `json:"phone_number" example:"081234567890"`
+	Treatments  []CaseloadTreatment `json:"treatments"`
+}
+
+// SetPreferredTherapistRequest sets a patient's preferred therapist
+// @Description Preferred therapist for a patient
+type SetPreferredTherapistRequest struct {
+	TherapistID uint `json:"therapist_id" binding:"required" example:"2"`
+}
+
+// SetRiskLevelRequest sets a patient's risk level, 0 (none) to 3 (high)
+// @Description New patient risk level
+type SetRiskLevelRequest struct {
+	RiskLevel *int `json:"risk_level" binding:"required" example:"3"`
+}
+
+// HighRiskPatient is a patient at or above a risk level, with their latest
+// visit
+// @Description Patient at or above the requested risk level
+type HighRiskPatient struct {
+	ID                uint   `json:"id" gorm:"column:id" example:"1"`
+	PatientCode       string `json:"patient_code" gorm:"column:patient_code" example:"J001"`
+	FullName          string `json:"full_name" gorm:"column:full_name" example:"John Doe"`
+	PhoneNumber       string `json:"phone_number" gorm:"column:phone_number" example:"081234567890"`
+	RiskLevel         int    `json:"risk_level" gorm:"column:risk_level" example:"3"`
+	LastTreatmentDate string `json:"last_treatment_date" gorm:"column:last_treatment_date" example:"2025-01-15"`
 }
@@ -8,3 +8,12 @@ type PatientCode struct {
 	Number   int    `json:"number"`
 	Code     string `json:"code" gorm:"uniqueIndex;size:191"`
 }
+
+// PatientCodePreview is the code a new patient with the given name would receive
+// @Description Next patient code preview
+type PatientCodePreview struct {
+	Name    string `json:"name" example:"John Doe"`
+	Initial string `json:"initial" example:"J"`
+	Number  int    `json:"number" example:"42"`
+	Code    string `json:"code" example:"J42"`
+}
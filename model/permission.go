@@ -0,0 +1,86 @@
+package model
+
+import "sort"
+
+// Permission names an action guarded by role checks. Routes are protected by
+// permission rather than by listing roles, so the roles allowed for an action
+// are defined once, in permissionRoles.
+type Permission string
+
+// Permissions checked by the API routes.
+const (
+	PermissionSearch             Permission = "search"
+	PermissionManageUsers        Permission = "users:manage"
+	PermissionManagePatients     Permission = "patients:manage"
+	PermissionViewPatientHistory Permission = "patients:view_history"
+	PermissionManageTreatments   Permission = "treatments:manage"
+	PermissionRepairTreatments   Permission = "treatments:repair"
+	PermissionViewTags           Permission = "tags:view"
+	PermissionManageDiseases     Permission = "diseases:manage"
+	PermissionManagePricing      Permission = "pricing:manage"
+	PermissionManageItems        Permission = "items:manage"
+	PermissionManageTransactions Permission = "transactions:manage"
+	PermissionViewTherapists     Permission = "therapists:view"
+	PermissionManageTherapists   Permission = "therapists:manage"
+	PermissionManageEmployees    Permission = "employees:manage"
+	PermissionViewReports        Permission = "reports:view"
+	PermissionViewClinicHours    Permission = "clinic_hours:view"
+	PermissionManageClinicHours  Permission = "clinic_hours:manage"
+	PermissionViewSecurity       Permission = "security:view"
+	PermissionViewSystemStatus   Permission = "system:view"
+	PermissionManageSystem       Permission = "system:manage"
+	PermissionDebug              Permission = "debug"
+)
+
+var permissionRoles = map[Permission][]uint32{
+	PermissionSearch:             {RoleAdmin},
+	PermissionManageUsers:        {RoleAdmin},
+	PermissionManagePatients:     {RoleAdmin},
+	PermissionViewPatientHistory: {RoleAdmin, RoleTherapist},
+	PermissionManageTreatments:   {RoleAdmin, RoleTherapist},
+	PermissionRepairTreatments:   {RoleAdmin},
+	PermissionViewTags:           {RoleAdmin, RoleTherapist},
+	PermissionManageDiseases:     {RoleAdmin},
+	PermissionManagePricing:      {RoleAdmin},
+	PermissionManageItems:        {RoleAdmin},
+	PermissionManageTransactions: {RoleAdmin},
+	PermissionViewTherapists:     {RoleAdmin, RoleTherapist},
+	PermissionManageTherapists:   {RoleAdmin},
+	PermissionManageEmployees:    {RoleAdmin},
+	PermissionViewReports:        {RoleAdmin},
+	PermissionViewClinicHours:    {RoleAdmin, RoleTherapist},
+	PermissionManageClinicHours:  {RoleAdmin},
+	PermissionViewSecurity:       {RoleAdmin},
+	PermissionViewSystemStatus:   {RoleAdmin},
+	PermissionManageSystem:       {RoleAdmin},
+	PermissionDebug:              {RoleAdmin},
+}
+
+// PermissionRoles returns the role IDs allowed to perform p. An unknown
+// permission allows no role.
+func PermissionRoles(p Permission) []uint32 {
+	return permissionRoles[p]
+}
+
+// PermissionsForRole returns the permissions granted to roleID, sorted by name.
+func PermissionsForRole(roleID uint32) []Permission {
+	permissions := []Permission{}
+	for p, roles := range permissionRoles {
+		for _, r := range roles {
+			if r == roleID {
+				permissions = append(permissions, p)
+				break
+			}
+		}
+	}
+	sort.Slice(permissions, func(i, j int) bool { return permissions[i] < permissions[j] })
+	return permissions
+}
+
+// UserPermissions is what the current user's role allows
+// @Description Role and permissions of the current user
+type UserPermissions struct {
+	RoleID      uint32       `json:"role_id" example:"3"`
+	Role        string       `json:"role" example:"Therapist"`
+	Permissions []Permission `json:"permissions" swaggertype:"array,string" example:"clinic_hours:view,patients:view_history,tags:view,therapists:view,treatments:manage"`
+}
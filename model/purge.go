@@ -0,0 +1,104 @@
+package model
+
+import (
+	"context"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"gorm.io/gorm"
+)
+
+// PurgeConfig controls the background job that permanently removes
+// soft-deleted records once they have been deleted for longer than Retention.
+type PurgeConfig struct {
+	Enabled   bool
+	Retention time.Duration
+	Interval  time.Duration
+}
+
+const (
+	defaultPurgeRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = 24 * time.Hour
+)
+
+// purgeTargets lists the soft-deletable models covered by the purge job,
+// keyed by the name used in log output.
+var purgeTargets = []struct {
+	name  string
+	model interface{}
+}{
+	{"patients", &Patient{}},
+	{"treatments", &Treatment{}},
+	{"users", &User{}},
+}
+
+// PurgeConfigFromEnv reads SOFT_DELETE_PURGE_ENABLED, SOFT_DELETE_RETENTION and
+// SOFT_DELETE_PURGE_INTERVAL. Durations use Go syntax (e.g. "720h"); invalid or
+// missing values fall back to 30 days retention and a daily run. The job is
+// disabled unless explicitly enabled.
+func PurgeConfigFromEnv() PurgeConfig {
+	cfg := PurgeConfig{
+		Enabled:   strings.EqualFold(os.Getenv("SOFT_DELETE_PURGE_ENABLED"), "true"),
+		Retention: defaultPurgeRetention,
+		Interval:  defaultPurgeInterval,
+	}
+	if d, err := time.ParseDuration(os.Getenv("SOFT_DELETE_RETENTION")); err == nil && d > 0 {
+		cfg.Retention = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("SOFT_DELETE_PURGE_INTERVAL")); err == nil && d > 0 {
+		cfg.Interval = d
+	}
+	return cfg
+}
+
+// PurgeSoftDeleted permanently deletes patients, treatments and users that were
+// soft-deleted before now minus retention. It returns the number of rows
+// purged per table.
+func PurgeSoftDeleted(db *gorm.DB, retention time.Duration) (map[string]int64, error) {
+	cutoff := time.Now().Add(-retention)
+	purged := make(map[string]int64, len(purgeTargets))
+	for _, target := range purgeTargets {
+		res := db.Unscoped().
+			Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+			Delete(target.model)
+		if res.Error != nil {
+			return purged, res.Error
+		}
+		purged[target.name] = res.RowsAffected
+	}
+	return purged, nil
+}
+
+// StartPurgeJob runs PurgeSoftDeleted every cfg.Interval until ctx is
+// cancelled. It is a no-op when the job is disabled.
+func StartPurgeJob(ctx context.Context, db *gorm.DB, cfg PurgeConfig) {
+	if !cfg.Enabled || cfg.Interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runPurge(db.WithContext(ctx), cfg.Retention)
+			}
+		}
+	}()
+}
+
+func runPurge(db *gorm.DB, retention time.Duration) {
+	purged, err := PurgeSoftDeleted(db, retention)
+	if err != nil {
+		config.Logger().Error("Soft-delete purge failed", "error", err)
+	}
+	for _, target := range purgeTargets {
+		if n, ok := purged[target.name]; ok {
+			config.Logger().Info("Soft-delete purge", "table", target.name, "removed", n)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func softDeleteAt(t *testing.T, db *gorm.DB, record interface{}, deletedAt time.Time) {
+	t.Helper()
+	if err := db.Create(record).Error; err != nil {
+		t.Fatalf("failed to create record: %v", err)
+	}
+	if err := db.Model(record).Update("deleted_at", deletedAt).Error; err != nil {
+		t.Fatalf("failed to soft-delete record: %v", err)
+	}
+}
+
+func TestPurgeSoftDeleted(t *testing.T) {
+	db := setupTestDB(t, "purge", &Patient{}, &Treatment{}, &User{})
+	retention := 30 * 24 * time.Hour
+	old := time.Now().Add(-40 * 24 * time.Hour)
+	recent := time.Now().Add(-2 * 24 * time.Hour)
+
+	softDeleteAt(t, db, &Patient{FullName: "Old Patient", PatientCode: "P1"}, old)
+	softDeleteAt(t, db, &Patient{FullName: "Recent Patient", PatientCode: "P2"}, recent)
+	if err := db.Create(&Patient{FullName: "Active Patient", PatientCode: "P3"}).Error; err != nil {
+		t.Fatalf("failed to create patient: %v", err)
+	}
+	softDeleteAt(t, db, &Treatment{PatientCode: "P1", TreatmentDate: "2024-01-01"}, old)
+	softDeleteAt(t, db, &Treatment{PatientCode: "P1", TreatmentDate: "2024-01-02"}, old)
+	softDeleteAt(t, db, &Treatment{PatientCode: "P2", TreatmentDate: "2024-02-01"}, recent)
+	softDeleteAt(t, db, &User{Name: "Old User", Email: "old@example.com", Password: "x", RoleID: RoleUser}, old)
+
+	purged, err := PurgeSoftDeleted(db, retention)
+	if err != nil {
+		t.Fatalf("purge failed: %v", err)
+	}
+
+	want := map[string]int64{"patients": 1, "treatments": 2, "users": 1}
+	for name, n := range want {
+		if purged[name] != n {
+			t.Errorf("expected %d %s purged, got %d", n, name, purged[name])
+		}
+	}
+
+	var patients []Patient
+	db.Unscoped().Order("patient_code").Find(&patients)
+	if len(patients) != 2 || patients[0].PatientCode != "P2" || patients[1].PatientCode != "P3" {
+		t.Errorf("expected recent and active patients to remain, got %+v", patients)
+	}
+	var treatments int64
+	db.Unscoped().Model(&Treatment{}).Count(&treatments)
+	if treatments != 1 {
+		t.Errorf("expected 1 treatment to remain, got %d", treatments)
+	}
+	var users int64
+	db.Unscoped().Model(&User{}).Count(&users)
+	if users != 0 {
+		t.Errorf("expected old user to be purged, got %d remaining", users)
+	}
+}
+
+func TestPurgeConfigFromEnv(t *testing.T) {
+	t.Setenv("SOFT_DELETE_PURGE_ENABLED", "")
+	t.Setenv("SOFT_DELETE_RETENTION", "")
+	t.Setenv("SOFT_DELETE_PURGE_INTERVAL", "")
+	cfg := PurgeConfigFromEnv()
+	if cfg.Enabled || cfg.Retention != defaultPurgeRetention || cfg.Interval != defaultPurgeInterval {
+		t.Errorf("unexpected defaults: %+v", cfg)
+	}
+
+	t.Setenv("SOFT_DELETE_PURGE_ENABLED", "true")
+	t.Setenv("SOFT_DELETE_RETENTION", "168h")
+	t.Setenv("SOFT_DELETE_PURGE_INTERVAL", "1h")
+	cfg = PurgeConfigFromEnv()
+	if !cfg.Enabled || cfg.Retention != 168*time.Hour || cfg.Interval != time.Hour {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -13,20 +15,30 @@ const (
 	RoleTherapist uint32 = 3
 )
 
+// RoleConstant pairs a role ID constant with its canonical name.
+type RoleConstant struct {
+	ID   uint32 `json:"id" example:"1"`
+	Name string `json:"name" example:"Admin"`
+}
+
+// RoleConstants lists the roles the server authorizes against, in ID order.
+// SeedRoles creates them in this order so the seeded IDs match the constants.
+func RoleConstants() []RoleConstant {
+	return []RoleConstant{
+		{ID: RoleAdmin, Name: "Admin"},
+		{ID: RoleUser, Name: "User"},
+		{ID: RoleTherapist, Name: "Therapist"},
+	}
+}
+
 type Role struct {
 	gorm.Model
 	Name string `gorm:"type:varchar(100);not null" json:"name"`
 }
 
 func SeedRoles(db *gorm.DB) error {
-	// Define the roles you want to seed.
-	roles := []Role{
-		{Name: "Admin"},
-		{Name: "User"},
-		{Name: "Therapist"},
-	}
-
-	for _, role := range roles {
+	for _, constant := range RoleConstants() {
+		role := Role{Name: constant.Name}
 		var existingRole Role
 		// Check if the role already exists.
 		err := db.Where("name = ?", role.Name).First(&existingRole).Error
@@ -43,3 +55,15 @@ func SeedRoles(db *gorm.DB) error {
 	}
 	return nil
 }
+
+// FindRole looks up a role by numeric ID or, case-insensitively, by name.
+func FindRole(db *gorm.DB, idOrName string) (Role, error) {
+	var role Role
+	idOrName = strings.TrimSpace(idOrName)
+	if id, err := strconv.ParseUint(idOrName, 10, 32); err == nil {
+		err := db.First(&role, id).Error
+		return role, err
+	}
+	err := db.Where("LOWER(name) = ?", strings.ToLower(idOrName)).First(&role).Error
+	return role, err
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"fmt"
+	"slices"
 	"testing"
 	"time"
 
@@ -32,3 +33,45 @@ func TestSeedRolesCreatesRoles(t *testing.T) {
 		t.Fatalf("expected at least 3 seeded roles, got %d", count)
 	}
 }
+
+func TestFindRole(t *testing.T) {
+	db := setupTestDB(t, "find_role", &Role{})
+	if err := SeedRoles(db); err != nil {
+		t.Fatalf("SeedRoles returned error: %v", err)
+	}
+
+	for _, value := range []string{"User", "user", " USER ", "2"} {
+		role, err := FindRole(db, value)
+		if err != nil || uint32(role.ID) != RoleUser {
+			t.Errorf("FindRole(%q) = %d, %v; want %d", value, role.ID, err, RoleUser)
+		}
+	}
+	for _, value := range []string{"Patient", "99", ""} {
+		if _, err := FindRole(db, value); err == nil {
+			t.Errorf("FindRole(%q) expected an error", value)
+		}
+	}
+}
+
+func TestPermissionsForRole(t *testing.T) {
+	// Admins may do everything the routes guard.
+	for p := range permissionRoles {
+		if !slices.Contains(PermissionsForRole(RoleAdmin), p) {
+			t.Errorf("admin lacks %s", p)
+		}
+	}
+
+	therapist := PermissionsForRole(RoleTherapist)
+	if !slices.Contains(therapist, PermissionManageTreatments) || slices.Contains(therapist, PermissionManagePatients) {
+		t.Errorf("unexpected therapist permissions %v", therapist)
+	}
+	if !slices.IsSorted(therapist) {
+		t.Errorf("permissions are not sorted: %v", therapist)
+	}
+	if got := PermissionsForRole(RoleUser); len(got) != 0 {
+		t.Errorf("expected no permissions for plain users, got %v", got)
+	}
+	if got := PermissionRoles("unknown"); len(got) != 0 {
+		t.Errorf("expected no roles for an unknown permission, got %v", got)
+	}
+}
@@ -0,0 +1,146 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MaxRecurringScheduleDays caps how far a recurring schedule may be expanded.
+const MaxRecurringScheduleDays = 366
+
+// Schedule statuses. A cancelled slot is kept with its reason for the audit
+// trail but no longer counts as availability.
+const (
+	ScheduleStatusActive    = "active"
+	ScheduleStatusCancelled = "cancelled"
+)
+
+// Schedule is one slot in which a therapist is available. Times are clinic
+// wall-clock times stored as UTC, the same way clinic hours are compared.
+// PatientCode is set when the slot is booked as a patient's appointment.
+// @Description Therapist availability slot
+type Schedule struct {
+	gorm.Model
+	TherapistID       uint       `json:"therapist_id" gorm:"not null;index" example:"1"`
+	ClinicID          uint       `json:"clinic_id" gorm:"column:clinic_id;index;default:0" example:"1"`
+	PatientCode       string     `json:"patient_code,omitempty" gorm:"column:patient_code;index" example:"J001"`
+	StartTime         time.Time  `json:"start_time" gorm:"not null;index" example:"2025-01-13T09:00:00Z"`
+	EndTime           time.Time  `json:"end_time" gorm:"not null" example:"2025-01-13T10:00:00Z"`
+	Status            string     `json:"status" gorm:"size:20;not null;default:active;index" example:"active"`
+	CancelReason      string     `json:"cancel_reason,omitempty" gorm:"column:cancel_reason" example:"Therapist on sick leave"`
+	CancelledAt       *time.Time `json:"cancelled_at,omitempty" gorm:"column:cancelled_at"`
+	CancelledByUserID *uint      `json:"cancelled_by_user_id,omitempty" gorm:"column:cancelled_by_user_id" example:"1"`
+}
+
+// ActiveSchedules is a GORM scope that leaves out cancelled schedules, for
+// queries about availability.
+func ActiveSchedules(db *gorm.DB) *gorm.DB {
+	return db.Where("schedules.status <> ?", ScheduleStatusCancelled)
+}
+
+// CancelScheduleRequest is the reason recorded when a schedule is cancelled.
+// @Description Schedule cancellation reason
+type CancelScheduleRequest struct {
+	Reason string `json:"reason" binding:"required" example:"Therapist on sick leave"`
+}
+
+// PatientAppointment is a schedule slot booked for a patient, with the
+// therapist it is with
+// @Description Booked schedule slot with therapist details
+type PatientAppointment struct {
+	ScheduleID     uint      `json:"schedule_id" example:"12"`
+	PatientCode    string    `json:"patient_code" example:"J001"`
+	ClinicID       uint      `json:"clinic_id" example:"1"`
+	StartTime      time.Time `json:"start_time" example:"2025-01-13T09:00:00Z"`
+	EndTime        time.Time `json:"end_time" example:"2025-01-13T10:00:00Z"`
+	TherapistID    uint      `json:"therapist_id" example:"1"`
+	TherapistName  string    `json:"therapist_name" example:"Dr. John Smith"`
+	TherapistPhone string    `json:"therapist_phone" example:"081234567890"`
+}
+
+// RecurringScheduleRequest describes a weekly slot to expand into schedules.
+// day_of_week is 0 (Sunday) to 6 (Saturday); start_date defaults to today.
+// @Description Weekly recurring availability slot
+type RecurringScheduleRequest struct {
+	DayOfWeek *int   `json:"day_of_week" binding:"required" example:"1"`
+	StartTime string `json:"start_time" binding:"required" example:"09:00"`
+	EndTime   string `json:"end_time" binding:"required" example:"10:00"`
+	StartDate string `json:"start_date,omitempty" example:"2025-01-01"`
+	EndDate   string `json:"end_date" binding:"required" example:"2025-03-31"`
+}
+
+// RecurringScheduleResult reports how many slots were created and why others
+// were skipped.
+// @Description Outcome of expanding a recurring schedule
+type RecurringScheduleResult struct {
+	Created            int `json:"created" example:"10"`
+	SkippedOverlap     int `json:"skipped_overlap" example:"2"`
+	SkippedClinicHours int `json:"skipped_clinic_hours" example:"1"`
+}
+
+// ExpandRecurringSchedule returns an unsaved schedule for every slot the
+// request covers, in date order. today is used when start_date is omitted.
+func ExpandRecurringSchedule(req RecurringScheduleRequest, today time.Time) ([]Schedule, error) {
+	if req.DayOfWeek == nil || *req.DayOfWeek < int(time.Sunday) || *req.DayOfWeek > int(time.Saturday) {
+		return nil, fmt.Errorf("day_of_week must be between 0 (Sunday) and 6 (Saturday)")
+	}
+	startClock, err := time.Parse(ClinicHoursLayout, req.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("start_time must be HH:MM")
+	}
+	endClock, err := time.Parse(ClinicHoursLayout, req.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("end_time must be HH:MM")
+	}
+	if !startClock.Before(endClock) {
+		return nil, fmt.Errorf("start_time must be before end_time")
+	}
+
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	if req.StartDate != "" {
+		if from, err = time.Parse(time.DateOnly, req.StartDate); err != nil {
+			return nil, fmt.Errorf("start_date must be YYYY-MM-DD")
+		}
+	}
+	until, err := time.Parse(time.DateOnly, req.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("end_date must be YYYY-MM-DD")
+	}
+	if until.Before(from) {
+		return nil, fmt.Errorf("end_date must not be before start_date")
+	}
+	if until.Sub(from) > MaxRecurringScheduleDays*24*time.Hour {
+		return nil, fmt.Errorf("the range may span at most %d days", MaxRecurringScheduleDays)
+	}
+
+	first := from.AddDate(0, 0, (*req.DayOfWeek-int(from.Weekday())+7)%7)
+	startOffset := time.Duration(startClock.Hour())*time.Hour + time.Duration(startClock.Minute())*time.Minute
+	endOffset := time.Duration(endClock.Hour())*time.Hour + time.Duration(endClock.Minute())*time.Minute
+
+	var slots []Schedule
+	for day := first; !day.After(until); day = day.AddDate(0, 0, 7) {
+		slots = append(slots, Schedule{StartTime: day.Add(startOffset), EndTime: day.Add(endOffset)})
+	}
+	return slots, nil
+}
+
+// TherapistUtilization compares a therapist's scheduled slots with their
+// completed treatments. UtilizationPercent is nil when nothing was scheduled.
+// @Description Scheduled slots versus completed treatments for one therapist
+type TherapistUtilization struct {
+	TherapistID         uint     `json:"therapist_id" example:"1"`
+	TherapistName       string   `json:"therapist_name" example:"Dr. John Smith"`
+	ScheduledSlots      int64    `json:"scheduled_slots" example:"20"`
+	CompletedTreatments int64    `json:"completed_treatments" example:"15"`
+	UtilizationPercent  *float64 `json:"utilization_percent" example:"75"`
+}
+
+// TherapistUtilizationReport lists therapist utilization over a date range
+// @Description Therapist utilization against schedule
+type TherapistUtilizationReport struct {
+	StartDate  string                 `json:"start_date" example:"2025-01-01"`
+	EndDate    string                 `json:"end_date" example:"2025-03-31"`
+	Therapists []TherapistUtilization `json:"therapists"`
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandRecurringSchedule(t *testing.T) {
+	req := RecurringScheduleRequest{DayOfWeek: intPtr(1), StartTime: "09:00", EndTime: "10:30", StartDate: "2025-01-01", EndDate: "2025-01-27"}
+	slots, err := ExpandRecurringSchedule(req, time.Time{})
+	if err != nil {
+		t.Fatalf("expand: %v", err)
+	}
+	want := []string{"2025-01-06", "2025-01-13", "2025-01-20", "2025-01-27"}
+	if len(slots) != len(want) {
+		t.Fatalf("expected %d slots, got %d", len(want), len(slots))
+	}
+	for i, slot := range slots {
+		if got := slot.StartTime.Format("2006-01-02 15:04"); got != want[i]+" 09:00" {
+			t.Errorf("slot %d starts at %s", i, got)
+		}
+		if got := slot.EndTime.Format("2006-01-02 15:04"); got != want[i]+" 10:30" {
+			t.Errorf("slot %d ends at %s", i, got)
+		}
+	}
+
+	today := time.Date(2025, 1, 13, 15, 0, 0, 0, time.UTC)
+	req.StartDate = ""
+	if slots, _ := ExpandRecurringSchedule(req, today); len(slots) != 3 {
+		t.Errorf("expected start_date to default to today, got %d slots", len(slots))
+	}
+
+	for name, bad := range map[string]RecurringScheduleRequest{
+		"missing day":   {StartTime: "09:00", EndTime: "10:00", EndDate: "2025-02-01"},
+		"day too large": {DayOfWeek: intPtr(7), StartTime: "09:00", EndTime: "10:00", EndDate: "2025-02-01"},
+		"bad start":     {DayOfWeek: intPtr(1), StartTime: "9", EndTime: "10:00", EndDate: "2025-02-01"},
+		"ends early":    {DayOfWeek: intPtr(1), StartTime: "10:00", EndTime: "10:00", EndDate: "2025-02-01"},
+		"bad end date":  {DayOfWeek: intPtr(1), StartTime: "09:00", EndTime: "10:00", EndDate: "01-02-2025"},
+		"reversed":      {DayOfWeek: intPtr(1), StartTime: "09:00", EndTime: "10:00", StartDate: "2025-02-02", EndDate: "2025-02-01"},
+		"too long":      {DayOfWeek: intPtr(1), StartTime: "09:00", EndTime: "10:00", StartDate: "2025-01-01", EndDate: "2026-06-01"},
+	} {
+		if _, err := ExpandRecurringSchedule(bad, today); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
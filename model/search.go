@@ -0,0 +1,25 @@
+package model
+
+// Search result types.
+const (
+	SearchPatient   = "patient"
+	SearchTherapist = "therapist"
+)
+
+// SearchResult is one person matched by the global search. Code is the
+// patient code for patients and empty for therapists.
+// @Description Patient or therapist matched by search
+type SearchResult struct {
+	Type        string `json:"type" example:"patient"`
+	ID          uint   `json:"id" example:"1"`
+	FullName    string `json:"full_name" example:"John Doe"`
+	Code        string `json:"code,omitempty" example:"J001"`
+	PhoneNumber string `json:"phone_number" example:"081234567890"`
+}
+
+// SearchResults groups global search matches by type
+// @Description Patients and therapists matching a search query
+type SearchResults struct {
+	Patients   []SearchResult `json:"patients"`
+	Therapists []SearchResult `json:"therapists"`
+}
@@ -18,3 +18,21 @@ type SecurityLog struct {
 	Message   string         `json:"message" gorm:"column:message;type:text"`
 	Details   datatypes.JSON `json:"details" gorm:"column:details;type:json"`
 }
+
+// SecurityLogLocation is the resolved location of an IP address. Fields are
+// empty when the IP cannot be resolved.
+// @Description Resolved location of an IP address
+type SecurityLogLocation struct {
+	City    string `json:"city" example:"Jakarta"`
+	Country string `json:"country" example:"Indonesia"`
+}
+
+// SecurityLogsByIP lists the security events recorded for one IP address,
+// with its location resolved once for the whole list.
+// @Description Security events for one IP address with its location
+type SecurityLogsByIP struct {
+	IP       string              `json:"ip" example:"203.0.113.9"`
+	Location SecurityLogLocation `json:"location"`
+	Total    int64               `json:"total" example:"12"`
+	Events   []SecurityLog       `json:"events"`
+}
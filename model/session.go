@@ -15,3 +15,60 @@ type Session struct {
 	ClientIP     string    `gorm:"not null"`
 	Browser      string    `gorm:"not null"`
 }
+
+// JWTClaims are the decoded claims of a verified JWT.
+type JWTClaims struct {
+	Subject   string `json:"sub"`
+	Email     string `json:"email,omitempty"`
+	Role      uint32 `json:"role"`
+	Issuer    string `json:"iss"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// SessionInfo is the metadata of a session, without its token
+// @Description Session metadata
+type SessionInfo struct {
+	CreatedAt time.Time `json:"created_at" example:"2025-01-15T09:30:00+07:00"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-01-16T09:30:00+07:00"`
+	ClientIP  string    `json:"client_ip" example:"203.0.113.7"`
+	Browser   string    `json:"browser" example:"Mozilla/5.0"`
+}
+
+// SessionTTL is the remaining lifetime of the current session
+// @Description Session expiry and seconds remaining
+type SessionTTL struct {
+	ExpiresAt time.Time `json:"expires_at" example:"2025-01-16T09:30:00+07:00"`
+	ExpiresIn int64     `json:"expires_in" example:"3540"`
+}
+
+// UserSessionCount is the number of a user's unexpired sessions and the newest of them
+// @Description Active session count for one user
+type UserSessionCount struct {
+	UserID         uint         `json:"user_id" example:"1"`
+	ActiveSessions int64        `json:"active_sessions" example:"2"`
+	Newest         *SessionInfo `json:"newest"`
+}
+
+// AdminSessionInfo is an unexpired session as listed to admins, without its token.
+// Country is resolved from the client IP.
+// @Description Active session with its owner and resolved country
+type AdminSessionInfo struct {
+	ID        uint      `json:"id" example:"12"`
+	UserID    uint      `json:"user_id" example:"1"`
+	Email     string    `json:"email" example:"admin@example.com"`
+	ClientIP  string    `json:"client_ip" example:"203.0.113.7"`
+	Country   string    `json:"country" example:"Indonesia"`
+	Browser   string    `json:"browser" example:"Mozilla/5.0"`
+	CreatedAt time.Time `json:"created_at" example:"2025-01-15T09:30:00+07:00"`
+	ExpiresAt time.Time `json:"expires_at" example:"2025-01-16T09:30:00+07:00"`
+}
+
+// ConcurrentGeoAnomaly is a user whose unexpired sessions come from IPs in
+// more than one country.
+// @Description User with active sessions in different countries
+type ConcurrentGeoAnomaly struct {
+	UserID    uint               `json:"user_id" example:"1"`
+	Email     string             `json:"email" example:"admin@example.com"`
+	Countries []string           `json:"countries" example:"Indonesia,Singapore"`
+	Sessions  []AdminSessionInfo `json:"sessions"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Tag is a normalized label used to categorize treatment issues for reporting.
+// @Description Treatment tag information
+type Tag struct {
+	gorm.Model
+	Name string `json:"name" gorm:"size:100;not null;uniqueIndex" example:"back pain"`
+}
+
+// TreatmentTag links a treatment to a tag.
+type TreatmentTag struct {
+	TreatmentID uint `json:"treatment_id" gorm:"primaryKey" example:"1"`
+	TagID       uint `json:"tag_id" gorm:"primaryKey;index" example:"1"`
+}
+
+// SetTreatmentTagsRequest represents the tags to assign to a treatment
+// @Description Tags to set on a treatment, replacing existing ones
+type SetTreatmentTagsRequest struct {
+	Tags []string `json:"tags" example:"back pain,posture"`
+}
+
+// NormalizeTagName lowercases a tag and collapses surrounding and repeated
+// whitespace so "Back  Pain " and "back pain" are the same tag.
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -1,20 +1,130 @@
 package model
 
-import "gorm.io/gorm"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TherapistActiveEmailIndex is the unique index that allows one active therapist per email.
+const TherapistActiveEmailIndex = "idx_therapists_active_email"
 
 // Therapist represents a therapist entity
 // @Description Therapist information
 type Therapist struct {
 	gorm.Model
-	FullName    string `json:"full_name" gorm:"column:full_name" example:"Dr. John Smith"`
-	Email       string `json:"email" gorm:"column:email" example:"dr.john@example.com"`
-	Password    string `json:"password" gorm:"column:password" example:"hashed_password"`
-	PhoneNumber string `json:"phone_number" gorm:"column:phone_number" example:"081234567890"`
-	Address     string `json:"address" gorm:"column:address" example:"123 Main St"`
-	DateOfBirth string `json:"date_of_birth" gorm:"column:date_of_birth" example:"1980-01-01"`
-	NIK         string `json:"nik" gorm:"column:nik" example:"1234567890123456"`
-	Weight      int    `json:"weight" gorm:"column:weight" example:"70"`
-	Height      int    `json:"height" gorm:"column:height" example:"175"`
-	Role        string `json:"role" gorm:"column:role" example:"Physical Therapist"`
-	IsApproved  bool   `json:"is_approved" gorm:"column:is_approved;default:false" example:"false"`
+	FullName    string     `json:"full_name" gorm:"column:full_name" example:"Dr. John Smith"`
+	Email       string     `json:"email" gorm:"column:email" example:"dr.john@example.com"`
+	Password    string     `json:"password" gorm:"column:password" example:"hashed_password"`
+	PhoneNumber string     `json:"phone_number" gorm:"column:phone_number" example:"081234567890"`
+	Address     string     `json:"address" gorm:"column:address" example:"123 Main St"`
+	DateOfBirth string     `json:"date_of_birth" gorm:"column:date_of_birth" example:"1980-01-01"`
+	NIK         string     `json:"nik" gorm:"column:nik" example:"1234567890123456"`
+	Weight      int        `json:"weight" gorm:"column:weight" example:"70"`
+	Height      int        `json:"height" gorm:"column:height" example:"175"`
+	Role        string     `json:"role" gorm:"column:role" example:"Physical Therapist"`
+	IsApproved  bool       `json:"is_approved" gorm:"column:is_approved;default:false" example:"false"`
+	ApprovedAt  *time.Time `json:"approved_at,omitempty" gorm:"column:approved_at"`
+	Latitude    *float64   `json:"latitude" gorm:"column:latitude" example:"-6.2088"`
+	Longitude   *float64   `json:"longitude" gorm:"column:longitude" example:"106.8456"`
+}
+
+// EnsureTherapistEmailIndex adds a unique index on therapists.email covering
+// only rows that are not soft-deleted and have an email, so a deleted
+// therapist's email can be registered again. SQLite gets a partial index;
+// MySQL has no partial indexes, so it indexes a generated column that is NULL
+// for deleted rows instead.
+func EnsureTherapistEmailIndex(db *gorm.DB) error {
+	switch db.Dialector.Name() {
+	case "sqlite":
+		return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS " + TherapistActiveEmailIndex + " ON therapists(email) WHERE deleted_at IS NULL AND email <> ''").Error
+	case "mysql":
+		if !db.Migrator().HasColumn(&Therapist{}, "active_email") {
+			if err := db.Exec("ALTER TABLE therapists ADD COLUMN active_email VARCHAR(191) AS (CASE WHEN deleted_at IS NULL AND email <> '' THEN email END) VIRTUAL").Error; err != nil {
+				return err
+			}
+		}
+		if db.Migrator().HasIndex(&Therapist{}, TherapistActiveEmailIndex) {
+			return nil
+		}
+		return db.Exec("CREATE UNIQUE INDEX " + TherapistActiveEmailIndex + " ON therapists(active_email)").Error
+	}
+	return nil
+}
+
+// TherapistWeeklyCount is the number of treatments a therapist performed in one ISO week
+// @Description Weekly treatment count
+type TherapistWeeklyCount struct {
+	Week      string `json:"week" example:"2025-W03"`
+	WeekStart string `json:"week_start" example:"2025-01-13"`
+	Count     int    `json:"count" example:"4"`
+}
+
+// TherapistCadence summarizes a therapist's treatment cadence over a date range
+// @Description Therapist treatments per ISO week with the weekly average
+type TherapistCadence struct {
+	TherapistID     uint                   `json:"therapist_id" example:"1"`
+	TherapistName   string                 `json:"therapist_name" example:"Dr. John Smith"`
+	StartDate       string                 `json:"start_date" example:"2025-01-01"`
+	EndDate         string                 `json:"end_date" example:"2025-03-31"`
+	TotalTreatments int                    `json:"total_treatments" example:"24"`
+	AveragePerWeek  float64                `json:"average_per_week" example:"1.85"`
+	Weeks           []TherapistWeeklyCount `json:"weeks"`
+}
+
+// TherapistApprovalStats counts therapists by approval status. The oldest
+// pending fields are omitted when nothing is pending.
+// @Description Therapist counts by approval status
+type TherapistApprovalStats struct {
+	Total                int64      `json:"total" example:"12"`
+	Approved             int64      `json:"approved" example:"9"`
+	Pending              int64      `json:"pending" example:"3"`
+	OldestPendingSince   *time.Time `json:"oldest_pending_since,omitempty"`
+	OldestPendingAgeDays *int       `json:"oldest_pending_age_days,omitempty" example:"5"`
+}
+
+// PendingTherapist is an unapproved therapist in the approval queue with how
+// long they have been waiting since registering.
+// @Description Therapist waiting for approval
+type PendingTherapist struct {
+	ID          uint      `json:"id" example:"4"`
+	FullName    string    `json:"full_name" example:"Dr. John Smith"`
+	Email       string    `json:"email" example:"dr.john@example.com"`
+	PhoneNumber string    `json:"phone_number" example:"081234567890"`
+	CreatedAt   time.Time `json:"created_at" example:"2025-01-10T08:00:00Z"`
+	WaitSeconds int64     `json:"wait_seconds" example:"432000"`
+	WaitDays    int       `json:"wait_days" example:"5"`
+}
+
+// BulkApproveTherapistRequest lists the therapists to approve in one batch
+// @Description Therapist IDs to approve
+type BulkApproveTherapistRequest struct {
+	IDs []uint `json:"ids" binding:"required" example:"1,2,3"`
+}
+
+// Therapist approval result statuses
+const (
+	TherapistApprovalApproved        = "approved"
+	TherapistApprovalAlreadyApproved = "already_approved"
+	TherapistApprovalNotFound        = "not_found"
+)
+
+// TherapistApprovalResult reports the outcome of approving one therapist in a batch
+// @Description Per-therapist bulk approval result
+type TherapistApprovalResult struct {
+	ID     uint   `json:"id" example:"1"`
+	Status string `json:"status" example:"approved"`
+	Note   string `json:"note,omitempty" example:"Therapist was already approved"`
+}
+
+// NearbyTherapist is an approved therapist with their distance to a patient
+// @Description Therapist ordered by distance to a patient
+type NearbyTherapist struct {
+	ID          uint    `json:"id" example:"1"`
+	FullName    string  `json:"full_name" example:"Dr. John Smith"`
+	PhoneNumber string  `json:"phone_number" example:"081234567890"`
+	Address     string  `json:"address" example:"123 Main St"`
+	Latitude    float64 `json:"latitude" example:"-6.2088"`
+	Longitude   float64 `json:"longitude" example:"106.8456"`
+	DistanceKm  float64 `json:"distance_km" example:"3.42"`
 }
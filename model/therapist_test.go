@@ -233,3 +233,16 @@ func TestTherapistModel_SearchByName(t *testing.T) {
 	assert.NoError(t, err)
 	assert.GreaterOrEqual(t, len(found), 1)
 }
+
+func TestEnsureTherapistEmailIndex(t *testing.T) {
+	db := setupTherapistTestDB(t)
+	assert.NoError(t, EnsureTherapistEmailIndex(db))
+	assert.NoError(t, EnsureTherapistEmailIndex(db), "index creation should be idempotent")
+	assert.True(t, db.Migrator().HasIndex(&Therapist{}, TherapistActiveEmailIndex))
+
+	first := insertTherapist(t, db, Therapist{FullName: "First", NIK: "1", Email: "same@example.com"})
+	assert.Error(t, db.Create(&Therapist{FullName: "Second", NIK: "2", Email: "same@example.com"}).Error)
+
+	assert.NoError(t, db.Delete(&first).Error)
+	insertTherapist(t, db, Therapist{FullName: "Third", NIK: "3", Email: "same@example.com"})
+}
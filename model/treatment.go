@@ -4,6 +4,42 @@ import (
 	"gorm.io/gorm"
 )
 
+// Treatment statuses. Treatments default to completed; no-shows and
+// cancellations are recorded explicitly. Scheduled treatments have not
+// happened yet and move to one of the other statuses.
+const (
+	TreatmentStatusScheduled = "scheduled"
+	TreatmentStatusCompleted = "completed"
+	TreatmentStatusNoShow    = "no_show"
+	TreatmentStatusCancelled = "cancelled"
+)
+
+// IsValidTreatmentStatus reports whether status is one of the treatment statuses.
+func IsValidTreatmentStatus(status string) bool {
+	switch status {
+	case TreatmentStatusScheduled, TreatmentStatusCompleted, TreatmentStatusNoShow, TreatmentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTreatmentStatus reports whether a treatment may move from one
+// status to another. Only scheduled treatments can change status; keeping the
+// current status is always allowed.
+func CanTransitionTreatmentStatus(from, to string) bool {
+	if from == to {
+		return true
+	}
+	if from != TreatmentStatusScheduled {
+		return false
+	}
+	switch to {
+	case TreatmentStatusCompleted, TreatmentStatusCancelled, TreatmentStatusNoShow:
+		return true
+	}
+	return false
+}
+
 // Treatment represents a treatment entity
 // @Description Treatment information
 type Treatment struct {
@@ -15,6 +51,14 @@ type Treatment struct {
 	Treatment     string `json:"treatment" gorm:"not null" example:"Massage therapy,Exercise"`
 	Remarks       string `json:"remarks" example:"Patient showed improvement"`
 	NextVisit     string `json:"next_visit" gorm:"not null" example:"2025-01-22"`
+	ClinicID      uint   `json:"clinic_id" gorm:"column:clinic_id;index;default:0" example:"1"`
+	Status        string `json:"status" gorm:"size:20;not null;default:completed;index" example:"completed"`
+	// Cost is the amount billed for the treatment, in the same currency units
+	// as pricing.
+	Cost int64 `json:"cost" gorm:"not null;default:0" example:"250000"`
+	// CreatedByUserID is the user who entered the treatment; nil for
+	// treatments recorded before it was tracked.
+	CreatedByUserID *uint `json:"created_by_user_id,omitempty" gorm:"column:created_by_user_id;index" example:"3"`
 }
 
 // TransactionRequest represents transaction data sent together with treatment creation.
@@ -25,7 +69,15 @@ type TransactionRequest struct {
 	PaymentStatus string `json:"payment_status,omitempty" example:"unpaid"`
 }
 
-// TreatementRequest represents a treatment request
+// CloneTreatmentRequest is the new date for a follow-up copied from an earlier treatment
+// @Description Follow-up treatment date and optional next visit
+type CloneTreatmentRequest struct {
+	TreatmentDate string `json:"treatment_date" binding:"required" example:"2025-01-22"`
+	NextVisit     string `json:"next_visit,omitempty" example:"2025-01-29"`
+}
+
+// TreatementRequest represents a treatment request. Cost defaults to the
+// therapist's current price when omitted.
 // @Description Treatment request information
 type TreatementRequest struct {
 	TreatmentDate string             `json:"treatment_date" example:"2025-01-15"`
@@ -35,15 +87,275 @@ type TreatementRequest struct {
 	Treatment     []string           `json:"treatment,omitempty" example:"Massage therapy,Exercise"`
 	Remarks       string             `json:"remarks,omitempty" example:"Patient showed improvement"`
 	NextVisit     string             `json:"next_visit,omitempty" example:"2025-01-22"`
+	Status        string             `json:"status,omitempty" example:"completed"`
+	Cost          *int64             `json:"cost,omitempty" example:"250000"`
 	Transaction   TransactionRequest `json:"transaction"`
 }
 
-// ListTreatementResponse represents a treatment list response
+// UpdateTreatmentRequest holds the editable fields of a treatment. The clinic,
+// the record ID, timestamps and who entered the treatment cannot be changed
+// through an update. Empty fields are left as they are.
+// @Description Treatment update information
+type UpdateTreatmentRequest struct {
+	TreatmentDate string `json:"treatment_date" example:"2025-01-15"`
+	PatientCode   string `json:"patient_code" example:"J001"`
+	TherapistID   uint   `json:"therapist_id" example:"1"`
+	Issues        string `json:"issues" example:"Back pain"`
+	Treatment     string `json:"treatment" example:"Massage therapy,Exercise"`
+	Remarks       string `json:"remarks" example:"Patient showed improvement"`
+	NextVisit     string `json:"next_visit" example:"2025-01-22"`
+	Status        string `json:"status" example:"completed"`
+	Cost          int64  `json:"cost" example:"250000"`
+}
+
+// ToTreatment returns the update as a Treatment holding only the editable
+// fields, for use with gorm's Updates.
+func (r UpdateTreatmentRequest) ToTreatment() Treatment {
+	return Treatment{
+		TreatmentDate: r.TreatmentDate,
+		PatientCode:   r.PatientCode,
+		TherapistID:   r.TherapistID,
+		Issues:        r.Issues,
+		Treatment:     r.Treatment,
+		Remarks:       r.Remarks,
+		NextVisit:     r.NextVisit,
+		Status:        r.Status,
+		Cost:          r.Cost,
+	}
+}
+
+// ListTreatementResponse represents a treatment list response. Deleted marks
+// a soft-deleted treatment returned as a tombstone for incremental sync.
 // @Description Treatment list response information
 type ListTreatementResponse struct {
 	Treatment
 	TherapistName string `json:"therapist_name" gorm:"column:therapist_name" example:"Dr. John Smith"`
 	PatientName   string `json:"patient_name" gorm:"column:patient_name" example:"John Doe"`
+	PhoneNumber   string `json:"phone_number" gorm:"column:phone_number" example:"081234567890,081234567891"`
 	Age           int    `json:"age" gorm:"column:age" example:"30"`
 	Price         int64  `json:"price" gorm:"column:price" example:"250000"`
+	Deleted       bool   `json:"deleted" gorm:"-" example:"false"`
+}
+
+// TreatmentSummaryItem represents a single row of a patient's printable treatment summary
+// @Description Treatment summary entry
+type TreatmentSummaryItem struct {
+	TreatmentDate string `json:"treatment_date" gorm:"column:treatment_date" example:"2025-01-15"`
+	Issues        string `json:"issues" gorm:"column:issues" example:"Back pain"`
+	Treatment     string `json:"treatment" gorm:"column:treatment" example:"Massage therapy,Exercise"`
+	Remarks       string `json:"remarks" gorm:"column:remarks" example:"Patient showed improvement"`
+	NextVisit     string `json:"next_visit" gorm:"column:next_visit" example:"2025-01-22"`
+	TherapistName string `json:"therapist_name" gorm:"column:therapist_name" example:"Dr. John Smith"`
+}
+
+// PatientTreatmentSummary groups a patient's treatments with totals for reporting
+// @Description Printable patient treatment summary
+type PatientTreatmentSummary struct {
+	PatientCode        string                 `json:"patient_code" example:"J001"`
+	PatientName        string                 `json:"patient_name" example:"John Doe"`
+	TotalTreatments    int                    `json:"total_treatments" example:"3"`
+	TotalTherapists    int                    `json:"total_therapists" example:"2"`
+	FirstTreatmentDate string                 `json:"first_treatment_date" example:"2025-01-01"`
+	LastTreatmentDate  string                 `json:"last_treatment_date" example:"2025-01-15"`
+	Treatments         []TreatmentSummaryItem `json:"treatments"`
+}
+
+// TherapistNoShowRate is a therapist's treatment outcomes over a date range
+// @Description No-show statistics for one therapist
+type TherapistNoShowRate struct {
+	TherapistID   uint    `json:"therapist_id" example:"1"`
+	TherapistName string  `json:"therapist_name" example:"Dr. John Smith"`
+	Completed     int64   `json:"completed" example:"18"`
+	NoShow        int64   `json:"no_show" example:"2"`
+	Cancelled     int64   `json:"cancelled" example:"1"`
+	NoShowRate    float64 `json:"no_show_rate" example:"0.1"`
+}
+
+// NoShowReport summarizes no-shows per therapist and overall over a date range.
+// The no-show rate is no_show / (completed + no_show); cancelled treatments
+// are reported but excluded from the rate.
+// @Description No-show rates per therapist and overall
+type NoShowReport struct {
+	StartDate  string                `json:"start_date" example:"2025-01-01"`
+	EndDate    string                `json:"end_date" example:"2025-03-31"`
+	Completed  int64                 `json:"completed" example:"90"`
+	NoShow     int64                 `json:"no_show" example:"10"`
+	Cancelled  int64                 `json:"cancelled" example:"4"`
+	NoShowRate float64               `json:"no_show_rate" example:"0.1"`
+	Therapists []TherapistNoShowRate `json:"therapists"`
+}
+
+// TherapistRetention splits a therapist's patients over a date range into
+// those seen more than once and those seen exactly once
+// @Description Patient retention for one therapist
+type TherapistRetention struct {
+	TherapistID       uint    `json:"therapist_id" example:"1"`
+	TherapistName     string  `json:"therapist_name" example:"Dr. John Smith"`
+	ReturningPatients int64   `json:"returning_patients" example:"12"`
+	OneTimePatients   int64   `json:"one_time_patients" example:"8"`
+	RetentionRate     float64 `json:"retention_rate" example:"0.6"`
+}
+
+// TherapistRetentionReport lists patient retention per therapist over a date
+// range. Only attended treatments count as visits; the retention rate is
+// returning / (returning + one_time).
+// @Description Patient retention per therapist
+type TherapistRetentionReport struct {
+	StartDate  string               `json:"start_date" example:"2025-01-01"`
+	EndDate    string               `json:"end_date" example:"2025-03-31"`
+	Therapists []TherapistRetention `json:"therapists"`
+}
+
+// TreatmentWeeklyTrend is the number of treatments in one ISO week and the
+// change from the week before. ChangePercent is nil when the prior week had
+// no treatments.
+// @Description Weekly treatment total with week-over-week change
+type TreatmentWeeklyTrend struct {
+	Week          string   `json:"week" example:"2025-W03"`
+	WeekStart     string   `json:"week_start" example:"2025-01-13"`
+	Total         int64    `json:"total" example:"24"`
+	PreviousTotal int64    `json:"previous_total" example:"20"`
+	ChangePercent *float64 `json:"change_percent" example:"20"`
+}
+
+// TreatmentTrendsReport lists weekly treatment totals over a date range,
+// widened to whole ISO weeks
+// @Description Weekly treatment totals with week-over-week growth
+type TreatmentTrendsReport struct {
+	StartDate string                 `json:"start_date" example:"2025-01-06"`
+	EndDate   string                 `json:"end_date" example:"2025-03-30"`
+	Total     int64                  `json:"total" example:"240"`
+	Weeks     []TreatmentWeeklyTrend `json:"weeks"`
+}
+
+// ReassignTherapistRequest moves treatments whose therapist no longer exists
+// to another therapist. Without treatment_ids every such treatment is moved.
+// @Description Target therapist and optional treatment IDs to reassign
+type ReassignTherapistRequest struct {
+	TherapistID  uint   `json:"therapist_id" binding:"required" example:"2"`
+	TreatmentIDs []uint `json:"treatment_ids,omitempty" example:"10,11"`
+}
+
+// TreatmentDuplicateCheckItem is one planned treatment to check.
+// @Description Patient and date of a planned treatment
+type TreatmentDuplicateCheckItem struct {
+	PatientCode   string `json:"patient_code" binding:"required" example:"J001"`
+	TreatmentDate string `json:"treatment_date" binding:"required" example:"2025-01-15"`
+}
+
+// TreatmentDuplicateCheckRequest is a batch of at most 500 planned treatments
+// to check before they are submitted.
+// @Description Planned treatments to check for duplicates
+type TreatmentDuplicateCheckRequest struct {
+	Treatments []TreatmentDuplicateCheckItem `json:"treatments" binding:"required,min=1,max=500,dive"`
+}
+
+// TreatmentDuplicateCollision is a planned treatment that would be rejected as
+// a duplicate. Reason is "exists" when the patient already has a treatment on
+// that date and "repeated_in_batch" when an earlier entry of the batch has the
+// same patient and date.
+// @Description Planned treatment that would collide
+type TreatmentDuplicateCollision struct {
+	Index         int    `json:"index" example:"2"`
+	PatientCode   string `json:"patient_code" example:"J001"`
+	TreatmentDate string `json:"treatment_date" example:"2025-01-15"`
+	Reason        string `json:"reason" example:"exists"`
+}
+
+// TreatmentDuplicateCheckResult lists the collisions of a checked batch.
+// @Description Outcome of a treatment duplicate check
+type TreatmentDuplicateCheckResult struct {
+	Checked    int                           `json:"checked" example:"12"`
+	Collisions []TreatmentDuplicateCollision `json:"collisions"`
+}
+
+// TreatmentNearDuplicate is a pair of treatments of one patient a few days
+// apart with the same issues and treatment text, likely entered twice
+// @Description Possible double entry of a treatment on nearby days
+type TreatmentNearDuplicate struct {
+	PatientCode         string `json:"patient_code" example:"J001"`
+	Issues              string `json:"issues" example:"Back pain"`
+	Treatment           string `json:"treatment" example:"Massage therapy"`
+	FirstTreatmentID    uint   `json:"first_treatment_id" example:"10"`
+	FirstTreatmentDate  string `json:"first_treatment_date" example:"2025-01-15"`
+	SecondTreatmentID   uint   `json:"second_treatment_id" example:"11"`
+	SecondTreatmentDate string `json:"second_treatment_date" example:"2025-01-16"`
+	DaysApart           int    `json:"days_apart" example:"1"`
+}
+
+// TherapistRevenue is the billed cost of one therapist's treatments
+// @Description Revenue for one therapist
+type TherapistRevenue struct {
+	TherapistID    uint   `json:"therapist_id" example:"1"`
+	TherapistName  string `json:"therapist_name" example:"Dr. John Smith"`
+	Total          int64  `json:"total" example:"2500000"`
+	TreatmentCount int64  `json:"treatment_count" example:"10"`
+}
+
+// MonthlyRevenue is the billed cost of the treatments in one month
+// @Description Revenue for one month
+type MonthlyRevenue struct {
+	Month          string `json:"month" example:"2025-01"`
+	Total          int64  `json:"total" example:"7500000"`
+	TreatmentCount int64  `json:"treatment_count" example:"30"`
+}
+
+// RevenueReport sums treatment costs over a date range per therapist and per
+// month. Cancelled and no-show treatments are not counted.
+// @Description Treatment revenue per therapist and per month
+type RevenueReport struct {
+	StartDate  string             `json:"start_date" example:"2025-01-01"`
+	EndDate    string             `json:"end_date" example:"2025-03-31"`
+	Total      int64              `json:"total" example:"22500000"`
+	Therapists []TherapistRevenue `json:"therapists"`
+	Months     []MonthlyRevenue   `json:"months"`
+}
+
+// CreateTreatmentResult is returned when a treatment is created. Warnings
+// flag unusual but allowed input, such as a very short interval since the
+// last visit.
+// @Description Created treatment and non-blocking warnings
+type CreateTreatmentResult struct {
+	TreatmentID uint     `json:"treatment_id" example:"42"`
+	Warnings    []string `json:"warnings" example:"Only 1 day(s) since the patient's last visit on 2025-01-14"`
+}
+
+// IssueTerm is a word or two-word phrase from treatment issues and how often
+// it occurs
+// @Description Issue term frequency
+type IssueTerm struct {
+	Term  string `json:"term" example:"back pain"`
+	Count int64  `json:"count" example:"12"`
+}
+
+// IssueTermsReport lists the most frequent terms in treatment issues over a
+// date range. Truncated is set when more treatments matched than were read.
+// @Description Most frequent treatment issue terms
+type IssueTermsReport struct {
+	StartDate      string      `json:"start_date" example:"2025-01-01"`
+	EndDate        string      `json:"end_date" example:"2025-03-31"`
+	TreatmentCount int64       `json:"treatment_count" example:"120"`
+	Truncated      bool        `json:"truncated" example:"false"`
+	Terms          []IssueTerm `json:"terms"`
+}
+
+// TherapistActivityMonth counts, for one month, the therapists on the roster
+// and how many of them had an attended treatment. ActiveRatio is active /
+// therapists, or 0 when there were none.
+// @Description Active and inactive therapists in one month
+type TherapistActivityMonth struct {
+	Month       string  `json:"month" example:"2025-01"`
+	Therapists  int64   `json:"therapists" example:"10"`
+	Active      int64   `json:"active" example:"8"`
+	Inactive    int64   `json:"inactive" example:"2"`
+	ActiveRatio float64 `json:"active_ratio" example:"0.8"`
+}
+
+// TherapistActivityReport lists active and inactive therapist counts per
+// month over a date range.
+// @Description Active versus inactive therapists per month
+type TherapistActivityReport struct {
+	StartDate string                   `json:"start_date" example:"2025-01-01"`
+	EndDate   string                   `json:"end_date" example:"2025-03-31"`
+	Months    []TherapistActivityMonth `json:"months"`
 }
@@ -13,4 +13,5 @@ type User struct {
 	RoleID         uint32 `gorm:"type:int(32);not null" json:"role_id"`
 	FailedAttempts uint   `gorm:"type:int;default:0" json:"-"`
 	LockedUntil    *int64 `gorm:"type:bigint;default:null" json:"-"`
+	ClinicID       uint   `gorm:"column:clinic_id;index;default:0" json:"clinic_id"`
 }
@@ -0,0 +1,15 @@
+package util
+
+import "regexp"
+
+// emailPattern is the shared check for a deliverable-looking address: a local
+// part of letters, digits and ._%+- without leading, trailing or repeated dots,
+// and a domain of dot-separated labels ending in a top-level domain of at least
+// two letters.
+var emailPattern = regexp.MustCompile(`^[A-Za-z0-9_%+-]+(\.[A-Za-z0-9_%+-]+)*@([A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?\.)+[A-Za-z]{2,}$`)
+
+// IsValidEmail reports whether email is well formed. Surrounding whitespace
+// makes it invalid.
+func IsValidEmail(email string) bool {
+	return emailPattern.MatchString(email)
+}
@@ -0,0 +1,31 @@
+package util
+
+import "testing"
+
+func TestIsValidEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		valid bool
+	}{
+		{"john@example.com", true},
+		{"john.doe+clinic@mail.example.co.id", true},
+		{"a_b-c%d@sub-domain.example.org", true},
+		{"", false},
+		{"john", false},
+		{"john@", false},
+		{"@example.com", false},
+		{"john@example", false},
+		{"john@@example.com", false},
+		{"john..doe@example.com", false},
+		{".john@example.com", false},
+		{"john@-example.com", false},
+		{"john@example.c", false},
+		{"john doe@example.com", false},
+		{" john@example.com", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidEmail(tt.email); got != tt.valid {
+			t.Errorf("IsValidEmail(%q) = %v, want %v", tt.email, got, tt.valid)
+		}
+	}
+}
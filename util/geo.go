@@ -0,0 +1,17 @@
+package util
+
+import "math"
+
+// earthRadiusKm is the mean radius of the Earth in kilometres.
+const earthRadiusKm = 6371.0
+
+// Haversine returns the great-circle distance in kilometres between two
+// points given as latitude and longitude in decimal degrees.
+func Haversine(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
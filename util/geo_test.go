@@ -0,0 +1,32 @@
+package util
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHaversine(t *testing.T) {
+	tests := []struct {
+		name                   string
+		lat1, lon1, lat2, lon2 float64
+		wantKm                 float64
+		tolerance              float64
+	}{
+		{"same point", -6.2088, 106.8456, -6.2088, 106.8456, 0, 1e-9},
+		{"Jakarta to Bandung", -6.2088, 106.8456, -6.9175, 107.6191, 116.0, 2},
+		{"London to Paris", 51.5074, -0.1278, 48.8566, 2.3522, 343.5, 2},
+		{"quarter meridian", 0, 0, 90, 0, math.Pi / 2 * earthRadiusKm, 1e-6},
+		{"antipodes", 0, 0, 0, 180, math.Pi * earthRadiusKm, 1e-6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Haversine(tt.lat1, tt.lon1, tt.lat2, tt.lon2)
+			if math.Abs(got-tt.wantKm) > tt.tolerance {
+				t.Errorf("Haversine() = %.3f km, want %.3f ± %.3f", got, tt.wantKm, tt.tolerance)
+			}
+			if back := Haversine(tt.lat2, tt.lon2, tt.lat1, tt.lon1); math.Abs(back-got) > 1e-9 {
+				t.Errorf("Haversine is not symmetric: %.6f vs %.6f", got, back)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -19,6 +20,8 @@ import (
 )
 
 var (
+	// geoipMu guards geoipDB so the updater can swap readers while lookups run.
+	geoipMu        sync.RWMutex
 	geoipDB        *geoip2.Reader
 	geoipCache     *cache.Cache
 	geoipCacheHits int64
@@ -47,7 +50,9 @@ func InitGeoIP(dbPath string) error {
 	if err != nil {
 		return err
 	}
+	geoipMu.Lock()
 	geoipDB = r
+	geoipMu.Unlock()
 	// Cache entries for 24h, purge every hour
 	geoipCache = cache.New(24*time.Hour, 1*time.Hour)
 	return nil
@@ -55,6 +60,8 @@ func InitGeoIP(dbPath string) error {
 
 // CloseGeoIP closes the GeoIP DB if opened.
 func CloseGeoIP() {
+	geoipMu.Lock()
+	defer geoipMu.Unlock()
 	if geoipDB != nil {
 		_ = geoipDB.Close()
 		geoipDB = nil
@@ -215,11 +222,10 @@ func copyResponseToTemp(reader io.Reader, tmpFile *os.File) error {
 // ValidateGeoIP attempts to open the MMDB file to ensure it's a valid DB.
 func ValidateGeoIP(path string) error {
 	r, err := geoip2.Open(path)
-	if err != nil {
-		return err
+	if r != nil {
+		_ = r.Close()
 	}
-	_ = r.Close()
-	return nil
+	return err
 }
 
 // GetIPLocation returns city and country name for the provided IP using the
@@ -267,6 +273,8 @@ func getCachedOrResolve(ip string) (IPLocation, bool) {
 // the extracted city and country. It returns empty values on any error or
 // if the lookup is unavailable.
 func resolveCityCountryFromNetIP(netip net.IP) IPLocation {
+	geoipMu.RLock()
+	defer geoipMu.RUnlock()
 	if geoipDB == nil || netip == nil {
 		return IPLocation{}
 	}
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+	cache "github.com/patrickmn/go-cache"
+)
+
+// GeoIPUpdateConfig groups the settings for the periodic GeoIP refresh.
+type GeoIPUpdateConfig struct {
+	URL      string
+	DestPath string
+	Interval time.Duration
+}
+
+// GeoIPUpdateConfigFromEnv builds the updater settings from GEOIP_UPDATE_URL,
+// GEOIP_UPDATE_INTERVAL (a Go duration such as "24h") and GEOIP_DB_PATH.
+// It returns false when the updater is not configured.
+func GeoIPUpdateConfigFromEnv() (GeoIPUpdateConfig, bool) {
+	cfg := GeoIPUpdateConfig{
+		URL:      os.Getenv("GEOIP_UPDATE_URL"),
+		DestPath: os.Getenv("GEOIP_DB_PATH"),
+	}
+	if cfg.URL == "" || cfg.DestPath == "" {
+		return cfg, false
+	}
+	interval, err := time.ParseDuration(os.Getenv("GEOIP_UPDATE_INTERVAL"))
+	if err != nil || interval <= 0 {
+		interval = 24 * time.Hour
+	}
+	cfg.Interval = interval
+	return cfg, true
+}
+
+// StartGeoIPUpdater refreshes the GeoIP database every cfg.Interval until ctx
+// is cancelled. Failed refreshes are logged and the current reader is kept.
+func StartGeoIPUpdater(ctx context.Context, cfg GeoIPUpdateConfig) {
+	if cfg.Interval <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = UpdateGeoIP(ctx, cfg)
+			}
+		}
+	}()
+}
+
+// UpdateGeoIP downloads a new MMDB to a temporary file, validates it, moves it
+// to cfg.DestPath and swaps it in as the active reader. When any step fails the
+// previously loaded reader keeps serving lookups.
+func UpdateGeoIP(ctx context.Context, cfg GeoIPUpdateConfig) error {
+	err := refreshGeoIP(ctx, cfg)
+	if securityLogger != nil {
+		if err != nil {
+			securityLogger.Printf("GeoIP update from %s failed: %v", cfg.URL, err)
+		} else {
+			securityLogger.Printf("GeoIP database updated from %s", cfg.URL)
+		}
+	}
+	return err
+}
+
+func refreshGeoIP(ctx context.Context, cfg GeoIPUpdateConfig) error {
+	if cfg.URL == "" || cfg.DestPath == "" {
+		return errors.New("geoip update requires a URL and destination path")
+	}
+	dl := normalizeDownloadRequest(DownloadRequest{URL: cfg.URL, DestPath: cfg.DestPath})
+	if err := ensureDirExists(dl.TempDir); err != nil {
+		return err
+	}
+
+	tmpPath, err := downloadToTemp(ctx, dl)
+	if err != nil {
+		return err
+	}
+	if err := ValidateGeoIP(tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, dl.DestPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+
+	r, err := geoip2.Open(dl.DestPath)
+	if err != nil {
+		if r != nil {
+			_ = r.Close()
+		}
+		return err
+	}
+	swapGeoIPReader(r)
+	return nil
+}
+
+// swapGeoIPReader installs r as the active reader, closes the previous one and
+// flushes cached lookups that may have come from the old database.
+func swapGeoIPReader(r *geoip2.Reader) {
+	geoipMu.Lock()
+	old := geoipDB
+	geoipDB = r
+	geoipMu.Unlock()
+
+	if old != nil {
+		_ = old.Close()
+	}
+	if geoipCache == nil {
+		geoipCache = cache.New(24*time.Hour, 1*time.Hour)
+		return
+	}
+	geoipCache.Flush()
+}
@@ -0,0 +1,177 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+// minimalMMDB returns the smallest valid GeoLite2-City database: a single
+// search tree node whose records both mean "not found", an empty data section
+// and the metadata map required by the reader.
+func minimalMMDB() []byte {
+	buf := []byte{0x00, 0x00, 0x01, 0x00, 0x00, 0x01} // one node, 24-bit records
+	buf = append(buf, make([]byte, 16)...)            // data section separator
+	buf = append(buf, []byte("\xAB\xCD\xEFMaxMind.com")...)
+	buf = append(buf, 0xE4) // map with 4 entries
+	buf = append(buf, 0x4D)
+	buf = append(buf, "database_type"...)
+	buf = append(buf, 0x4D)
+	buf = append(buf, "GeoLite2-City"...)
+	buf = append(buf, 0x4A)
+	buf = append(buf, "ip_version"...)
+	buf = append(buf, 0xA1, 0x04)
+	buf = append(buf, 0x4A)
+	buf = append(buf, "node_count"...)
+	buf = append(buf, 0xC1, 0x01)
+	buf = append(buf, 0x4B)
+	buf = append(buf, "record_size"...)
+	buf = append(buf, 0xA1, 0x18)
+	return buf
+}
+
+func serveBytes(t *testing.T, data []byte) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// installTestGeoIPReader loads a minimal DB as the active reader and restores
+// the previous state when the test ends.
+func installTestGeoIPReader(t *testing.T) *geoip2.Reader {
+	t.Helper()
+	r, err := geoip2.FromBytes(minimalMMDB())
+	if err != nil {
+		t.Fatalf("failed to open minimal mmdb: %v", err)
+	}
+	geoipMu.Lock()
+	prev := geoipDB
+	geoipDB = r
+	geoipMu.Unlock()
+	t.Cleanup(func() {
+		geoipMu.Lock()
+		if geoipDB != nil && geoipDB != prev {
+			_ = geoipDB.Close()
+		}
+		geoipDB = prev
+		geoipMu.Unlock()
+	})
+	return r
+}
+
+func currentGeoIPReader() *geoip2.Reader {
+	geoipMu.RLock()
+	defer geoipMu.RUnlock()
+	return geoipDB
+}
+
+func TestUpdateGeoIP_SwapsReader(t *testing.T) {
+	old := installTestGeoIPReader(t)
+	server := serveBytes(t, minimalMMDB())
+	destPath := filepath.Join(t.TempDir(), "geoip.mmdb")
+
+	err := UpdateGeoIP(context.Background(), GeoIPUpdateConfig{URL: server.URL, DestPath: destPath})
+	if err != nil {
+		t.Fatalf("expected update to succeed, got %v", err)
+	}
+
+	current := currentGeoIPReader()
+	if current == nil || current == old {
+		t.Fatal("expected geoipDB to be replaced with a new reader")
+	}
+	if current.Metadata().DatabaseType != "GeoLite2-City" {
+		t.Errorf("unexpected database type %q", current.Metadata().DatabaseType)
+	}
+	if _, err := os.Stat(destPath); err != nil {
+		t.Errorf("expected database at %s: %v", destPath, err)
+	}
+}
+
+func TestUpdateGeoIP_InvalidDownloadKeepsOldReader(t *testing.T) {
+	old := installTestGeoIPReader(t)
+	server := serveBytes(t, []byte("not a maxmind database"))
+	dir := t.TempDir()
+	destPath := filepath.Join(dir, "geoip.mmdb")
+
+	err := UpdateGeoIP(context.Background(), GeoIPUpdateConfig{URL: server.URL, DestPath: destPath})
+	if err == nil {
+		t.Fatal("expected validation error for invalid database")
+	}
+
+	if currentGeoIPReader() != old {
+		t.Fatal("expected the previous reader to remain active")
+	}
+	if old.Metadata().DatabaseType != "GeoLite2-City" {
+		t.Error("expected the previous reader to remain usable")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to read temp dir: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no files left behind, found %d", len(entries))
+	}
+}
+
+func TestStartGeoIPUpdater_RefreshesOnInterval(t *testing.T) {
+	old := installTestGeoIPReader(t)
+	// Only the first download is valid so later ticks cannot swap the reader
+	// underneath the test cleanup.
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			_, _ = w.Write(minimalMMDB())
+			return
+		}
+		_, _ = w.Write([]byte("invalid"))
+	}))
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	StartGeoIPUpdater(ctx, GeoIPUpdateConfig{
+		URL:      server.URL,
+		DestPath: filepath.Join(t.TempDir(), "geoip.mmdb"),
+		Interval: 10 * time.Millisecond,
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for currentGeoIPReader() == old {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the scheduler to swap in a new reader")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGeoIPUpdateConfigFromEnv(t *testing.T) {
+	t.Setenv("GEOIP_UPDATE_URL", "")
+	t.Setenv("GEOIP_DB_PATH", "/tmp/geoip.mmdb")
+	if _, ok := GeoIPUpdateConfigFromEnv(); ok {
+		t.Error("expected updater to be disabled without a URL")
+	}
+
+	t.Setenv("GEOIP_UPDATE_URL", "https://example.com/geoip.mmdb")
+	t.Setenv("GEOIP_UPDATE_INTERVAL", "bogus")
+	cfg, ok := GeoIPUpdateConfigFromEnv()
+	if !ok || cfg.Interval != 24*time.Hour {
+		t.Errorf("expected default 24h interval, got %v (ok=%v)", cfg.Interval, ok)
+	}
+
+	t.Setenv("GEOIP_UPDATE_INTERVAL", "6h")
+	cfg, _ = GeoIPUpdateConfigFromEnv()
+	if cfg.Interval != 6*time.Hour {
+		t.Errorf("expected 6h interval, got %v", cfg.Interval)
+	}
+}
@@ -17,6 +17,16 @@ type APIResponse struct {
 type APIErrorParams struct {
 	Msg string
 	Err error
+	// Data optionally carries error details (e.g. per-field validation errors).
+	Data interface{}
+}
+
+// errorData returns the error details to include in a response, defaulting to an empty object.
+func errorData(params APIErrorParams) interface{} {
+	if params.Data != nil {
+		return params.Data
+	}
+	return map[string]interface{}{}
 }
 
 type APISuccessParams struct {
@@ -51,7 +61,7 @@ func CallUserError(c *gin.Context, params APIErrorParams) {
 		Success: false,
 		Error:   params.Err.Error(),
 		Msg:     params.Msg,
-		Data:    map[string]interface{}{},
+		Data:    errorData(params),
 	}
 	c.JSON(http.StatusBadRequest, response)
 }
@@ -99,6 +109,34 @@ func CallUserNotAuthorized(c *gin.Context, params APIErrorParams) {
 	c.JSON(http.StatusUnauthorized, response)
 }
 
+// CallForbidden is for return API response with status code 403, you need to specify msg and error as function parameter
+func CallForbidden(c *gin.Context, params APIErrorParams) {
+	response := APIResponse{
+		Success: false,
+		Error:   params.Err.Error(),
+		Msg:     params.Msg,
+		Data:    errorData(params),
+	}
+	c.JSON(http.StatusForbidden, response)
+}
+
+// CallTooManyRequests is for return API response with status code 429, you need to specify msg and error as function parameter
+func CallTooManyRequests(c *gin.Context, params APIErrorParams) {
+	response := APIResponse{
+		Success: false,
+		Error:   params.Err.Error(),
+		Msg:     params.Msg,
+		Data:    errorData(params),
+	}
+	c.JSON(http.StatusTooManyRequests, response)
+}
+
+// NormalizePatientCode trims a patient code and upper-cases it, so "p001 "
+// and "P001" refer to the same patient.
+func NormalizePatientCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
 // NormalizeName normalizes a name by trimming leading/trailing whitespace
 // and collapsing multiple internal spaces into single spaces.
 // This ensures consistent name formatting and helps prevent duplicate detection bypass.
@@ -79,3 +79,16 @@ func TestNormalizeName(t *testing.T) {
 		})
 	}
 }
+
+func TestNormalizePatientCode(t *testing.T) {
+	for input, expected := range map[string]string{
+		"P001":   "P001",
+		"p001":   "P001",
+		" j12  ": "J12",
+		"":       "",
+	} {
+		if got := NormalizePatientCode(input); got != expected {
+			t.Errorf("NormalizePatientCode(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+// Package interop maps records to the simplified FHIR-like JSON consumed by
+// partner systems. Only the fields those systems read are mapped.
+package interop
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+)
+
+// Reference points at another resource, e.g. "Patient/J001".
+type Reference struct {
+	Reference string `json:"reference" example:"Patient/J001"`
+	Display   string `json:"display,omitempty" example:"John Doe"`
+}
+
+// Period is the date range of an encounter, as YYYY-MM-DD.
+type Period struct {
+	Start string `json:"start" example:"2025-01-15"`
+	End   string `json:"end" example:"2025-01-15"`
+}
+
+// CodeableConcept carries free text where FHIR would use a coded value.
+type CodeableConcept struct {
+	Text string `json:"text" example:"Back pain"`
+}
+
+// Encounter is a minimal FHIR Encounter-style resource for one treatment.
+// @Description FHIR-lite Encounter for a treatment
+type Encounter struct {
+	ResourceType string            `json:"resourceType" example:"Encounter"`
+	ID           string            `json:"id" example:"42"`
+	Status       string            `json:"status" example:"finished"`
+	Subject      Reference         `json:"subject"`
+	Performer    []Reference       `json:"performer"`
+	Period       Period            `json:"period"`
+	ReasonCode   []CodeableConcept `json:"reasonCode"`
+}
+
+// encounterStatuses maps treatment statuses to FHIR Encounter statuses.
+var encounterStatuses = map[string]string{
+	model.TreatmentStatusScheduled: "planned",
+	model.TreatmentStatusCompleted: "finished",
+	model.TreatmentStatusNoShow:    "cancelled",
+	model.TreatmentStatusCancelled: "cancelled",
+}
+
+// PatientReference returns the reference to a patient, keyed by patient code.
+func PatientReference(patientCode string) string {
+	return "Patient/" + patientCode
+}
+
+// PractitionerReference returns the reference to a therapist, keyed by ID.
+func PractitionerReference(therapistID uint) string {
+	return fmt.Sprintf("Practitioner/%d", therapistID)
+}
+
+// EncounterFromTreatment maps a treatment to an Encounter. patientName and
+// therapistName become the reference displays and may be empty.
+func EncounterFromTreatment(t model.Treatment, patientName, therapistName string) Encounter {
+	status, ok := encounterStatuses[t.Status]
+	if !ok {
+		status = "unknown"
+	}
+	reasons := []CodeableConcept{}
+	if issues := strings.TrimSpace(t.Issues); issues != "" {
+		reasons = append(reasons, CodeableConcept{Text: issues})
+	}
+	return Encounter{
+		ResourceType: "Encounter",
+		ID:           fmt.Sprintf("%d", t.ID),
+		Status:       status,
+		Subject:      Reference{Reference: PatientReference(t.PatientCode), Display: patientName},
+		Performer:    []Reference{{Reference: PractitionerReference(t.TherapistID), Display: therapistName}},
+		Period:       Period{Start: t.TreatmentDate, End: t.TreatmentDate},
+		ReasonCode:   reasons,
+	}
+}
@@ -0,0 +1,65 @@
+package interop
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+)
+
+func TestEncounterFromTreatment(t *testing.T) {
+	tr := model.Treatment{TreatmentDate: "2025-01-15", PatientCode: "J001", TherapistID: 7, Issues: " Back pain ", Status: model.TreatmentStatusCompleted}
+	tr.ID = 42
+
+	enc := EncounterFromTreatment(tr, "John Doe", "Dr. Smith")
+	if enc.ResourceType != "Encounter" || enc.ID != "42" || enc.Status != "finished" {
+		t.Fatalf("unexpected encounter header: %+v", enc)
+	}
+	if enc.Subject != (Reference{Reference: "Patient/J001", Display: "John Doe"}) {
+		t.Errorf("unexpected subject: %+v", enc.Subject)
+	}
+	if len(enc.Performer) != 1 || enc.Performer[0] != (Reference{Reference: "Practitioner/7", Display: "Dr. Smith"}) {
+		t.Errorf("unexpected performer: %+v", enc.Performer)
+	}
+	if enc.Period != (Period{Start: "2025-01-15", End: "2025-01-15"}) {
+		t.Errorf("unexpected period: %+v", enc.Period)
+	}
+	if len(enc.ReasonCode) != 1 || enc.ReasonCode[0].Text != "Back pain" {
+		t.Errorf("unexpected reasonCode: %+v", enc.ReasonCode)
+	}
+
+	b, err := json.Marshal(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"resourceType", "subject", "performer", "period", "reasonCode"} {
+		if _, ok := raw[key]; !ok {
+			t.Errorf("missing %q in %s", key, b)
+		}
+	}
+}
+
+func TestEncounterFromTreatment_StatusAndEmptyIssues(t *testing.T) {
+	cases := map[string]string{
+		model.TreatmentStatusScheduled: "planned",
+		model.TreatmentStatusNoShow:    "cancelled",
+		model.TreatmentStatusCancelled: "cancelled",
+		"":                             "unknown",
+	}
+	for status, want := range cases {
+		enc := EncounterFromTreatment(model.Treatment{Status: status, PatientCode: "J001"}, "", "")
+		if enc.Status != want {
+			t.Errorf("status %q: got %q, want %q", status, enc.Status, want)
+		}
+		if len(enc.ReasonCode) != 0 {
+			t.Errorf("expected no reasonCode for empty issues, got %+v", enc.ReasonCode)
+		}
+		if enc.Subject.Display != "" || enc.Performer[0].Display != "" {
+			t.Errorf("expected empty displays, got %+v", enc)
+		}
+	}
+}
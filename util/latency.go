@@ -0,0 +1,178 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the request latency
+// histogram buckets. Requests slower than the last bound fall in +Inf.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// routeHistogram counts one route's requests per latency bucket. Fields are
+// updated atomically so recording never takes a lock.
+type routeHistogram struct {
+	method string
+	route  string
+	counts []atomic.Int64 // len(latencyBuckets)+1, the last one is +Inf
+	count  atomic.Int64
+	sumNs  atomic.Int64
+}
+
+// routeLatencies maps "METHOD route" to its *routeHistogram. Routes are
+// added once and read on every request, which suits sync.Map.
+var routeLatencies sync.Map
+
+// RouteLatency is a point-in-time snapshot of one route's latencies. P50 and
+// P95 are estimated from the histogram buckets, in seconds.
+type RouteLatency struct {
+	Method  string
+	Route   string
+	Buckets []int64 // cumulative counts per latencyBuckets bound, then +Inf
+	Count   int64
+	Sum     float64
+	P50     float64
+	P95     float64
+}
+
+// RecordRouteLatency adds one request to the latency histogram of the route
+// pattern (for example "/patient/:id"). Requests that matched no route are
+// not recorded, so unknown paths cannot grow the metrics without bound.
+func RecordRouteLatency(method, route string, d time.Duration) {
+	if route == "" {
+		return
+	}
+	key := method + " " + route
+	h, ok := routeLatencies.Load(key)
+	if !ok {
+		h, _ = routeLatencies.LoadOrStore(key, &routeHistogram{
+			method: method,
+			route:  route,
+			counts: make([]atomic.Int64, len(latencyBuckets)+1),
+		})
+	}
+	hist := h.(*routeHistogram)
+	hist.counts[sort.SearchFloat64s(latencyBuckets, d.Seconds())].Add(1)
+	hist.count.Add(1)
+	hist.sumNs.Add(int64(d))
+}
+
+// GetRouteLatencies returns a snapshot of every recorded route, ordered by
+// route then method.
+func GetRouteLatencies() []RouteLatency {
+	var out []RouteLatency
+	routeLatencies.Range(func(_, v interface{}) bool {
+		out = append(out, v.(*routeHistogram).snapshot())
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Route != out[j].Route {
+			return out[i].Route < out[j].Route
+		}
+		return out[i].Method < out[j].Method
+	})
+	return out
+}
+
+// ResetRouteLatenciesForTest drops all recorded latencies for testing purposes
+func ResetRouteLatenciesForTest() {
+	routeLatencies.Range(func(k, _ interface{}) bool {
+		routeLatencies.Delete(k)
+		return true
+	})
+}
+
+func (h *routeHistogram) snapshot() RouteLatency {
+	s := RouteLatency{
+		Method:  h.method,
+		Route:   h.route,
+		Buckets: make([]int64, len(h.counts)),
+		Sum:     time.Duration(h.sumNs.Load()).Seconds(),
+	}
+	var total int64
+	for i := range h.counts {
+		total += h.counts[i].Load()
+		s.Buckets[i] = total
+	}
+	// Count is taken from the buckets so the snapshot is self-consistent
+	// while other requests are being recorded.
+	s.Count = total
+	s.P50 = bucketQuantile(0.5, s.Buckets)
+	s.P95 = bucketQuantile(0.95, s.Buckets)
+	return s
+}
+
+// bucketQuantile estimates the q-quantile from cumulative bucket counts by
+// interpolating linearly within the bucket that contains it, as Prometheus'
+// histogram_quantile does. Quantiles in the +Inf bucket report the highest
+// finite bound.
+func bucketQuantile(q float64, cumulative []int64) float64 {
+	total := cumulative[len(cumulative)-1]
+	if total == 0 {
+		return 0
+	}
+	rank := q * float64(total)
+	i := sort.Search(len(cumulative), func(i int) bool { return float64(cumulative[i]) >= rank })
+	if i >= len(latencyBuckets) {
+		return latencyBuckets[len(latencyBuckets)-1]
+	}
+	lower, below := 0.0, int64(0)
+	if i > 0 {
+		lower, below = latencyBuckets[i-1], cumulative[i-1]
+	}
+	inBucket := cumulative[i] - below
+	if inBucket == 0 {
+		return latencyBuckets[i]
+	}
+	return lower + (latencyBuckets[i]-lower)*(rank-float64(below))/float64(inBucket)
+}
+
+// promLabelValue escapes a label value for the Prometheus text format.
+func promLabelValue(v string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(v)
+}
+
+// writeRouteLatencyMetrics writes the per-route latency histograms and their
+// p50/p95 estimates.
+func writeRouteLatencyMetrics(w io.Writer) error {
+	routes := GetRouteLatencies()
+	if _, err := fmt.Fprint(w,
+		"# HELP http_request_duration_seconds Request latency per route.\n",
+		"# TYPE http_request_duration_seconds histogram\n"); err != nil {
+		return err
+	}
+	for _, r := range routes {
+		labels := fmt.Sprintf(`method="%s",route="%s"`, promLabelValue(r.Method), promLabelValue(r.Route))
+		for i, n := range r.Buckets {
+			le := "+Inf"
+			if i < len(latencyBuckets) {
+				le = strconv.FormatFloat(latencyBuckets[i], 'g', -1, 64)
+			}
+			if _, err := fmt.Fprintf(w, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, le, n); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "http_request_duration_seconds_sum{%s} %g\nhttp_request_duration_seconds_count{%s} %d\n", labels, r.Sum, labels, r.Count); err != nil {
+			return err
+		}
+	}
+
+	if _, err := fmt.Fprint(w,
+		"# HELP http_request_duration_seconds_estimate Request latency quantiles per route, estimated from the histogram buckets.\n",
+		"# TYPE http_request_duration_seconds_estimate gauge\n"); err != nil {
+		return err
+	}
+	for _, r := range routes {
+		labels := fmt.Sprintf(`method="%s",route="%s"`, promLabelValue(r.Method), promLabelValue(r.Route))
+		if _, err := fmt.Fprintf(w, "http_request_duration_seconds_estimate{%s,quantile=\"0.5\"} %g\nhttp_request_duration_seconds_estimate{%s,quantile=\"0.95\"} %g\n", labels, r.P50, labels, r.P95); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordRouteLatency_Snapshot(t *testing.T) {
+	ResetRouteLatenciesForTest()
+	defer ResetRouteLatenciesForTest()
+
+	for i := 0; i < 9; i++ {
+		RecordRouteLatency("GET", "/patient/:id", 3*time.Millisecond)
+	}
+	RecordRouteLatency("GET", "/patient/:id", 300*time.Millisecond)
+	RecordRouteLatency("GET", "", time.Second) // unmatched route
+
+	routes := GetRouteLatencies()
+	if len(routes) != 1 {
+		t.Fatalf("expected 1 route, got %+v", routes)
+	}
+	got := routes[0]
+	if got.Method != "GET" || got.Route != "/patient/:id" || got.Count != 10 {
+		t.Fatalf("unexpected snapshot %+v", got)
+	}
+	if got.Buckets[0] != 9 || got.Buckets[len(got.Buckets)-1] != 10 {
+		t.Errorf("unexpected cumulative buckets %v", got.Buckets)
+	}
+	if got.P50 <= 0 || got.P50 > 0.005 {
+		t.Errorf("expected p50 within the first bucket, got %v", got.P50)
+	}
+	if got.P95 <= 0.25 || got.P95 > 0.5 {
+		t.Errorf("expected p95 within the 0.5s bucket, got %v", got.P95)
+	}
+}
+
+func TestBucketQuantile(t *testing.T) {
+	cumulative := make([]int64, len(latencyBuckets)+1)
+	if got := bucketQuantile(0.5, cumulative); got != 0 {
+		t.Errorf("expected 0 without observations, got %v", got)
+	}
+
+	// All observations above the last bound report the last bound.
+	cumulative[len(cumulative)-1] = 4
+	if got := bucketQuantile(0.95, cumulative); got != 10 {
+		t.Errorf("expected the highest finite bound, got %v", got)
+	}
+}
+
+func TestWritePrometheusMetrics_RouteLatency(t *testing.T) {
+	ResetRouteLatenciesForTest()
+	defer ResetRouteLatenciesForTest()
+
+	RecordRouteLatency("POST", "/login", 20*time.Millisecond)
+
+	var buf bytes.Buffer
+	if err := WritePrometheusMetrics(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE http_request_duration_seconds histogram\n",
+		`http_request_duration_seconds_bucket{method="POST",route="/login",le="0.01"} 0` + "\n",
+		`http_request_duration_seconds_bucket{method="POST",route="/login",le="0.025"} 1` + "\n",
+		`http_request_duration_seconds_bucket{method="POST",route="/login",le="+Inf"} 1` + "\n",
+		`http_request_duration_seconds_count{method="POST",route="/login"} 1` + "\n",
+		`http_request_duration_seconds_estimate{method="POST",route="/login",quantile="0.5"}`,
+		`http_request_duration_seconds_estimate{method="POST",route="/login",quantile="0.95"}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %q\nGot:\n%s", want, out)
+		}
+	}
+}
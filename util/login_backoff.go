@@ -0,0 +1,198 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ariebrainware/basis-data-ltt/config"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultLoginBackoffBase = time.Second
+	defaultLoginBackoffMax  = 5 * time.Minute
+
+	// loginBackoffMemoryLimit caps the in-memory store, which is keyed by
+	// whatever email was submitted.
+	loginBackoffMemoryLimit = 10000
+)
+
+// LoginBackoffConfig controls the per-account delay enforced between failed
+// login attempts. After the nth consecutive failure the next attempt is
+// rejected until Base*2^n has elapsed, capped at Max.
+type LoginBackoffConfig struct {
+	Enabled bool
+	Base    time.Duration
+	Max     time.Duration
+}
+
+// LoginBackoffConfigFromEnv reads LOGIN_BACKOFF_ENABLED, LOGIN_BACKOFF_BASE and
+// LOGIN_BACKOFF_MAX (Go durations such as "1s" or "5m").
+func LoginBackoffConfigFromEnv() LoginBackoffConfig {
+	cfg := LoginBackoffConfig{
+		Enabled: os.Getenv("LOGIN_BACKOFF_ENABLED") == "true",
+		Base:    defaultLoginBackoffBase,
+		Max:     defaultLoginBackoffMax,
+	}
+	if d, err := time.ParseDuration(os.Getenv("LOGIN_BACKOFF_BASE")); err == nil && d > 0 {
+		cfg.Base = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("LOGIN_BACKOFF_MAX")); err == nil && d > 0 {
+		cfg.Max = d
+	}
+	return cfg
+}
+
+// delay returns how long to wait after the given number of consecutive failures.
+func (cfg LoginBackoffConfig) delay(failures int64) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	d := cfg.Base
+	for i := int64(0); i < failures; i++ {
+		d *= 2
+		if d >= cfg.Max {
+			return cfg.Max
+		}
+	}
+	return d
+}
+
+// loginBackoffState is the failure count and time of the latest failure for an account.
+type loginBackoffState struct {
+	failures    int64
+	lastFailure time.Time
+}
+
+// loginBackoffMemory is the fallback store used when Redis is unavailable.
+var loginBackoffMemory = struct {
+	sync.Mutex
+	entries map[string]loginBackoffState
+}{entries: make(map[string]loginBackoffState)}
+
+// loginBackoffNow is swapped in tests to control the clock.
+var loginBackoffNow = time.Now
+
+func loginBackoffKey(email string) string {
+	return fmt.Sprintf("login_backoff:%s", strings.ToLower(strings.TrimSpace(email)))
+}
+
+func getLoginBackoffStateWithClient(rdb *redis.Client, key string) (loginBackoffState, error) {
+	if rdb == nil {
+		loginBackoffMemory.Lock()
+		defer loginBackoffMemory.Unlock()
+		return loginBackoffMemory.entries[key], nil
+	}
+	values, err := rdb.HMGet(context.Background(), key, "failures", "last").Result()
+	if err != nil {
+		return loginBackoffState{}, err
+	}
+	var state loginBackoffState
+	if s, ok := values[0].(string); ok {
+		state.failures, _ = strconv.ParseInt(s, 10, 64)
+	}
+	if s, ok := values[1].(string); ok {
+		last, _ := strconv.ParseInt(s, 10, 64)
+		state.lastFailure = time.UnixMilli(last)
+	}
+	return state, nil
+}
+
+func recordLoginBackoffFailureWithClient(rdb *redis.Client, key string, ttl time.Duration) error {
+	now := loginBackoffNow()
+	if rdb == nil {
+		loginBackoffMemory.Lock()
+		defer loginBackoffMemory.Unlock()
+		state := loginBackoffMemory.entries[key]
+		if !state.lastFailure.IsZero() && now.Sub(state.lastFailure) > ttl {
+			state.failures = 0
+		}
+		state.failures++
+		state.lastFailure = now
+		if _, ok := loginBackoffMemory.entries[key]; !ok && len(loginBackoffMemory.entries) >= loginBackoffMemoryLimit {
+			evictLoginBackoffMemory(now, ttl)
+		}
+		loginBackoffMemory.entries[key] = state
+		return nil
+	}
+	ctx := context.Background()
+	pipe := rdb.TxPipeline()
+	pipe.HIncrBy(ctx, key, "failures", 1)
+	pipe.HSet(ctx, key, "last", now.UnixMilli())
+	pipe.Expire(ctx, key, ttl)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// evictLoginBackoffMemory drops entries quiet for longer than ttl and, if the
+// store is still full, the entry with the oldest failure. The caller holds the
+// lock.
+func evictLoginBackoffMemory(now time.Time, ttl time.Duration) {
+	var oldestKey string
+	var oldest time.Time
+	for k, s := range loginBackoffMemory.entries {
+		if now.Sub(s.lastFailure) > ttl {
+			delete(loginBackoffMemory.entries, k)
+			continue
+		}
+		if oldestKey == "" || s.lastFailure.Before(oldest) {
+			oldestKey, oldest = k, s.lastFailure
+		}
+	}
+	if len(loginBackoffMemory.entries) >= loginBackoffMemoryLimit {
+		delete(loginBackoffMemory.entries, oldestKey)
+	}
+}
+
+func resetLoginBackoffWithClient(rdb *redis.Client, key string) error {
+	if rdb == nil {
+		loginBackoffMemory.Lock()
+		defer loginBackoffMemory.Unlock()
+		delete(loginBackoffMemory.entries, key)
+		return nil
+	}
+	return rdb.Del(context.Background(), key).Err()
+}
+
+// LoginBackoffRemaining returns how long the account must wait before another
+// login attempt is accepted. It returns 0 when backoff is disabled, the
+// account has no recent failures, or the backoff store cannot be read.
+func LoginBackoffRemaining(email string) time.Duration {
+	cfg := LoginBackoffConfigFromEnv()
+	if !cfg.Enabled {
+		return 0
+	}
+	state, err := getLoginBackoffStateWithClient(config.GetRedisClient(), loginBackoffKey(email))
+	if err != nil || state.failures == 0 {
+		return 0
+	}
+	remaining := state.lastFailure.Add(cfg.delay(state.failures)).Sub(loginBackoffNow())
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// RecordLoginBackoffFailure counts a failed login for the account. Failures
+// are forgotten once the account has been quiet for twice the maximum delay.
+func RecordLoginBackoffFailure(email string) {
+	cfg := LoginBackoffConfigFromEnv()
+	if !cfg.Enabled {
+		return
+	}
+	if err := recordLoginBackoffFailureWithClient(config.GetRedisClient(), loginBackoffKey(email), 2*cfg.Max); err != nil && securityLogger != nil {
+		securityLogger.Printf("Failed to record login backoff for %s: %v", email, err)
+	}
+}
+
+// ResetLoginBackoff clears the failure history for the account after a successful login.
+func ResetLoginBackoff(email string) {
+	if err := resetLoginBackoffWithClient(config.GetRedisClient(), loginBackoffKey(email)); err != nil && securityLogger != nil {
+		securityLogger.Printf("Failed to reset login backoff for %s: %v", email, err)
+	}
+}
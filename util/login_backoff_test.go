@@ -0,0 +1,176 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redismock/v9"
+)
+
+func withLoginBackoffClock(t *testing.T, start time.Time) *time.Time {
+	t.Helper()
+	now := start
+	prev := loginBackoffNow
+	loginBackoffNow = func() time.Time { return now }
+	t.Cleanup(func() { loginBackoffNow = prev })
+	return &now
+}
+
+func TestLoginBackoffDelay(t *testing.T) {
+	cfg := LoginBackoffConfig{Enabled: true, Base: time.Second, Max: 10 * time.Second}
+	cases := map[int64]time.Duration{
+		0:  0,
+		1:  2 * time.Second,
+		2:  4 * time.Second,
+		3:  8 * time.Second,
+		4:  10 * time.Second,
+		40: 10 * time.Second,
+	}
+	for failures, want := range cases {
+		if got := cfg.delay(failures); got != want {
+			t.Errorf("delay(%d) = %v, want %v", failures, got, want)
+		}
+	}
+}
+
+func TestLoginBackoffConfigFromEnv(t *testing.T) {
+	t.Setenv("LOGIN_BACKOFF_ENABLED", "")
+	t.Setenv("LOGIN_BACKOFF_BASE", "")
+	t.Setenv("LOGIN_BACKOFF_MAX", "bogus")
+	cfg := LoginBackoffConfigFromEnv()
+	if cfg.Enabled || cfg.Base != defaultLoginBackoffBase || cfg.Max != defaultLoginBackoffMax {
+		t.Fatalf("unexpected defaults: %+v", cfg)
+	}
+
+	t.Setenv("LOGIN_BACKOFF_ENABLED", "true")
+	t.Setenv("LOGIN_BACKOFF_BASE", "500ms")
+	t.Setenv("LOGIN_BACKOFF_MAX", "1m")
+	cfg = LoginBackoffConfigFromEnv()
+	if !cfg.Enabled || cfg.Base != 500*time.Millisecond || cfg.Max != time.Minute {
+		t.Fatalf("unexpected config: %+v", cfg)
+	}
+}
+
+func TestLoginBackoff_InMemoryEnforcement(t *testing.T) {
+	t.Setenv("LOGIN_BACKOFF_ENABLED", "true")
+	t.Setenv("LOGIN_BACKOFF_BASE", "1s")
+	t.Setenv("LOGIN_BACKOFF_MAX", "8s")
+	now := withLoginBackoffClock(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	email := "Backoff@Example.com"
+	t.Cleanup(func() { ResetLoginBackoff(email) })
+
+	if got := LoginBackoffRemaining(email); got != 0 {
+		t.Fatalf("expected no backoff before failures, got %v", got)
+	}
+
+	// Rapid failures double the wait each time until the cap.
+	for i, want := range []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second} {
+		RecordLoginBackoffFailure(email)
+		if got := LoginBackoffRemaining("backoff@example.com"); got != want {
+			t.Fatalf("after failure %d: remaining = %v, want %v", i+1, got, want)
+		}
+	}
+
+	*now = now.Add(5 * time.Second)
+	if got := LoginBackoffRemaining(email); got != 3*time.Second {
+		t.Fatalf("expected 3s remaining after partial wait, got %v", got)
+	}
+	*now = now.Add(3 * time.Second)
+	if got := LoginBackoffRemaining(email); got != 0 {
+		t.Fatalf("expected backoff to elapse, got %v", got)
+	}
+
+	ResetLoginBackoff(email)
+	RecordLoginBackoffFailure(email)
+	if got := LoginBackoffRemaining(email); got != 2*time.Second {
+		t.Fatalf("expected reset to restart the sequence, got %v", got)
+	}
+}
+
+func TestLoginBackoff_DisabledIsNoop(t *testing.T) {
+	t.Setenv("LOGIN_BACKOFF_ENABLED", "false")
+	email := "disabled-backoff@example.com"
+	t.Cleanup(func() { ResetLoginBackoff(email) })
+
+	RecordLoginBackoffFailure(email)
+	t.Setenv("LOGIN_BACKOFF_ENABLED", "true")
+	if got := LoginBackoffRemaining(email); got != 0 {
+		t.Fatalf("expected failures to be ignored while disabled, got %v", got)
+	}
+}
+
+func TestLoginBackoff_RedisStore(t *testing.T) {
+	rdb, mock := redismock.NewClientMock()
+	now := withLoginBackoffClock(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	key := loginBackoffKey("user@example.com")
+
+	mock.ExpectTxPipeline()
+	mock.ExpectHIncrBy(key, "failures", 1).SetVal(1)
+	mock.ExpectHSet(key, "last", now.UnixMilli()).SetVal(1)
+	mock.ExpectExpire(key, time.Minute).SetVal(true)
+	mock.ExpectTxPipelineExec()
+	if err := recordLoginBackoffFailureWithClient(rdb, key, time.Minute); err != nil {
+		t.Fatalf("record failure: %v", err)
+	}
+
+	mock.ExpectHMGet(key, "failures", "last").SetVal([]interface{}{"3", "1735689600000"})
+	state, err := getLoginBackoffStateWithClient(rdb, key)
+	if err != nil {
+		t.Fatalf("get state: %v", err)
+	}
+	if state.failures != 3 || !state.lastFailure.Equal(*now) {
+		t.Fatalf("unexpected state: %+v", state)
+	}
+
+	mock.ExpectDel(key).SetVal(1)
+	if err := resetLoginBackoffWithClient(rdb, key); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatalf("unmet redis expectations: %v", err)
+	}
+}
+
+func TestLoginBackoff_InMemoryStoreIsBounded(t *testing.T) {
+	now := withLoginBackoffClock(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	loginBackoffMemory.Lock()
+	prev := loginBackoffMemory.entries
+	loginBackoffMemory.entries = make(map[string]loginBackoffState)
+	loginBackoffMemory.Unlock()
+	t.Cleanup(func() {
+		loginBackoffMemory.Lock()
+		loginBackoffMemory.entries = prev
+		loginBackoffMemory.Unlock()
+	})
+
+	ttl := time.Minute
+	for i := 0; i < loginBackoffMemoryLimit; i++ {
+		last := now.Add(-time.Duration(i) * time.Millisecond)
+		if i%2 == 0 {
+			last = now.Add(-2 * ttl)
+		}
+		loginBackoffMemory.entries[loginBackoffKey(fmt.Sprintf("user%d@example.com", i))] = loginBackoffState{failures: 1, lastFailure: last}
+	}
+
+	if err := recordLoginBackoffFailureWithClient(nil, loginBackoffKey("new@example.com"), ttl); err != nil {
+		t.Fatalf("record failure: %v", err)
+	}
+	if got, want := len(loginBackoffMemory.entries), loginBackoffMemoryLimit/2+1; got != want {
+		t.Fatalf("expected stale entries to be swept leaving %d, got %d", want, got)
+	}
+
+	for i := 0; len(loginBackoffMemory.entries) < loginBackoffMemoryLimit; i++ {
+		loginBackoffMemory.entries[loginBackoffKey(fmt.Sprintf("fresh%d@example.com", i))] = loginBackoffState{failures: 1, lastFailure: *now}
+	}
+	if err := recordLoginBackoffFailureWithClient(nil, loginBackoffKey("newest@example.com"), ttl); err != nil {
+		t.Fatalf("record failure: %v", err)
+	}
+	if got := len(loginBackoffMemory.entries); got != loginBackoffMemoryLimit {
+		t.Fatalf("expected the store to stay at %d entries, got %d", loginBackoffMemoryLimit, got)
+	}
+	oldest := loginBackoffKey(fmt.Sprintf("user%d@example.com", loginBackoffMemoryLimit-1))
+	if _, ok := loginBackoffMemory.entries[oldest]; ok {
+		t.Fatalf("expected the oldest entry to be evicted")
+	}
+}
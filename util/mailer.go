@@ -0,0 +1,61 @@
+package util
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+)
+
+// MailerConfig groups the SMTP settings used to send email.
+type MailerConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// MailerConfigFromEnv builds the SMTP settings from SMTP_HOST, SMTP_PORT
+// (default 587), SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM. It returns false
+// when no mailer is configured, i.e. SMTP_HOST or SMTP_FROM is empty.
+func MailerConfigFromEnv() (MailerConfig, bool) {
+	cfg := MailerConfig{
+		Host:     strings.TrimSpace(os.Getenv("SMTP_HOST")),
+		Port:     strings.TrimSpace(os.Getenv("SMTP_PORT")),
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     strings.TrimSpace(os.Getenv("SMTP_FROM")),
+	}
+	if cfg.Port == "" {
+		cfg.Port = "587"
+	}
+	return cfg, cfg.Host != "" && cfg.From != ""
+}
+
+// smtpSendMail delivers a message; tests replace it to capture mail.
+var smtpSendMail = smtp.SendMail
+
+// SendMail sends a plain-text email through the configured SMTP server. It
+// fails when no mailer is configured. Header values are stripped of line
+// breaks so they cannot inject extra headers.
+func SendMail(to, subject, body string) error {
+	cfg, ok := MailerConfigFromEnv()
+	if !ok {
+		return fmt.Errorf("no mailer configured: SMTP_HOST and SMTP_FROM are required")
+	}
+	headerValue := strings.NewReplacer("\r", "", "\n", "").Replace
+	msg := "From: " + headerValue(cfg.From) + "\r\n" +
+		"To: " + headerValue(to) + "\r\n" +
+		"Subject: " + headerValue(subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" + body
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return smtpSendMail(net.JoinHostPort(cfg.Host, cfg.Port), auth, cfg.From, []string{headerValue(to)}, []byte(msg))
+}
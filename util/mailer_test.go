@@ -0,0 +1,63 @@
+package util
+
+import (
+	"net/smtp"
+	"strings"
+	"testing"
+)
+
+func TestMailerConfigFromEnv(t *testing.T) {
+	t.Setenv("SMTP_HOST", "")
+	t.Setenv("SMTP_FROM", "noreply@example.com")
+	if _, ok := MailerConfigFromEnv(); ok {
+		t.Fatalf("expected no mailer without SMTP_HOST")
+	}
+
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_PORT", "")
+	cfg, ok := MailerConfigFromEnv()
+	if !ok {
+		t.Fatalf("expected a configured mailer")
+	}
+	if cfg.Port != "587" {
+		t.Errorf("expected default port 587, got %q", cfg.Port)
+	}
+}
+
+func TestSendMail(t *testing.T) {
+	t.Setenv("SMTP_HOST", "smtp.example.com")
+	t.Setenv("SMTP_PORT", "2525")
+	t.Setenv("SMTP_USERNAME", "")
+	t.Setenv("SMTP_FROM", "noreply@example.com")
+
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	orig := smtpSendMail
+	t.Cleanup(func() { smtpSendMail = orig })
+	smtpSendMail = func(addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	if err := SendMail("user@example.com", "Hello\r\nBcc: evil@example.com", "Body"); err != nil {
+		t.Fatalf("SendMail: %v", err)
+	}
+	if gotAddr != "smtp.example.com:2525" {
+		t.Errorf("unexpected address %q", gotAddr)
+	}
+	if len(gotTo) != 1 || gotTo[0] != "user@example.com" {
+		t.Errorf("unexpected recipients %v", gotTo)
+	}
+	if strings.Contains(gotMsg, "\r\nBcc:") {
+		t.Errorf("header injection not stripped: %q", gotMsg)
+	}
+	if !strings.HasSuffix(gotMsg, "\r\n\r\nBody") {
+		t.Errorf("unexpected message %q", gotMsg)
+	}
+
+	t.Setenv("SMTP_HOST", "")
+	if err := SendMail("user@example.com", "Hello", "Body"); err == nil {
+		t.Errorf("expected an error without a mailer")
+	}
+}
@@ -0,0 +1,75 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+var (
+	loginSuccessTotal      int64
+	loginFailureTotal      int64
+	accountLockedTotal     int64
+	rateLimitExceededTotal int64
+
+	loginBackoffRejectedTotal int64
+)
+
+// LoginMetrics is a point-in-time snapshot of the authentication counters.
+type LoginMetrics struct {
+	LoginSuccess      int64 `json:"login_success"`
+	LoginFailure      int64 `json:"login_failure"`
+	AccountLocked     int64 `json:"account_locked"`
+	RateLimitExceeded int64 `json:"rate_limit_exceeded"`
+	LoginBackoff      int64 `json:"login_backoff"`
+}
+
+// GetLoginMetrics returns the current values of the authentication counters.
+func GetLoginMetrics() LoginMetrics {
+	return LoginMetrics{
+		LoginSuccess:      atomic.LoadInt64(&loginSuccessTotal),
+		LoginFailure:      atomic.LoadInt64(&loginFailureTotal),
+		AccountLocked:     atomic.LoadInt64(&accountLockedTotal),
+		RateLimitExceeded: atomic.LoadInt64(&rateLimitExceededTotal),
+		LoginBackoff:      atomic.LoadInt64(&loginBackoffRejectedTotal),
+	}
+}
+
+// ResetLoginMetricsForTest zeroes the authentication counters for testing purposes
+func ResetLoginMetricsForTest() {
+	atomic.StoreInt64(&loginSuccessTotal, 0)
+	atomic.StoreInt64(&loginFailureTotal, 0)
+	atomic.StoreInt64(&accountLockedTotal, 0)
+	atomic.StoreInt64(&rateLimitExceededTotal, 0)
+	atomic.StoreInt64(&loginBackoffRejectedTotal, 0)
+}
+
+type promMetric struct {
+	name  string
+	help  string
+	kind  string
+	value int64
+}
+
+// WritePrometheusMetrics writes the login, GeoIP cache and per-route latency
+// metrics to w using the Prometheus text exposition format.
+func WritePrometheusMetrics(w io.Writer) error {
+	login := GetLoginMetrics()
+	hits, misses, size := GetGeoIPCacheMetrics()
+	metrics := []promMetric{
+		{"login_success_total", "Total number of successful logins.", "counter", login.LoginSuccess},
+		{"login_failure_total", "Total number of failed login attempts.", "counter", login.LoginFailure},
+		{"account_locked_total", "Total number of accounts locked after repeated failures.", "counter", login.AccountLocked},
+		{"rate_limit_exceeded_total", "Total number of requests rejected by the rate limiter.", "counter", login.RateLimitExceeded},
+		{"login_backoff_total", "Total number of login attempts rejected by the per-account backoff.", "counter", login.LoginBackoff},
+		{"geoip_cache_hits_total", "Total number of GeoIP cache hits.", "counter", hits},
+		{"geoip_cache_misses_total", "Total number of GeoIP cache misses.", "counter", misses},
+		{"geoip_cache_items", "Current number of entries in the GeoIP cache.", "gauge", int64(size)},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %d\n", m.name, m.help, m.name, m.kind, m.name, m.value); err != nil {
+			return err
+		}
+	}
+	return writeRouteLatencyMetrics(w)
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoginMetrics_IncrementOnEvents(t *testing.T) {
+	_, cleanup := setupTestLogger()
+	defer cleanup()
+	ResetLoginMetricsForTest()
+	defer ResetLoginMetricsForTest()
+
+	LogLoginSuccess(LoginParams{UserID: 1, Email: "a@example.com"})
+	LogLoginSuccess(LoginParams{UserID: 1, Email: "a@example.com"})
+	LogLoginFailure(LoginParams{Email: "a@example.com", Reason: "bad password"})
+	LogAccountLocked(AccountLockParams{UserID: 1, Email: "a@example.com"})
+	LogRateLimitExceeded(RateLimitParams{IP: "203.0.113.1", Endpoint: "/login"})
+	LogRateLimitExceeded(RateLimitParams{IP: "203.0.113.1", Endpoint: "/login"})
+	LogRateLimitExceeded(RateLimitParams{IP: "203.0.113.1", Endpoint: "/login"})
+	LogLoginBackoff(RateLimitParams{Email: "a@example.com", IP: "203.0.113.1", Endpoint: "/login"})
+
+	got := GetLoginMetrics()
+	want := LoginMetrics{LoginSuccess: 2, LoginFailure: 1, AccountLocked: 1, RateLimitExceeded: 3, LoginBackoff: 1}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestLoginMetrics_LogoutDoesNotCount(t *testing.T) {
+	_, cleanup := setupTestLogger()
+	defer cleanup()
+	ResetLoginMetricsForTest()
+	defer ResetLoginMetricsForTest()
+
+	LogLogout(LoginParams{UserID: 1})
+
+	if got := GetLoginMetrics(); got != (LoginMetrics{}) {
+		t.Errorf("expected no counters to change, got %+v", got)
+	}
+}
+
+func TestWritePrometheusMetrics(t *testing.T) {
+	_, cleanup := setupTestLogger()
+	defer cleanup()
+	ResetLoginMetricsForTest()
+	defer ResetLoginMetricsForTest()
+
+	LogLoginFailure(LoginParams{Email: "a@example.com"})
+
+	var buf bytes.Buffer
+	if err := WritePrometheusMetrics(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE login_failure_total counter\nlogin_failure_total 1\n",
+		"login_success_total 0\n",
+		"account_locked_total 0\n",
+		"rate_limit_exceeded_total 0\n",
+		"login_backoff_total 0\n",
+		"# TYPE geoip_cache_items gauge\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("metrics output missing %q\nGot:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package util
+
+// PageResponse is the data of a paginated list response. Every list returns
+// the same keys: the page of items, total (all matches), total_fetched (items
+// on this page), has_more and next_cursor (null for offset-paginated lists).
+type PageResponse map[string]interface{}
+
+// NewPageResponse builds the data of a paginated list response. The items are
+// under "items"; use Named for lists whose clients read them under a resource
+// name. A nil nextCursor is encoded as null.
+func NewPageResponse(items interface{}, total int64, totalFetched int, hasMore bool, nextCursor interface{}) PageResponse {
+	return PageResponse{
+		"items":         items,
+		"total":         total,
+		"total_fetched": totalFetched,
+		"has_more":      hasMore,
+		"next_cursor":   nextCursor,
+	}
+}
+
+// Named returns the page with its items under key, e.g. "users", instead of
+// "items".
+func (p PageResponse) Named(key string) PageResponse {
+	named := make(PageResponse, len(p))
+	for k, v := range p {
+		if k == "items" {
+			k = key
+		}
+		named[k] = v
+	}
+	return named
+}
+
+// OffsetHasMore reports whether matches remain after a page of fetched items
+// that started at offset.
+func OffsetHasMore(offset, fetched int, total int64) bool {
+	return int64(offset+fetched) < total
+}
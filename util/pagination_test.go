@@ -0,0 +1,78 @@
+package util
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewPageResponse(t *testing.T) {
+	cursor := uint(42)
+	tests := []struct {
+		name string
+		page PageResponse
+		want string
+	}{
+		{
+			name: "cursor page with more results",
+			page: NewPageResponse([]string{"a", "b"}, 5, 2, true, &cursor),
+			want: `{"has_more":true,"items":["a","b"],"next_cursor":42,"total":5,"total_fetched":2}`,
+		},
+		{
+			name: "last page has a null cursor",
+			page: NewPageResponse([]string{"e"}, 5, 1, false, nil),
+			want: `{"has_more":false,"items":["e"],"next_cursor":null,"total":5,"total_fetched":1}`,
+		},
+		{
+			name: "typed nil cursor is null",
+			page: NewPageResponse([]int{}, 0, 0, false, (*uint)(nil)),
+			want: `{"has_more":false,"items":[],"next_cursor":null,"total":0,"total_fetched":0}`,
+		},
+		{
+			name: "named items",
+			page: NewPageResponse([]string{"a"}, 1, 1, false, nil).Named("users"),
+			want: `{"has_more":false,"next_cursor":null,"total":1,"total_fetched":1,"users":["a"]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.page)
+			if err != nil {
+				t.Fatalf("marshal: %v", err)
+			}
+			// encoding/json sorts map keys, so the output is deterministic.
+			if string(got) != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPageResponseNamed_LeavesOriginalUnchanged(t *testing.T) {
+	page := NewPageResponse([]string{"a"}, 1, 1, false, nil)
+	_ = page.Named("patients")
+	if _, ok := page["items"]; !ok {
+		t.Fatalf("expected original page to keep items")
+	}
+	if _, ok := page["patients"]; ok {
+		t.Fatalf("expected original page not to gain patients")
+	}
+}
+
+func TestOffsetHasMore(t *testing.T) {
+	tests := []struct {
+		offset, fetched int
+		total           int64
+		want            bool
+	}{
+		{0, 10, 25, true},
+		{10, 10, 25, true},
+		{20, 5, 25, false},
+		{0, 0, 0, false},
+		{30, 0, 25, false},
+	}
+	for _, tt := range tests {
+		if got := OffsetHasMore(tt.offset, tt.fetched, tt.total); got != tt.want {
+			t.Errorf("OffsetHasMore(%d, %d, %d) = %v, want %v", tt.offset, tt.fetched, tt.total, got, tt.want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 	"sync"
@@ -62,6 +63,26 @@ func GenerateSalt() (string, error) {
 	return base64.RawStdEncoding.EncodeToString(salt), nil
 }
 
+// temporaryPasswordAlphabet leaves out characters that are easy to misread
+// when a password is passed on by phone or on paper (0/O, 1/l/I).
+const temporaryPasswordAlphabet = "abcdefghijkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateTemporaryPassword returns a random password of length characters
+// from an alphabet without look-alike characters, for handing to a user who
+// is expected to change it.
+func GenerateTemporaryPassword(length int) (string, error) {
+	buf := make([]byte, length)
+	max := big.NewInt(int64(len(temporaryPasswordAlphabet)))
+	for i := range buf {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		buf[i] = temporaryPasswordAlphabet[n.Int64()]
+	}
+	return string(buf), nil
+}
+
 // HashPasswordArgon2 hashes a password using Argon2id with a unique salt
 // Returns the encoded hash in the format: argon2id$base64(salt)$base64(hash)
 func HashPasswordArgon2(password, salt string) (string, error) {
@@ -1,6 +1,9 @@
 package util
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestHashPasswordDeterministic(t *testing.T) {
 	SetJWTSecret("secret1")
@@ -20,3 +23,19 @@ func TestHashPasswordDifferentSecrets(t *testing.T) {
 		t.Fatalf("expected different hashes for different secrets, both %s", h1)
 	}
 }
+
+func TestGenerateTemporaryPassword(t *testing.T) {
+	p1, err := GenerateTemporaryPassword(12)
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	p2, _ := GenerateTemporaryPassword(12)
+	if len(p1) != 12 || p1 == p2 {
+		t.Fatalf("expected two different 12 character passwords, got %q and %q", p1, p2)
+	}
+	for _, r := range p1 {
+		if !strings.ContainsRune(temporaryPasswordAlphabet, r) {
+			t.Errorf("unexpected character %q in %q", r, p1)
+		}
+	}
+}
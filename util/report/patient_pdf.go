@@ -0,0 +1,136 @@
+// Package report renders printable documents such as patient summaries.
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+	"github.com/go-pdf/fpdf"
+)
+
+// treatmentColumn is one column of the treatment history table.
+type treatmentColumn struct {
+	title string
+	width float64
+	value func(model.TreatmentSummaryItem) string
+}
+
+var treatmentColumns = []treatmentColumn{
+	{"Date", 25, func(t model.TreatmentSummaryItem) string { return t.TreatmentDate }},
+	{"Therapist", 40, func(t model.TreatmentSummaryItem) string { return t.TherapistName }},
+	{"Issues", 55, func(t model.TreatmentSummaryItem) string { return t.Issues }},
+	{"Treatment", 60, func(t model.TreatmentSummaryItem) string { return strings.ReplaceAll(t.Treatment, ",", ", ") }},
+	{"Remarks", 65, func(t model.TreatmentSummaryItem) string { return t.Remarks }},
+	{"Next visit", 32, func(t model.TreatmentSummaryItem) string { return t.NextVisit }},
+}
+
+const (
+	lineHeight = 5.0
+	cellMargin = 1.0
+)
+
+// WritePatientPDF renders the patient's details and treatment history as an
+// A4 landscape PDF and writes it to w.
+func WritePatientPDF(w io.Writer, patient model.Patient, summary model.PatientTreatmentSummary) error {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Patient report %s", summary.PatientCode), true)
+	pdf.SetAutoPageBreak(true, 15)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-12)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(0, lineHeight, fmt.Sprintf("Page %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 10, tr(fmt.Sprintf("%s (%s)", summary.PatientName, summary.PatientCode)), "", 1, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	for _, field := range [][2]string{
+		{"Gender", patient.Gender},
+		{"Age", fmt.Sprintf("%d", patient.Age)},
+		{"Phone", strings.ReplaceAll(patient.PhoneNumber, ",", ", ")},
+		{"Address", patient.Address},
+		{"Health history", strings.ReplaceAll(patient.HealthHistory, ",", ", ")},
+		{"Surgery history", patient.SurgeryHistory},
+		{"Treatments", fmt.Sprintf("%d with %d therapist(s)", summary.TotalTreatments, summary.TotalTherapists)},
+		{"Period", treatmentPeriod(summary)},
+	} {
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(35, lineHeight+1, field[0], "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.MultiCell(0, lineHeight+1, tr(field[1]), "", "L", false)
+	}
+	pdf.Ln(4)
+
+	writeTreatmentTable(pdf, tr, summary.Treatments)
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+func treatmentPeriod(summary model.PatientTreatmentSummary) string {
+	if summary.FirstTreatmentDate == "" {
+		return "-"
+	}
+	return fmt.Sprintf("%s to %s", summary.FirstTreatmentDate, summary.LastTreatmentDate)
+}
+
+func writeTreatmentTableHeader(pdf *fpdf.Fpdf) {
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	for _, col := range treatmentColumns {
+		pdf.CellFormat(col.width, lineHeight+2, col.title, "1", 0, "L", true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Helvetica", "", 9)
+}
+
+// writeTreatmentTable draws one row per treatment, wrapping long cells and
+// repeating the header on each new page.
+func writeTreatmentTable(pdf *fpdf.Fpdf, tr func(string) string, treatments []model.TreatmentSummaryItem) {
+	if len(treatments) == 0 {
+		pdf.SetFont("Helvetica", "I", 10)
+		pdf.CellFormat(0, lineHeight, "No treatments recorded.", "", 1, "L", false, 0, "")
+		return
+	}
+
+	writeTreatmentTableHeader(pdf)
+	_, pageHeight := pdf.GetPageSize()
+	leftMargin, _, _, bottomMargin := pdf.GetMargins()
+
+	for _, t := range treatments {
+		cells := make([][]string, len(treatmentColumns))
+		lines := 1
+		for i, col := range treatmentColumns {
+			for _, line := range pdf.SplitLines([]byte(tr(col.value(t))), col.width-2*cellMargin) {
+				cells[i] = append(cells[i], string(line))
+			}
+			if len(cells[i]) > lines {
+				lines = len(cells[i])
+			}
+		}
+		rowHeight := float64(lines)*lineHeight + 2*cellMargin
+
+		if pdf.GetY()+rowHeight > pageHeight-bottomMargin {
+			pdf.AddPage()
+			writeTreatmentTableHeader(pdf)
+		}
+
+		x, y := pdf.GetXY()
+		for i, col := range treatmentColumns {
+			pdf.Rect(x, y, col.width, rowHeight, "D")
+			for j, line := range cells[i] {
+				pdf.SetXY(x+cellMargin, y+cellMargin+float64(j)*lineHeight)
+				pdf.CellFormat(col.width-2*cellMargin, lineHeight, line, "", 0, "L", false, 0, "")
+			}
+			x += col.width
+		}
+		pdf.SetXY(leftMargin, y+rowHeight)
+	}
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/ariebrainware/basis-data-ltt/model"
+)
+
+var pdfStreamPattern = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+
+// pdfText returns the decompressed content streams of a PDF so tests can
+// look for rendered text.
+func pdfText(t *testing.T, data []byte) string {
+	t.Helper()
+	var out strings.Builder
+	for _, m := range pdfStreamPattern.FindAllSubmatch(data, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(m[1]))
+		if err != nil {
+			out.Write(m[1])
+			continue
+		}
+		b, _ := io.ReadAll(r)
+		out.Write(b)
+	}
+	return out.String()
+}
+
+func TestWritePatientPDF(t *testing.T) {
+	patient := model.Patient{FullName: "Jane Doe", PatientCode: "J001", Gender: "Female", Age: 42, PhoneNumber: "0811,0812", HealthHistory: "Diabetes,Hypertension"}
+	summary := model.PatientTreatmentSummary{
+		PatientCode:        "J001",
+		PatientName:        "Jane Doe",
+		TotalTreatments:    2,
+		TotalTherapists:    1,
+		FirstTreatmentDate: "2025-01-06",
+		LastTreatmentDate:  "2025-01-13",
+		Treatments: []model.TreatmentSummaryItem{
+			{TreatmentDate: "2025-01-06", TherapistName: "Dr. Smith", Issues: "Back pain", Treatment: "Massage therapy,Exercise", NextVisit: "2025-01-13"},
+			{TreatmentDate: "2025-01-13", TherapistName: "Dr. Smith", Issues: "Follow-up", Remarks: strings.Repeat("Long remark that wraps. ", 10)},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WritePatientPDF(&buf, patient, summary); err != nil {
+		t.Fatalf("WritePatientPDF: %v", err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")) {
+		t.Fatalf("output does not start with a PDF header: %q", buf.Bytes()[:min(16, buf.Len())])
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("%%EOF")) {
+		t.Fatal("output is missing the PDF trailer")
+	}
+
+	text := pdfText(t, buf.Bytes())
+	for _, want := range []string{"Jane Doe", "J001", "Diabetes, Hypertension", "Dr. Smith", "Back pain", "Massage therapy, Exercise", "2025-01-13", "Long remark that wraps."} {
+		if !strings.Contains(text, want) {
+			t.Errorf("PDF text missing %q", want)
+		}
+	}
+}
+
+func TestWritePatientPDF_PaginatesLongHistory(t *testing.T) {
+	summary := model.PatientTreatmentSummary{PatientCode: "L001", PatientName: "Long History"}
+	for i := 0; i < 80; i++ {
+		summary.Treatments = append(summary.Treatments, model.TreatmentSummaryItem{TreatmentDate: fmt.Sprintf("2025-01-%02d", i%28+1), TherapistName: "Dr. Page", Issues: "Check"})
+	}
+	summary.TotalTreatments = len(summary.Treatments)
+
+	var buf bytes.Buffer
+	if err := WritePatientPDF(&buf, model.Patient{}, summary); err != nil {
+		t.Fatalf("WritePatientPDF: %v", err)
+	}
+	text := pdfText(t, buf.Bytes())
+	if !strings.Contains(text, "Page 2") {
+		t.Error("expected the treatment table to continue on a second page")
+	}
+	// The table header is repeated on every page.
+	if got := strings.Count(text, "(Therapist)"); got < 2 {
+		t.Errorf("table header rendered %d times, want at least 2", got)
+	}
+}
+
+func TestWritePatientPDF_NoTreatments(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePatientPDF(&buf, model.Patient{}, model.PatientTreatmentSummary{PatientCode: "N001", PatientName: "New Patient"}); err != nil {
+		t.Fatalf("WritePatientPDF: %v", err)
+	}
+	if text := pdfText(t, buf.Bytes()); !strings.Contains(text, "No treatments recorded.") {
+		t.Error("expected an empty-history note")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"log"
 	"os"
 	"strings"
+	"sync/atomic"
 
 	"github.com/ariebrainware/basis-data-ltt/model"
 	"gorm.io/datatypes"
@@ -24,8 +25,11 @@ const (
 	EventPasswordChanged    SecurityEventType = "PASSWORD_CHANGED"
 	EventUnauthorizedAccess SecurityEventType = "UNAUTHORIZED_ACCESS"
 	EventRateLimitExceeded  SecurityEventType = "RATE_LIMIT_EXCEEDED"
+	EventLoginBackoff       SecurityEventType = "LOGIN_BACKOFF"
 	EventSuspiciousActivity SecurityEventType = "SUSPICIOUS_ACTIVITY"
 	EventEndpointCall       SecurityEventType = "ENDPOINT_CALL"
+	EventAdminAction        SecurityEventType = "ADMIN_ACTION"
+	EventEmailVerified      SecurityEventType = "EMAIL_VERIFIED"
 )
 
 // SecurityEvent represents a security event to be logged
@@ -179,11 +183,13 @@ func logLoginEventWithUserID(eventType SecurityEventType, message string, params
 
 // LogLoginSuccess logs a successful login event
 func LogLoginSuccess(params LoginParams) {
+	atomic.AddInt64(&loginSuccessTotal, 1)
 	logLoginEventWithUserID(EventLoginSuccess, "User logged in successfully", params)
 }
 
 // LogLoginFailure logs a failed login attempt
 func LogLoginFailure(params LoginParams) {
+	atomic.AddInt64(&loginFailureTotal, 1)
 	msg := "Login failed"
 	if params.Reason != "" {
 		msg = fmt.Sprintf("Login failed: %s", params.Reason)
@@ -212,6 +218,7 @@ type AccountLockParams struct {
 
 // LogAccountLocked logs when an account is locked
 func LogAccountLocked(params AccountLockParams) {
+	atomic.AddInt64(&accountLockedTotal, 1)
 	LogSecurityEvent(SecurityEvent{
 		EventType: EventAccountLocked,
 		UserID:    fmt.Sprintf("%d", params.UserID),
@@ -230,6 +237,7 @@ type RateLimitParams struct {
 
 // LogRateLimitExceeded logs when rate limit is exceeded
 func LogRateLimitExceeded(params RateLimitParams) {
+	atomic.AddInt64(&rateLimitExceededTotal, 1)
 	LogSecurityEvent(SecurityEvent{
 		EventType: EventRateLimitExceeded,
 		Email:     params.Email,
@@ -238,6 +246,18 @@ func LogRateLimitExceeded(params RateLimitParams) {
 	})
 }
 
+// LogLoginBackoff logs a login attempt rejected because the account's backoff
+// delay has not elapsed. It is counted apart from the rate limiter.
+func LogLoginBackoff(params RateLimitParams) {
+	atomic.AddInt64(&loginBackoffRejectedTotal, 1)
+	LogSecurityEvent(SecurityEvent{
+		EventType: EventLoginBackoff,
+		Email:     params.Email,
+		IP:        params.IP,
+		Message:   fmt.Sprintf("Login backoff in effect for endpoint: %s", params.Endpoint),
+	})
+}
+
 // GetSecurityLoggerForTest returns the current security logger for testing purposes
 func GetSecurityLoggerForTest() *log.Logger {
 	return securityLogger